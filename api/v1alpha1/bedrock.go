@@ -0,0 +1,48 @@
+package v1alpha1
+
+import (
+	"slices"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/kmdkuk/mcing/pkg/constants"
+)
+
+// bedrockPluginNames are the plugins installed by the controller for spec.bedrock.
+var bedrockPluginNames = []string{constants.GeyserPluginName, constants.FloodgatePluginName}
+
+// InstallsPlugins returns true if the controller installs the Geyser plugin.
+func (b *Bedrock) InstallsPlugins() bool {
+	return b != nil && b.Enabled && (b.InstallPlugins == nil || *b.InstallPlugins)
+}
+
+func (b *Bedrock) validate(p *field.Path, server *Server, plugins []Artifact) field.ErrorList {
+	var allErrs field.ErrorList
+	if !b.Enabled {
+		return nil
+	}
+
+	if b.InstallsPlugins() {
+		if server == nil || server.Type != ServerTypePaper {
+			allErrs = append(allErrs, field.Invalid(p.Child("installPlugins"), true,
+				"requires spec.server.type Paper; set it to false to install Geyser yourself"))
+		}
+		for i, a := range plugins {
+			if slices.Contains(bedrockPluginNames, a.Name) {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "plugins").Index(i).Child("name"), a.Name,
+					"the plugin is installed by spec.bedrock"))
+			}
+		}
+	} else {
+		if b.Floodgate {
+			allErrs = append(allErrs, field.Invalid(p.Child("floodgate"), true, "requires installPlugins"))
+		}
+		if b.GeyserDownload != nil {
+			allErrs = append(allErrs, field.Forbidden(p.Child("geyserDownload"), "requires installPlugins"))
+		}
+	}
+	if b.FloodgateDownload != nil && !b.Floodgate {
+		allErrs = append(allErrs, field.Forbidden(p.Child("floodgateDownload"), "requires floodgate"))
+	}
+	return allErrs
+}
@@ -0,0 +1,44 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"k8s.io/utils/ptr"
+)
+
+func TestBedrock_InstallsPlugins(t *testing.T) {
+	tests := []struct {
+		name    string
+		bedrock *Bedrock
+		want    bool
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name:    "disabled",
+			bedrock: &Bedrock{},
+		},
+		{
+			name:    "enabled",
+			bedrock: &Bedrock{Enabled: true},
+			want:    true,
+		},
+		{
+			name:    "installed by the user",
+			bedrock: &Bedrock{Enabled: true, InstallPlugins: ptr.To(false)},
+		},
+		{
+			name:    "explicitly installed",
+			bedrock: &Bedrock{Enabled: true, InstallPlugins: ptr.To(true)},
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.bedrock.InstallsPlugins(); got != tt.want {
+				t.Errorf("InstallsPlugins() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// HostnameIndexKey is the field index of the Minecrafts by the hostnames set in their spec.
+const HostnameIndexKey = "spec.hostnames"
+
+// ClaimedHostnames returns the hostnames set by spec.externalHostname and spec.hostnameAliases.
+// The generated hostname is not included, because it depends on the domain of the gateway.
+func (m *Minecraft) ClaimedHostnames() []string {
+	var hostnames []string
+	if m.Spec.ExternalHostname != nil && *m.Spec.ExternalHostname != "" {
+		hostnames = append(hostnames, *m.Spec.ExternalHostname)
+	}
+	return append(hostnames, m.Spec.HostnameAliases...)
+}
+
+// GetExternalServerNames returns the hostnames that mc-router routes to the server.
+// The first one is the external server name, followed by the aliases.
+func (m *Minecraft) GetExternalServerNames(defaultDomain string) []string {
+	return append([]string{m.GetExternalServerName(defaultDomain)}, m.Spec.HostnameAliases...)
+}
+
+// IndexHostnames is the indexer function of HostnameIndexKey.
+func IndexHostnames(obj client.Object) []string {
+	m, ok := obj.(*Minecraft)
+	if !ok {
+		return nil
+	}
+	return m.ClaimedHostnames()
+}
+
+func (s *MinecraftSpec) validateHostnames(p *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	seen := map[string]bool{}
+	if s.ExternalHostname != nil && *s.ExternalHostname != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(*s.ExternalHostname) {
+			allErrs = append(allErrs, field.Invalid(p.Child("externalHostname"), *s.ExternalHostname, msg))
+		}
+		seen[*s.ExternalHostname] = true
+	}
+	pp := p.Child("hostnameAliases")
+	for i, h := range s.HostnameAliases {
+		for _, msg := range validation.IsDNS1123Subdomain(h) {
+			allErrs = append(allErrs, field.Invalid(pp.Index(i), h, msg))
+		}
+		if seen[h] {
+			allErrs = append(allErrs, field.Duplicate(pp.Index(i), h))
+		}
+		seen[h] = true
+	}
+	return allErrs
+}
+
+// validateHostnamesUnique checks that no other Minecraft in the cluster claims the hostnames of m,
+// including the hostnames generated with the domains of the gateways.
+// The Minecrafts are listed with HostnameIndexKey from the cache, so two servers created at the same time
+// may still claim the same hostname.
+func validateHostnamesUnique(ctx context.Context, reader client.Reader, m *Minecraft) (field.ErrorList, error) {
+	var allErrs field.ErrorList
+
+	p := field.NewPath("spec")
+	paths := map[string]*field.Path{}
+	if m.Spec.ExternalHostname != nil && *m.Spec.ExternalHostname != "" {
+		paths[*m.Spec.ExternalHostname] = p.Child("externalHostname")
+	}
+	for i, h := range m.Spec.HostnameAliases {
+		if _, ok := paths[h]; !ok {
+			paths[h] = p.Child("hostnameAliases").Index(i)
+		}
+	}
+	for _, h := range m.ClaimedHostnames() {
+		owner, err := claimingServer(ctx, reader, m, h)
+		if err != nil {
+			return nil, err
+		}
+		if owner != "" {
+			allErrs = append(allErrs, field.Invalid(paths[h], h, "the hostname is used by "+owner))
+		}
+	}
+
+	gateways := &MinecraftGatewayList{}
+	if err := reader.List(ctx, gateways); err != nil {
+		return nil, err
+	}
+	for _, gw := range gateways.Items {
+		domain := gw.Spec.DefaultDomain
+		if domain == "" {
+			continue
+		}
+		for _, h := range m.ClaimedHostnames() {
+			owner, err := generatingServer(ctx, reader, m, h, domain)
+			if err != nil {
+				return nil, err
+			}
+			if owner != "" {
+				allErrs = append(allErrs, field.Invalid(paths[h], h,
+					fmt.Sprintf("the hostname is generated for %s by MinecraftGateway %s", owner, gw.Name)))
+			}
+		}
+		if m.Spec.ExternalHostname != nil && *m.Spec.ExternalHostname != "" {
+			continue
+		}
+		generated := m.GetExternalServerName(domain)
+		owner, err := claimingServer(ctx, reader, m, generated)
+		if err != nil {
+			return nil, err
+		}
+		if owner != "" {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("metadata", "name"), m.Name,
+				fmt.Sprintf("the hostname %s generated by MinecraftGateway %s is used by %s", generated, gw.Name, owner)))
+		}
+	}
+	return allErrs, nil
+}
+
+// claimingServer returns the namespace and the name of another Minecraft that claims h, or an empty string.
+func claimingServer(ctx context.Context, reader client.Reader, m *Minecraft, h string) (string, error) {
+	others := &MinecraftList{}
+	if err := reader.List(ctx, others, client.MatchingFields{HostnameIndexKey: h}); err != nil {
+		return "", err
+	}
+	for _, other := range others.Items {
+		if other.Namespace == m.Namespace && other.Name == m.Name {
+			continue
+		}
+		return other.Namespace + "/" + other.Name, nil
+	}
+	return "", nil
+}
+
+// generatingServer returns the namespace and the name of another Minecraft whose generated hostname for domain is h,
+// or an empty string.
+func generatingServer(ctx context.Context, reader client.Reader, m *Minecraft, h, domain string) (string, error) {
+	prefix, ok := strings.CutSuffix(h, "."+domain)
+	if !ok {
+		return "", nil
+	}
+	// A namespace name has no dots, so the last label is the namespace.
+	i := strings.LastIndex(prefix, ".")
+	if i <= 0 {
+		return "", nil
+	}
+	name, namespace := prefix[:i], prefix[i+1:]
+	if namespace == m.Namespace && name == m.Name {
+		return "", nil
+	}
+	other := &Minecraft{}
+	err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, other)
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if other.GetExternalServerName(domain) != h {
+		// The server uses spec.externalHostname instead of the generated hostname.
+		return "", nil
+	}
+	return namespace + "/" + name, nil
+}
@@ -0,0 +1,202 @@
+package v1alpha1
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMinecraft_GetExternalServerNames(t *testing.T) {
+	tests := []struct {
+		name string
+		spec MinecraftSpec
+		want []string
+	}{
+		{
+			name: "generated hostname",
+			want: []string{"lobby.default.mc.example.com"},
+		},
+		{
+			name: "external hostname and aliases",
+			spec: MinecraftSpec{
+				ExternalHostname: ptr.To("survival.example.com"),
+				HostnameAliases:  []string{"smp.example.com", "survival.example.net"},
+			},
+			want: []string{"survival.example.com", "smp.example.com", "survival.example.net"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Minecraft{
+				ObjectMeta: metav1.ObjectMeta{Name: "lobby", Namespace: "default"},
+				Spec:       tt.spec,
+			}
+			if got := m.GetExternalServerNames("mc.example.com"); !slices.Equal(got, tt.want) {
+				t.Errorf("GetExternalServerNames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMinecraftSpec_validateHostnames(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    MinecraftSpec
+		wantErr string
+	}{
+		{
+			name: "valid",
+			spec: MinecraftSpec{
+				ExternalHostname: ptr.To("survival.example.com"),
+				HostnameAliases:  []string{"smp.example.com"},
+			},
+		},
+		{
+			name:    "invalid external hostname",
+			spec:    MinecraftSpec{ExternalHostname: ptr.To("Survival_example")},
+			wantErr: "spec.externalHostname",
+		},
+		{
+			name:    "invalid alias",
+			spec:    MinecraftSpec{HostnameAliases: []string{"smp.example.com", "smp..example.com"}},
+			wantErr: "spec.hostnameAliases[1]",
+		},
+		{
+			name: "alias of the external hostname",
+			spec: MinecraftSpec{
+				ExternalHostname: ptr.To("survival.example.com"),
+				HostnameAliases:  []string{"survival.example.com"},
+			},
+			wantErr: "spec.hostnameAliases[0]: Duplicate value",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.spec.validateHostnames(field.NewPath("spec"))
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("validateHostnames() = %v, want no error", errs)
+				}
+				return
+			}
+			if !strings.Contains(errs.ToAggregate().Error(), tt.wantErr) {
+				t.Errorf("validateHostnames() = %v, want %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateHostnamesUnique(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	existing := &Minecraft{
+		ObjectMeta: metav1.ObjectMeta{Name: "survival", Namespace: "team-a"},
+		Spec: MinecraftSpec{
+			ExternalHostname: ptr.To("survival.example.com"),
+			HostnameAliases:  []string{"smp.example.com"},
+		},
+	}
+	lobby := &Minecraft{
+		ObjectMeta: metav1.ObjectMeta{Name: "lobby", Namespace: "team-a"},
+		Spec:       MinecraftSpec{HostnameAliases: []string{"hub.team-c.minecraft.local"}},
+	}
+	gateway := &MinecraftGateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec:       MinecraftGatewaySpec{DefaultDomain: "minecraft.local"},
+	}
+	reader := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(existing, lobby, gateway).
+		WithIndex(&Minecraft{}, HostnameIndexKey, IndexHostnames).
+		Build()
+
+	tests := []struct {
+		name    string
+		mc      *Minecraft
+		wantErr string
+	}{
+		{
+			name: "unique",
+			mc: &Minecraft{
+				ObjectMeta: metav1.ObjectMeta{Name: "creative", Namespace: "team-b"},
+				Spec:       MinecraftSpec{ExternalHostname: ptr.To("creative.example.com")},
+			},
+		},
+		{
+			name: "update of the same server",
+			mc:   existing,
+		},
+		{
+			name: "external hostname of another server",
+			mc: &Minecraft{
+				ObjectMeta: metav1.ObjectMeta{Name: "survival", Namespace: "team-b"},
+				Spec:       MinecraftSpec{ExternalHostname: ptr.To("survival.example.com")},
+			},
+			wantErr: "spec.externalHostname: Invalid value: \"survival.example.com\": the hostname is used by team-a/survival",
+		},
+		{
+			name: "alias of another server",
+			mc: &Minecraft{
+				ObjectMeta: metav1.ObjectMeta{Name: "creative", Namespace: "team-b"},
+				Spec:       MinecraftSpec{HostnameAliases: []string{"creative.example.com", "smp.example.com"}},
+			},
+			wantErr: "spec.hostnameAliases[1]",
+		},
+		{
+			name: "generated hostname of another server",
+			mc: &Minecraft{
+				ObjectMeta: metav1.ObjectMeta{Name: "creative", Namespace: "team-b"},
+				Spec:       MinecraftSpec{ExternalHostname: ptr.To("lobby.team-a.minecraft.local")},
+			},
+			wantErr: "the hostname is generated for team-a/lobby by MinecraftGateway default",
+		},
+		{
+			name: "hostname of a server using the external hostname",
+			mc: &Minecraft{
+				ObjectMeta: metav1.ObjectMeta{Name: "creative", Namespace: "team-b"},
+				Spec:       MinecraftSpec{HostnameAliases: []string{"survival.team-a.minecraft.local"}},
+			},
+		},
+		{
+			name: "generated hostname used by another server",
+			mc: &Minecraft{
+				ObjectMeta: metav1.ObjectMeta{Name: "hub", Namespace: "team-c"},
+			},
+			wantErr: "metadata.name: Invalid value: \"hub\": the hostname hub.team-c.minecraft.local generated by " +
+				"MinecraftGateway default is used by team-a/lobby",
+		},
+		{
+			name: "generated hostname not used with the external hostname",
+			mc: &Minecraft{
+				ObjectMeta: metav1.ObjectMeta{Name: "hub", Namespace: "team-c"},
+				Spec:       MinecraftSpec{ExternalHostname: ptr.To("hub.example.com")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, err := validateHostnamesUnique(context.Background(), reader, tt.mc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("validateHostnamesUnique() = %v, want no error", errs)
+				}
+				return
+			}
+			if !strings.Contains(errs.ToAggregate().Error(), tt.wantErr) {
+				t.Errorf("validateHostnamesUnique() = %v, want %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
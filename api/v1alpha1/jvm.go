@@ -0,0 +1,84 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/kmdkuk/mcing/pkg/constants"
+)
+
+const (
+	mebibyte = 1024 * 1024
+	// minHeapMiB is the smallest heap that runs a server.
+	minHeapMiB = 512
+	// minNonHeapMiB is the memory left for the metaspace, the thread stacks and the native memory of the JVM.
+	minNonHeapMiB = 256
+)
+
+// jvmEnvNames are the environment variables of itzg/minecraft-server that the controller sets for spec.jvm.
+var jvmEnvNames = []string{
+	constants.MemoryEnvName,
+	constants.InitMemoryEnvName,
+	constants.MaxMemoryEnvName,
+	constants.JVMOptsEnvName,
+	constants.AikarFlagsEnvName,
+}
+
+// HeapMiB returns the heap size in MiB for the memory limit of the minecraft container,
+// or 0 if the heap size is not managed.
+func (j *JVM) HeapMiB(c *corev1.Container) int64 {
+	if j.HeapPercentage == 0 || c == nil {
+		return 0
+	}
+	limit := c.Resources.Limits.Memory()
+	if limit.IsZero() {
+		return 0
+	}
+	return limit.Value() / mebibyte * int64(j.HeapPercentage) / 100
+}
+
+// UseAikarFlags returns true if the server runs with Aikar's flags.
+func (j *JVM) UseAikarFlags(server *Server) bool {
+	switch j.GCPreset {
+	case GCPresetAikar:
+		return true
+	case GCPresetDefault:
+		return false
+	}
+	return server != nil && server.Type == ServerTypePaper
+}
+
+func (j *JVM) validate(p *field.Path, c *corev1.Container) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if j.HeapPercentage != 0 && c != nil {
+		limit := c.Resources.Limits.Memory()
+		heap := j.HeapMiB(c)
+		switch {
+		case limit.IsZero():
+			allErrs = append(allErrs, field.Required(p.Child("heapPercentage"),
+				"requires the memory limit of the minecraft container"))
+		case heap < minHeapMiB:
+			allErrs = append(allErrs, field.Invalid(p.Child("heapPercentage"), j.HeapPercentage,
+				fmt.Sprintf("the heap of %dMi is smaller than %dMi", heap, minHeapMiB)))
+		case limit.Value()/mebibyte-heap < minNonHeapMiB:
+			allErrs = append(allErrs, field.Invalid(p.Child("heapPercentage"), j.HeapPercentage,
+				fmt.Sprintf("the memory limit of %s leaves less than %dMi outside the heap", limit, minNonHeapMiB)))
+		}
+	}
+
+	pp := p.Child("extraArgs")
+	for i, arg := range j.ExtraArgs {
+		switch {
+		case arg == "" || strings.ContainsAny(arg, " \t\n"):
+			allErrs = append(allErrs, field.Invalid(pp.Index(i), arg, "must be a single argument without spaces"))
+		case j.HeapPercentage != 0 && (strings.HasPrefix(arg, "-Xmx") || strings.HasPrefix(arg, "-Xms") ||
+			strings.HasPrefix(arg, "-XX:MaxRAMPercentage")):
+			allErrs = append(allErrs, field.Invalid(pp.Index(i), arg, "the heap size is set by heapPercentage"))
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,89 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestJVM_HeapMiB(t *testing.T) {
+	tests := []struct {
+		name  string
+		jvm   JVM
+		limit string
+		want  int64
+	}{
+		{
+			name:  "percentage of the limit",
+			jvm:   JVM{HeapPercentage: 75},
+			limit: "4Gi",
+			want:  3072,
+		},
+		{
+			name:  "decimal limit",
+			jvm:   JVM{HeapPercentage: 50},
+			limit: "2G",
+			want:  953,
+		},
+		{
+			name:  "no percentage",
+			jvm:   JVM{},
+			limit: "4Gi",
+		},
+		{
+			name: "no limit",
+			jvm:  JVM{HeapPercentage: 75},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &corev1.Container{}
+			if tt.limit != "" {
+				c.Resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(tt.limit)}
+			}
+			if got := tt.jvm.HeapMiB(c); got != tt.want {
+				t.Errorf("HeapMiB() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJVM_UseAikarFlags(t *testing.T) {
+	tests := []struct {
+		name   string
+		jvm    JVM
+		server *Server
+		want   bool
+	}{
+		{
+			name:   "default for Paper",
+			server: &Server{Type: ServerTypePaper},
+			want:   true,
+		},
+		{
+			name:   "default for Vanilla",
+			server: &Server{Type: ServerTypeVanilla},
+		},
+		{
+			name: "default without spec.server",
+		},
+		{
+			name: "Aikar",
+			jvm:  JVM{GCPreset: GCPresetAikar},
+			want: true,
+		},
+		{
+			name:   "Default for Paper",
+			jvm:    JVM{GCPreset: GCPresetDefault},
+			server: &Server{Type: ServerTypePaper},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.jvm.UseAikarFlags(tt.server); got != tt.want {
+				t.Errorf("UseAikarFlags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,10 +3,20 @@ package v1alpha1
 import (
 	"fmt"
 	"maps"
+	"net"
+	"net/url"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 
 	"github.com/kmdkuk/mcing/pkg/constants"
 )
@@ -22,16 +32,71 @@ type MinecraftSpec struct {
 	// PodTemplate is a `Pod` template for Minecraft server container.
 	PodTemplate PodTemplateSpec `json:"podTemplate"`
 
+	// Server selects the server software run by the itzg/minecraft-server image.
+	// +optional
+	Server *Server `json:"server,omitempty"`
+
+	// JVM configures the heap size and the flags of the JVM that runs the server.
+	// +optional
+	JVM *JVM `json:"jvm,omitempty"`
+
 	// PersistentVolumeClaimSpec is a specification of `PersistentVolumeClaim` for persisting data in minecraft.
 	// A claim named "minecraft-data" must be included in the list.
 	// +kubebuilder:validation:MinItems=1
 	// +kubebuilder:validation:MaxItems=1
 	VolumeClaimTemplates []PersistentVolumeClaim `json:"volumeClaimTemplates"`
 
+	// PersistentVolumeClaimRetentionPolicy is set to the StatefulSet to decide what happens to the PVCs
+	// when the StatefulSet is deleted or scaled down. If not set, the PVCs are retained.
+	// whenScaled must not be Delete with spec.hibernation, which scales the StatefulSet to zero.
+	// whenDeleted must not be Delete unless spec.deletionPolicy is Delete.
+	// +optional
+	PersistentVolumeClaimRetentionPolicy *appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy `json:"persistentVolumeClaimRetentionPolicy,omitempty"`
+
+	// DataSource seeds the data volume of a new server before its first start,
+	// such as to make a staging copy of another server. It cannot be changed after creation.
+	// +optional
+	DataSource *DataSource `json:"dataSource,omitempty"`
+
 	// ServiceTemplate is a `Service` template.
 	// +optional
 	ServiceTemplate *ServiceTemplate `json:"serviceTemplate,omitempty"`
 
+	// Mods are installed into the mods directory of the data volume by mcing-init.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Mods []Artifact `json:"mods,omitempty"`
+
+	// Plugins are installed into the plugins directory of the data volume by mcing-init.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Plugins []Artifact `json:"plugins,omitempty"`
+
+	// Datapacks are zip files installed into the datapacks directory of the level.
+	// Changes are applied by /reload without restarting the server.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Datapacks []Artifact `json:"datapacks,omitempty"`
+
+	// ResourcePack is the resource pack offered to players.
+	// +optional
+	ResourcePack *ResourcePack `json:"resourcePack,omitempty"`
+
+	// Bedrock lets Bedrock Edition players join the server through Geyser.
+	// +optional
+	Bedrock *Bedrock `json:"bedrock,omitempty"`
+
+	// Agent overrides the mcing-agent sidecar container.
+	// +optional
+	Agent ContainerOverride `json:"agent,omitempty"`
+
+	// Init overrides the mcing-init init container.
+	// +optional
+	Init ContainerOverride `json:"init,omitempty"`
+
 	// operators on server. exec /op or /deop
 	// +optional
 	Ops Ops `json:"ops,omitempty"`
@@ -39,16 +104,35 @@ type MinecraftSpec struct {
 	// whitelist
 	Whitelist Whitelist `json:"whitelist,omitempty"`
 
+	// ServerProperties are typed values of `server.properties`.
+	// They take precedence over ServerPropertiesConfigMapName and the defaults.
+	// +optional
+	ServerProperties *ServerProperties `json:"serverProperties,omitempty"`
+
 	// ServerPropertiesConfigMapName is a `ConfigMap` name of `server.properties`.
 	// +nullable
 	// +optional
 	ServerPropertiesConfigMapName *string `json:"serverPropertiesConfigMapName,omitempty"`
 
+	// PropertiesRestartPolicy decides what happens when a change of `server.properties` requires a restart.
+	// "Manual" reports the change with the RestartRequired condition.
+	// "Rollout" also restarts the server pod.
+	// +kubebuilder:default=Manual
+	// +optional
+	PropertiesRestartPolicy PropertiesRestartPolicy `json:"propertiesRestartPolicy,omitempty"`
+
 	// OtherConfigMapName is a `ConfigMap` name of other configurations file(eg. banned-ips.json, ops.json etc)
 	// +nullable
 	// +optional
 	OtherConfigMapName *string `json:"otherConfigMapName,omitempty"`
 
+	// ConfigFiles are extra config files placed in the data directory, such as `config/paper-global.yml`.
+	// mcing-init places them when the pod starts, and mcing-agent places their changes while the server runs.
+	// +optional
+	// +listType=map
+	// +listMapKey=path
+	ConfigFiles []ConfigFile `json:"configFiles,omitempty"`
+
 	// RconPasswordSecretName is a `Secret` name for RCON password.
 	// +nullable
 	// +optional
@@ -58,15 +142,387 @@ type MinecraftSpec struct {
 	// +optional
 	AutoPause AutoPause `json:"autoPause,omitempty"`
 
+	// Hibernation configuration
+	// +optional
+	Hibernation Hibernation `json:"hibernation,omitempty"`
+
 	// Backup configuration
 	// +optional
 	Backup Backup `json:"backup,omitempty"`
 
+	// DeletionPolicy is what happens to the PVCs of the server when the Minecraft is deleted.
+	// Retain keeps them, Delete deletes them, and Snapshot takes a VolumeSnapshot of each of them
+	// as the final backup and then deletes them.
+	// +kubebuilder:default=Retain
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// GatewayName is the name of the MinecraftGateway that routes players to the server.
+	// If not set, the default gateway is used.
+	// +optional
+	GatewayName *string `json:"gatewayName,omitempty"`
+
 	// ExternalHostname is the custom hostname for mc-router routing.
 	// If not set, FQDN will be generated as <name>.<namespace>.<default-domain>.
-	// Only used when mc-router is enabled on the controller.
+	// Only used when the server is routed by a MinecraftGateway.
 	// +optional
 	ExternalHostname *string `json:"externalHostname,omitempty"`
+
+	// HostnameAliases are additional hostnames that mc-router routes to the server.
+	// A hostname set by externalHostname or hostnameAliases cannot be used by another Minecraft.
+	// +optional
+	HostnameAliases []string `json:"hostnameAliases,omitempty"`
+}
+
+// Server defines the server software of the Minecraft server.
+// The controller translates it into the image tag and the environment variables of itzg/minecraft-server.
+type Server struct {
+	// Type is the server software.
+	// +kubebuilder:default=Vanilla
+	// +optional
+	Type ServerType `json:"type,omitempty"`
+
+	// Version is the Minecraft version such as "1.21.1", or LATEST or SNAPSHOT.
+	// +kubebuilder:default=LATEST
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// JavaVersion is the major version of Java that runs the server.
+	// If not set, it is derived from Version.
+	// +kubebuilder:validation:Enum=8;11;17;21;25
+	// +optional
+	JavaVersion int32 `json:"javaVersion,omitempty"`
+}
+
+// ServerType is the server software.
+// +kubebuilder:validation:Enum=Vanilla;Paper;Fabric;Forge;NeoForge
+type ServerType string
+
+const (
+	// ServerTypeVanilla is the official server.
+	ServerTypeVanilla ServerType = "Vanilla"
+	// ServerTypePaper is the Paper server.
+	ServerTypePaper ServerType = "Paper"
+	// ServerTypeFabric is the Fabric mod loader.
+	ServerTypeFabric ServerType = "Fabric"
+	// ServerTypeForge is the Forge mod loader.
+	ServerTypeForge ServerType = "Forge"
+	// ServerTypeNeoForge is the NeoForge mod loader.
+	ServerTypeNeoForge ServerType = "NeoForge"
+)
+
+// JVM defines the settings of the JVM that runs the server.
+type JVM struct {
+	// HeapPercentage is the heap size as a percentage of the memory limit of the minecraft container.
+	// The rest of the limit is left for the memory that the JVM uses outside of the heap.
+	// The memory limit is required with HeapPercentage.
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=90
+	// +optional
+	HeapPercentage int32 `json:"heapPercentage,omitempty"`
+
+	// GCPreset is the set of garbage collector flags.
+	// "Aikar" uses Aikar's flags, and "Default" uses the defaults of the JVM.
+	// If not set, Aikar's flags are used for Paper.
+	// +optional
+	GCPreset GCPreset `json:"gcPreset,omitempty"`
+
+	// ExtraArgs are additional arguments of the JVM such as "-XX:+UseZGC".
+	// +optional
+	ExtraArgs []string `json:"extraArgs,omitempty"`
+}
+
+// GCPreset is a set of garbage collector flags.
+// +kubebuilder:validation:Enum=Aikar;Default
+type GCPreset string
+
+const (
+	// GCPresetAikar is Aikar's flags for G1GC.
+	GCPresetAikar GCPreset = "Aikar"
+	// GCPresetDefault is the defaults of the JVM.
+	GCPresetDefault GCPreset = "Default"
+)
+
+// ServerProperties defines typed values of server.properties. Unset fields keep the other values.
+type ServerProperties struct {
+	// Difficulty is the difficulty of the world.
+	// +optional
+	Difficulty Difficulty `json:"difficulty,omitempty"`
+
+	// Gamemode is the default game mode of players.
+	// +optional
+	Gamemode Gamemode `json:"gamemode,omitempty"`
+
+	// ForceGamemode makes players join in the default game mode.
+	// +optional
+	ForceGamemode *bool `json:"forceGamemode,omitempty"`
+
+	// Hardcore makes players spectators when they die.
+	// +optional
+	Hardcore *bool `json:"hardcore,omitempty"`
+
+	// MaxPlayers is the maximum number of players online at the same time.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxPlayers *int32 `json:"maxPlayers,omitempty"`
+
+	// MOTD is the message shown in the server list.
+	// +optional
+	MOTD string `json:"motd,omitempty"`
+
+	// ViewDistance is the number of chunks sent to players around them.
+	// +kubebuilder:validation:Minimum=3
+	// +kubebuilder:validation:Maximum=32
+	// +optional
+	ViewDistance *int32 `json:"viewDistance,omitempty"`
+
+	// SimulationDistance is the number of chunks around players that are updated.
+	// +kubebuilder:validation:Minimum=3
+	// +kubebuilder:validation:Maximum=32
+	// +optional
+	SimulationDistance *int32 `json:"simulationDistance,omitempty"`
+
+	// PVP allows players to damage each other.
+	// +optional
+	PVP *bool `json:"pvp,omitempty"`
+
+	// OnlineMode verifies players with the Minecraft account service.
+	// +optional
+	OnlineMode *bool `json:"onlineMode,omitempty"`
+
+	// AllowFlight keeps players who fly in survival mode from being kicked.
+	// +optional
+	AllowFlight *bool `json:"allowFlight,omitempty"`
+
+	// AllowNether allows players to travel to the Nether.
+	// +optional
+	AllowNether *bool `json:"allowNether,omitempty"`
+
+	// EnableCommandBlock enables command blocks.
+	// +optional
+	EnableCommandBlock *bool `json:"enableCommandBlock,omitempty"`
+
+	// SpawnProtection is the radius of the spawn area that only operators can change. 0 disables it.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SpawnProtection *int32 `json:"spawnProtection,omitempty"`
+
+	// PlayerIdleTimeout is the number of minutes after which idle players are kicked. 0 disables it.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PlayerIdleTimeout *int32 `json:"playerIdleTimeout,omitempty"`
+
+	// LevelName is the directory of the world in the data volume.
+	// +kubebuilder:validation:Pattern=`^[^./][^/]*$`
+	// +optional
+	LevelName string `json:"levelName,omitempty"`
+
+	// LevelSeed is the seed used to generate a new world.
+	// +optional
+	LevelSeed string `json:"levelSeed,omitempty"`
+}
+
+// Difficulty is the difficulty of the world.
+// +kubebuilder:validation:Enum=peaceful;easy;normal;hard
+type Difficulty string
+
+const (
+	// DifficultyPeaceful is the peaceful difficulty.
+	DifficultyPeaceful Difficulty = "peaceful"
+	// DifficultyEasy is the easy difficulty.
+	DifficultyEasy Difficulty = "easy"
+	// DifficultyNormal is the normal difficulty.
+	DifficultyNormal Difficulty = "normal"
+	// DifficultyHard is the hard difficulty.
+	DifficultyHard Difficulty = "hard"
+)
+
+// Gamemode is the game mode of players.
+// +kubebuilder:validation:Enum=survival;creative;adventure;spectator
+type Gamemode string
+
+const (
+	// GamemodeSurvival is the survival mode.
+	GamemodeSurvival Gamemode = "survival"
+	// GamemodeCreative is the creative mode.
+	GamemodeCreative Gamemode = "creative"
+	// GamemodeAdventure is the adventure mode.
+	GamemodeAdventure Gamemode = "adventure"
+	// GamemodeSpectator is the spectator mode.
+	GamemodeSpectator Gamemode = "spectator"
+)
+
+// Artifact is a mod, plugin or datapack file. Exactly one of url, configMap and oci must be set.
+// Files are cached on the data volume and removed when they are removed from the list.
+type Artifact struct {
+	// Name is the file name in the mods, plugins or datapacks directory.
+	// +kubebuilder:validation:Pattern=`^[^./][^/]*$`
+	Name string `json:"name"`
+
+	// URL is the HTTP(S) URL to download the file from. SHA256 is required with URL.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// SHA256 is the hex-encoded SHA-256 digest to verify the file.
+	// +kubebuilder:validation:Pattern=`^[0-9a-f]{64}$`
+	// +optional
+	SHA256 string `json:"sha256,omitempty"`
+
+	// ConfigMap is a key of a ConfigMap in the same namespace that holds the file.
+	// +optional
+	ConfigMap *corev1.ConfigMapKeySelector `json:"configMap,omitempty"`
+
+	// OCI is a reference to an OCI artifact that holds the file, such as ghcr.io/example/mod:1.0.0.
+	// An artifact with several layers must have a layer titled Name.
+	// +optional
+	OCI string `json:"oci,omitempty"`
+}
+
+// ConfigFile is a config file placed in the data directory.
+// Exactly one of configMapKeyRef, secretKeyRef and inline must be set.
+type ConfigFile struct {
+	// Path is the path of the file relative to the data directory.
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
+
+	// ConfigMapKeyRef is a key of a ConfigMap in the same namespace that holds the content.
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+
+	// SecretKeyRef is a key of a Secret in the same namespace that holds the content.
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+
+	// Inline is the content.
+	// +optional
+	Inline string `json:"inline,omitempty"`
+
+	// MergeStrategy decides how the content is applied to the file on the data volume.
+	// "Replace" overwrites the file.
+	// "Merge" applies the content as a JSON merge patch (RFC 7386), which keeps the other keys of the file.
+	// Only JSON and YAML files can be merged, and the comments in a merged YAML file are not kept.
+	// +kubebuilder:default=Replace
+	// +optional
+	MergeStrategy MergeStrategy `json:"mergeStrategy,omitempty"`
+}
+
+// MergeStrategy is how the content of a config file is applied.
+// +kubebuilder:validation:Enum=Replace;Merge
+type MergeStrategy string
+
+const (
+	// MergeStrategyReplace overwrites the file.
+	MergeStrategyReplace MergeStrategy = "Replace"
+	// MergeStrategyMerge applies the content to the file as a JSON merge patch.
+	MergeStrategyMerge MergeStrategy = "Merge"
+)
+
+// ResourcePack defines the resource pack offered to players.
+type ResourcePack struct {
+	// URL is the HTTP(S) URL to download the resource pack from.
+	URL string `json:"url"`
+
+	// SHA1 is the hex-encoded SHA-1 digest of the resource pack.
+	// If empty, mcing-init downloads the resource pack and computes it when the server starts.
+	// +kubebuilder:validation:Pattern=`^[0-9a-f]{40}$`
+	// +optional
+	SHA1 string `json:"sha1,omitempty"`
+
+	// Required makes players who decline the resource pack disconnect.
+	// +optional
+	Required bool `json:"required,omitempty"`
+
+	// Prompt is the message shown to players when they are asked to accept the resource pack.
+	// +optional
+	Prompt string `json:"prompt,omitempty"`
+}
+
+// Bedrock defines how Bedrock Edition players join the server.
+// Geyser translates their connections on UDP port 19132 into Java Edition connections.
+type Bedrock struct {
+	// Enabled exposes UDP port 19132 of the server for Bedrock Edition players.
+	Enabled bool `json:"enabled"`
+
+	// InstallPlugins installs the Geyser plugin, which requires the Paper server type.
+	// Set it to false to install Geyser yourself, e.g. as a Fabric mod listening on port 19132.
+	// +kubebuilder:default=true
+	// +optional
+	InstallPlugins *bool `json:"installPlugins,omitempty"`
+
+	// Floodgate also installs the Floodgate plugin, which lets Bedrock Edition players join
+	// without a Java Edition account.
+	// +optional
+	Floodgate bool `json:"floodgate,omitempty"`
+
+	// GeyserDownload pins the build of the Geyser plugin.
+	// If not set, the latest build is downloaded without verification every time the pod starts.
+	// +optional
+	GeyserDownload *PluginDownload `json:"geyserDownload,omitempty"`
+
+	// FloodgateDownload pins the build of the Floodgate plugin.
+	// If not set, the latest build is downloaded without verification every time the pod starts.
+	// +optional
+	FloodgateDownload *PluginDownload `json:"floodgateDownload,omitempty"`
+}
+
+// PluginDownload is a pinned build of a plugin installed by spec.bedrock.
+type PluginDownload struct {
+	// URL is the HTTP(S) URL to download the plugin from, such as a build of the GeyserMC download API.
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// SHA256 is the hex-encoded SHA-256 digest to verify the plugin.
+	// +kubebuilder:validation:Pattern=`^[0-9a-f]{64}$`
+	SHA256 string `json:"sha256"`
+}
+
+// InstalledResourcePack is the resource pack whose digest was computed by mcing-init.
+type InstalledResourcePack struct {
+	// URL is the URL of the resource pack.
+	URL string `json:"url"`
+
+	// SHA1 is the hex-encoded SHA-1 digest of the resource pack.
+	SHA1 string `json:"sha1"`
+}
+
+// InstalledArtifact is a file installed by mcing-init.
+type InstalledArtifact struct {
+	// Name is the file name.
+	Name string `json:"name"`
+
+	// SHA256 is the hex-encoded SHA-256 digest of the file.
+	// It is omitted when there are too many files to report.
+	// +optional
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// ContainerOverride defines the settings of a container managed by MCing.
+type ContainerOverride struct {
+	// Image overrides the image given to the controller.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Resources are the compute resources of the container.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// SecurityContext replaces the default security context,
+	// which satisfies the restricted Pod Security Standard.
+	// +optional
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// Env is appended to the environment variables set by MCing.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// VolumeMounts are appended to the volume mounts set by MCing.
+	// The volumes must be defined in podTemplate or volumeClaimTemplates.
+	// +optional
+	// +listType=map
+	// +listMapKey=mountPath
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
 }
 
 // Backup defines the backup configuration for the Minecraft server.
@@ -74,8 +530,86 @@ type Backup struct {
 	// Excludes is a list of file patterns to exclude from the backup.
 	// +optional
 	Excludes []string `json:"excludes,omitempty"`
+
+	// VolumeSnapshotClassName is the VolumeSnapshotClass of the snapshots of the PVCs.
+	// If not set, the default VolumeSnapshotClass is used.
+	// +optional
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty"`
+
+	// Snapshot takes a VolumeSnapshot of the data volume periodically.
+	// The world is saved and auto-save is disabled while the snapshot is taken.
+	// It requires the CSI snapshot controller.
+	// +optional
+	Snapshot *SnapshotBackup `json:"snapshot,omitempty"`
+}
+
+// SnapshotBackup defines the periodic VolumeSnapshot backup of the data volume.
+type SnapshotBackup struct {
+	// IntervalSeconds is the interval in seconds between snapshots.
+	// Default is 86400 seconds.
+	// +optional
+	// +kubebuilder:default=86400
+	// +kubebuilder:validation:Minimum=600
+	IntervalSeconds int `json:"intervalSeconds,omitempty"`
+
+	// Keep is the number of the snapshots to keep. Older snapshots are deleted.
+	// Default is 7.
+	// +optional
+	// +kubebuilder:default=7
+	// +kubebuilder:validation:Minimum=1
+	Keep int `json:"keep,omitempty"`
+}
+
+// DataSource is where the data volume of a new server is copied from.
+// Exactly one of minecraft, volumeSnapshot and backup must be set.
+type DataSource struct {
+	// Minecraft is the name of a Minecraft in the same namespace whose data volume is cloned.
+	// The StorageClass must support volume cloning.
+	// +optional
+	Minecraft string `json:"minecraft,omitempty"`
+
+	// VolumeSnapshot is the name of a VolumeSnapshot in the same namespace that the data volume is restored from.
+	// +optional
+	VolumeSnapshot string `json:"volumeSnapshot,omitempty"`
+
+	// Backup is an archive of the data directory that mcing-init extracts into the data volume.
+	// +optional
+	Backup *BackupArchive `json:"backup,omitempty"`
+
+	// StripOps removes the operators copied from the source before the first start.
+	// Only the users in spec.ops are operators of the new server.
+	// +optional
+	StripOps bool `json:"stripOps,omitempty"`
+
+	// StripWhitelist removes the whitelist copied from the source before the first start.
+	// Only the users in spec.whitelist are whitelisted on the new server.
+	// +optional
+	StripWhitelist bool `json:"stripWhitelist,omitempty"`
+}
+
+// BackupArchive is a tar.gz archive of the data directory, such as one made by `kubectl mcing download`.
+type BackupArchive struct {
+	// URL is the HTTP(S) URL to download the archive from.
+	URL string `json:"url"`
+
+	// SHA256 is the hex-encoded SHA-256 digest to verify the archive.
+	// +kubebuilder:validation:Pattern=`^[0-9a-f]{64}$`
+	SHA256 string `json:"sha256"`
 }
 
+// DeletionPolicy is what happens to the PVCs of a deleted server.
+// +kubebuilder:validation:Enum=Retain;Delete;Snapshot
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyRetain keeps the PVCs.
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// DeletionPolicyDelete deletes the PVCs.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicySnapshot takes a VolumeSnapshot of each PVC and then deletes the PVCs.
+	DeletionPolicySnapshot DeletionPolicy = "Snapshot"
+)
+
 // AutoPause defines the auto-pause configuration for the Minecraft server.
 type AutoPause struct {
 	// Enabled enables the auto-pause function.
@@ -88,6 +622,216 @@ type AutoPause struct {
 	// +optional
 	// +kubebuilder:default=300
 	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+
+	// Mode is the backend that pauses the server.
+	// "lazymc" runs lazymc as the main process of the minecraft container.
+	// "agent" lets mcing-agent proxy the server port and start/stop the server process,
+	// for clusters that cannot ship the lazymc binary.
+	// +optional
+	// +kubebuilder:default=lazymc
+	Mode AutoPauseMode `json:"mode,omitempty"`
+
+	// Lazymc holds the lazymc settings beyond the sleep timeout.
+	// It is only used when Mode is lazymc.
+	// +optional
+	Lazymc *LazymcOptions `json:"lazymc,omitempty"`
+}
+
+// LazymcOptions defines the lazymc settings rendered into lazymc.toml.
+// Unset fields fall back to the lazymc defaults.
+type LazymcOptions struct {
+	// FreezeProcess freezes the server process with SIGSTOP instead of stopping it.
+	// A frozen server resumes instantly but keeps its memory.
+	// +optional
+	FreezeProcess bool `json:"freezeProcess,omitempty"`
+
+	// WakeOnStart starts the server as soon as lazymc starts.
+	// +optional
+	WakeOnStart bool `json:"wakeOnStart,omitempty"`
+
+	// WakeOnCrash restarts the server after it crashed.
+	// +optional
+	WakeOnCrash bool `json:"wakeOnCrash,omitempty"`
+
+	// WakeWhitelist only wakes the server for players on the whitelist.
+	// lazymc enables it by default.
+	// +optional
+	WakeWhitelist *bool `json:"wakeWhitelist,omitempty"`
+
+	// StartTimeoutSeconds is the time in seconds lazymc waits for the server to start.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	StartTimeoutSeconds int32 `json:"startTimeoutSeconds,omitempty"`
+
+	// StopTimeoutSeconds is the time in seconds lazymc waits for the server to stop before killing it.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	StopTimeoutSeconds int32 `json:"stopTimeoutSeconds,omitempty"`
+
+	// MinimumOnlineTimeSeconds is the minimum time in seconds the server stays up after it started.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MinimumOnlineTimeSeconds int32 `json:"minimumOnlineTimeSeconds,omitempty"`
+
+	// MOTD is the message of the day shown while the server is not running.
+	// +optional
+	MOTD LazymcMOTD `json:"motd,omitempty"`
+
+	// Join defines how players joining a sleeping server are handled.
+	// +optional
+	Join LazymcJoin `json:"join,omitempty"`
+
+	// Lockout rejects every player with a message, e.g. during maintenance.
+	// +optional
+	Lockout LazymcLockout `json:"lockout,omitempty"`
+}
+
+// LazymcMOTD defines the messages of the day shown by lazymc.
+type LazymcMOTD struct {
+	// Sleeping is shown while the server is sleeping.
+	// +optional
+	Sleeping string `json:"sleeping,omitempty"`
+
+	// Starting is shown while the server is starting.
+	// +optional
+	Starting string `json:"starting,omitempty"`
+
+	// Stopping is shown while the server is stopping.
+	// +optional
+	Stopping string `json:"stopping,omitempty"`
+
+	// FromServer shows the MOTD of the server itself once it has been seen.
+	// +optional
+	FromServer bool `json:"fromServer,omitempty"`
+}
+
+// LazymcJoinMethod is a way lazymc handles a player joining a sleeping server.
+// +kubebuilder:validation:Enum=kick;hold;forward;lobby
+type LazymcJoinMethod string
+
+const (
+	// LazymcJoinMethodKick kicks the player with a message.
+	LazymcJoinMethodKick LazymcJoinMethod = "kick"
+	// LazymcJoinMethodHold holds the player until the server is ready.
+	LazymcJoinMethodHold LazymcJoinMethod = "hold"
+	// LazymcJoinMethodForward forwards the player to another server.
+	LazymcJoinMethodForward LazymcJoinMethod = "forward"
+	// LazymcJoinMethodLobby keeps the player in an emulated lobby until the server is ready.
+	LazymcJoinMethodLobby LazymcJoinMethod = "lobby"
+)
+
+// LazymcJoin defines how lazymc handles players joining a sleeping server.
+type LazymcJoin struct {
+	// Methods are tried in order. Default is [hold, kick].
+	// kick, forward and lobby never fall through, so they must be the last method.
+	// +optional
+	Methods []LazymcJoinMethod `json:"methods,omitempty"`
+
+	// Kick defines the messages for the kick method.
+	// +optional
+	Kick LazymcJoinKick `json:"kick,omitempty"`
+
+	// Hold defines the hold method.
+	// +optional
+	Hold LazymcJoinHold `json:"hold,omitempty"`
+
+	// Forward defines the forward method.
+	// +optional
+	Forward LazymcJoinForward `json:"forward,omitempty"`
+
+	// Lobby defines the lobby method.
+	// +optional
+	Lobby LazymcJoinLobby `json:"lobby,omitempty"`
+}
+
+// LazymcJoinKick defines the messages for the kick join method.
+type LazymcJoinKick struct {
+	// Starting is the kick message while the server is starting.
+	// +optional
+	Starting string `json:"starting,omitempty"`
+
+	// Stopping is the kick message while the server is stopping.
+	// +optional
+	Stopping string `json:"stopping,omitempty"`
+}
+
+// LazymcJoinHold defines the hold join method.
+type LazymcJoinHold struct {
+	// TimeoutSeconds is the time in seconds a player is held before the next method is tried.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// LazymcJoinForward defines the forward join method.
+type LazymcJoinForward struct {
+	// Address is the host:port of the server players are forwarded to.
+	// +optional
+	Address string `json:"address,omitempty"`
+
+	// SendProxyV2 sends a PROXY protocol v2 header to the forwarded server.
+	// +optional
+	SendProxyV2 bool `json:"sendProxyV2,omitempty"`
+}
+
+// LazymcJoinLobby defines the lobby join method.
+type LazymcJoinLobby struct {
+	// TimeoutSeconds is the time in seconds a player stays in the lobby before being kicked.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+
+	// Message is shown to players in the lobby.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// LazymcLockout defines the lockout of lazymc.
+type LazymcLockout struct {
+	// Enabled rejects every player.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Message is the kick message for rejected players.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// AutoPauseMode is the backend that pauses the server.
+// +kubebuilder:validation:Enum=lazymc;agent
+type AutoPauseMode string
+
+const (
+	// AutoPauseModeLazymc pauses the server with lazymc.
+	AutoPauseModeLazymc AutoPauseMode = "lazymc"
+	// AutoPauseModeAgent pauses the server with the idle-proxy in mcing-agent.
+	AutoPauseModeAgent AutoPauseMode = "agent"
+)
+
+// PropertiesRestartPolicy decides what happens when a change of `server.properties` requires a restart.
+// +kubebuilder:validation:Enum=Manual;Rollout
+type PropertiesRestartPolicy string
+
+const (
+	// PropertiesRestartPolicyManual leaves the restart to the user.
+	PropertiesRestartPolicyManual PropertiesRestartPolicy = "Manual"
+	// PropertiesRestartPolicyRollout restarts the server pod.
+	PropertiesRestartPolicyRollout PropertiesRestartPolicy = "Rollout"
+)
+
+// Hibernation defines the scale-to-zero configuration for the Minecraft server.
+type Hibernation struct {
+	// Enabled scales the StatefulSet to zero after the server has been idle for IdleSeconds.
+	// The mc-router gateway scales it back to one when a player connects.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// IdleSeconds is the time in seconds without players before the StatefulSet is scaled to zero.
+	// Default is 3600 seconds.
+	// +optional
+	// +kubebuilder:default=3600
+	// +kubebuilder:validation:Minimum=60
+	IdleSeconds int `json:"idleSeconds,omitempty"`
 }
 
 // Ops represents the ops.json file.
@@ -213,6 +957,59 @@ func (s *MinecraftSpec) validateCreate() field.ErrorList {
 		)
 	}
 
+	if s.Hibernation.Enabled && s.PersistentVolumeClaimRetentionPolicy != nil &&
+		s.PersistentVolumeClaimRetentionPolicy.WhenScaled == appsv1.DeletePersistentVolumeClaimRetentionPolicyType {
+		allErrs = append(allErrs, field.Forbidden(p.Child("persistentVolumeClaimRetentionPolicy", "whenScaled"),
+			"must not be Delete with spec.hibernation, which scales the server to zero"))
+	}
+	// The StatefulSet owns the PVCs with whenDeleted=Delete, so they would be garbage collected
+	// even though releaseClaims keeps them for the Retain and Snapshot deletion policies.
+	if s.PersistentVolumeClaimRetentionPolicy != nil &&
+		s.PersistentVolumeClaimRetentionPolicy.WhenDeleted == appsv1.DeletePersistentVolumeClaimRetentionPolicyType &&
+		s.DeletionPolicy != DeletionPolicyDelete {
+		allErrs = append(allErrs, field.Forbidden(p.Child("persistentVolumeClaimRetentionPolicy", "whenDeleted"),
+			"must not be Delete unless spec.deletionPolicy is Delete"))
+	}
+	if s.DataSource != nil {
+		allErrs = append(allErrs, s.validateDataSource(p.Child("dataSource"))...)
+	}
+	allErrs = append(allErrs, s.validateLazymc(p.Child("autoPause", "lazymc"))...)
+	if s.Server != nil {
+		allErrs = append(allErrs, s.Server.validate(p.Child("server"))...)
+	}
+	if s.ServerProperties != nil {
+		allErrs = append(allErrs, s.ServerProperties.validate(p.Child("serverProperties"))...)
+	}
+	if s.JVM != nil {
+		allErrs = append(allErrs, s.JVM.validate(p.Child("jvm"), s.minecraftContainer())...)
+	}
+	if s.Bedrock != nil {
+		allErrs = append(allErrs, s.Bedrock.validate(p.Child("bedrock"), s.Server, s.Plugins)...)
+	}
+	allErrs = append(allErrs, validateArtifacts(p.Child("mods"), s.Mods)...)
+	allErrs = append(allErrs, validateArtifacts(p.Child("plugins"), s.Plugins)...)
+	allErrs = append(allErrs, validateArtifacts(p.Child("datapacks"), s.Datapacks)...)
+	for i, d := range s.Datapacks {
+		pp := p.Child("datapacks").Index(i)
+		if !strings.HasSuffix(d.Name, ".zip") {
+			allErrs = append(allErrs, field.Invalid(pp.Child("name"), d.Name, "must be a .zip file"))
+		}
+		// The agent installs datapacks while the server is running, so they cannot be projected into the pod.
+		if d.ConfigMap != nil {
+			allErrs = append(allErrs, field.Forbidden(pp.Child("configMap"), "not supported for datapacks"))
+		}
+	}
+	allErrs = append(allErrs, validateConfigFiles(p.Child("configFiles"), s.ConfigFiles)...)
+	allErrs = append(allErrs, s.validateHostnames(p)...)
+	if s.ResourcePack != nil {
+		if !isHTTPURL(s.ResourcePack.URL) {
+			allErrs = append(allErrs, field.Invalid(p.Child("resourcePack", "url"), s.ResourcePack.URL,
+				"must be an http or https URL"))
+		}
+	}
+	allErrs = append(allErrs, s.validateContainerOverride(p.Child("agent"), &s.Agent)...)
+	allErrs = append(allErrs, s.validateContainerOverride(p.Child("init"), &s.Init)...)
+
 	p = p.Child("podTemplate", "spec")
 
 	pp = p.Child("containers")
@@ -236,9 +1033,13 @@ func (s *MinecraftSpec) validateCreate() field.ErrorList {
 			switch port.ContainerPort {
 			case constants.ServerPort, constants.RconPort:
 				allErrs = append(allErrs, field.Invalid(pp.Index(i), port.ContainerPort, "reserved port"))
+			case constants.BedrockPort:
+				if s.Bedrock != nil && s.Bedrock.Enabled {
+					allErrs = append(allErrs, field.Invalid(pp.Index(i), port.ContainerPort, "reserved port for spec.bedrock"))
+				}
 			}
 			switch port.Name {
-			case constants.ServerPortName, constants.RconPortName:
+			case constants.ServerPortName, constants.RconPortName, constants.BedrockPortName:
 				allErrs = append(allErrs, field.Invalid(pp.Index(i), port.Name, "reserved port name"))
 			}
 		}
@@ -251,6 +1052,25 @@ func (s *MinecraftSpec) validateCreate() field.ErrorList {
 				hasEula = true
 			}
 		}
+		if s.Server != nil {
+			for i := range s.PodTemplate.Spec.Containers[minecraftIndex].Env {
+				env := &s.PodTemplate.Spec.Containers[minecraftIndex].Env[i]
+				switch env.Name {
+				case constants.ServerTypeEnvName, constants.ServerVersionEnvName:
+					allErrs = append(allErrs, field.Invalid(pp.Index(i).Child("name"), env.Name,
+						"must not be set together with spec.server"))
+				}
+			}
+		}
+		if s.JVM != nil {
+			for i := range s.PodTemplate.Spec.Containers[minecraftIndex].Env {
+				env := &s.PodTemplate.Spec.Containers[minecraftIndex].Env[i]
+				if slices.Contains(jvmEnvNames, env.Name) {
+					allErrs = append(allErrs, field.Invalid(pp.Index(i).Child("name"), env.Name,
+						"must not be set together with spec.jvm"))
+				}
+			}
+		}
 		if !hasEula {
 			allErrs = append(
 				allErrs,
@@ -261,18 +1081,342 @@ func (s *MinecraftSpec) validateCreate() field.ErrorList {
 	return allErrs
 }
 
-func (s *MinecraftSpec) validateUpdate(_ MinecraftSpec) field.ErrorList {
+// validateDataSource validates spec.dataSource. The data source of the data volume is set by
+// spec.dataSource or by the claim template, but not both.
+func (s *MinecraftSpec) validateDataSource(p *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	ds := s.DataSource
+	sources := 0
+	if ds.Minecraft != "" {
+		sources++
+	}
+	if ds.VolumeSnapshot != "" {
+		sources++
+	}
+	if ds.Backup != nil {
+		sources++
+		if !isHTTPURL(ds.Backup.URL) {
+			allErrs = append(allErrs, field.Invalid(p.Child("backup", "url"), ds.Backup.URL,
+				"must be an http or https URL"))
+		}
+	}
+	if sources != 1 {
+		allErrs = append(allErrs, field.Invalid(p, "",
+			"exactly one of minecraft, volumeSnapshot and backup must be set"))
+	}
+	for i := range s.VolumeClaimTemplates {
+		vc := &s.VolumeClaimTemplates[i]
+		if vc.Name == constants.DataVolumeName && (vc.Spec.DataSource != nil || vc.Spec.DataSourceRef != nil) {
+			allErrs = append(allErrs, field.Forbidden(p,
+				"must not be set together with the data source of the "+constants.DataVolumeName+" claim template"))
+		}
+	}
+	return allErrs
+}
+
+// RestoreSnapshotName returns the name of the VolumeSnapshot that the data volume is restored from,
+// or "" if it is not restored from a snapshot.
+func (s *MinecraftSpec) RestoreSnapshotName() string {
+	if s.DataSource != nil && s.DataSource.VolumeSnapshot != "" {
+		return s.DataSource.VolumeSnapshot
+	}
+	for i := range s.VolumeClaimTemplates {
+		vc := &s.VolumeClaimTemplates[i]
+		if vc.Name != constants.DataVolumeName {
+			continue
+		}
+		ds := vc.Spec.DataSource
+		if ds == nil || ds.Kind != constants.VolumeSnapshotKind {
+			continue
+		}
+		if ptr.Deref(ds.APIGroup, "") == constants.VolumeSnapshotGroup {
+			return ds.Name
+		}
+	}
+	return ""
+}
+
+// minecraftContainer returns the minecraft container in the pod template, or nil if it is missing.
+func (s *MinecraftSpec) minecraftContainer() *corev1.Container {
+	for i := range s.PodTemplate.Spec.Containers {
+		if s.PodTemplate.Spec.Containers[i].Name == constants.MinecraftContainerName {
+			return &s.PodTemplate.Spec.Containers[i]
+		}
+	}
+	return nil
+}
+
+func (s *MinecraftSpec) validateLazymc(p *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	opts := s.AutoPause.Lazymc
+	if opts == nil {
+		return allErrs
+	}
+	if s.AutoPause.Mode != "" && s.AutoPause.Mode != AutoPauseModeLazymc {
+		return append(allErrs, field.Forbidden(p, "lazymc is only used when mode is lazymc"))
+	}
+
+	pp := p.Child("join", "methods")
+	seen := map[LazymcJoinMethod]bool{}
+	for i, m := range opts.Join.Methods {
+		switch m {
+		case LazymcJoinMethodKick, LazymcJoinMethodHold, LazymcJoinMethodForward, LazymcJoinMethodLobby:
+		default:
+			allErrs = append(allErrs, field.NotSupported(pp.Index(i), m, []LazymcJoinMethod{
+				LazymcJoinMethodKick, LazymcJoinMethodHold, LazymcJoinMethodForward, LazymcJoinMethodLobby,
+			}))
+			continue
+		}
+		if seen[m] {
+			allErrs = append(allErrs, field.Duplicate(pp.Index(i), m))
+		}
+		seen[m] = true
+		if m != LazymcJoinMethodHold && i != len(opts.Join.Methods)-1 {
+			allErrs = append(allErrs, field.Invalid(pp.Index(i), m, "must be the last method"))
+		}
+	}
+
+	if seen[LazymcJoinMethodForward] {
+		pp := p.Child("join", "forward", "address")
+		if opts.Join.Forward.Address == "" {
+			allErrs = append(allErrs, field.Required(pp, "required by the forward join method"))
+		} else if _, _, err := net.SplitHostPort(opts.Join.Forward.Address); err != nil {
+			allErrs = append(allErrs, field.Invalid(pp, opts.Join.Forward.Address, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+func validateArtifacts(p *field.Path, artifacts []Artifact) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := map[string]bool{}
+	for i := range artifacts {
+		a := &artifacts[i]
+		pp := p.Index(i)
+		if names[a.Name] {
+			allErrs = append(allErrs, field.Duplicate(pp.Child("name"), a.Name))
+		}
+		names[a.Name] = true
+
+		sources := 0
+		if a.URL != "" {
+			sources++
+			if !isHTTPURL(a.URL) {
+				allErrs = append(allErrs, field.Invalid(pp.Child("url"), a.URL, "must be an http or https URL"))
+			}
+			if a.SHA256 == "" {
+				allErrs = append(allErrs, field.Required(pp.Child("sha256"), "required with url"))
+			}
+		}
+		if a.ConfigMap != nil {
+			sources++
+		}
+		if a.OCI != "" {
+			sources++
+		}
+		if sources != 1 {
+			allErrs = append(allErrs, field.Invalid(pp, a.Name, "exactly one of url, configMap and oci must be set"))
+		}
+	}
+	return allErrs
+}
+
+// reservedConfigFiles are the files in the data directory managed by the other fields.
+var reservedConfigFiles = map[string]bool{
+	constants.ServerPropsName: true,
+	constants.BanIPName:       true,
+	constants.BanPlayerName:   true,
+	constants.OpsName:         true,
+	constants.WhiteListName:   true,
+}
+
+func validateConfigFiles(p *field.Path, files []ConfigFile) field.ErrorList {
+	var allErrs field.ErrorList
+
+	paths := map[string]bool{}
+	for i := range files {
+		f := &files[i]
+		pp := p.Index(i)
+		clean := path.Clean(f.Path)
+		switch {
+		case !filepath.IsLocal(f.Path) || strings.Contains(f.Path, "\\"):
+			allErrs = append(allErrs, field.Invalid(pp.Child("path"), f.Path,
+				"must be a relative path inside the data directory"))
+		case reservedConfigFiles[clean]:
+			allErrs = append(allErrs, field.Invalid(pp.Child("path"), f.Path, "managed by mcing"))
+		case paths[clean]:
+			allErrs = append(allErrs, field.Duplicate(pp.Child("path"), f.Path))
+		}
+		paths[clean] = true
+
+		sources := 0
+		if f.ConfigMapKeyRef != nil {
+			sources++
+		}
+		if f.SecretKeyRef != nil {
+			sources++
+		}
+		if f.Inline != "" {
+			sources++
+		}
+		if sources != 1 {
+			allErrs = append(allErrs, field.Invalid(pp, f.Path,
+				"exactly one of configMapKeyRef, secretKeyRef and inline must be set"))
+		}
+		ext := strings.ToLower(filepath.Ext(f.Path))
+		if f.MergeStrategy == MergeStrategyMerge && !slices.Contains(constants.MergeableConfigExtensions, ext) {
+			allErrs = append(allErrs, field.Invalid(pp.Child("mergeStrategy"), f.MergeStrategy,
+				"only .json, .yaml and .yml files can be merged"))
+		}
+	}
+	return allErrs
+}
+
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func (s *MinecraftSpec) validateContainerOverride(p *field.Path, o *ContainerOverride) field.ErrorList {
+	var allErrs field.ErrorList
+
+	pp := p.Child("env")
+	for i := range o.Env {
+		if o.Env[i].Name == constants.RconPasswordEnvName {
+			allErrs = append(allErrs, field.Invalid(pp.Index(i).Child("name"), o.Env[i].Name, "reserved environment variable"))
+		}
+	}
+
+	volumes := map[string]bool{}
+	for i := range s.PodTemplate.Spec.Volumes {
+		volumes[s.PodTemplate.Spec.Volumes[i].Name] = true
+	}
+	for i := range s.VolumeClaimTemplates {
+		volumes[s.VolumeClaimTemplates[i].Name] = true
+	}
+
+	pp = p.Child("volumeMounts")
+	for i := range o.VolumeMounts {
+		vm := &o.VolumeMounts[i]
+		if !volumes[vm.Name] {
+			allErrs = append(allErrs, field.NotFound(pp.Index(i).Child("name"), vm.Name))
+		}
+		switch vm.MountPath {
+		case constants.DataPath, constants.ConfigPath, constants.LazymcPath, constants.AutoPausePath,
+			constants.ArtifactsPath, constants.ConfigFilesPath, constants.SecretsPath:
+			allErrs = append(allErrs, field.Invalid(pp.Index(i).Child("mountPath"), vm.MountPath, "reserved mount path"))
+		}
+	}
+	return allErrs
+}
+
+func (s *MinecraftSpec) validateUpdate(old MinecraftSpec) field.ErrorList {
 	var allErrs field.ErrorList
 
+	allErrs = append(allErrs, s.validateVolumeClaimTemplatesUpdate(old)...)
+	// The data volume is seeded only once, so a new source would not be applied.
+	if !equality.Semantic.DeepEqual(s.DataSource, old.DataSource) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "dataSource"),
+			"cannot be changed after creation"))
+	}
 	return append(allErrs, s.validateCreate()...)
 }
 
+// validateVolumeClaimTemplatesUpdate allows only increasing the storage request of the volume claim templates,
+// because the controller expands the existing PVCs but cannot change the templates of the StatefulSet.
+func (s *MinecraftSpec) validateVolumeClaimTemplatesUpdate(old MinecraftSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	p := field.NewPath("spec", "volumeClaimTemplates")
+	for i := range s.VolumeClaimTemplates {
+		vc := &s.VolumeClaimTemplates[i]
+		idx := slices.IndexFunc(old.VolumeClaimTemplates, func(o PersistentVolumeClaim) bool { return o.Name == vc.Name })
+		if idx == -1 {
+			continue
+		}
+		oldVC := &old.VolumeClaimTemplates[idx]
+		pp := p.Index(i).Child("spec")
+
+		storage := vc.Spec.Resources.Requests[corev1.ResourceStorage]
+		if storage.Cmp(oldVC.Spec.Resources.Requests[corev1.ResourceStorage]) < 0 {
+			allErrs = append(allErrs, field.Forbidden(pp.Child("resources", "requests", "storage"),
+				"the storage request must not be decreased"))
+		}
+
+		spec := vc.Spec.DeepCopy()
+		oldSpec := oldVC.Spec.DeepCopy()
+		spec.Resources.Requests = nil
+		oldSpec.Resources.Requests = nil
+		if !equality.Semantic.DeepEqual(spec, oldSpec) {
+			allErrs = append(allErrs, field.Forbidden(pp, "only the storage request can be changed"))
+		}
+	}
+	return allErrs
+}
+
 // MinecraftStatus defines the observed state of Minecraft.
 type MinecraftStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
+
+	// Mods are the mods installed by mcing-init when the server pod started.
+	// +optional
+	Mods []InstalledArtifact `json:"mods,omitempty"`
+
+	// Plugins are the plugins installed by mcing-init when the server pod started.
+	// +optional
+	Plugins []InstalledArtifact `json:"plugins,omitempty"`
+
+	// ResourcePack is the resource pack whose SHA-1 digest was computed by mcing-init.
+	// +optional
+	ResourcePack *InstalledResourcePack `json:"resourcePack,omitempty"`
+
+	// Storage is the capacity and usage of the data volume.
+	// +optional
+	Storage *StorageStatus `json:"storage,omitempty"`
+
+	// Conditions are the latest observations of the Minecraft state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// StorageStatus is the capacity and usage of the data volume.
+type StorageStatus struct {
+	// Requested is the storage requested by the PVC.
+	// +optional
+	Requested *resource.Quantity `json:"requested,omitempty"`
+
+	// Capacity is the actual capacity of the volume bound to the PVC.
+	// +optional
+	Capacity *resource.Quantity `json:"capacity,omitempty"`
+
+	// Used is the size of the space used in the volume, reported by mcing-agent.
+	// +optional
+	Used *resource.Quantity `json:"used,omitempty"`
 }
 
+const (
+	// ConditionReferencesResolved is true when all ConfigMaps and Secrets referenced by the spec exist.
+	ConditionReferencesResolved = "ReferencesResolved"
+	// ConditionRestartRequired is true when changed properties of `server.properties` wait for a restart.
+	ConditionRestartRequired = "RestartRequired"
+	// ConditionConfigSynced is true when mcing-agent has applied every config file to the running server.
+	ConditionConfigSynced = "ConfigSynced"
+	// ConditionRouted is true when every mc-router pod of the gateway has registered the hostname of the server.
+	ConditionRouted = "Routed"
+	// ConditionStorageResized is true when the data PVC has the storage requested in spec.volumeClaimTemplates.
+	ConditionStorageResized = "StorageResized"
+	// ConditionRestoreSnapshotReady is true when the VolumeSnapshot that the data volume is restored from is ready to use.
+	ConditionRestoreSnapshotReady = "RestoreSnapshotReady"
+	// ConditionCloneSourceReady is true when the data volume of the Minecraft in spec.dataSource exists to be cloned.
+	ConditionCloneSourceReady = "CloneSourceReady"
+)
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 
@@ -295,6 +1439,15 @@ func (m *Minecraft) PodName() string {
 	return m.PrefixedName() + "-0"
 }
 
+// ClaimNames returns the names of the PVCs created from spec.volumeClaimTemplates.
+func (m *Minecraft) ClaimNames() []string {
+	names := make([]string, 0, len(m.Spec.VolumeClaimTemplates))
+	for _, t := range m.Spec.VolumeClaimTemplates {
+		names = append(names, t.Name+"-"+m.PodName())
+	}
+	return names
+}
+
 // HeadlessServiceName returns the headless service name.
 func (m *Minecraft) HeadlessServiceName() string {
 	return m.PrefixedName() + "-headless"
@@ -308,6 +1461,23 @@ func (m *Minecraft) RconSecretName() string {
 	return m.PrefixedName() + "-rcon-password"
 }
 
+// AutoPauseEnabled returns whether the server process is managed by an auto-pause backend.
+// An unset value is treated as enabled, matching the API default.
+func (m *Minecraft) AutoPauseEnabled() bool {
+	return m.Spec.AutoPause.Enabled == nil || *m.Spec.AutoPause.Enabled
+}
+
+// AutoPauseMode returns the auto-pause backend, or an empty string when auto-pause is disabled.
+func (m *Minecraft) AutoPauseMode() AutoPauseMode {
+	if !m.AutoPauseEnabled() {
+		return ""
+	}
+	if m.Spec.AutoPause.Mode == "" {
+		return AutoPauseModeLazymc
+	}
+	return m.Spec.AutoPause.Mode
+}
+
 // GetExternalServerName returns the external server name for mc-router annotation.
 // If ExternalHostname is set, it returns that value.
 // Otherwise, it generates FQDN as <name>.<namespace>.<defaultDomain>.
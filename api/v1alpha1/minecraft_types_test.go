@@ -1,11 +1,14 @@
 package v1alpha1
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 func TestMinecraft_PrefixedName(t *testing.T) {
@@ -165,6 +168,176 @@ func TestPersistentVolumeClaim_ToCoreV1(t *testing.T) {
 	}
 }
 
+func TestMinecraftSpec_validateVolumeClaimTemplatesUpdate(t *testing.T) {
+	template := func(storage string, class *string) MinecraftSpec {
+		return MinecraftSpec{
+			VolumeClaimTemplates: []PersistentVolumeClaim{{
+				ObjectMeta: ObjectMeta{Name: "minecraft-data"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					StorageClassName: class,
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(storage)},
+					},
+				},
+			}},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		old     MinecraftSpec
+		new     MinecraftSpec
+		wantErr string
+	}{
+		{
+			name: "unchanged",
+			old:  template("1Gi", nil),
+			new:  template("1Gi", nil),
+		},
+		{
+			name: "increase",
+			old:  template("1Gi", nil),
+			new:  template("2Gi", nil),
+		},
+		{
+			name:    "decrease",
+			old:     template("2Gi", nil),
+			new:     template("1Gi", nil),
+			wantErr: "spec.volumeClaimTemplates[0].spec.resources.requests.storage: Forbidden",
+		},
+		{
+			name:    "storage class",
+			old:     template("1Gi", nil),
+			new:     template("1Gi", stringPtr("fast")),
+			wantErr: "spec.volumeClaimTemplates[0].spec: Forbidden: only the storage request can be changed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.new.validateVolumeClaimTemplatesUpdate(tt.old)
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("validateVolumeClaimTemplatesUpdate() = %v, want no error", errs)
+				}
+				return
+			}
+			if !strings.Contains(errs.ToAggregate().Error(), tt.wantErr) {
+				t.Errorf("validateVolumeClaimTemplatesUpdate() = %v, want %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMinecraftSpec_RestoreSnapshotName(t *testing.T) {
+	tests := []struct {
+		name           string
+		dataSource     *corev1.TypedLocalObjectReference
+		specDataSource *DataSource
+		want           string
+	}{
+		{
+			name: "no data source",
+		},
+		{
+			name: "VolumeSnapshot",
+			dataSource: &corev1.TypedLocalObjectReference{
+				APIGroup: stringPtr("snapshot.storage.k8s.io"),
+				Kind:     "VolumeSnapshot",
+				Name:     "world",
+			},
+			want: "world",
+		},
+		{
+			name:       "PVC",
+			dataSource: &corev1.TypedLocalObjectReference{Kind: "PersistentVolumeClaim", Name: "world"},
+		},
+		{
+			name:           "spec.dataSource",
+			specDataSource: &DataSource{VolumeSnapshot: "world"},
+			want:           "world",
+		},
+		{
+			name:           "clone",
+			specDataSource: &DataSource{Minecraft: "production"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &MinecraftSpec{
+				VolumeClaimTemplates: []PersistentVolumeClaim{{
+					ObjectMeta: ObjectMeta{Name: "minecraft-data"},
+					Spec:       corev1.PersistentVolumeClaimSpec{DataSource: tt.dataSource},
+				}},
+				DataSource: tt.specDataSource,
+			}
+			if got := s.RestoreSnapshotName(); got != tt.want {
+				t.Errorf("RestoreSnapshotName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMinecraftSpec_validateDataSource(t *testing.T) {
+	digest := strings.Repeat("0", 64)
+	tests := []struct {
+		name         string
+		dataSource   *DataSource
+		templateFrom *corev1.TypedLocalObjectReference
+		wantErr      string
+	}{
+		{
+			name:       "minecraft",
+			dataSource: &DataSource{Minecraft: "production", StripOps: true, StripWhitelist: true},
+		},
+		{
+			name:       "backup",
+			dataSource: &DataSource{Backup: &BackupArchive{URL: "https://example.com/world.tar.gz", SHA256: digest}},
+		},
+		{
+			name:       "no source",
+			dataSource: &DataSource{StripOps: true},
+			wantErr:    "exactly one of minecraft, volumeSnapshot and backup must be set",
+		},
+		{
+			name:       "multiple sources",
+			dataSource: &DataSource{Minecraft: "production", VolumeSnapshot: "world"},
+			wantErr:    "exactly one of minecraft, volumeSnapshot and backup must be set",
+		},
+		{
+			name:       "invalid url",
+			dataSource: &DataSource{Backup: &BackupArchive{URL: "s3://bucket/world.tar.gz", SHA256: digest}},
+			wantErr:    "spec.dataSource.backup.url: Invalid value",
+		},
+		{
+			name:         "with the data source of the template",
+			dataSource:   &DataSource{Minecraft: "production"},
+			templateFrom: &corev1.TypedLocalObjectReference{Kind: "PersistentVolumeClaim", Name: "world"},
+			wantErr:      "spec.dataSource: Forbidden",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &MinecraftSpec{
+				VolumeClaimTemplates: []PersistentVolumeClaim{{
+					ObjectMeta: ObjectMeta{Name: "minecraft-data"},
+					Spec:       corev1.PersistentVolumeClaimSpec{DataSource: tt.templateFrom},
+				}},
+				DataSource: tt.dataSource,
+			}
+			errs := s.validateDataSource(field.NewPath("spec", "dataSource"))
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("validateDataSource() = %v, want no error", errs)
+				}
+				return
+			}
+			if !strings.Contains(errs.ToAggregate().Error(), tt.wantErr) {
+				t.Errorf("validateDataSource() = %v, want %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
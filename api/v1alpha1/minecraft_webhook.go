@@ -25,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
@@ -35,11 +36,16 @@ func minecraftLog() logr.Logger {
 }
 
 // SetupWebhookWithManager will setup the manager to manage the webhooks.
+// It also registers HostnameIndexKey, which the validator uses to find the servers that claim a hostname.
 func (r *Minecraft) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &Minecraft{}, HostnameIndexKey, IndexHostnames)
+	if err != nil {
+		return err
+	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		WithDefaulter(&Minecraft{}).
-		WithValidator(&Minecraft{}).
+		WithValidator(&minecraftValidator{reader: mgr.GetClient()}).
 		Complete()
 }
 
@@ -109,6 +115,53 @@ func (r *Minecraft) ValidateUpdate(
 	return nil, nil
 }
 
+// minecraftValidator validates a Minecraft against the other Minecrafts in the cluster
+// in addition to the validation of the Minecraft itself.
+type minecraftValidator struct {
+	reader client.Reader
+}
+
+var _ admission.CustomValidator = &minecraftValidator{}
+
+func (v *minecraftValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	warnings, err := (&Minecraft{}).ValidateCreate(ctx, obj)
+	if err != nil {
+		return warnings, err
+	}
+	return warnings, v.validateHostnames(ctx, obj)
+}
+
+func (v *minecraftValidator) ValidateUpdate(
+	ctx context.Context,
+	oldObj runtime.Object,
+	newObj runtime.Object,
+) (admission.Warnings, error) {
+	warnings, err := (&Minecraft{}).ValidateUpdate(ctx, oldObj, newObj)
+	if err != nil {
+		return warnings, err
+	}
+	return warnings, v.validateHostnames(ctx, newObj)
+}
+
+func (v *minecraftValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return (&Minecraft{}).ValidateDelete(ctx, obj)
+}
+
+func (v *minecraftValidator) validateHostnames(ctx context.Context, obj runtime.Object) error {
+	m, ok := obj.(*Minecraft)
+	if !ok {
+		return fmt.Errorf("expected *Minecraft object but got %T", obj)
+	}
+	errs, err := validateHostnamesUnique(ctx, v.reader, m)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if len(errs) != 0 {
+		return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "Minecraft"}, m.Name, errs)
+	}
+	return nil
+}
+
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
 func (r *Minecraft) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
 	m, ok := obj.(*Minecraft)
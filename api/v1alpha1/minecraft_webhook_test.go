@@ -1,11 +1,15 @@
 package v1alpha1
 
 import (
+	"strings"
+
 	. "github.com/onsi/ginkgo/v2" //nolint:revive // dot imports for tests
 	. "github.com/onsi/gomega"    //nolint:revive // dot imports for tests
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	"github.com/kmdkuk/mcing/pkg/constants"
 )
@@ -105,6 +109,589 @@ var _ = Describe("Minecraft Webhook", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("reserved port"))
 		})
+
+		It("should validate lazymc options", func() {
+			minecraft.Spec.AutoPause.Lazymc = &LazymcOptions{
+				FreezeProcess: true,
+				MOTD:          LazymcMOTD{Sleeping: "Zzz"},
+				Join: LazymcJoin{
+					Methods: []LazymcJoinMethod{LazymcJoinMethodHold, LazymcJoinMethodForward},
+					Forward: LazymcJoinForward{Address: "lobby.example.com:25565"},
+				},
+			}
+			warnings, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should fail if lazymc options are set with the agent mode", func() {
+			minecraft.Spec.AutoPause.Mode = AutoPauseModeAgent
+			minecraft.Spec.AutoPause.Lazymc = &LazymcOptions{FreezeProcess: true}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("lazymc is only used when mode is lazymc"))
+		})
+
+		It("should fail if a join method follows a terminal method", func() {
+			minecraft.Spec.AutoPause.Lazymc = &LazymcOptions{
+				Join: LazymcJoin{
+					Methods: []LazymcJoinMethod{LazymcJoinMethodKick, LazymcJoinMethodHold},
+				},
+			}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("must be the last method"))
+		})
+
+		It("should fail if a join method is duplicated", func() {
+			minecraft.Spec.AutoPause.Lazymc = &LazymcOptions{
+				Join: LazymcJoin{
+					Methods: []LazymcJoinMethod{LazymcJoinMethodHold, LazymcJoinMethodHold},
+				},
+			}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Duplicate value"))
+		})
+
+		It("should fail if the forward join method has no valid address", func() {
+			minecraft.Spec.AutoPause.Lazymc = &LazymcOptions{
+				Join: LazymcJoin{
+					Methods: []LazymcJoinMethod{LazymcJoinMethodForward},
+				},
+			}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("required by the forward join method"))
+
+			minecraft.Spec.AutoPause.Lazymc.Join.Forward.Address = "lobby.example.com"
+			_, err = minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("missing port in address"))
+		})
+	})
+
+	Context("Server", func() {
+		It("should validate supported server software", func() {
+			for _, server := range []Server{
+				{},
+				{Type: ServerTypePaper, Version: "1.21.1"},
+				{Type: ServerTypeFabric, Version: ServerVersionSnapshot},
+				{Type: ServerTypeForge, Version: "1.12.2", JavaVersion: 8},
+				{Type: ServerTypeNeoForge, Version: "1.21.1", JavaVersion: 21},
+			} {
+				minecraft.Spec.Server = &server
+				warnings, err := minecraft.ValidateCreate(ctx, minecraft)
+				Expect(err).NotTo(HaveOccurred(), "server: %+v", server)
+				Expect(warnings).To(BeEmpty())
+			}
+		})
+
+		It("should fail if the version is malformed", func() {
+			minecraft.Spec.Server = &Server{Version: "latest-ish"}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("must be a release version"))
+		})
+
+		It("should fail if the type does not support the version", func() {
+			minecraft.Spec.Server = &Server{Type: ServerTypeNeoForge, Version: "1.19.2"}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("NeoForge supports 1.20.1 or later"))
+
+			minecraft.Spec.Server = &Server{Type: ServerTypePaper, Version: ServerVersionSnapshot}
+			_, err = minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Paper does not support snapshots"))
+		})
+
+		It("should fail if the Java version does not match the version", func() {
+			minecraft.Spec.Server = &Server{Version: "1.21.1", JavaVersion: 17}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Minecraft 1.21.1 requires Java 21 or later"))
+
+			minecraft.Spec.Server = &Server{Type: ServerTypeForge, Version: "1.16.5", JavaVersion: 17}
+			_, err = minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Forge before 1.17 requires Java 8"))
+		})
+
+		It("should fail if TYPE is also set in the pod template", func() {
+			minecraft.Spec.Server = &Server{Type: ServerTypePaper}
+			minecraft.Spec.PodTemplate.Spec.Containers[0].Env = append(
+				minecraft.Spec.PodTemplate.Spec.Containers[0].Env,
+				corev1.EnvVar{Name: constants.ServerTypeEnvName, Value: "FABRIC"},
+			)
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("must not be set together with spec.server"))
+		})
+	})
+
+	Context("JVM", func() {
+		setMemoryLimit := func(limit string) {
+			minecraft.Spec.PodTemplate.Spec.Containers[0].Resources.Limits = corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse(limit),
+			}
+		}
+
+		It("should validate the JVM settings", func() {
+			setMemoryLimit("4Gi")
+			minecraft.Spec.JVM = &JVM{
+				HeapPercentage: 75,
+				GCPreset:       GCPresetAikar,
+				ExtraArgs:      []string{"-XX:+UseStringDeduplication"},
+			}
+			warnings, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should fail if the heap percentage is set without the memory limit", func() {
+			minecraft.Spec.JVM = &JVM{HeapPercentage: 75}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.jvm.heapPercentage: Required value"))
+		})
+
+		It("should fail if the heap does not fit in the memory limit", func() {
+			setMemoryLimit("512Mi")
+			minecraft.Spec.JVM = &JVM{HeapPercentage: 50}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the heap of 256Mi is smaller than 512Mi"))
+
+			setMemoryLimit("1Gi")
+			minecraft.Spec.JVM = &JVM{HeapPercentage: 90}
+			_, err = minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("leaves less than 256Mi outside the heap"))
+		})
+
+		It("should fail if the extra arguments are malformed or set the heap size", func() {
+			setMemoryLimit("4Gi")
+			minecraft.Spec.JVM = &JVM{
+				HeapPercentage: 75,
+				ExtraArgs:      []string{"-XX:+UseZGC -XX:+ZGenerational", "-Xmx2G"},
+			}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.jvm.extraArgs[0]: Invalid value"))
+			Expect(err.Error()).To(ContainSubstring("spec.jvm.extraArgs[1]: Invalid value"))
+		})
+
+		It("should fail if the JVM environment variables are also set in the pod template", func() {
+			minecraft.Spec.JVM = &JVM{GCPreset: GCPresetDefault}
+			minecraft.Spec.PodTemplate.Spec.Containers[0].Env = append(
+				minecraft.Spec.PodTemplate.Spec.Containers[0].Env,
+				corev1.EnvVar{Name: constants.MemoryEnvName, Value: "2G"},
+			)
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("must not be set together with spec.jvm"))
+		})
+	})
+
+	Context("ServerProperties", func() {
+		It("should validate typed server properties", func() {
+			minecraft.Spec.ServerProperties = &ServerProperties{
+				Difficulty:   DifficultyHard,
+				Gamemode:     GamemodeAdventure,
+				ViewDistance: ptr.To[int32](16),
+			}
+			warnings, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should fail if a value is out of range", func() {
+			minecraft.Spec.ServerProperties = &ServerProperties{
+				Difficulty:   "nightmare",
+				ViewDistance: ptr.To[int32](64),
+			}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.serverProperties.difficulty: Unsupported value"))
+			Expect(err.Error()).To(ContainSubstring("spec.serverProperties.viewDistance: Invalid value"))
+		})
+	})
+
+	Context("ContainerOverride", func() {
+		It("should validate agent and init overrides", func() {
+			minecraft.Spec.PodTemplate.Spec.Volumes = []corev1.Volume{{Name: "extra"}}
+			minecraft.Spec.Agent = ContainerOverride{
+				Image:        "example.com/mcing-agent:dev",
+				Env:          []corev1.EnvVar{{Name: "FOO", Value: "bar"}},
+				VolumeMounts: []corev1.VolumeMount{{Name: "extra", MountPath: "/extra"}},
+			}
+			minecraft.Spec.Init = ContainerOverride{
+				VolumeMounts: []corev1.VolumeMount{{Name: constants.DataVolumeName, MountPath: "/backup"}},
+			}
+			warnings, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should fail if the RCON password variable is overridden", func() {
+			minecraft.Spec.Agent.Env = []corev1.EnvVar{{Name: constants.RconPasswordEnvName, Value: "x"}}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("reserved environment variable"))
+		})
+
+		It("should fail if a volume mount refers to an unknown volume", func() {
+			minecraft.Spec.Init.VolumeMounts = []corev1.VolumeMount{{Name: "unknown", MountPath: "/extra"}}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.init.volumeMounts[0].name: Not found"))
+		})
+
+		It("should fail if a volume mount uses a reserved path", func() {
+			minecraft.Spec.Agent.VolumeMounts = []corev1.VolumeMount{
+				{Name: constants.DataVolumeName, MountPath: constants.ConfigPath},
+			}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("reserved mount path"))
+		})
+	})
+
+	Context("Artifacts", func() {
+		digest := strings.Repeat("0", 64)
+
+		It("should validate mods and plugins", func() {
+			minecraft.Spec.Mods = []Artifact{
+				{Name: "a.jar", URL: "https://example.com/a.jar", SHA256: digest},
+				{Name: "b.jar", OCI: "ghcr.io/example/mods:1.0.0"},
+			}
+			minecraft.Spec.Plugins = []Artifact{
+				{
+					Name: "c.jar",
+					ConfigMap: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "plugins"},
+						Key:                  "c.jar",
+					},
+				},
+			}
+			warnings, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should fail if a URL has no sha256", func() {
+			minecraft.Spec.Mods = []Artifact{{Name: "a.jar", URL: "https://example.com/a.jar"}}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.mods[0].sha256: Required value"))
+		})
+
+		It("should fail if a URL is not HTTP", func() {
+			minecraft.Spec.Plugins = []Artifact{{Name: "a.jar", URL: "file:///a.jar", SHA256: digest}}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("must be an http or https URL"))
+		})
+
+		It("should fail unless exactly one source is set", func() {
+			minecraft.Spec.Mods = []Artifact{
+				{Name: "a.jar"},
+				{Name: "b.jar", URL: "https://example.com/b.jar", SHA256: digest, OCI: "ghcr.io/example/mods:1.0.0"},
+			}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.mods[0]: Invalid value"))
+			Expect(err.Error()).To(ContainSubstring("spec.mods[1]: Invalid value"))
+		})
+
+		It("should fail if a name is duplicated", func() {
+			minecraft.Spec.Mods = []Artifact{
+				{Name: "a.jar", OCI: "ghcr.io/example/mods:1.0.0"},
+				{Name: "a.jar", OCI: "ghcr.io/example/mods:2.0.0"},
+			}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.mods[1].name: Duplicate value"))
+		})
+
+		It("should validate datapacks and the resource pack", func() {
+			minecraft.Spec.Datapacks = []Artifact{{Name: "pack.zip", URL: "https://example.com/pack.zip", SHA256: digest}}
+			minecraft.Spec.ResourcePack = &ResourcePack{URL: "https://example.com/resources.zip", Required: true}
+			warnings, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should fail if a datapack is not a zip file or comes from a ConfigMap", func() {
+			minecraft.Spec.Datapacks = []Artifact{
+				{Name: "pack", URL: "https://example.com/pack.zip", SHA256: digest},
+				{
+					Name: "other.zip",
+					ConfigMap: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "datapacks"},
+						Key:                  "other.zip",
+					},
+				},
+			}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("must be a .zip file"))
+			Expect(err.Error()).To(ContainSubstring("spec.datapacks[1].configMap: Forbidden"))
+		})
+
+		It("should fail if the resource pack URL is not HTTP", func() {
+			minecraft.Spec.ResourcePack = &ResourcePack{URL: "resources.zip"}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.resourcePack.url: Invalid value"))
+		})
+	})
+
+	Context("ConfigFiles", func() {
+		It("should validate config files", func() {
+			minecraft.Spec.ConfigFiles = []ConfigFile{
+				{Path: "config/paper-global.yml", Inline: "proxies: {}\n", MergeStrategy: MergeStrategyMerge},
+				{
+					Path: "plugins/LuckPerms/config.yml",
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "luckperms"},
+						Key:                  "config.yml",
+					},
+				},
+				{
+					Path: "config/mod.toml",
+					ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "mods"},
+						Key:                  "mod.toml",
+					},
+				},
+			}
+			warnings, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should fail if a path is outside the data directory", func() {
+			minecraft.Spec.ConfigFiles = []ConfigFile{
+				{Path: "../etc/passwd", Inline: "x"},
+				{Path: "/etc/passwd", Inline: "x"},
+			}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.configFiles[0].path: Invalid value"))
+			Expect(err.Error()).To(ContainSubstring("spec.configFiles[1].path: Invalid value"))
+		})
+
+		It("should fail if a path is managed by mcing or duplicated", func() {
+			minecraft.Spec.ConfigFiles = []ConfigFile{
+				{Path: "ops.json", Inline: "[]"},
+				{Path: "bukkit.yml", Inline: "x"},
+				{Path: "./bukkit.yml", Inline: "y"},
+			}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.configFiles[0].path: Invalid value"))
+			Expect(err.Error()).To(ContainSubstring("spec.configFiles[2].path: Duplicate value"))
+		})
+
+		It("should fail unless exactly one source is set", func() {
+			minecraft.Spec.ConfigFiles = []ConfigFile{
+				{Path: "bukkit.yml"},
+				{
+					Path:   "spigot.yml",
+					Inline: "x",
+					ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "spigot"},
+						Key:                  "spigot.yml",
+					},
+				},
+			}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.configFiles[0]: Invalid value"))
+			Expect(err.Error()).To(ContainSubstring("spec.configFiles[1]: Invalid value"))
+		})
+
+		It("should fail if a file that cannot be merged has the Merge strategy", func() {
+			minecraft.Spec.ConfigFiles = []ConfigFile{
+				{Path: "config/mod.toml", Inline: "a = 1", MergeStrategy: MergeStrategyMerge},
+			}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.configFiles[0].mergeStrategy: Invalid value"))
+		})
+	})
+
+	Context("Bedrock", func() {
+		It("should validate Geyser and Floodgate for Paper", func() {
+			minecraft.Spec.Server = &Server{Type: ServerTypePaper}
+			minecraft.Spec.Bedrock = &Bedrock{Enabled: true, Floodgate: true}
+			warnings, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should validate Geyser installed by the user", func() {
+			minecraft.Spec.Server = &Server{Type: ServerTypeFabric}
+			minecraft.Spec.Bedrock = &Bedrock{Enabled: true, InstallPlugins: ptr.To(false)}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail if the plugins are installed for a server without plugins", func() {
+			minecraft.Spec.Server = &Server{Type: ServerTypeFabric}
+			minecraft.Spec.Bedrock = &Bedrock{Enabled: true}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.bedrock.installPlugins: Invalid value"))
+		})
+
+		It("should fail if Floodgate is enabled without the plugins", func() {
+			minecraft.Spec.Bedrock = &Bedrock{Enabled: true, InstallPlugins: ptr.To(false), Floodgate: true}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.bedrock.floodgate: Invalid value"))
+		})
+
+		It("should fail if the builds are pinned for plugins that are not installed", func() {
+			pinned := &PluginDownload{URL: "https://example.com/plugin.jar", SHA256: strings.Repeat("a", 64)}
+			minecraft.Spec.Bedrock = &Bedrock{
+				Enabled:           true,
+				InstallPlugins:    ptr.To(false),
+				GeyserDownload:    pinned,
+				FloodgateDownload: pinned,
+			}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.bedrock.geyserDownload: Forbidden: requires installPlugins"))
+			Expect(err.Error()).To(ContainSubstring("spec.bedrock.floodgateDownload: Forbidden: requires floodgate"))
+		})
+
+		It("should fail if a plugin has the name of Geyser", func() {
+			minecraft.Spec.Server = &Server{Type: ServerTypePaper}
+			minecraft.Spec.Bedrock = &Bedrock{Enabled: true}
+			minecraft.Spec.Plugins = []Artifact{
+				{Name: constants.GeyserPluginName, URL: "https://example.com/Geyser-Spigot.jar", SHA256: strings.Repeat("a", 64)},
+			}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the plugin is installed by spec.bedrock"))
+		})
+
+		It("should fail if reserved port 19132 is used", func() {
+			minecraft.Spec.Bedrock = &Bedrock{Enabled: true, InstallPlugins: ptr.To(false)}
+			minecraft.Spec.PodTemplate.Spec.Containers[0].Ports = []corev1.ContainerPort{
+				{
+					ContainerPort: constants.BedrockPort,
+					Protocol:      corev1.ProtocolUDP,
+				},
+			}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("reserved port for spec.bedrock"))
+		})
+
+		It("should allow port 19132 without Bedrock", func() {
+			minecraft.Spec.PodTemplate.Spec.Containers[0].Ports = []corev1.ContainerPort{
+				{
+					ContainerPort: constants.BedrockPort,
+					Protocol:      corev1.ProtocolUDP,
+				},
+			}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("Hostnames", func() {
+		It("should fail if an alias is duplicated", func() {
+			minecraft.Spec.ExternalHostname = ptr.To("survival.example.com")
+			minecraft.Spec.HostnameAliases = []string{"smp.example.com", "survival.example.com"}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.hostnameAliases[1]: Duplicate value"))
+		})
+
+		It("should reject a hostname used by a Minecraft in another namespace", func() {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "hostname-test"}}
+			Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+
+			minecraft.Name = "hostname-owner"
+			minecraft.Spec.ExternalHostname = ptr.To("owner.example.com")
+			minecraft.Spec.HostnameAliases = []string{"shared.example.com"}
+			Expect(k8sClient.Create(ctx, minecraft)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, minecraft)).To(Succeed())
+			})
+
+			other := minecraft.DeepCopy()
+			other.ObjectMeta = metav1.ObjectMeta{Name: "hostname-other", Namespace: ns.Name}
+			other.Spec.ExternalHostname = ptr.To("other.example.com")
+			other.Spec.HostnameAliases = []string{"shared.example.com"}
+			// The webhook finds the owner from the cache of the manager, which may not have it yet.
+			Eventually(func(g Gomega) {
+				err := k8sClient.Create(ctx, other.DeepCopy())
+				if err == nil {
+					_ = k8sClient.Delete(ctx, other)
+				}
+				g.Expect(err).To(MatchError(ContainSubstring("the hostname is used by default/hostname-owner")))
+			}).Should(Succeed())
+
+			other.Spec.HostnameAliases = []string{"other-alias.example.com"}
+			Expect(k8sClient.Create(ctx, other)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, other)).To(Succeed())
+		})
+	})
+
+	Context("PersistentVolumeClaimRetentionPolicy", func() {
+		It("should fail if PVCs are deleted when a hibernating server is scaled down", func() {
+			minecraft.Spec.Hibernation.Enabled = true
+			minecraft.Spec.PersistentVolumeClaimRetentionPolicy = &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+				WhenScaled: appsv1.DeletePersistentVolumeClaimRetentionPolicyType,
+			}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.persistentVolumeClaimRetentionPolicy.whenScaled: Forbidden"))
+		})
+
+		It("should allow deleting PVCs with the StatefulSet", func() {
+			minecraft.Spec.Hibernation.Enabled = true
+			minecraft.Spec.DeletionPolicy = DeletionPolicyDelete
+			minecraft.Spec.PersistentVolumeClaimRetentionPolicy = &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+				WhenDeleted: appsv1.DeletePersistentVolumeClaimRetentionPolicyType,
+			}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail if PVCs are deleted with the StatefulSet but retained by the deletion policy", func() {
+			for _, policy := range []DeletionPolicy{DeletionPolicyRetain, DeletionPolicySnapshot} {
+				minecraft.Spec.DeletionPolicy = policy
+				minecraft.Spec.PersistentVolumeClaimRetentionPolicy = &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+					WhenDeleted: appsv1.DeletePersistentVolumeClaimRetentionPolicyType,
+				}
+				_, err := minecraft.ValidateCreate(ctx, minecraft)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(
+					"spec.persistentVolumeClaimRetentionPolicy.whenDeleted: Forbidden"))
+			}
+		})
+	})
+
+	Context("DataSource", func() {
+		It("should validate cloning another Minecraft", func() {
+			minecraft.Spec.DataSource = &DataSource{Minecraft: "production", StripOps: true}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail unless exactly one source is set", func() {
+			minecraft.Spec.DataSource = &DataSource{Minecraft: "production", VolumeSnapshot: "world"}
+			_, err := minecraft.ValidateCreate(ctx, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("exactly one of minecraft, volumeSnapshot and backup must be set"))
+		})
 	})
 
 	Context("ValidateUpdate", func() {
@@ -121,6 +708,28 @@ var _ = Describe("Minecraft Webhook", func() {
 			Expect(warnings).To(BeEmpty())
 		})
 
+		It("should allow increasing the storage request", func() {
+			minecraft.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests = corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse("10Gi"),
+			}
+			_, err := minecraft.ValidateUpdate(ctx, oldMinecraft, minecraft)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail if the volume claim template is changed other than the storage request", func() {
+			minecraft.Spec.VolumeClaimTemplates[0].Spec.StorageClassName = ptr.To("fast")
+			_, err := minecraft.ValidateUpdate(ctx, oldMinecraft, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only the storage request can be changed"))
+		})
+
+		It("should fail if the data source is changed", func() {
+			minecraft.Spec.DataSource = &DataSource{Minecraft: "production"}
+			_, err := minecraft.ValidateUpdate(ctx, oldMinecraft, minecraft)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.dataSource: Forbidden: cannot be changed after creation"))
+		})
+
 		It("should fail if update creates an invalid state (missing EULA)", func() {
 			minecraft.Spec.PodTemplate.Spec.Containers[0].Env = []corev1.EnvVar{}
 			_, err := minecraft.ValidateUpdate(ctx, oldMinecraft, minecraft)
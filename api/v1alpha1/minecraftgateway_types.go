@@ -0,0 +1,224 @@
+package v1alpha1
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/kmdkuk/mcing/pkg/constants"
+)
+
+// managedGatewayArgs are the mc-router flags managed by the controller.
+// --in-kube-cluster is left unset because mc-router would route every server in the cluster.
+var managedGatewayArgs = []string{"--in-kube-cluster", "--api-binding", "--port", "--metrics-backend"}
+
+// MinecraftGatewaySpec defines the desired state of MinecraftGateway.
+type MinecraftGatewaySpec struct {
+	// Default makes the gateway route the Minecraft servers that do not set `gatewayName`.
+	// If more than one gateway is the default, the one with the smallest name is used.
+	// +optional
+	Default bool `json:"default,omitempty"`
+
+	// Namespace is the namespace where mc-router is deployed. It cannot be changed.
+	// +kubebuilder:default=mcing-gateway
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// DefaultDomain is the domain of the generated hostnames, `<name>.<namespace>.<defaultDomain>`.
+	// +kubebuilder:default=minecraft.local
+	// +optional
+	DefaultDomain string `json:"defaultDomain,omitempty"`
+
+	// Image is the mc-router container image.
+	// +kubebuilder:default="itzg/mc-router:latest"
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Replicas is the number of mc-router pods.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Resources are the compute resources of the mc-router container.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Service configures the Service that exposes mc-router to the players.
+	// +optional
+	Service GatewayService `json:"service,omitempty"`
+
+	// ExtraArgs are additional arguments of mc-router such as "--connection-rate-limit=10".
+	// The flags set by the controller cannot be overridden.
+	// +optional
+	ExtraArgs []string `json:"extraArgs,omitempty"`
+
+	// NodeSelector selects the nodes that run mc-router.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations are the tolerations of the mc-router pods.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// DNS publishes the hostnames of the backends with external-dns.
+	// No record is published if it is not set.
+	// +optional
+	DNS *GatewayDNS `json:"dns,omitempty"`
+}
+
+// GatewayService defines the Service of a gateway.
+type GatewayService struct {
+	// Type is the type of the Service.
+	// +kubebuilder:validation:Enum=LoadBalancer;NodePort;ClusterIP
+	// +kubebuilder:default=LoadBalancer
+	// +optional
+	Type corev1.ServiceType `json:"type,omitempty"`
+
+	// Annotations are added to the Service, e.g. to configure the load balancer.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// GatewayDNSMode is how the DNS records of a gateway are published.
+// +kubebuilder:validation:Enum=Annotation;DNSEndpoint
+type GatewayDNSMode string
+
+const (
+	// GatewayDNSAnnotation annotates the Service of mc-router with the hostnames for the service source of external-dns.
+	GatewayDNSAnnotation GatewayDNSMode = "Annotation"
+	// GatewayDNSEndpoint creates a DNSEndpoint for the crd source of external-dns.
+	// The records point to the external addresses of the gateway.
+	GatewayDNSEndpoint GatewayDNSMode = "DNSEndpoint"
+)
+
+// GatewayDNS configures the DNS records of the hostnames routed by a gateway.
+type GatewayDNS struct {
+	// Mode is how the records are published.
+	// +kubebuilder:default=Annotation
+	// +optional
+	Mode GatewayDNSMode `json:"mode,omitempty"`
+
+	// TTL is the TTL of the records in seconds. The default TTL of the DNS provider is used if it is not set.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TTL *int64 `json:"ttl,omitempty"`
+}
+
+func (s *MinecraftGatewaySpec) validateCreate() field.ErrorList {
+	var allErrs field.ErrorList
+	p := field.NewPath("spec")
+
+	if s.Namespace != "" {
+		for _, msg := range validation.IsDNS1123Label(s.Namespace) {
+			allErrs = append(allErrs, field.Invalid(p.Child("namespace"), s.Namespace, msg))
+		}
+	}
+	if s.DefaultDomain != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(s.DefaultDomain) {
+			allErrs = append(allErrs, field.Invalid(p.Child("defaultDomain"), s.DefaultDomain, msg))
+		}
+	}
+
+	pp := p.Child("extraArgs")
+	for i, arg := range s.ExtraArgs {
+		name, _, _ := strings.Cut(arg, "=")
+		for _, managed := range managedGatewayArgs {
+			// mc-router accepts flags with one or two dashes.
+			if name == managed || name == managed[1:] {
+				allErrs = append(allErrs, field.Invalid(pp.Index(i), arg, "the flag is managed by the controller"))
+			}
+		}
+	}
+	return allErrs
+}
+
+func (s *MinecraftGatewaySpec) validateUpdate(old MinecraftGatewaySpec) field.ErrorList {
+	allErrs := s.validateCreate()
+	if s.Namespace != old.Namespace {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "namespace"), "the namespace cannot be changed"))
+	}
+	return allErrs
+}
+
+// MinecraftGatewayStatus defines the observed state of MinecraftGateway.
+type MinecraftGatewayStatus struct {
+	// ExternalAddresses are the addresses of the load balancer and the external IPs of the Service.
+	// +optional
+	ExternalAddresses []string `json:"externalAddresses,omitempty"`
+
+	// NodePort is the node port of the Service when its type is NodePort.
+	// +optional
+	NodePort int32 `json:"nodePort,omitempty"`
+
+	// ReadyReplicas is the number of ready mc-router pods.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// Backends are the Minecraft servers routed by the gateway.
+	// +optional
+	Backends []GatewayBackend `json:"backends,omitempty"`
+}
+
+// GatewayBackend is a Minecraft server routed by a gateway.
+type GatewayBackend struct {
+	// Namespace is the namespace of the Minecraft.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the Minecraft.
+	Name string `json:"name"`
+
+	// Hostname is the hostname that players connect to.
+	Hostname string `json:"hostname"`
+
+	// Aliases are the other hostnames of the Minecraft.
+	// +optional
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// Hostnames returns the hostname and the aliases of the backend.
+func (b *GatewayBackend) Hostnames() []string {
+	return append([]string{b.Hostname}, b.Aliases...)
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster,shortName=mcgw
+//+kubebuilder:printcolumn:name="DEFAULT",type="boolean",JSONPath=".spec.default"
+//+kubebuilder:printcolumn:name="DOMAIN",type="string",JSONPath=".spec.defaultDomain"
+//+kubebuilder:printcolumn:name="ADDRESS",type="string",JSONPath=".status.externalAddresses[0]"
+//+kubebuilder:printcolumn:name="READY",type="integer",JSONPath=".status.readyReplicas"
+//+kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// MinecraftGateway is the Schema for the minecraftgateways API.
+// It deploys mc-router, which routes players to Minecraft servers by the hostname they connect to.
+type MinecraftGateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MinecraftGatewaySpec   `json:"spec,omitempty"`
+	Status MinecraftGatewayStatus `json:"status,omitempty"`
+}
+
+// PrefixedName returns the name of the resources of the gateway.
+func (g *MinecraftGateway) PrefixedName() string {
+	return constants.MCRouterAppName + "-" + g.Name
+}
+
+//+kubebuilder:object:root=true
+
+// MinecraftGatewayList contains a list of MinecraftGateway.
+type MinecraftGatewayList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []MinecraftGateway `json:"items"`
+}
+
+//nolint:gochecknoinits // required by kubebuilder
+func init() {
+	SchemeBuilder.Register(&MinecraftGateway{}, &MinecraftGatewayList{})
+}
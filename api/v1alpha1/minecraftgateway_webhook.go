@@ -0,0 +1,81 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func minecraftGatewayLog() logr.Logger {
+	return logf.Log.WithName("minecraftgateway-resource")
+}
+
+// SetupWebhookWithManager will setup the manager to manage the webhooks.
+func (r *MinecraftGateway) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(&MinecraftGateway{}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-mcing-kmdkuk-com-v1alpha1-minecraftgateway,mutating=false,failurePolicy=fail,sideEffects=None,groups=mcing.kmdkuk.com,resources=minecraftgateways,verbs=create;update,versions=v1alpha1,name=vminecraftgateway.kb.io,admissionReviewVersions=v1
+
+var _ admission.CustomValidator = &MinecraftGateway{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (r *MinecraftGateway) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	g, ok := obj.(*MinecraftGateway)
+	if !ok {
+		return admission.Warnings{}, fmt.Errorf("expected *MinecraftGateway object but got %T", obj)
+	}
+	minecraftGatewayLog().Info("validate create", "name", g.Name)
+
+	errs := g.Spec.validateCreate()
+	if len(errs) != 0 {
+		return admission.Warnings{}, apierrors.NewInvalid(
+			schema.GroupKind{Group: GroupVersion.Group, Kind: "MinecraftGateway"},
+			g.Name,
+			errs,
+		)
+	}
+	return nil, nil
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (r *MinecraftGateway) ValidateUpdate(
+	_ context.Context,
+	oldObj runtime.Object,
+	newObj runtime.Object,
+) (admission.Warnings, error) {
+	oldG, ok := oldObj.(*MinecraftGateway)
+	if !ok {
+		return admission.Warnings{}, fmt.Errorf("expected *MinecraftGateway object but got %T", oldObj)
+	}
+	newG, ok := newObj.(*MinecraftGateway)
+	if !ok {
+		return admission.Warnings{}, fmt.Errorf("expected *MinecraftGateway object but got %T", newObj)
+	}
+	minecraftGatewayLog().Info("validate update", "name", newG.Name)
+
+	errs := newG.Spec.validateUpdate(oldG.Spec)
+	if len(errs) != 0 {
+		return admission.Warnings{}, apierrors.NewInvalid(
+			schema.GroupKind{Group: GroupVersion.Group, Kind: "MinecraftGateway"},
+			newG.Name,
+			errs,
+		)
+	}
+	return nil, nil
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (r *MinecraftGateway) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
@@ -0,0 +1,73 @@
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // dot imports for tests
+	. "github.com/onsi/gomega"    //nolint:revive // dot imports for tests
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("MinecraftGateway Webhook", func() {
+	var gateway *MinecraftGateway
+
+	BeforeEach(func() {
+		gateway = &MinecraftGateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-gateway",
+			},
+			Spec: MinecraftGatewaySpec{
+				Default:       true,
+				Namespace:     "mcing-gateway",
+				DefaultDomain: "minecraft.local",
+			},
+		}
+	})
+
+	Context("ValidateCreate", func() {
+		It("should validate a valid MinecraftGateway resource", func() {
+			gateway.Spec.ExtraArgs = []string{"--connection-rate-limit=10"}
+			warnings, err := gateway.ValidateCreate(ctx, gateway)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should fail if the namespace is invalid", func() {
+			gateway.Spec.Namespace = "Invalid_Namespace"
+			_, err := gateway.ValidateCreate(ctx, gateway)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.namespace"))
+		})
+
+		It("should fail if the default domain is invalid", func() {
+			gateway.Spec.DefaultDomain = "-minecraft.local"
+			_, err := gateway.ValidateCreate(ctx, gateway)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.defaultDomain"))
+		})
+
+		It("should fail if extraArgs override the flags set by the controller", func() {
+			for _, arg := range []string{"--in-kube-cluster", "-api-binding=:9090", "--port=25566", "--metrics-backend=discard"} {
+				gateway.Spec.ExtraArgs = []string{arg}
+				_, err := gateway.ValidateCreate(ctx, gateway)
+				Expect(err).To(HaveOccurred(), arg)
+				Expect(err.Error()).To(ContainSubstring("the flag is managed by the controller"))
+			}
+		})
+	})
+
+	Context("ValidateUpdate", func() {
+		It("should allow changing the default domain", func() {
+			newGateway := gateway.DeepCopy()
+			newGateway.Spec.DefaultDomain = "mc.example.com"
+			_, err := newGateway.ValidateUpdate(ctx, gateway, newGateway)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail if the namespace is changed", func() {
+			newGateway := gateway.DeepCopy()
+			newGateway.Spec.Namespace = "other-gateway"
+			_, err := newGateway.ValidateUpdate(ctx, gateway, newGateway)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the namespace cannot be changed"))
+		})
+	})
+})
@@ -0,0 +1,142 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// MinecraftNetworkSpec defines the desired state of MinecraftNetwork.
+type MinecraftNetworkSpec struct {
+	// Selector selects the Minecraft servers in the same namespace that join the network.
+	// A server selected by more than one network joins the one with the smallest name.
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// Try is the list of server names that players join first, in order.
+	// If not set, the servers are tried in the order of their names.
+	// +optional
+	Try []string `json:"try,omitempty"`
+
+	// Image is the container image of the proxy. It must be compatible with itzg/mc-proxy.
+	// +kubebuilder:default="itzg/mc-proxy:latest"
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Version is the Velocity version such as "3.4.0-SNAPSHOT". If not set, the latest version is used.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Replicas is the number of proxy pods.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Resources are the compute resources of the proxy container.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// ServiceTemplate is a `Service` template of the proxy.
+	// +optional
+	ServiceTemplate *ServiceTemplate `json:"serviceTemplate,omitempty"`
+
+	// MOTD is the message of the day of the proxy in the MiniMessage format.
+	// +optional
+	MOTD string `json:"motd,omitempty"`
+
+	// MaxPlayers is the maximum number of players shown in the server list.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxPlayers *int32 `json:"maxPlayers,omitempty"`
+
+	// OnlineMode makes the proxy authenticate players with Mojang.
+	// +kubebuilder:default=true
+	// +optional
+	OnlineMode *bool `json:"onlineMode,omitempty"`
+}
+
+func (s *MinecraftNetworkSpec) validate() field.ErrorList {
+	var allErrs field.ErrorList
+	p := field.NewPath("spec")
+
+	if _, err := metav1.LabelSelectorAsSelector(&s.Selector); err != nil {
+		allErrs = append(allErrs, field.Invalid(p.Child("selector"), s.Selector, err.Error()))
+	}
+
+	pp := p.Child("try")
+	seen := map[string]bool{}
+	for i, name := range s.Try {
+		for _, msg := range validation.IsDNS1123Subdomain(name) {
+			allErrs = append(allErrs, field.Invalid(pp.Index(i), name, msg))
+		}
+		if seen[name] {
+			allErrs = append(allErrs, field.Duplicate(pp.Index(i), name))
+		}
+		seen[name] = true
+	}
+	return allErrs
+}
+
+// MinecraftNetworkStatus defines the observed state of MinecraftNetwork.
+type MinecraftNetworkStatus struct {
+	// Backends are the Minecraft servers registered in the proxy.
+	// +optional
+	Backends []NetworkBackend `json:"backends,omitempty"`
+
+	// ReadyReplicas is the number of ready proxy pods.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+}
+
+// NetworkBackend is a Minecraft server registered in a proxy.
+type NetworkBackend struct {
+	// Name is the name of the Minecraft, which is also the server name in the proxy.
+	Name string `json:"name"`
+
+	// Address is the address that the proxy connects to.
+	Address string `json:"address"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=mcnet
+//+kubebuilder:printcolumn:name="BACKENDS",type="string",JSONPath=".status.backends[*].name"
+//+kubebuilder:printcolumn:name="READY",type="integer",JSONPath=".status.readyReplicas"
+//+kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// MinecraftNetwork is the Schema for the minecraftnetworks API.
+// It deploys a Velocity proxy in front of the selected Minecraft servers.
+type MinecraftNetwork struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MinecraftNetworkSpec   `json:"spec,omitempty"`
+	Status MinecraftNetworkStatus `json:"status,omitempty"`
+}
+
+// PrefixedName returns the name of the resources of the network.
+func (n *MinecraftNetwork) PrefixedName() string {
+	return "mcing-network-" + n.Name
+}
+
+// ForwardingSecretName returns the name of the Secret that holds the forwarding secret
+// shared by the proxy and the backends.
+func (n *MinecraftNetwork) ForwardingSecretName() string {
+	return n.PrefixedName() + "-forwarding"
+}
+
+//+kubebuilder:object:root=true
+
+// MinecraftNetworkList contains a list of MinecraftNetwork.
+type MinecraftNetworkList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []MinecraftNetwork `json:"items"`
+}
+
+//nolint:gochecknoinits // required by kubebuilder
+func init() {
+	SchemeBuilder.Register(&MinecraftNetwork{}, &MinecraftNetworkList{})
+}
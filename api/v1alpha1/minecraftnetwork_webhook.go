@@ -0,0 +1,77 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func minecraftNetworkLog() logr.Logger {
+	return logf.Log.WithName("minecraftnetwork-resource")
+}
+
+// SetupWebhookWithManager will setup the manager to manage the webhooks.
+func (r *MinecraftNetwork) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(&MinecraftNetwork{}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-mcing-kmdkuk-com-v1alpha1-minecraftnetwork,mutating=false,failurePolicy=fail,sideEffects=None,groups=mcing.kmdkuk.com,resources=minecraftnetworks,verbs=create;update,versions=v1alpha1,name=vminecraftnetwork.kb.io,admissionReviewVersions=v1
+
+var _ admission.CustomValidator = &MinecraftNetwork{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (r *MinecraftNetwork) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	n, ok := obj.(*MinecraftNetwork)
+	if !ok {
+		return admission.Warnings{}, fmt.Errorf("expected *MinecraftNetwork object but got %T", obj)
+	}
+	minecraftNetworkLog().Info("validate create", "name", n.Name)
+
+	errs := n.Spec.validate()
+	if len(errs) != 0 {
+		return admission.Warnings{}, apierrors.NewInvalid(
+			schema.GroupKind{Group: GroupVersion.Group, Kind: "MinecraftNetwork"},
+			n.Name,
+			errs,
+		)
+	}
+	return nil, nil
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (r *MinecraftNetwork) ValidateUpdate(
+	_ context.Context,
+	_ runtime.Object,
+	newObj runtime.Object,
+) (admission.Warnings, error) {
+	n, ok := newObj.(*MinecraftNetwork)
+	if !ok {
+		return admission.Warnings{}, fmt.Errorf("expected *MinecraftNetwork object but got %T", newObj)
+	}
+	minecraftNetworkLog().Info("validate update", "name", n.Name)
+
+	errs := n.Spec.validate()
+	if len(errs) != 0 {
+		return admission.Warnings{}, apierrors.NewInvalid(
+			schema.GroupKind{Group: GroupVersion.Group, Kind: "MinecraftNetwork"},
+			n.Name,
+			errs,
+		)
+	}
+	return nil, nil
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (r *MinecraftNetwork) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
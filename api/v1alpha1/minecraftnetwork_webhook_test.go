@@ -0,0 +1,73 @@
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // dot imports for tests
+	. "github.com/onsi/gomega"    //nolint:revive // dot imports for tests
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("MinecraftNetwork Webhook", func() {
+	var network *MinecraftNetwork
+
+	BeforeEach(func() {
+		network = &MinecraftNetwork{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-network",
+				Namespace: "default",
+			},
+			Spec: MinecraftNetworkSpec{
+				Selector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"network": "test-network"},
+				},
+			},
+		}
+	})
+
+	Context("ValidateCreate", func() {
+		It("should validate a valid MinecraftNetwork resource", func() {
+			network.Spec.Try = []string{"lobby", "survival"}
+			warnings, err := network.ValidateCreate(ctx, network)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should fail if the selector is invalid", func() {
+			network.Spec.Selector.MatchExpressions = []metav1.LabelSelectorRequirement{
+				{Key: "network", Operator: "Equals", Values: []string{"test-network"}},
+			}
+			_, err := network.ValidateCreate(ctx, network)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.selector"))
+		})
+
+		It("should fail if a try entry is not a server name", func() {
+			network.Spec.Try = []string{"Lobby_1"}
+			_, err := network.ValidateCreate(ctx, network)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.try[0]"))
+		})
+
+		It("should fail if a try entry is duplicated", func() {
+			network.Spec.Try = []string{"lobby", "lobby"}
+			_, err := network.ValidateCreate(ctx, network)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.try[1]: Duplicate value"))
+		})
+	})
+
+	Context("ValidateUpdate", func() {
+		It("should allow changing the selector", func() {
+			newNetwork := network.DeepCopy()
+			newNetwork.Spec.Selector.MatchLabels = map[string]string{"network": "other"}
+			_, err := newNetwork.ValidateUpdate(ctx, network, newNetwork)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail if the new try list is invalid", func() {
+			newNetwork := network.DeepCopy()
+			newNetwork.Spec.Try = []string{"lobby", "lobby"}
+			_, err := newNetwork.ValidateUpdate(ctx, network, newNetwork)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
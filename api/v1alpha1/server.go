@@ -0,0 +1,156 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/kmdkuk/mcing/pkg/constants"
+)
+
+const (
+	// ServerVersionLatest is the latest release of Minecraft.
+	ServerVersionLatest = "LATEST"
+	// ServerVersionSnapshot is the latest snapshot of Minecraft.
+	ServerVersionSnapshot = "SNAPSHOT"
+)
+
+var serverVersionPattern = regexp.MustCompile(`^(\d+)\.(\d+)(?:\.(\d+))?$`)
+
+// serverVersion is a parsed release version such as 1.20.5 or 26.1.
+type serverVersion [3]int
+
+func parseServerVersion(v string) (serverVersion, bool) {
+	m := serverVersionPattern.FindStringSubmatch(v)
+	if m == nil {
+		return serverVersion{}, false
+	}
+	var sv serverVersion
+	for i := range sv {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			return serverVersion{}, false
+		}
+		sv[i] = n
+	}
+	return sv, true
+}
+
+func (v serverVersion) atLeast(major, minor, patch int) bool {
+	o := serverVersion{major, minor, patch}
+	for i := range v {
+		if v[i] != o[i] {
+			return v[i] > o[i]
+		}
+	}
+	return true
+}
+
+func (v serverVersion) String() string {
+	if v[2] == 0 {
+		return fmt.Sprintf("%d.%d", v[0], v[1])
+	}
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+}
+
+// minimumJavaVersion returns the oldest Java that runs the given Minecraft release.
+func (v serverVersion) minimumJavaVersion() int32 {
+	switch {
+	case v.atLeast(26, 1, 0):
+		return 25
+	case v.atLeast(1, 20, 5):
+		return 21
+	case v.atLeast(1, 17, 0):
+		return 17
+	default:
+		return 8
+	}
+}
+
+// minimumServerVersions are the oldest Minecraft releases supported by each server type.
+var minimumServerVersions = map[ServerType]serverVersion{
+	ServerTypePaper:    {1, 8, 0},
+	ServerTypeFabric:   {1, 14, 0},
+	ServerTypeNeoForge: {1, 20, 1},
+}
+
+// ServerTypeName returns the server type in the form of the TYPE variable of itzg/minecraft-server.
+func (s *Server) ServerTypeName() string {
+	if s.Type == "" {
+		return strings.ToUpper(string(ServerTypeVanilla))
+	}
+	return strings.ToUpper(string(s.Type))
+}
+
+// ServerVersion returns the Minecraft version, defaulting to LATEST.
+func (s *Server) ServerVersion() string {
+	if s.Version == "" {
+		return ServerVersionLatest
+	}
+	return s.Version
+}
+
+// Image returns the itzg/minecraft-server image for the Java version.
+// Without JavaVersion, the Java version is derived from a release Version,
+// and LATEST or SNAPSHOT uses the latest image.
+func (s *Server) Image() string {
+	java := s.JavaVersion
+	if java == 0 {
+		v, ok := parseServerVersion(s.ServerVersion())
+		if !ok {
+			return constants.ServerImageRepository + ":latest"
+		}
+		java = v.minimumJavaVersion()
+	}
+	return fmt.Sprintf("%s:java%d", constants.ServerImageRepository, java)
+}
+
+func (s *Server) validate(p *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	typ := s.Type
+	if typ == "" {
+		typ = ServerTypeVanilla
+	}
+	version := s.ServerVersion()
+
+	switch version {
+	case ServerVersionLatest:
+		return allErrs
+	case ServerVersionSnapshot:
+		if typ != ServerTypeVanilla && typ != ServerTypeFabric {
+			allErrs = append(allErrs, field.Invalid(p.Child("version"), version,
+				fmt.Sprintf("%s does not support snapshots", typ)))
+		}
+		return allErrs
+	}
+
+	v, ok := parseServerVersion(version)
+	if !ok {
+		return append(allErrs, field.Invalid(p.Child("version"), version,
+			"must be a release version such as 1.21.1, LATEST or SNAPSHOT"))
+	}
+	if minimum, ok := minimumServerVersions[typ]; ok && !v.atLeast(minimum[0], minimum[1], minimum[2]) {
+		allErrs = append(allErrs, field.Invalid(p.Child("version"), version,
+			fmt.Sprintf("%s supports %s or later", typ, minimum)))
+	}
+
+	if s.JavaVersion == 0 {
+		return allErrs
+	}
+	if minimum := v.minimumJavaVersion(); s.JavaVersion < minimum {
+		allErrs = append(allErrs, field.Invalid(p.Child("javaVersion"), s.JavaVersion,
+			fmt.Sprintf("Minecraft %s requires Java %d or later", version, minimum)))
+	}
+	if typ == ServerTypeForge && !v.atLeast(1, 17, 0) && s.JavaVersion != 8 {
+		allErrs = append(allErrs, field.Invalid(p.Child("javaVersion"), s.JavaVersion,
+			"Forge before 1.17 requires Java 8"))
+	}
+	return allErrs
+}
@@ -0,0 +1,106 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	minViewDistance = 3
+	maxViewDistance = 32
+)
+
+var (
+	difficulties = []Difficulty{DifficultyPeaceful, DifficultyEasy, DifficultyNormal, DifficultyHard}
+	gamemodes    = []Gamemode{GamemodeSurvival, GamemodeCreative, GamemodeAdventure, GamemodeSpectator}
+)
+
+// Properties returns the set fields as server.properties keys and values.
+func (p *ServerProperties) Properties() map[string]string {
+	props := map[string]string{}
+	setString := func(key, v string) {
+		if v != "" {
+			props[key] = v
+		}
+	}
+	setBool := func(key string, v *bool) {
+		if v != nil {
+			props[key] = strconv.FormatBool(*v)
+		}
+	}
+	setInt := func(key string, v *int32) {
+		if v != nil {
+			props[key] = strconv.Itoa(int(*v))
+		}
+	}
+
+	setString("difficulty", string(p.Difficulty))
+	setString("gamemode", string(p.Gamemode))
+	setBool("force-gamemode", p.ForceGamemode)
+	setBool("hardcore", p.Hardcore)
+	setInt("max-players", p.MaxPlayers)
+	setString("motd", p.MOTD)
+	setInt("view-distance", p.ViewDistance)
+	setInt("simulation-distance", p.SimulationDistance)
+	setBool("pvp", p.PVP)
+	setBool("online-mode", p.OnlineMode)
+	setBool("allow-flight", p.AllowFlight)
+	setBool("allow-nether", p.AllowNether)
+	setBool("enable-command-block", p.EnableCommandBlock)
+	setInt("spawn-protection", p.SpawnProtection)
+	setInt("player-idle-timeout", p.PlayerIdleTimeout)
+	setString("level-name", p.LevelName)
+	setString("level-seed", p.LevelSeed)
+	return props
+}
+
+func (p *ServerProperties) validate(path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if p.Difficulty != "" && !slices.Contains(difficulties, p.Difficulty) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("difficulty"), p.Difficulty, difficulties))
+	}
+	if p.Gamemode != "" && !slices.Contains(gamemodes, p.Gamemode) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("gamemode"), p.Gamemode, gamemodes))
+	}
+	if p.MaxPlayers != nil && *p.MaxPlayers < 1 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxPlayers"), *p.MaxPlayers, "must be at least 1"))
+	}
+	for _, f := range []struct {
+		name     string
+		v        *int32
+		min, max int32
+	}{
+		{"viewDistance", p.ViewDistance, minViewDistance, maxViewDistance},
+		{"simulationDistance", p.SimulationDistance, minViewDistance, maxViewDistance},
+		{"spawnProtection", p.SpawnProtection, 0, math.MaxInt32},
+		{"playerIdleTimeout", p.PlayerIdleTimeout, 0, math.MaxInt32},
+	} {
+		if f.v != nil && (*f.v < f.min || *f.v > f.max) {
+			allErrs = append(allErrs, field.Invalid(path.Child(f.name), *f.v,
+				fmt.Sprintf("must be between %d and %d", f.min, f.max)))
+		}
+	}
+	for _, f := range []struct {
+		name string
+		v    string
+	}{
+		{"motd", p.MOTD},
+		{"levelSeed", p.LevelSeed},
+	} {
+		// A line break would end the property in server.properties.
+		if strings.ContainsAny(f.v, "\r\n") {
+			allErrs = append(allErrs, field.Invalid(path.Child(f.name), f.v, "must not contain line breaks"))
+		}
+	}
+	if p.LevelName != "" && (strings.Contains(p.LevelName, "/") || strings.HasPrefix(p.LevelName, ".")) {
+		allErrs = append(allErrs, field.Invalid(path.Child("levelName"), p.LevelName,
+			"must be a directory name in the data volume"))
+	}
+	return allErrs
+}
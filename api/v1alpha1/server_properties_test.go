@@ -0,0 +1,119 @@
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+)
+
+func TestServerProperties_Properties(t *testing.T) {
+	tests := []struct {
+		name  string
+		props ServerProperties
+		want  map[string]string
+	}{
+		{
+			name:  "empty",
+			props: ServerProperties{},
+			want:  map[string]string{},
+		},
+		{
+			name: "set fields",
+			props: ServerProperties{
+				Difficulty:   DifficultyHard,
+				Gamemode:     GamemodeCreative,
+				MaxPlayers:   ptr.To[int32](50),
+				MOTD:         "Welcome",
+				ViewDistance: ptr.To[int32](12),
+				PVP:          ptr.To(false),
+				LevelName:    "survival",
+			},
+			want: map[string]string{
+				"difficulty":    "hard",
+				"gamemode":      "creative",
+				"max-players":   "50",
+				"motd":          "Welcome",
+				"view-distance": "12",
+				"pvp":           "false",
+				"level-name":    "survival",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, tt.props.Properties()); diff != "" {
+				t.Errorf("ServerProperties.Properties() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServerProperties_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		props   ServerProperties
+		wantErr string
+	}{
+		{
+			name: "valid",
+			props: ServerProperties{
+				Difficulty:         DifficultyPeaceful,
+				Gamemode:           GamemodeSpectator,
+				SimulationDistance: ptr.To[int32](32),
+				SpawnProtection:    ptr.To[int32](0),
+			},
+		},
+		{
+			name:    "unknown difficulty",
+			props:   ServerProperties{Difficulty: "nightmare"},
+			wantErr: "spec.serverProperties.difficulty: Unsupported value",
+		},
+		{
+			name:    "unknown gamemode",
+			props:   ServerProperties{Gamemode: "hardcore"},
+			wantErr: "spec.serverProperties.gamemode: Unsupported value",
+		},
+		{
+			name:    "no players",
+			props:   ServerProperties{MaxPlayers: ptr.To[int32](0)},
+			wantErr: "spec.serverProperties.maxPlayers: Invalid value",
+		},
+		{
+			name:    "view distance too far",
+			props:   ServerProperties{ViewDistance: ptr.To[int32](33)},
+			wantErr: "must be between 3 and 32",
+		},
+		{
+			name:    "negative spawn protection",
+			props:   ServerProperties{SpawnProtection: ptr.To[int32](-1)},
+			wantErr: "spec.serverProperties.spawnProtection: Invalid value",
+		},
+		{
+			name:    "multi-line motd",
+			props:   ServerProperties{MOTD: "line 1\nline 2"},
+			wantErr: "must not contain line breaks",
+		},
+		{
+			name:    "level name with a path",
+			props:   ServerProperties{LevelName: "../world"},
+			wantErr: "spec.serverProperties.levelName: Invalid value",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.props.validate(field.NewPath("spec", "serverProperties"))
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("validate() = %v, want no errors", errs)
+				}
+				return
+			}
+			if !strings.Contains(errs.ToAggregate().Error(), tt.wantErr) {
+				t.Errorf("validate() = %v, want %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package v1alpha1
+
+import (
+	"testing"
+)
+
+func TestServer_Image(t *testing.T) {
+	tests := []struct {
+		name   string
+		server Server
+		want   string
+	}{
+		{
+			name:   "latest",
+			server: Server{},
+			want:   "itzg/minecraft-server:latest",
+		},
+		{
+			name:   "snapshot",
+			server: Server{Version: ServerVersionSnapshot},
+			want:   "itzg/minecraft-server:latest",
+		},
+		{
+			name:   "java 8 for old versions",
+			server: Server{Type: ServerTypeForge, Version: "1.12.2"},
+			want:   "itzg/minecraft-server:java8",
+		},
+		{
+			name:   "java 17",
+			server: Server{Version: "1.20.4"},
+			want:   "itzg/minecraft-server:java17",
+		},
+		{
+			name:   "java 21",
+			server: Server{Type: ServerTypePaper, Version: "1.21.1"},
+			want:   "itzg/minecraft-server:java21",
+		},
+		{
+			name:   "java 25",
+			server: Server{Version: "26.1"},
+			want:   "itzg/minecraft-server:java25",
+		},
+		{
+			name:   "explicit java version",
+			server: Server{Version: "1.20.4", JavaVersion: 21},
+			want:   "itzg/minecraft-server:java21",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.server.Image(); got != tt.want {
+				t.Errorf("Server.Image() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_ServerTypeName(t *testing.T) {
+	tests := []struct {
+		typ  ServerType
+		want string
+	}{
+		{typ: "", want: "VANILLA"},
+		{typ: ServerTypePaper, want: "PAPER"},
+		{typ: ServerTypeNeoForge, want: "NEOFORGE"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.typ), func(t *testing.T) {
+			s := &Server{Type: tt.typ}
+			if got := s.ServerTypeName(); got != tt.want {
+				t.Errorf("Server.ServerTypeName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
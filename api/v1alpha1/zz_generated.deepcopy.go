@@ -5,10 +5,32 @@
 package v1alpha1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Artifact) DeepCopyInto(out *Artifact) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Artifact.
+func (in *Artifact) DeepCopy() *Artifact {
+	if in == nil {
+		return nil
+	}
+	out := new(Artifact)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AutoPause) DeepCopyInto(out *AutoPause) {
 	*out = *in
@@ -17,59 +39,654 @@ func (in *AutoPause) DeepCopyInto(out *AutoPause) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Lazymc != nil {
+		in, out := &in.Lazymc, &out.Lazymc
+		*out = new(LazymcOptions)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoPause.
+func (in *AutoPause) DeepCopy() *AutoPause {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoPause)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Backup) DeepCopyInto(out *Backup) {
+	*out = *in
+	if in.Excludes != nil {
+		in, out := &in.Excludes, &out.Excludes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VolumeSnapshotClassName != nil {
+		in, out := &in.VolumeSnapshotClassName, &out.VolumeSnapshotClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Snapshot != nil {
+		in, out := &in.Snapshot, &out.Snapshot
+		*out = new(SnapshotBackup)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Backup.
+func (in *Backup) DeepCopy() *Backup {
+	if in == nil {
+		return nil
+	}
+	out := new(Backup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupArchive) DeepCopyInto(out *BackupArchive) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupArchive.
+func (in *BackupArchive) DeepCopy() *BackupArchive {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupArchive)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Bedrock) DeepCopyInto(out *Bedrock) {
+	*out = *in
+	if in.InstallPlugins != nil {
+		in, out := &in.InstallPlugins, &out.InstallPlugins
+		*out = new(bool)
+		**out = **in
+	}
+	if in.GeyserDownload != nil {
+		in, out := &in.GeyserDownload, &out.GeyserDownload
+		*out = new(PluginDownload)
+		**out = **in
+	}
+	if in.FloodgateDownload != nil {
+		in, out := &in.FloodgateDownload, &out.FloodgateDownload
+		*out = new(PluginDownload)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Bedrock.
+func (in *Bedrock) DeepCopy() *Bedrock {
+	if in == nil {
+		return nil
+	}
+	out := new(Bedrock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigFile) DeepCopyInto(out *ConfigFile) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigFile.
+func (in *ConfigFile) DeepCopy() *ConfigFile {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigFile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerOverride) DeepCopyInto(out *ContainerOverride) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerOverride.
+func (in *ContainerOverride) DeepCopy() *ContainerOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataSource) DeepCopyInto(out *DataSource) {
+	*out = *in
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(BackupArchive)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataSource.
+func (in *DataSource) DeepCopy() *DataSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DataSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayBackend) DeepCopyInto(out *GatewayBackend) {
+	*out = *in
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayBackend.
+func (in *GatewayBackend) DeepCopy() *GatewayBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayDNS) DeepCopyInto(out *GatewayDNS) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayDNS.
+func (in *GatewayDNS) DeepCopy() *GatewayDNS {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayDNS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayService) DeepCopyInto(out *GatewayService) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayService.
+func (in *GatewayService) DeepCopy() *GatewayService {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Hibernation) DeepCopyInto(out *Hibernation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Hibernation.
+func (in *Hibernation) DeepCopy() *Hibernation {
+	if in == nil {
+		return nil
+	}
+	out := new(Hibernation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstalledArtifact) DeepCopyInto(out *InstalledArtifact) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstalledArtifact.
+func (in *InstalledArtifact) DeepCopy() *InstalledArtifact {
+	if in == nil {
+		return nil
+	}
+	out := new(InstalledArtifact)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstalledResourcePack) DeepCopyInto(out *InstalledResourcePack) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstalledResourcePack.
+func (in *InstalledResourcePack) DeepCopy() *InstalledResourcePack {
+	if in == nil {
+		return nil
+	}
+	out := new(InstalledResourcePack)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JVM) DeepCopyInto(out *JVM) {
+	*out = *in
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JVM.
+func (in *JVM) DeepCopy() *JVM {
+	if in == nil {
+		return nil
+	}
+	out := new(JVM)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LazymcJoin) DeepCopyInto(out *LazymcJoin) {
+	*out = *in
+	if in.Methods != nil {
+		in, out := &in.Methods, &out.Methods
+		*out = make([]LazymcJoinMethod, len(*in))
+		copy(*out, *in)
+	}
+	out.Kick = in.Kick
+	out.Hold = in.Hold
+	out.Forward = in.Forward
+	out.Lobby = in.Lobby
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LazymcJoin.
+func (in *LazymcJoin) DeepCopy() *LazymcJoin {
+	if in == nil {
+		return nil
+	}
+	out := new(LazymcJoin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LazymcJoinForward) DeepCopyInto(out *LazymcJoinForward) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LazymcJoinForward.
+func (in *LazymcJoinForward) DeepCopy() *LazymcJoinForward {
+	if in == nil {
+		return nil
+	}
+	out := new(LazymcJoinForward)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LazymcJoinHold) DeepCopyInto(out *LazymcJoinHold) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LazymcJoinHold.
+func (in *LazymcJoinHold) DeepCopy() *LazymcJoinHold {
+	if in == nil {
+		return nil
+	}
+	out := new(LazymcJoinHold)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LazymcJoinKick) DeepCopyInto(out *LazymcJoinKick) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LazymcJoinKick.
+func (in *LazymcJoinKick) DeepCopy() *LazymcJoinKick {
+	if in == nil {
+		return nil
+	}
+	out := new(LazymcJoinKick)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LazymcJoinLobby) DeepCopyInto(out *LazymcJoinLobby) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LazymcJoinLobby.
+func (in *LazymcJoinLobby) DeepCopy() *LazymcJoinLobby {
+	if in == nil {
+		return nil
+	}
+	out := new(LazymcJoinLobby)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LazymcLockout) DeepCopyInto(out *LazymcLockout) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LazymcLockout.
+func (in *LazymcLockout) DeepCopy() *LazymcLockout {
+	if in == nil {
+		return nil
+	}
+	out := new(LazymcLockout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LazymcMOTD) DeepCopyInto(out *LazymcMOTD) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LazymcMOTD.
+func (in *LazymcMOTD) DeepCopy() *LazymcMOTD {
+	if in == nil {
+		return nil
+	}
+	out := new(LazymcMOTD)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LazymcOptions) DeepCopyInto(out *LazymcOptions) {
+	*out = *in
+	if in.WakeWhitelist != nil {
+		in, out := &in.WakeWhitelist, &out.WakeWhitelist
+		*out = new(bool)
+		**out = **in
+	}
+	out.MOTD = in.MOTD
+	in.Join.DeepCopyInto(&out.Join)
+	out.Lockout = in.Lockout
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LazymcOptions.
+func (in *LazymcOptions) DeepCopy() *LazymcOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(LazymcOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Minecraft) DeepCopyInto(out *Minecraft) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Minecraft.
+func (in *Minecraft) DeepCopy() *Minecraft {
+	if in == nil {
+		return nil
+	}
+	out := new(Minecraft)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Minecraft) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MinecraftGateway) DeepCopyInto(out *MinecraftGateway) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinecraftGateway.
+func (in *MinecraftGateway) DeepCopy() *MinecraftGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(MinecraftGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MinecraftGateway) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MinecraftGatewayList) DeepCopyInto(out *MinecraftGatewayList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MinecraftGateway, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinecraftGatewayList.
+func (in *MinecraftGatewayList) DeepCopy() *MinecraftGatewayList {
+	if in == nil {
+		return nil
+	}
+	out := new(MinecraftGatewayList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MinecraftGatewayList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MinecraftGatewaySpec) DeepCopyInto(out *MinecraftGatewaySpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.Service.DeepCopyInto(&out.Service)
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DNS != nil {
+		in, out := &in.DNS, &out.DNS
+		*out = new(GatewayDNS)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoPause.
-func (in *AutoPause) DeepCopy() *AutoPause {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinecraftGatewaySpec.
+func (in *MinecraftGatewaySpec) DeepCopy() *MinecraftGatewaySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(AutoPause)
+	out := new(MinecraftGatewaySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Backup) DeepCopyInto(out *Backup) {
+func (in *MinecraftGatewayStatus) DeepCopyInto(out *MinecraftGatewayStatus) {
 	*out = *in
-	if in.Excludes != nil {
-		in, out := &in.Excludes, &out.Excludes
+	if in.ExternalAddresses != nil {
+		in, out := &in.ExternalAddresses, &out.ExternalAddresses
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]GatewayBackend, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Backup.
-func (in *Backup) DeepCopy() *Backup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinecraftGatewayStatus.
+func (in *MinecraftGatewayStatus) DeepCopy() *MinecraftGatewayStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Backup)
+	out := new(MinecraftGatewayStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Minecraft) DeepCopyInto(out *Minecraft) {
+func (in *MinecraftList) DeepCopyInto(out *MinecraftList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Minecraft, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinecraftList.
+func (in *MinecraftList) DeepCopy() *MinecraftList {
+	if in == nil {
+		return nil
+	}
+	out := new(MinecraftList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MinecraftList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MinecraftNetwork) DeepCopyInto(out *MinecraftNetwork) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Minecraft.
-func (in *Minecraft) DeepCopy() *Minecraft {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinecraftNetwork.
+func (in *MinecraftNetwork) DeepCopy() *MinecraftNetwork {
 	if in == nil {
 		return nil
 	}
-	out := new(Minecraft)
+	out := new(MinecraftNetwork)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Minecraft) DeepCopyObject() runtime.Object {
+func (in *MinecraftNetwork) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -77,37 +694,99 @@ func (in *Minecraft) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MinecraftList) DeepCopyInto(out *MinecraftList) {
+func (in *MinecraftNetworkList) DeepCopyInto(out *MinecraftNetworkList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Minecraft, len(*in))
+		*out = make([]MinecraftNetwork, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinecraftList.
-func (in *MinecraftList) DeepCopy() *MinecraftList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinecraftNetworkList.
+func (in *MinecraftNetworkList) DeepCopy() *MinecraftNetworkList {
 	if in == nil {
 		return nil
 	}
-	out := new(MinecraftList)
+	out := new(MinecraftNetworkList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MinecraftList) DeepCopyObject() runtime.Object {
+func (in *MinecraftNetworkList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MinecraftNetworkSpec) DeepCopyInto(out *MinecraftNetworkSpec) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	if in.Try != nil {
+		in, out := &in.Try, &out.Try
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.ServiceTemplate != nil {
+		in, out := &in.ServiceTemplate, &out.ServiceTemplate
+		*out = new(ServiceTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxPlayers != nil {
+		in, out := &in.MaxPlayers, &out.MaxPlayers
+		*out = new(int32)
+		**out = **in
+	}
+	if in.OnlineMode != nil {
+		in, out := &in.OnlineMode, &out.OnlineMode
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinecraftNetworkSpec.
+func (in *MinecraftNetworkSpec) DeepCopy() *MinecraftNetworkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MinecraftNetworkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MinecraftNetworkStatus) DeepCopyInto(out *MinecraftNetworkStatus) {
+	*out = *in
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]NetworkBackend, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinecraftNetworkStatus.
+func (in *MinecraftNetworkStatus) DeepCopy() *MinecraftNetworkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MinecraftNetworkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MinecraftSpec) DeepCopyInto(out *MinecraftSpec) {
 	*out = *in
@@ -119,13 +798,71 @@ func (in *MinecraftSpec) DeepCopyInto(out *MinecraftSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PersistentVolumeClaimRetentionPolicy != nil {
+		in, out := &in.PersistentVolumeClaimRetentionPolicy, &out.PersistentVolumeClaimRetentionPolicy
+		*out = new(appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy)
+		**out = **in
+	}
+	if in.DataSource != nil {
+		in, out := &in.DataSource, &out.DataSource
+		*out = new(DataSource)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ServiceTemplate != nil {
 		in, out := &in.ServiceTemplate, &out.ServiceTemplate
 		*out = new(ServiceTemplate)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Server != nil {
+		in, out := &in.Server, &out.Server
+		*out = new(Server)
+		**out = **in
+	}
+	if in.JVM != nil {
+		in, out := &in.JVM, &out.JVM
+		*out = new(JVM)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Mods != nil {
+		in, out := &in.Mods, &out.Mods
+		*out = make([]Artifact, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]Artifact, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Datapacks != nil {
+		in, out := &in.Datapacks, &out.Datapacks
+		*out = make([]Artifact, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResourcePack != nil {
+		in, out := &in.ResourcePack, &out.ResourcePack
+		*out = new(ResourcePack)
+		**out = **in
+	}
+	if in.Bedrock != nil {
+		in, out := &in.Bedrock, &out.Bedrock
+		*out = new(Bedrock)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Agent.DeepCopyInto(&out.Agent)
+	in.Init.DeepCopyInto(&out.Init)
 	in.Ops.DeepCopyInto(&out.Ops)
 	in.Whitelist.DeepCopyInto(&out.Whitelist)
+	if in.ServerProperties != nil {
+		in, out := &in.ServerProperties, &out.ServerProperties
+		*out = new(ServerProperties)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ServerPropertiesConfigMapName != nil {
 		in, out := &in.ServerPropertiesConfigMapName, &out.ServerPropertiesConfigMapName
 		*out = new(string)
@@ -136,18 +873,36 @@ func (in *MinecraftSpec) DeepCopyInto(out *MinecraftSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ConfigFiles != nil {
+		in, out := &in.ConfigFiles, &out.ConfigFiles
+		*out = make([]ConfigFile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.RconPasswordSecretName != nil {
 		in, out := &in.RconPasswordSecretName, &out.RconPasswordSecretName
 		*out = new(string)
 		**out = **in
 	}
 	in.AutoPause.DeepCopyInto(&out.AutoPause)
+	out.Hibernation = in.Hibernation
 	in.Backup.DeepCopyInto(&out.Backup)
+	if in.GatewayName != nil {
+		in, out := &in.GatewayName, &out.GatewayName
+		*out = new(string)
+		**out = **in
+	}
 	if in.ExternalHostname != nil {
 		in, out := &in.ExternalHostname, &out.ExternalHostname
 		*out = new(string)
 		**out = **in
 	}
+	if in.HostnameAliases != nil {
+		in, out := &in.HostnameAliases, &out.HostnameAliases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinecraftSpec.
@@ -163,6 +918,33 @@ func (in *MinecraftSpec) DeepCopy() *MinecraftSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MinecraftStatus) DeepCopyInto(out *MinecraftStatus) {
 	*out = *in
+	if in.Mods != nil {
+		in, out := &in.Mods, &out.Mods
+		*out = make([]InstalledArtifact, len(*in))
+		copy(*out, *in)
+	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]InstalledArtifact, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourcePack != nil {
+		in, out := &in.ResourcePack, &out.ResourcePack
+		*out = new(InstalledResourcePack)
+		**out = **in
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(StorageStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinecraftStatus.
@@ -175,6 +957,21 @@ func (in *MinecraftStatus) DeepCopy() *MinecraftStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkBackend) DeepCopyInto(out *NetworkBackend) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkBackend.
+func (in *NetworkBackend) DeepCopy() *NetworkBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectMeta) DeepCopyInto(out *ObjectMeta) {
 	*out = *in
@@ -241,6 +1038,21 @@ func (in *PersistentVolumeClaim) DeepCopy() *PersistentVolumeClaim {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginDownload) DeepCopyInto(out *PluginDownload) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginDownload.
+func (in *PluginDownload) DeepCopy() *PluginDownload {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginDownload)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodTemplateSpec) DeepCopyInto(out *PodTemplateSpec) {
 	*out = *in
@@ -258,6 +1070,111 @@ func (in *PodTemplateSpec) DeepCopy() *PodTemplateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourcePack) DeepCopyInto(out *ResourcePack) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourcePack.
+func (in *ResourcePack) DeepCopy() *ResourcePack {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourcePack)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Server) DeepCopyInto(out *Server) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Server.
+func (in *Server) DeepCopy() *Server {
+	if in == nil {
+		return nil
+	}
+	out := new(Server)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerProperties) DeepCopyInto(out *ServerProperties) {
+	*out = *in
+	if in.ForceGamemode != nil {
+		in, out := &in.ForceGamemode, &out.ForceGamemode
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Hardcore != nil {
+		in, out := &in.Hardcore, &out.Hardcore
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxPlayers != nil {
+		in, out := &in.MaxPlayers, &out.MaxPlayers
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ViewDistance != nil {
+		in, out := &in.ViewDistance, &out.ViewDistance
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SimulationDistance != nil {
+		in, out := &in.SimulationDistance, &out.SimulationDistance
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PVP != nil {
+		in, out := &in.PVP, &out.PVP
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OnlineMode != nil {
+		in, out := &in.OnlineMode, &out.OnlineMode
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowFlight != nil {
+		in, out := &in.AllowFlight, &out.AllowFlight
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowNether != nil {
+		in, out := &in.AllowNether, &out.AllowNether
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableCommandBlock != nil {
+		in, out := &in.EnableCommandBlock, &out.EnableCommandBlock
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SpawnProtection != nil {
+		in, out := &in.SpawnProtection, &out.SpawnProtection
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PlayerIdleTimeout != nil {
+		in, out := &in.PlayerIdleTimeout, &out.PlayerIdleTimeout
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerProperties.
+func (in *ServerProperties) DeepCopy() *ServerProperties {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerProperties)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceTemplate) DeepCopyInto(out *ServiceTemplate) {
 	*out = *in
@@ -279,6 +1196,51 @@ func (in *ServiceTemplate) DeepCopy() *ServiceTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotBackup) DeepCopyInto(out *SnapshotBackup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotBackup.
+func (in *SnapshotBackup) DeepCopy() *SnapshotBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageStatus) DeepCopyInto(out *StorageStatus) {
+	*out = *in
+	if in.Requested != nil {
+		in, out := &in.Requested, &out.Requested
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Used != nil {
+		in, out := &in.Used, &out.Used
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageStatus.
+func (in *StorageStatus) DeepCopy() *StorageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Whitelist) DeepCopyInto(out *Whitelist) {
 	*out = *in
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kmdkuk/mcing/internal/cli/clone"
+)
+
+// NewCloneCmd creates a new clone command.
+func NewCloneCmd(opts *MCingOptions) *cobra.Command {
+	o := clone.NewOptions()
+	cmd := &cobra.Command{
+		Use:   "clone <source-minecraft-name> <minecraft-name>",
+		Short: "Clone a minecraft server",
+		Long: `Create a Minecraft with the spec of another Minecraft in the same namespace.
+The data volume of the source is cloned before the first start. The hostnames are not copied.`,
+		Args: cobra.ExactArgs(2), //nolint:mnd // source and destination
+		RunE: func(_ *cobra.Command, args []string) error {
+			if err := o.Complete(args); err != nil {
+				return err
+			}
+
+			if o.Namespace == "" {
+				var err error
+				o.Namespace, _, err = opts.ConfigFlags.ToRawKubeConfigLoader().Namespace()
+				if err != nil {
+					return err
+				}
+			}
+
+			c := clone.NewCloner(o, opts.K8sClient, opts.IOStreams.Out)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			sigCh := make(chan os.Signal, 1)
+			signal.Notify(sigCh, os.Interrupt)
+			go func() {
+				<-sigCh
+				cancel()
+			}()
+			return c.Run(ctx)
+		},
+	}
+
+	cmd.Flags().StringVar(&o.Snapshot, "snapshot", "",
+		"Restore the data volume from this VolumeSnapshot of the source instead of cloning the live volume")
+	cmd.Flags().BoolVar(&o.StripOps, "strip-ops", false, "Do not copy the operators of the source")
+	cmd.Flags().BoolVar(&o.StripWhitelist, "strip-whitelist", false, "Do not copy the whitelisted users of the source")
+
+	return cmd
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kmdkuk/mcing/internal/cli/power"
+	"github.com/kmdkuk/mcing/pkg/kube"
+)
+
+// NewWakeCmd creates a new wake command.
+func NewWakeCmd(opts *MCingOptions) *cobra.Command {
+	return newPowerCmd(opts, &cobra.Command{
+		Use:   "wake <minecraft-name>",
+		Short: "Wake up a sleeping minecraft server",
+		Long:  `Start the server process stopped by autoPause (lazymc) and wait until it accepts connections.`,
+		Args:  cobra.ExactArgs(1),
+	}, (*power.Switcher).Wake)
+}
+
+// NewSleepCmd creates a new sleep command.
+func NewSleepCmd(opts *MCingOptions) *cobra.Command {
+	return newPowerCmd(opts, &cobra.Command{
+		Use:   "sleep <minecraft-name>",
+		Short: "Put a minecraft server to sleep",
+		Long: `Stop the server process of a Minecraft with autoPause enabled.
+lazymc starts it again when a player joins.`,
+		Args: cobra.ExactArgs(1),
+	}, (*power.Switcher).Sleep)
+}
+
+func newPowerCmd(
+	opts *MCingOptions,
+	cmd *cobra.Command,
+	run func(*power.Switcher, context.Context) error,
+) *cobra.Command {
+	o := power.NewOptions()
+	cmd.RunE = func(_ *cobra.Command, args []string) error {
+		if err := o.Complete(args); err != nil {
+			return err
+		}
+
+		if o.Namespace == "" {
+			var err error
+			o.Namespace, _, err = opts.ConfigFlags.ToRawKubeConfigLoader().Namespace()
+			if err != nil {
+				return err
+			}
+		}
+
+		kubeExecutor := &kube.DefaultExecutor{
+			Clientset:  opts.Clientset,
+			RestConfig: opts.RestConfig,
+		}
+
+		s := power.NewSwitcher(o, opts.K8sClient, kubeExecutor, opts.IOStreams.Out)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, os.Interrupt)
+		go func() {
+			<-sigCh
+			cancel()
+		}()
+		return run(s, ctx)
+	}
+	return cmd
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -64,6 +65,9 @@ This plugin provides commands to interact with Minecraft servers managed by MCin
 			if err = corev1.AddToScheme(scheme); err != nil {
 				return err
 			}
+			if err = appsv1.AddToScheme(scheme); err != nil {
+				return err
+			}
 
 			o.K8sClient, err = client.New(o.RestConfig, client.Options{Scheme: scheme})
 			if err != nil {
@@ -82,6 +86,9 @@ This plugin provides commands to interact with Minecraft servers managed by MCin
 	rootCmd.PersistentFlags().AddGoFlagSet(flag.CommandLine)
 
 	rootCmd.AddCommand(NewDownloadCmd(o))
+	rootCmd.AddCommand(NewCloneCmd(o))
+	rootCmd.AddCommand(NewWakeCmd(o))
+	rootCmd.AddCommand(NewSleepCmd(o))
 	rootCmd.AddCommand(NewVersionCmd())
 
 	return rootCmd
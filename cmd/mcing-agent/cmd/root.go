@@ -29,17 +29,18 @@ import (
 	"net"
 	"os"
 	"path"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
-	james4krcon "github.com/james4k/rcon"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/keepalive"
 
+	"github.com/kmdkuk/mcing/pkg/autopause"
 	"github.com/kmdkuk/mcing/pkg/config"
 	"github.com/kmdkuk/mcing/pkg/constants"
 	"github.com/kmdkuk/mcing/pkg/proto"
@@ -53,10 +54,14 @@ const (
 	minKeepaliveTime = 10 * time.Second
 	rconRetryCount   = 30
 	watcherInterval  = 10 * time.Second
+
+	defaultAutoPauseTimeout = 5 * time.Minute
 )
 
 type flags struct {
-	address string
+	address          string
+	autoPauseMode    string
+	autoPauseTimeout time.Duration
 }
 
 // InterceptorLogger adapts zap logger to interceptor logger.
@@ -100,7 +105,7 @@ func InterceptorLogger(l *zap.Logger) logging.Logger {
 
 // NewRootCmd represents the base command when called without any subcommands.
 func NewRootCmd() *cobra.Command {
-	f := flags{address: ""}
+	f := flags{address: "", autoPauseMode: "", autoPauseTimeout: 0}
 	rootCmd := &cobra.Command{
 		Use:   "mcing-agent",
 		Short: "A brief description of your application",
@@ -119,6 +124,11 @@ to quickly create a Cobra application.`,
 
 	fs := rootCmd.Flags()
 	fs.StringVar(&f.address, "address", grpcDefaultAddr, "Listening address and port for gRPC API.")
+	fs.StringVar(&f.autoPauseMode, "auto-pause-mode", "",
+		fmt.Sprintf("Auto-pause backend managing the server process (%q or %q). Empty disables auto-pause.",
+			autopause.ModeLazymc, autopause.ModeAgent))
+	fs.DurationVar(&f.autoPauseTimeout, "auto-pause-timeout", defaultAutoPauseTimeout,
+		"Time without players before the server is put to sleep. Used by the agent mode.")
 
 	rootCmd.AddCommand(newVersionCmd())
 	return rootCmd
@@ -157,39 +167,70 @@ func runAgent(f flags) error {
 			// Add any other interceptor you want.
 		),
 	)
-	retryCount := 0
-	var conn *james4krcon.RemoteConsole
-	var props map[string]string
-	for {
-		props, err = config.ParseServerPropsFromPath(path.Join(constants.DataPath, constants.ServerPropsName))
-		if err != nil {
-			return err
-		}
-
-		hostPort := "127.0.0.1:" + props[constants.RconPortProps]
-		password := os.Getenv(constants.RconPasswordEnvName)
-
-		conn, err = rcon.NewConn(hostPort, password)
-		if err == nil {
-			break
-		}
-		if retryCount > rconRetryCount {
-			return err
-		}
-		retryCount++
-		wait := 1 * retryCount
-		zapLogger.Error(fmt.Sprintf("connection error, retry after %d seconds", wait), zap.Error(err))
-		time.Sleep(time.Duration(wait) * time.Second)
+	props, err := config.ParseServerPropsFromPath(path.Join(constants.DataPath, constants.ServerPropsName))
+	if err != nil {
+		return err
 	}
+	hostPort := "127.0.0.1:" + props[constants.RconPortProps]
+	password := os.Getenv(constants.RconPasswordEnvName)
+	conn := rcon.NewReconnectingConsole(hostPort, password)
 	defer func() {
 		err = conn.Close()
 	}()
 
-	proto.RegisterAgentServer(grpcServer, server.NewAgentService(zapLogger, conn))
+	// With auto-pause the server may be sleeping, so the agent must not wait for RCON.
+	// The console connects on first use instead.
+	if f.autoPauseMode == "" {
+		retryCount := 0
+		for {
+			err = conn.Connect()
+			if err == nil {
+				break
+			}
+			if retryCount > rconRetryCount {
+				return err
+			}
+			retryCount++
+			wait := 1 * retryCount
+			zapLogger.Error(fmt.Sprintf("connection error, retry after %d seconds", wait), zap.Error(err))
+			time.Sleep(time.Duration(wait) * time.Second)
+		}
+	}
+
+	publicPort := strconv.Itoa(int(constants.ServerPort))
+	backendAddr := net.JoinHostPort("127.0.0.1", strconv.Itoa(int(constants.InternalServerPort)))
+	watcherConfig := watcher.NewDefaultConfig()
+	var autoPause autopause.Controller
+	var proxy *autopause.Proxy
+	switch f.autoPauseMode {
+	case "":
+	case autopause.ModeLazymc:
+		lazymc := autopause.NewLazymc(zapLogger, conn, net.JoinHostPort("127.0.0.1", publicPort), backendAddr)
+		autoPause = lazymc
+		watcherConfig.Paused = lazymc.Sleeping
+	case autopause.ModeAgent:
+		proxy = autopause.NewProxy(zapLogger, conn, net.JoinHostPort("", publicPort), backendAddr,
+			path.Join(constants.AutoPausePath, constants.AutoPauseMarkerName), f.autoPauseTimeout)
+		autoPause = proxy
+		watcherConfig.Paused = func(context.Context) bool { return proxy.Sleeping() }
+	default:
+		return fmt.Errorf("unknown auto-pause mode: %s", f.autoPauseMode)
+	}
+
+	proto.RegisterAgentServer(grpcServer,
+		server.NewAgentService(zapLogger, conn, autoPause, watcherConfig.Results))
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	var wg sync.WaitGroup
+	if proxy != nil {
+		wg.Go(func() {
+			if err := proxy.Run(ctx); err != nil {
+				zapLogger.Error("failed to run auto-pause proxy", zap.Error(err))
+				cancel()
+			}
+		})
+	}
 	wg.Go(func() {
 		err := grpcServer.Serve(lis)
 		if err != nil {
@@ -208,7 +249,7 @@ func runAgent(f flags) error {
 	wg.Add(1)
 	go func(ctx context.Context) {
 		defer wg.Done()
-		err := watcher.Watch(ctx, conn, watcherInterval, watcher.NewDefaultConfig())
+		err := watcher.Watch(ctx, conn, watcherInterval, watcherConfig)
 		if err != nil {
 			zapLogger.Error("failed to watch", zap.Error(err))
 		}
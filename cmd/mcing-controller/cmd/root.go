@@ -14,10 +14,6 @@ import (
 	"github.com/kmdkuk/mcing/pkg/version"
 )
 
-const (
-	defaultMCRouterReconcileInterval = 3 * time.Minute
-)
-
 // Config represents the configuration for the controller.
 type Config struct {
 	metricsAddr          string
@@ -30,15 +26,8 @@ type Config struct {
 	initImageName        string
 	agentImageName       string
 	interval             time.Duration
-
-	// mc-router configuration
-	enableMCRouter            bool
-	mcRouterDefaultDomain     string
-	mcRouterNamespace         string
-	mcRouterServiceAccount    string
-	mcRouterServiceType       string
-	mcRouterImage             string
-	mcRouterReconcileInterval time.Duration
+	wakerBindAddr        string
+	wakerAddr            string
 }
 
 // NewRootCmd represents the base command when called without any subcommands.
@@ -56,15 +45,8 @@ func NewRootCmd() *cobra.Command {
 		initImageName        string
 		agentImageName       string
 		interval             time.Duration
-
-		// mc-router configuration
-		enableMCRouter            bool
-		mcRouterDefaultDomain     string
-		mcRouterNamespace         string
-		mcRouterServiceAccount    string
-		mcRouterServiceType       string
-		mcRouterImage             string
-		mcRouterReconcileInterval time.Duration
+		wakerBindAddr        string
+		wakerAddr            string
 	)
 
 	rootCmd := &cobra.Command{
@@ -75,23 +57,18 @@ func NewRootCmd() *cobra.Command {
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			cmd.SilenceUsage = true
 			cfg := Config{
-				metricsAddr:               metricAddr,
-				probeAddr:                 probeAddr,
-				enableLeaderElection:      enableLeaderElection,
-				webhookCertPath:           webhookCertPath,
-				webhookCertName:           webhookCertName,
-				webhookCertKey:            webhookCertKey,
-				zapOpts:                   zapOpts,
-				initImageName:             initImageName,
-				agentImageName:            agentImageName,
-				interval:                  interval,
-				enableMCRouter:            enableMCRouter,
-				mcRouterDefaultDomain:     mcRouterDefaultDomain,
-				mcRouterNamespace:         mcRouterNamespace,
-				mcRouterServiceAccount:    mcRouterServiceAccount,
-				mcRouterServiceType:       mcRouterServiceType,
-				mcRouterImage:             mcRouterImage,
-				mcRouterReconcileInterval: mcRouterReconcileInterval,
+				metricsAddr:          metricAddr,
+				probeAddr:            probeAddr,
+				enableLeaderElection: enableLeaderElection,
+				webhookCertPath:      webhookCertPath,
+				webhookCertName:      webhookCertName,
+				webhookCertKey:       webhookCertKey,
+				zapOpts:              zapOpts,
+				initImageName:        initImageName,
+				agentImageName:       agentImageName,
+				interval:             interval,
+				wakerBindAddr:        wakerBindAddr,
+				wakerAddr:            wakerAddr,
 			}
 			return subMain(cfg)
 		},
@@ -123,27 +100,12 @@ func NewRootCmd() *cobra.Command {
 		"ghcr.io/kmdkuk/mcing-agent:"+strings.TrimPrefix(version.Version, "v"),
 		"mcing-agent image name",
 	)
-	fs.DurationVar(&interval, "check-interval", 1*time.Minute, "Interval of minecraft maintenance")
+	fs.DurationVar(&interval, "check-interval", 1*time.Minute, "Interval of minecraft maintenance and of checking the routes of mc-router")
 
-	// mc-router flags
-	fs.BoolVar(&enableMCRouter, "enable-mc-router", false,
-		"Enable mc-router gateway for hostname-based Minecraft server routing")
-	fs.StringVar(&mcRouterDefaultDomain, "mc-router-default-domain", "minecraft.local",
-		"Default domain for mc-router FQDN generation (<name>.<namespace>.<domain>)")
-	fs.StringVar(&mcRouterNamespace, "mc-router-namespace", "mcing-gateway",
-		"Namespace where mc-router gateway will be deployed")
-	fs.StringVar(&mcRouterServiceAccount, "mc-router-service-account", "mc-router",
-		"Service account name for mc-router")
-	fs.StringVar(&mcRouterServiceType, "mc-router-service-type", "LoadBalancer",
-		"Service type for mc-router gateway (LoadBalancer or NodePort)")
-	fs.StringVar(&mcRouterImage, "mc-router-image", "itzg/mc-router:latest",
-		"mc-router container image")
-	fs.DurationVar(
-		&mcRouterReconcileInterval,
-		"mc-router-reconcile-interval",
-		defaultMCRouterReconcileInterval,
-		"Interval for mc-router gateway reconciliation",
-	)
+	fs.StringVar(&wakerBindAddr, "waker-bind-address", ":25565",
+		"The address the waker of hibernated servers binds to. Set it to empty to disable the waker.")
+	fs.StringVar(&wakerAddr, "waker-address", "mcing-waker-service.mcing-system.svc:25565",
+		"The address mc-router connects to for hibernated servers")
 
 	goflags := flag.NewFlagSet("klog", flag.ExitOnError)
 	klog.InitFlags(goflags)
@@ -14,7 +14,6 @@ import (
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth" // required for authentication
 
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -31,6 +30,7 @@ import (
 	"github.com/kmdkuk/mcing/internal/controller"
 	"github.com/kmdkuk/mcing/internal/minecraft"
 	"github.com/kmdkuk/mcing/pkg/agent"
+	"github.com/kmdkuk/mcing/pkg/mcrouter"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -85,28 +85,6 @@ func subMain(config Config) error {
 
 	minecraftMgr := minecraft.NewManager(af, config.interval, mgr, mcMgrLog)
 
-	// Create gateway config from command-line flags
-	gatewayConfig := controller.GatewayConfig{
-		Enabled:           config.enableMCRouter,
-		DefaultDomain:     config.mcRouterDefaultDomain,
-		Namespace:         config.mcRouterNamespace,
-		ServiceAccount:    config.mcRouterServiceAccount,
-		ServiceType:       corev1.ServiceType(config.mcRouterServiceType),
-		Image:             config.mcRouterImage,
-		ReconcileInterval: config.mcRouterReconcileInterval,
-	}
-
-	// Validate mc-router service type
-	if gatewayConfig.Enabled {
-		if gatewayConfig.ServiceType != corev1.ServiceTypeLoadBalancer &&
-			gatewayConfig.ServiceType != corev1.ServiceTypeNodePort {
-			return fmt.Errorf(
-				"invalid mc-router-service-type %q: must be LoadBalancer or NodePort",
-				config.mcRouterServiceType,
-			)
-		}
-	}
-
 	if err = (controller.NewMinecraftReconciler(
 		mgr.GetClient(),
 		ctrl.Log.WithName("controllers"),
@@ -114,29 +92,54 @@ func subMain(config Config) error {
 		config.initImageName,
 		config.agentImageName,
 		minecraftMgr,
-		gatewayConfig,
 	)).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Minecraft")
 		return err
 	}
 
-	// Register Gateway controller if mc-router is enabled
-	if gatewayConfig.Enabled {
-		if err = (controller.NewGatewayReconciler(
-			mgr.GetClient(),
-			ctrl.Log.WithName("controllers"),
-			mgr.GetScheme(),
-			gatewayConfig,
-		)).SetupWithManager(mgr); err != nil {
-			setupLog.Error(err, "unable to create controller", "controller", "Gateway")
-			return err
-		}
+	if err = (controller.NewGatewayReconciler(
+		mgr.GetClient(),
+		ctrl.Log.WithName("controllers"),
+		mgr.GetScheme(),
+		controller.GatewayConfig{
+			RouterFactory:  mcrouter.NewFactory(),
+			WakerAddress:   wakerAddress(config),
+			RoutesInterval: config.interval,
+		},
+	)).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Gateway")
+		return err
+	}
+
+	if err = (controller.NewNetworkReconciler(
+		mgr.GetClient(),
+		ctrl.Log.WithName("controllers"),
+		mgr.GetScheme(),
+	)).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Network")
+		return err
 	}
 
 	if err = (&mcingv1alpha1.Minecraft{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Minecraft")
 		return err
 	}
+	if err = (&mcingv1alpha1.MinecraftGateway{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "MinecraftGateway")
+		return err
+	}
+	if err = (&mcingv1alpha1.MinecraftNetwork{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "MinecraftNetwork")
+		return err
+	}
+
+	if config.wakerBindAddr != "" {
+		waker := controller.NewWaker(mgr.GetClient(), ctrl.Log.WithName("controllers"), config.wakerBindAddr)
+		if err := mgr.Add(waker); err != nil {
+			setupLog.Error(err, "unable to add the waker to manager")
+			return err
+		}
+	}
 
 	if webhookCertWatcher != nil {
 		setupLog.Info("Adding webhook certificate watcher to manager")
@@ -166,6 +169,14 @@ func subMain(config Config) error {
 	return nil
 }
 
+// wakerAddress returns the address of the waker for mc-router, which is empty if the waker is disabled.
+func wakerAddress(config Config) string {
+	if config.wakerBindAddr == "" {
+		return ""
+	}
+	return config.wakerAddr
+}
+
 func setupWebhookCertWatcher(
 	config Config,
 	setupLog logr.Logger,
@@ -5,16 +5,23 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kmdkuk/mcing/pkg/seed"
 )
 
 // Config represents the configuration for the init.
 type Config struct {
-	EnableLazyMC bool
+	EnableLazyMC           bool
+	TerminationMessagePath string
+	Seed                   seed.Source
 }
 
 // NewRootCmd represents the base command when called without any subcommands.
 func NewRootCmd() *cobra.Command {
 	var enableLazyMC bool
+	var terminationMessagePath string
+	var seedSource seed.Source
 	rootCmd := &cobra.Command{
 		Use:   "mcing-init",
 		Short: "mcing init",
@@ -23,14 +30,23 @@ func NewRootCmd() *cobra.Command {
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			cmd.SilenceUsage = true
 			cfg := Config{
-				EnableLazyMC: enableLazyMC,
+				EnableLazyMC:           enableLazyMC,
+				TerminationMessagePath: terminationMessagePath,
+				Seed:                   seedSource,
 			}
-			return subMain(cfg)
+			return subMain(cmd.Context(), cfg)
 		},
 	}
 
 	fs := rootCmd.Flags()
 	fs.BoolVar(&enableLazyMC, "enable-lazymc", false, "Enable LazyMC")
+	fs.StringVar(&terminationMessagePath, "termination-message-path", corev1.TerminationMessagePathDefault,
+		"Path to report the installed mods and plugins to the controller")
+	fs.StringVar(&seedSource.ID, "seed-id", "", "ID of the server to seed the data volume for only once")
+	fs.StringVar(&seedSource.URL, "seed-url", "", "URL of a tar.gz archive of the data directory to seed from")
+	fs.StringVar(&seedSource.SHA256, "seed-sha256", "", "SHA-256 digest of the archive to seed from")
+	fs.BoolVar(&seedSource.StripOps, "strip-ops", false, "Empty ops.json copied from the source")
+	fs.BoolVar(&seedSource.StripWhitelist, "strip-whitelist", false, "Empty whitelist.json copied from the source")
 
 	return rootCmd
 }
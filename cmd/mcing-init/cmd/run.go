@@ -2,25 +2,111 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 
+	"github.com/kmdkuk/mcing/pkg/artifact"
+	"github.com/kmdkuk/mcing/pkg/config"
+	"github.com/kmdkuk/mcing/pkg/configfile"
 	"github.com/kmdkuk/mcing/pkg/constants"
+	"github.com/kmdkuk/mcing/pkg/seed"
 )
 
-func subMain(cfg Config) error {
+func subMain(ctx context.Context, cfg Config) error {
+	// The data source is placed first, so that the files rendered by the controller take precedence.
+	if cfg.Seed.ID != "" {
+		if err := seed.NewSeeder(constants.DataPath, nil).Seed(ctx, cfg.Seed); err != nil {
+			return err
+		}
+	}
+
 	if err := copyFiles(cfg); err != nil {
 		return err
 	}
 
+	if err := recordStartProps(); err != nil {
+		return err
+	}
+
+	if err := placeConfigFiles(); err != nil {
+		return err
+	}
+
 	if err := buildSaveLazymcConfig(cfg); err != nil {
 		return err
 	}
 
-	return nil
+	return installArtifacts(ctx, cfg)
+}
+
+// recordStartProps records the server.properties in the config that the server starts with.
+// The start command of the server records it again when auto-pause restarts the server.
+func recordStartProps() error {
+	if err := os.MkdirAll(filepath.Dir(constants.StartPropsPath), 0o750); err != nil {
+		return err
+	}
+	return copyFile(filepath.Join(constants.ConfigPath, constants.ServerPropsName), constants.StartPropsPath)
+}
+
+// placeConfigFiles places the config files of spec.configFiles into the data directory.
+func placeConfigFiles() error {
+	manifest, err := configfile.ReadManifest(filepath.Join(constants.ConfigPath, constants.ConfigFilesName))
+	if err != nil {
+		return err
+	}
+	return configfile.Place(constants.DataPath, constants.SecretsPath, manifest)
+}
+
+// installArtifacts installs the mods, plugins and datapacks and reports them in the termination message,
+// which the controller copies into the Minecraft status.
+// The SHA-1 digest of the resource pack is written into server.properties.
+func installArtifacts(ctx context.Context, cfg Config) error {
+	manifest, err := artifact.ReadManifest(filepath.Join(constants.ConfigPath, constants.ArtifactsName))
+	if err != nil {
+		return err
+	}
+	report, err := artifact.NewInstaller(constants.DataPath, nil).Install(ctx, manifest)
+	if err != nil {
+		return err
+	}
+	if report.ResourcePack != nil {
+		err := setServerProperty(constants.ServerPropsPath, constants.ResourcePackSHA1Props, report.ResourcePack.SHA1)
+		if err != nil {
+			return err
+		}
+	}
+	if cfg.TerminationMessagePath == "" {
+		return nil
+	}
+	msg, err := artifact.EncodeReport(report)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(cfg.TerminationMessagePath, msg, 0o600)
+}
+
+// setServerProperty replaces the value of key in server.properties, or appends it.
+func setServerProperty(path, key, value string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	found := false
+	for i, l := range lines {
+		if strings.HasPrefix(l, key+"=") {
+			lines[i] = key + "=" + value
+			found = true
+		}
+	}
+	if !found {
+		lines = append(lines, key+"="+value)
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600)
 }
 
 func isFileExists(filename string) bool {
@@ -36,9 +122,22 @@ func copyFile(from, to string) error {
 	return os.WriteFile(to, b, 0o600)
 }
 
+// copyConfigFile copies a config file rendered by the controller and resolves the references to Secret keys.
+func copyConfigFile(from, to string) error {
+	b, err := os.ReadFile(from)
+	if err != nil {
+		return err
+	}
+	b, err = config.ResolveSecretRefs(b, constants.SecretsPath, to)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(to, b, 0o600)
+}
+
 func copyFileWithExist(from, to string) error {
 	if isFileExists(from) {
-		return copyFile(from, to)
+		return copyConfigFile(from, to)
 	}
 	return nil
 }
@@ -56,7 +155,7 @@ func copyFiles(cfg Config) error {
 	if err := os.Remove(constants.ServerPropsPath); err != nil && !os.IsNotExist(err) {
 		return err
 	}
-	if err := copyFile(serverPropsPath, constants.ServerPropsPath); err != nil {
+	if err := copyConfigFile(serverPropsPath, constants.ServerPropsPath); err != nil {
 		return err
 	}
 
@@ -76,7 +175,7 @@ func copyFiles(cfg Config) error {
 			needs: true,
 			isBin: false,
 			from:  filepath.Join(constants.ConfigPath, constants.BanPlayerName),
-			to:    constants.BanIPPath,
+			to:    constants.BanPlayerPath,
 		},
 		{
 			needs: true,
@@ -90,12 +189,6 @@ func copyFiles(cfg Config) error {
 			from:  filepath.Join(constants.ConfigPath, constants.WhiteListName),
 			to:    constants.WhiteListPath,
 		},
-		{
-			needs: true,
-			isBin: false,
-			from:  filepath.Join(constants.ConfigPath, constants.WhiteListName),
-			to:    constants.WhiteListPath,
-		},
 		{
 			needs: cfg.EnableLazyMC,
 			isBin: true,
@@ -2,12 +2,14 @@ package e2e
 
 import (
 	"context"
+	"errors"
 	"net"
 	"strconv"
 	"time"
 
 	"github.com/Tnze/go-mc/bot"
 	"github.com/Tnze/go-mc/bot/basic"
+	mcnet "github.com/Tnze/go-mc/net"
 )
 
 // mcConnect attempts to connect to a Minecraft server using the Minecraft protocol.
@@ -67,3 +69,43 @@ func mcConnect(host string, port int, playerName string) error {
 func mcTriggerServerStart(host string, port int) error {
 	return mcConnect(host, port, "E2ETestPlayer")
 }
+
+// routerDialer connects to mc-router whatever the server address is,
+// so that the handshake carries the hostname routed by mc-router.
+type routerDialer struct {
+	addr string
+}
+
+func (d routerDialer) DialMCContext(ctx context.Context, _ string) (*mcnet.Conn, error) {
+	//nolint:exhaustruct // Only Timeout is needed
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", d.addr)
+	if err != nil {
+		return nil, err
+	}
+	return mcnet.WrapConn(conn), nil
+}
+
+// mcConnectViaRouter sends a login request for hostname to mc-router listening on routerAddr.
+// This triggers mc-router to scale up the StatefulSet of a hibernated server.
+func mcConnectViaRouter(routerAddr, hostname string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client := bot.NewClient()
+	client.Auth.Name = "E2ETestPlayer"
+
+	//nolint:exhaustruct // Only MCDialer and Context are needed
+	err := client.JoinServerWithOptions(net.JoinHostPort(hostname, "25565"), bot.JoinOptions{
+		MCDialer: routerDialer{addr: routerAddr},
+		Context:  ctx,
+	})
+	var loginErr bot.LoginErr
+	if errors.As(err, &loginErr) && loginErr.Stage == "connect server" {
+		return err
+	}
+	// mc-router holds the login request until the server is ready, so the login may time out.
+	return nil
+}
@@ -1,45 +1,58 @@
 package e2e
 
 import (
+	"context"
 	_ "embed"
 	"encoding/json"
 	"fmt"
 
 	. "github.com/onsi/ginkgo/v2" //nolint:revive // dot imports for tests
 	. "github.com/onsi/gomega"    //nolint:revive // dot imports for tests
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
 	"github.com/kmdkuk/mcing/pkg/constants"
 )
 
 //go:embed testdata/mc-router-minecraft.yaml.tmpl
 var mcRouterMinecraftYAML string
 
+//go:embed testdata/mc-router-gateway.yaml
+var mcRouterGatewayYAML string
+
 func testMCRouter() {
 	const (
+		gatewayName   = "default"
 		gatewayNS     = "mcing-gateway"
+		routerName    = "mc-router-" + gatewayName
 		testNS        = "default"
 		defaultDomain = "minecraft.local"
 	)
 
 	It("should deploy mc-router gateway", func() {
+		By("Creating a MinecraftGateway")
+		kubectlSafeWithInput([]byte(mcRouterGatewayYAML), "apply", "-f", "-")
+
 		By("Verifying mc-router deployment exists")
 		Eventually(func(g Gomega) {
-			stdout, stderr, err := kubectl("get", "deployment", "mc-router", "-n", gatewayNS, "-o", "json")
+			stdout, stderr, err := kubectl("get", "deployment", routerName, "-n", gatewayNS, "-o", "json")
 			g.Expect(err).ShouldNot(HaveOccurred(), "stdout: %s, stderr: %s", stdout, stderr)
 		}).Should(Succeed())
 
 		By("Verifying mc-router service exists")
 		Eventually(func(g Gomega) {
-			stdout, stderr, err := kubectl("get", "service", "mc-router", "-n", gatewayNS, "-o", "json")
+			stdout, stderr, err := kubectl("get", "service", routerName, "-n", gatewayNS, "-o", "json")
 			g.Expect(err).ShouldNot(HaveOccurred(), "stdout: %s, stderr: %s", stdout, stderr)
 		}).Should(Succeed())
 
 		By("Waiting for mc-router deployment to be ready")
-		waitDeployment(gatewayNS, "mc-router", 1)
+		waitDeployment(gatewayNS, routerName, 1)
 	})
 
-	It("should add mc-router annotation to Minecraft service", func() {
+	It("should register the hostname of the Minecraft server on mc-router", func() {
 		name := "mc-router-test"
 		stsName := "mcing-" + name
 		data := map[string]any{
@@ -55,19 +68,13 @@ func testMCRouter() {
 
 		waitStatefullSet(testNS, stsName, 1)
 
-		By("Verifying service has mc-router annotation")
+		By("Verifying mc-router routes the hostname to the service")
 		Eventually(func(g Gomega) {
-			stdout, stderr, err := kubectl("get", "service", stsName, "-n", testNS, "-o", "json")
-			g.Expect(err).ShouldNot(HaveOccurred(), "stdout: %s, stderr: %s", stdout, stderr)
-
-			svc := &corev1.Service{}
-			err = json.Unmarshal(stdout, svc)
-			g.Expect(err).ShouldNot(HaveOccurred())
-
-			annotation, ok := svc.Annotations[constants.MCRouterAnnotation]
-			g.Expect(ok).Should(BeTrue(), "mc-router annotation should exist")
+			cond := routedCondition(g, testNS, name)
+			g.Expect(cond).ShouldNot(BeNil())
+			g.Expect(cond.Status).Should(Equal(metav1.ConditionTrue))
 			expectedFQDN := fmt.Sprintf("%s.%s.%s", name, testNS, defaultDomain)
-			g.Expect(annotation).Should(Equal(expectedFQDN))
+			g.Expect(cond.Message).Should(ContainSubstring(expectedFQDN + " to " + stsName + "." + testNS + ".svc"))
 		}).Should(Succeed())
 
 		By("Verifying service is ClusterIP type")
@@ -101,17 +108,128 @@ func testMCRouter() {
 
 		waitStatefullSet(testNS, stsName, 1)
 
-		By("Verifying service uses custom hostname in annotation")
+		By("Verifying mc-router routes the custom hostname")
 		Eventually(func(g Gomega) {
-			stdout, stderr, err := kubectl("get", "service", stsName, "-n", testNS, "-o", "json")
+			cond := routedCondition(g, testNS, name)
+			g.Expect(cond).ShouldNot(BeNil())
+			g.Expect(cond.Status).Should(Equal(metav1.ConditionTrue))
+			g.Expect(cond.Message).Should(ContainSubstring(customHostname + " to "))
+		}).Should(Succeed())
+	})
+
+	It("should wake a hibernated server through the gateway", func() {
+		name := "mc-router-wake"
+		stsName := "mcing-" + name
+		data := map[string]any{
+			"Name":        name,
+			"Namespace":   testNS,
+			"Hibernation": true,
+		}
+		manifest := renderTemplate(mcRouterMinecraftYAML, data)
+		kubectlSafeWithInput(manifest, "apply", "-f", "-")
+
+		defer func() {
+			kubectlSafeWithInput(manifest, "delete", "-f", "-")
+		}()
+
+		waitStatefullSet(testNS, stsName, 1)
+
+		By("Scaling the StatefulSet to zero")
+		kubectlSafe("scale", "statefulset", stsName, "-n", testNS, "--replicas=0")
+		Eventually(func(g Gomega) {
+			stdout, stderr, err := kubectl("get", "statefulset", stsName, "-n", testNS, "-o", "json")
 			g.Expect(err).ShouldNot(HaveOccurred(), "stdout: %s, stderr: %s", stdout, stderr)
 
-			svc := &corev1.Service{}
-			err = json.Unmarshal(stdout, svc)
+			sts := &appsv1.StatefulSet{}
+			err = json.Unmarshal(stdout, sts)
 			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(sts.Status.Replicas).Should(BeZero())
+		}).Should(Succeed())
+
+		By("Connecting to the server through mc-router")
+		portForwardCmd, localPort, err := PortForwardCmd(
+			context.Background(),
+			gatewayNS,
+			"service/"+routerName,
+			int(constants.MCRouterPort),
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		portForwardCmd.Stdout = GinkgoWriter
+		portForwardCmd.Stderr = GinkgoWriter
+		Expect(portForwardCmd.Start()).Should(Succeed())
+		defer func() {
+			if portForwardCmd.Process != nil {
+				_ = portForwardCmd.Process.Kill()
+			}
+		}()
+
+		routerAddr := fmt.Sprintf("127.0.0.1:%d", localPort)
+		hostname := fmt.Sprintf("%s.%s.%s", name, testNS, defaultDomain)
+		Eventually(func(g Gomega) {
+			g.Expect(mcConnectViaRouter(routerAddr, hostname)).Should(Succeed())
 
-			annotation := svc.Annotations[constants.MCRouterAnnotation]
-			g.Expect(annotation).Should(Equal(customHostname))
+			stdout, stderr, err := kubectl("get", "statefulset", stsName, "-n", testNS, "-o", "json")
+			g.Expect(err).ShouldNot(HaveOccurred(), "stdout: %s, stderr: %s", stdout, stderr)
+
+			sts := &appsv1.StatefulSet{}
+			err = json.Unmarshal(stdout, sts)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(sts.Spec.Replicas).ShouldNot(BeNil())
+			g.Expect(*sts.Spec.Replicas).Should(Equal(int32(1)))
 		}).Should(Succeed())
+
+		By("Verifying the server is woken up")
+		waitStatefullSet(testNS, stsName, 1)
 	})
+
+	It("should clean up mc-router when the gateway is deleted", func() {
+		name := "mc-router-cleanup"
+		stsName := "mcing-" + name
+		data := map[string]any{
+			"Name":      name,
+			"Namespace": testNS,
+		}
+		manifest := renderTemplate(mcRouterMinecraftYAML, data)
+		kubectlSafeWithInput(manifest, "apply", "-f", "-")
+
+		defer func() {
+			kubectlSafeWithInput(manifest, "delete", "-f", "-")
+		}()
+
+		waitStatefullSet(testNS, stsName, 1)
+
+		By("Deleting the MinecraftGateway")
+		kubectlSafeWithInput([]byte(mcRouterGatewayYAML), "delete", "-f", "-")
+
+		By("Verifying the resources of mc-router are removed")
+		Eventually(func(g Gomega) {
+			for _, kind := range []string{"deployment", "service", "serviceaccount"} {
+				_, stderr, err := kubectl("get", kind, routerName, "-n", gatewayNS)
+				g.Expect(err).Should(HaveOccurred(), "%s should be removed", kind)
+				g.Expect(string(stderr)).Should(ContainSubstring("NotFound"))
+			}
+		}).Should(Succeed())
+
+		By("Verifying the namespace of mc-router is removed")
+		Eventually(func(g Gomega) {
+			_, stderr, err := kubectl("get", "namespace", gatewayNS)
+			g.Expect(err).Should(HaveOccurred())
+			g.Expect(string(stderr)).Should(ContainSubstring("NotFound"))
+		}).Should(Succeed())
+
+		By("Verifying the Routed condition is removed from the Minecraft")
+		Eventually(func(g Gomega) {
+			g.Expect(routedCondition(g, testNS, name)).Should(BeNil())
+		}).Should(Succeed())
+	})
+}
+
+// routedCondition returns the Routed condition of the Minecraft, or nil if it has none.
+func routedCondition(g Gomega, namespace, name string) *metav1.Condition {
+	stdout, stderr, err := kubectl("get", "minecraft", name, "-n", namespace, "-o", "json")
+	g.Expect(err).ShouldNot(HaveOccurred(), "stdout: %s, stderr: %s", stdout, stderr)
+
+	mc := &mcingv1alpha1.Minecraft{}
+	g.Expect(json.Unmarshal(stdout, mc)).Should(Succeed())
+	return meta.FindStatusCondition(mc.Status.Conditions, mcingv1alpha1.ConditionRouted)
 }
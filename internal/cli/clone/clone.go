@@ -0,0 +1,127 @@
+package clone
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
+	"github.com/kmdkuk/mcing/pkg/constants"
+)
+
+// Options struct for holding clone command options.
+type Options struct {
+	Namespace      string
+	SourceName     string
+	Name           string
+	Snapshot       string
+	StripOps       bool
+	StripWhitelist bool
+}
+
+// NewOptions creates a new Options struct.
+func NewOptions() *Options {
+	return &Options{
+		Namespace:      "",
+		SourceName:     "",
+		Name:           "",
+		Snapshot:       "",
+		StripOps:       false,
+		StripWhitelist: false,
+	}
+}
+
+// Complete completes validation of the options.
+func (o *Options) Complete(args []string) error {
+	o.SourceName = args[0]
+	o.Name = args[1]
+	if o.SourceName == o.Name {
+		return fmt.Errorf("cannot clone minecraft/%s into itself", o.Name)
+	}
+	return nil
+}
+
+// Cloner creates a Minecraft from the spec and the data of another Minecraft.
+type Cloner struct {
+	Options *Options
+
+	k8sClient client.Client
+	out       io.Writer
+}
+
+// NewCloner creates a new Cloner struct.
+func NewCloner(opts *Options, k8sClient client.Client, out io.Writer) *Cloner {
+	return &Cloner{
+		Options:   opts,
+		k8sClient: k8sClient,
+		out:       out,
+	}
+}
+
+// Run creates the clone. The controller seeds its data volume before the first start.
+func (c *Cloner) Run(ctx context.Context) error {
+	var src mcingv1alpha1.Minecraft
+	err := c.k8sClient.Get(
+		ctx,
+		types.NamespacedName{Namespace: c.Options.Namespace, Name: c.Options.SourceName},
+		&src,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get Minecraft resource: %w", err)
+	}
+
+	mc := &mcingv1alpha1.Minecraft{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: c.Options.Namespace,
+			Name:      c.Options.Name,
+		},
+		Spec: *c.cloneSpec(&src.Spec),
+	}
+	if err := c.k8sClient.Create(ctx, mc); err != nil {
+		return fmt.Errorf("failed to create Minecraft resource: %w", err)
+	}
+
+	from := "minecraft/" + src.Name
+	if c.Options.Snapshot != "" {
+		from = "volumesnapshot/" + c.Options.Snapshot
+	}
+	_, _ = fmt.Fprintf(c.out, "minecraft/%s created from %s\n", mc.Name, from)
+	return nil
+}
+
+// cloneSpec returns the spec of the clone.
+// The hostnames are not copied, because a hostname cannot be used by two Minecrafts.
+func (c *Cloner) cloneSpec(src *mcingv1alpha1.MinecraftSpec) *mcingv1alpha1.MinecraftSpec {
+	spec := src.DeepCopy()
+	spec.ExternalHostname = nil
+	spec.HostnameAliases = nil
+
+	spec.DataSource = &mcingv1alpha1.DataSource{
+		StripOps:       c.Options.StripOps,
+		StripWhitelist: c.Options.StripWhitelist,
+	}
+	if c.Options.Snapshot != "" {
+		spec.DataSource.VolumeSnapshot = c.Options.Snapshot
+	} else {
+		spec.DataSource.Minecraft = c.Options.SourceName
+	}
+	// The source may have been restored from a snapshot itself.
+	for i := range spec.VolumeClaimTemplates {
+		if spec.VolumeClaimTemplates[i].Name == constants.DataVolumeName {
+			spec.VolumeClaimTemplates[i].Spec.DataSource = nil
+			spec.VolumeClaimTemplates[i].Spec.DataSourceRef = nil
+		}
+	}
+
+	if c.Options.StripOps {
+		spec.Ops.Users = nil
+	}
+	if c.Options.StripWhitelist {
+		spec.Whitelist.Users = nil
+	}
+	return spec
+}
@@ -0,0 +1,135 @@
+package clone
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
+)
+
+//nolint:funlen // test function
+func TestCloner(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = mcingv1alpha1.AddToScheme(scheme)
+
+	tests := []struct {
+		name           string
+		args           []string
+		snapshot       string
+		stripOps       bool
+		stripWhitelist bool
+		wantDataSource *mcingv1alpha1.DataSource
+		wantOps        []string
+		wantWhitelist  []string
+		wantOutput     string
+		expectedErr    bool
+	}{
+		{
+			name:           "Clone the data volume",
+			args:           []string{"production", "staging"},
+			wantDataSource: &mcingv1alpha1.DataSource{Minecraft: "production"},
+			wantOps:        []string{"admin"},
+			wantWhitelist:  []string{"player"},
+			wantOutput:     "minecraft/staging created from minecraft/production\n",
+		},
+		{
+			name:           "Clone from a snapshot",
+			args:           []string{"production", "staging"},
+			snapshot:       "world",
+			wantDataSource: &mcingv1alpha1.DataSource{VolumeSnapshot: "world"},
+			wantOps:        []string{"admin"},
+			wantWhitelist:  []string{"player"},
+			wantOutput:     "minecraft/staging created from volumesnapshot/world\n",
+		},
+		{
+			name:           "Strip ops and whitelist",
+			args:           []string{"production", "staging"},
+			stripOps:       true,
+			stripWhitelist: true,
+			wantDataSource: &mcingv1alpha1.DataSource{Minecraft: "production", StripOps: true, StripWhitelist: true},
+			wantOutput:     "minecraft/staging created from minecraft/production\n",
+		},
+		{
+			name:        "Source not found",
+			args:        []string{"missing", "staging"},
+			expectedErr: true,
+		},
+		{
+			name:        "Destination exists",
+			args:        []string{"production", "existing"},
+			expectedErr: true,
+		},
+		{
+			name:        "Clone into itself",
+			args:        []string{"production", "production"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := &mcingv1alpha1.Minecraft{
+				ObjectMeta: metav1.ObjectMeta{Name: "production", Namespace: "default"},
+				Spec: mcingv1alpha1.MinecraftSpec{
+					VolumeClaimTemplates: []mcingv1alpha1.PersistentVolumeClaim{{
+						ObjectMeta: mcingv1alpha1.ObjectMeta{Name: "minecraft-data"},
+						Spec: corev1.PersistentVolumeClaimSpec{
+							DataSource: &corev1.TypedLocalObjectReference{
+								APIGroup: ptr.To("snapshot.storage.k8s.io"),
+								Kind:     "VolumeSnapshot",
+								Name:     "restored",
+							},
+						},
+					}},
+					Ops:              mcingv1alpha1.Ops{Users: []string{"admin"}},
+					Whitelist:        mcingv1alpha1.Whitelist{Enabled: true, Users: []string{"player"}},
+					ExternalHostname: ptr.To("play.example.com"),
+					HostnameAliases:  []string{"mc.example.com"},
+				},
+			}
+			existing := &mcingv1alpha1.Minecraft{
+				ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"},
+			}
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(src, existing).Build()
+
+			opts := NewOptions()
+			opts.Namespace = "default"
+			opts.Snapshot = tt.snapshot
+			opts.StripOps = tt.stripOps
+			opts.StripWhitelist = tt.stripWhitelist
+
+			var out bytes.Buffer
+			err := opts.Complete(tt.args)
+			if err == nil {
+				err = NewCloner(opts, k8sClient, &out).Run(context.Background())
+			}
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantOutput, out.String())
+
+			mc := &mcingv1alpha1.Minecraft{}
+			require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{
+				Namespace: "default", Name: "staging",
+			}, mc))
+			require.Equal(t, tt.wantDataSource, mc.Spec.DataSource)
+			require.Equal(t, tt.wantOps, mc.Spec.Ops.Users)
+			require.Equal(t, tt.wantWhitelist, mc.Spec.Whitelist.Users)
+			require.True(t, mc.Spec.Whitelist.Enabled)
+			require.Nil(t, mc.Spec.ExternalHostname)
+			require.Empty(t, mc.Spec.HostnameAliases)
+			require.Nil(t, mc.Spec.VolumeClaimTemplates[0].Spec.DataSource)
+		})
+	}
+}
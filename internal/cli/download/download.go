@@ -177,7 +177,7 @@ func (d *Downloader) checkSleepingAndWarn(
 // For example, Status.Phase should indicate "Sleeping" or similar.
 // Then, this CLI command can just check the status instead of executing pgrep.
 func (d *Downloader) isServerSleeping(ctx context.Context, mc *mcingv1alpha1.Minecraft) (bool, error) {
-	if mc.Spec.AutoPause.Enabled != nil && !*mc.Spec.AutoPause.Enabled {
+	if !mc.AutoPauseEnabled() {
 		return false, nil
 	}
 
@@ -0,0 +1,212 @@
+package power
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
+	"github.com/kmdkuk/mcing/pkg/constants"
+	"github.com/kmdkuk/mcing/pkg/kube"
+	agent "github.com/kmdkuk/mcing/pkg/proto"
+)
+
+const (
+	defaultPollInterval = 2 * time.Second
+	resumeTimeout       = 10 * time.Minute
+)
+
+// AgentClientFactory is a function to create an agent client.
+type AgentClientFactory func(port int) (agent.AgentClient, func() error, error)
+
+func defaultAgentClientFactory(port int) (agent.AgentClient, func() error, error) {
+	conn, err := grpc.NewClient(
+		fmt.Sprintf("127.0.0.1:%d", port),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return agent.NewAgentClient(conn), conn.Close, nil
+}
+
+// Options struct for holding wake and sleep command options.
+type Options struct {
+	Namespace     string
+	MinecraftName string
+}
+
+// NewOptions creates a new Options struct.
+func NewOptions() *Options {
+	return &Options{
+		Namespace:     "",
+		MinecraftName: "",
+	}
+}
+
+// Complete completes validation of the options.
+func (o *Options) Complete(args []string) error {
+	o.MinecraftName = args[0]
+	return nil
+}
+
+// Switcher wakes up or puts to sleep a Minecraft server managed by lazymc.
+type Switcher struct {
+	Options *Options
+
+	k8sClient    client.Client
+	kubeExecutor kube.Executor
+	agentFactory AgentClientFactory
+	out          io.Writer
+	pollInterval time.Duration
+}
+
+// NewSwitcher creates a new Switcher struct.
+func NewSwitcher(
+	opts *Options,
+	k8sClient client.Client,
+	kubeExecutor kube.Executor,
+	out io.Writer,
+) *Switcher {
+	return &Switcher{
+		Options:      opts,
+		k8sClient:    k8sClient,
+		kubeExecutor: kubeExecutor,
+		agentFactory: defaultAgentClientFactory,
+		out:          out,
+		pollInterval: defaultPollInterval,
+	}
+}
+
+// Wake starts the server process and waits until it is ready.
+// A hibernated server is scaled back to one first.
+func (s *Switcher) Wake(ctx context.Context) error {
+	mc, err := s.getMinecraft(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.resume(ctx, mc); err != nil {
+		return err
+	}
+	return s.withAgent(ctx, mc, func(mc *mcingv1alpha1.Minecraft, agentClient agent.AgentClient) error {
+		res, err := agentClient.Wake(ctx, &agent.WakeRequest{})
+		if err != nil {
+			return fmt.Errorf("failed to wake %s: %w", mc.Name, err)
+		}
+		if res.GetWasSleeping() {
+			_, _ = fmt.Fprintf(s.out, "minecraft/%s woke up\n", mc.Name)
+		} else {
+			_, _ = fmt.Fprintf(s.out, "minecraft/%s is already running\n", mc.Name)
+		}
+		return nil
+	})
+}
+
+// Sleep stops the server process. lazymc starts it again when a player joins.
+func (s *Switcher) Sleep(ctx context.Context) error {
+	mc, err := s.getMinecraft(ctx)
+	if err != nil {
+		return err
+	}
+	return s.withAgent(ctx, mc, func(mc *mcingv1alpha1.Minecraft, agentClient agent.AgentClient) error {
+		if !mc.AutoPauseEnabled() {
+			return fmt.Errorf("minecraft/%s does not enable autoPause", mc.Name)
+		}
+		res, err := agentClient.Sleep(ctx, &agent.SleepRequest{})
+		if err != nil {
+			return fmt.Errorf("failed to put %s to sleep: %w", mc.Name, err)
+		}
+		if res.GetWasSleeping() {
+			_, _ = fmt.Fprintf(s.out, "minecraft/%s is already sleeping\n", mc.Name)
+		} else {
+			_, _ = fmt.Fprintf(s.out, "minecraft/%s is going to sleep\n", mc.Name)
+		}
+		return nil
+	})
+}
+
+func (s *Switcher) getMinecraft(ctx context.Context) (*mcingv1alpha1.Minecraft, error) {
+	var mc mcingv1alpha1.Minecraft
+	err := s.k8sClient.Get(
+		ctx,
+		types.NamespacedName{Namespace: s.Options.Namespace, Name: s.Options.MinecraftName},
+		&mc,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Minecraft resource: %w", err)
+	}
+	return &mc, nil
+}
+
+// resume scales a hibernated StatefulSet back to one and waits until the pod is ready.
+func (s *Switcher) resume(ctx context.Context, mc *mcingv1alpha1.Minecraft) error {
+	sts := &appsv1.StatefulSet{}
+	if err := s.k8sClient.Get(ctx, types.NamespacedName{Namespace: mc.Namespace, Name: mc.PrefixedName()}, sts); err != nil {
+		return fmt.Errorf("failed to get StatefulSet: %w", err)
+	}
+	if sts.Spec.Replicas == nil || *sts.Spec.Replicas != 0 {
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(s.out, "minecraft/%s is hibernating, scaling it up\n", mc.Name)
+	patch := client.MergeFrom(sts.DeepCopy())
+	sts.Spec.Replicas = ptr.To[int32](1)
+	if err := s.k8sClient.Patch(ctx, sts, patch); err != nil {
+		return fmt.Errorf("failed to scale StatefulSet: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, resumeTimeout)
+	defer cancel()
+	return wait.PollUntilContextCancel(ctx, s.pollInterval, true, func(ctx context.Context) (bool, error) {
+		pod := &corev1.Pod{}
+		err := s.k8sClient.Get(ctx, types.NamespacedName{Namespace: mc.Namespace, Name: mc.PodName()}, pod)
+		if err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+}
+
+func (s *Switcher) withAgent(
+	ctx context.Context,
+	mc *mcingv1alpha1.Minecraft,
+	fn func(mc *mcingv1alpha1.Minecraft, agentClient agent.AgentClient) error,
+) error {
+	localPort, stopCh, err := s.kubeExecutor.PortForward(
+		s.Options.Namespace,
+		mc.PodName(),
+		int(constants.AgentPort),
+		nil,
+		os.Stderr,
+	)
+	if err != nil {
+		return err
+	}
+	defer close(stopCh)
+
+	agentClient, closeConn, err := s.agentFactory(localPort)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = closeConn()
+	}()
+
+	return fn(mc, agentClient)
+}
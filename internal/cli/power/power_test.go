@@ -0,0 +1,244 @@
+package power
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
+	agent "github.com/kmdkuk/mcing/pkg/proto"
+)
+
+// MockKubeExecutor mocks kube.Executor.
+type MockKubeExecutor struct {
+	mock.Mock
+}
+
+//nolint:errcheck // mock implementation
+func (m *MockKubeExecutor) PortForward(
+	namespace, podName string,
+	remotePort int,
+	out, errOut io.Writer,
+) (int, chan struct{}, error) {
+	args := m.Called(namespace, podName, remotePort, out, errOut)
+	return args.Int(0), args.Get(1).(chan struct{}), args.Error(2)
+}
+
+func (m *MockKubeExecutor) Exec(
+	ctx context.Context,
+	namespace, podName, container string,
+	cmd []string,
+	stdin io.Reader,
+	out, errOut io.Writer,
+) error {
+	args := m.Called(ctx, namespace, podName, container, cmd, stdin, out, errOut)
+	return args.Error(0)
+}
+
+// MockAgentClient mocks AgentClient.
+type MockAgentClient struct {
+	mock.Mock
+	agent.AgentClient // Embed interface
+}
+
+//nolint:errcheck // mock implementation
+func (m *MockAgentClient) Wake(
+	ctx context.Context,
+	in *agent.WakeRequest,
+	opts ...grpc.CallOption,
+) (*agent.WakeResponse, error) {
+	args := m.Called(ctx, in, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*agent.WakeResponse), args.Error(1)
+}
+
+//nolint:errcheck // mock implementation
+func (m *MockAgentClient) Sleep(
+	ctx context.Context,
+	in *agent.SleepRequest,
+	opts ...grpc.CallOption,
+) (*agent.SleepResponse, error) {
+	args := m.Called(ctx, in, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*agent.SleepResponse), args.Error(1)
+}
+
+//nolint:funlen // test function
+func TestSwitcher(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = mcingv1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+
+	enabled := true
+	disabled := false
+
+	tests := []struct {
+		name        string
+		autoPause   *bool
+		hibernated  bool
+		sleep       bool
+		setupMocks  func(*MockKubeExecutor, *MockAgentClient)
+		wantOutput  string
+		expectedErr bool
+	}{
+		{
+			name:      "Wake sleeping server",
+			autoPause: &enabled,
+			setupMocks: func(mk *MockKubeExecutor, ma *MockAgentClient) {
+				mk.On("PortForward", "default", "mcing-test-mc-0", 9080, mock.Anything, mock.Anything).
+					Return(12345, make(chan struct{}), nil)
+				ma.On("Wake", mock.Anything, mock.Anything, mock.Anything).
+					Return(&agent.WakeResponse{WasSleeping: true}, nil)
+			},
+			wantOutput: "minecraft/test-mc woke up\n",
+		},
+		{
+			name:       "Wake hibernated server",
+			autoPause:  &enabled,
+			hibernated: true,
+			setupMocks: func(mk *MockKubeExecutor, ma *MockAgentClient) {
+				mk.On("PortForward", "default", "mcing-test-mc-0", 9080, mock.Anything, mock.Anything).
+					Return(12345, make(chan struct{}), nil)
+				ma.On("Wake", mock.Anything, mock.Anything, mock.Anything).
+					Return(&agent.WakeResponse{WasSleeping: false}, nil)
+			},
+			wantOutput: "minecraft/test-mc is hibernating, scaling it up\nminecraft/test-mc is already running\n",
+		},
+		{
+			name:      "Wake running server",
+			autoPause: &disabled,
+			setupMocks: func(mk *MockKubeExecutor, ma *MockAgentClient) {
+				mk.On("PortForward", "default", "mcing-test-mc-0", 9080, mock.Anything, mock.Anything).
+					Return(12345, make(chan struct{}), nil)
+				ma.On("Wake", mock.Anything, mock.Anything, mock.Anything).
+					Return(&agent.WakeResponse{WasSleeping: false}, nil)
+			},
+			wantOutput: "minecraft/test-mc is already running\n",
+		},
+		{
+			name:      "Wake failure",
+			autoPause: &enabled,
+			setupMocks: func(mk *MockKubeExecutor, ma *MockAgentClient) {
+				mk.On("PortForward", "default", "mcing-test-mc-0", 9080, mock.Anything, mock.Anything).
+					Return(12345, make(chan struct{}), nil)
+				ma.On("Wake", mock.Anything, mock.Anything, mock.Anything).
+					Return(nil, errors.New("deadline exceeded"))
+			},
+			expectedErr: true,
+		},
+		{
+			name:      "PortForward failure",
+			autoPause: &enabled,
+			setupMocks: func(mk *MockKubeExecutor, _ *MockAgentClient) {
+				mk.On("PortForward", "default", "mcing-test-mc-0", 9080, mock.Anything, mock.Anything).
+					Return(0, (chan struct{})(nil), errors.New("portforward failed"))
+			},
+			expectedErr: true,
+		},
+		{
+			name:      "Sleep running server",
+			autoPause: &enabled,
+			sleep:     true,
+			setupMocks: func(mk *MockKubeExecutor, ma *MockAgentClient) {
+				mk.On("PortForward", "default", "mcing-test-mc-0", 9080, mock.Anything, mock.Anything).
+					Return(12345, make(chan struct{}), nil)
+				ma.On("Sleep", mock.Anything, mock.Anything, mock.Anything).
+					Return(&agent.SleepResponse{WasSleeping: false}, nil)
+			},
+			wantOutput: "minecraft/test-mc is going to sleep\n",
+		},
+		{
+			name:      "Sleep without autoPause",
+			autoPause: &disabled,
+			sleep:     true,
+			setupMocks: func(mk *MockKubeExecutor, _ *MockAgentClient) {
+				mk.On("PortForward", "default", "mcing-test-mc-0", 9080, mock.Anything, mock.Anything).
+					Return(12345, make(chan struct{}), nil)
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := &mcingv1alpha1.Minecraft{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-mc",
+					Namespace: "default",
+				},
+				Spec: mcingv1alpha1.MinecraftSpec{
+					AutoPause: mcingv1alpha1.AutoPause{
+						Enabled: tt.autoPause,
+					},
+				},
+			}
+			replicas := int32(1)
+			if tt.hibernated {
+				replicas = 0
+			}
+			sts := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: mc.PrefixedName(), Namespace: mc.Namespace},
+				Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To(replicas)},
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: mc.PodName(), Namespace: mc.Namespace},
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+				},
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(mc, sts, pod).
+				Build()
+
+			mockKube := new(MockKubeExecutor)
+			mockAgent := new(MockAgentClient)
+			tt.setupMocks(mockKube, mockAgent)
+
+			var out bytes.Buffer
+			s := NewSwitcher(&Options{Namespace: "default", MinecraftName: "test-mc"}, fakeClient, mockKube, &out)
+			s.pollInterval = time.Millisecond
+			s.agentFactory = func(_ int) (agent.AgentClient, func() error, error) {
+				return mockAgent, func() error { return nil }, nil
+			}
+
+			var err error
+			if tt.sleep {
+				err = s.Sleep(context.Background())
+			} else {
+				err = s.Wake(context.Background())
+			}
+			if tt.expectedErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantOutput, out.String())
+			}
+			mockKube.AssertExpectations(t)
+			mockAgent.AssertExpectations(t)
+
+			got := &appsv1.StatefulSet{}
+			require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(sts), got))
+			require.Equal(t, int32(1), *got.Spec.Replicas)
+		})
+	}
+}
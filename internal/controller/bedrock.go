@@ -0,0 +1,58 @@
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
+	"github.com/kmdkuk/mcing/pkg/artifact"
+	"github.com/kmdkuk/mcing/pkg/constants"
+)
+
+// bedrockEnabled returns true if the server accepts Bedrock Edition players.
+func bedrockEnabled(mc *mcingv1alpha1.Minecraft) bool {
+	return mc.Spec.Bedrock != nil && mc.Spec.Bedrock.Enabled
+}
+
+// bedrockPlugins returns the plugins installed for spec.bedrock.
+// Without a pinned build, the latest build is downloaded without a digest.
+func bedrockPlugins(mc *mcingv1alpha1.Minecraft) []artifact.Artifact {
+	if !mc.Spec.Bedrock.InstallsPlugins() {
+		return nil
+	}
+	plugins := []artifact.Artifact{
+		bedrockPlugin(constants.GeyserPluginName, constants.GeyserPluginURL, mc.Spec.Bedrock.GeyserDownload),
+	}
+	if mc.Spec.Bedrock.Floodgate {
+		plugins = append(plugins,
+			bedrockPlugin(constants.FloodgatePluginName, constants.FloodgatePluginURL, mc.Spec.Bedrock.FloodgateDownload))
+	}
+	return plugins
+}
+
+func bedrockPlugin(name, latestURL string, pinned *mcingv1alpha1.PluginDownload) artifact.Artifact {
+	if pinned == nil {
+		return artifact.Artifact{Name: name, URL: latestURL}
+	}
+	return artifact.Artifact{Name: name, URL: pinned.URL, SHA256: pinned.SHA256}
+}
+
+// bedrockContainerPort returns the port that Geyser listens on in the minecraft container.
+func bedrockContainerPort() corev1.ContainerPort {
+	return corev1.ContainerPort{
+		ContainerPort: constants.BedrockPort,
+		Name:          constants.BedrockPortName,
+		Protocol:      corev1.ProtocolUDP,
+	}
+}
+
+// bedrockServicePort returns the Service port for Bedrock Edition players.
+func bedrockServicePort(nodePort int32) corev1.ServicePort {
+	return corev1.ServicePort{
+		Name:       constants.BedrockPortName,
+		Protocol:   corev1.ProtocolUDP,
+		Port:       constants.BedrockPort,
+		TargetPort: intstr.FromString(constants.BedrockPortName),
+		NodePort:   nodePort,
+	}
+}
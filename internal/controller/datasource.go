@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
+	"github.com/kmdkuk/mcing/pkg/constants"
+)
+
+// cloneSourceClaimName returns the name of the data PVC of the Minecraft that mc is cloned from, or "".
+func cloneSourceClaimName(mc *mcingv1alpha1.Minecraft) string {
+	if mc.Spec.DataSource == nil || mc.Spec.DataSource.Minecraft == "" {
+		return ""
+	}
+	src := &mcingv1alpha1.Minecraft{ObjectMeta: metav1.ObjectMeta{Name: mc.Spec.DataSource.Minecraft}}
+	return constants.DataVolumeName + "-" + src.PodName()
+}
+
+// dataVolumeSource returns the data source of the data PVC set by spec.dataSource, or nil.
+// A backup archive is extracted by mcing-init instead.
+func dataVolumeSource(mc *mcingv1alpha1.Minecraft) *corev1.TypedLocalObjectReference {
+	ds := mc.Spec.DataSource
+	switch {
+	case ds == nil:
+		return nil
+	case ds.Minecraft != "":
+		return &corev1.TypedLocalObjectReference{
+			Kind: "PersistentVolumeClaim",
+			Name: cloneSourceClaimName(mc),
+		}
+	case ds.VolumeSnapshot != "":
+		return &corev1.TypedLocalObjectReference{
+			APIGroup: ptr.To(constants.VolumeSnapshotGroup),
+			Kind:     constants.VolumeSnapshotKind,
+			Name:     ds.VolumeSnapshot,
+		}
+	}
+	return nil
+}
+
+// cloneSourceReady returns whether the data PVC of the Minecraft in spec.dataSource exists to be cloned.
+// As with restoreSnapshotReady, it is checked only before the StatefulSet is created.
+func (r *MinecraftReconciler) cloneSourceReady(ctx context.Context, mc *mcingv1alpha1.Minecraft) (bool, error) {
+	claim := cloneSourceClaimName(mc)
+	if claim == "" {
+		return true, nil
+	}
+	err := r.Get(ctx, client.ObjectKey{Namespace: mc.Namespace, Name: mc.PrefixedName()}, &appsv1.StatefulSet{})
+	if err == nil {
+		return true, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return false, err
+	}
+
+	source := mc.Spec.DataSource.Minecraft
+	cond := metav1.Condition{
+		Type:               mcingv1alpha1.ConditionCloneSourceReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Ready",
+		Message:            fmt.Sprintf("PVC %s of Minecraft %s is ready to clone", claim, source),
+		ObservedGeneration: mc.Generation,
+	}
+	pvc := &corev1.PersistentVolumeClaim{}
+	err = r.Get(ctx, client.ObjectKey{Namespace: mc.Namespace, Name: claim}, pvc)
+	switch {
+	case apierrors.IsNotFound(err):
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "SourceNotFound"
+		cond.Message = fmt.Sprintf("PVC %s of Minecraft %s is not found", claim, source)
+	case err != nil:
+		return false, err
+	case pvc.Status.Phase != corev1.ClaimBound:
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "SourceNotBound"
+		cond.Message = fmt.Sprintf("PVC %s of Minecraft %s is not bound yet", claim, source)
+	}
+
+	if meta.SetStatusCondition(&mc.Status.Conditions, cond) {
+		if err := r.Status().Update(ctx, mc); err != nil {
+			return false, err
+		}
+	}
+	return cond.Status == metav1.ConditionTrue, nil
+}
@@ -1,22 +1,33 @@
 package controller
 
 import (
+	"cmp"
 	"context"
+	"slices"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
+	"github.com/kmdkuk/mcing/pkg/config"
 	"github.com/kmdkuk/mcing/pkg/constants"
+	"github.com/kmdkuk/mcing/pkg/mcrouter"
 )
 
 // Gateway controller probe constants.
@@ -27,18 +38,18 @@ const (
 	gatewayLivenessPeriodSeconds        = 20
 )
 
-// GatewayConfig holds mc-router gateway configuration.
+// GatewayConfig holds the configuration shared by all mc-router gateways.
 type GatewayConfig struct {
-	Enabled           bool
-	DefaultDomain     string
-	Namespace         string
-	ServiceAccount    string
-	ServiceType       corev1.ServiceType
-	Image             string
-	ReconcileInterval time.Duration
+	// RouterFactory creates the clients of the mc-router API, which are used to register the routes.
+	RouterFactory mcrouter.Factory
+	// WakerAddress is the address of the waker that hibernated servers are routed to.
+	// Hibernated servers are routed to their Services if it is empty.
+	WakerAddress string
+	// RoutesInterval is the interval of checking the routes of mc-router.
+	RoutesInterval time.Duration
 }
 
-// GatewayReconciler reconciles the mc-router gateway.
+// GatewayReconciler reconciles a MinecraftGateway by deploying mc-router.
 type GatewayReconciler struct {
 	client.Client
 
@@ -62,55 +73,124 @@ func NewGatewayReconciler(
 	}
 }
 
+//+kubebuilder:rbac:groups=mcing.kmdkuk.com,resources=minecraftgateways,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=mcing.kmdkuk.com,resources=minecraftgateways/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=mcing.kmdkuk.com,resources=minecraftgateways/finalizers,verbs=update
+//+kubebuilder:rbac:groups=mcing.kmdkuk.com,resources=minecrafts/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch;create
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch;create;delete
 //+kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups=externaldns.k8s.io,resources=dnsendpoints,verbs=get;list;watch;create;update;patch;delete
 
-// Reconcile reconciles the mc-router gateway resources.
-func (r *GatewayReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
-	log := r.log
+// Reconcile deploys mc-router for a MinecraftGateway and reports its address and backends.
+// The resources of a deleted gateway are removed by the garbage collector through their owner references,
+// and the namespace is removed by the finalizer.
+// The controller registers the routes of the backends of the gateway on its mc-router pods,
+// so a gateway never routes the servers of another gateway.
+// The gateway is reconciled again after RoutesInterval to register the routes on restarted mc-router pods.
+func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("gateway", req.Name)
 
-	// Only reconcile if mc-router is enabled
-	if !r.config.Enabled {
+	gw := &mcingv1alpha1.MinecraftGateway{}
+	if err := r.Get(ctx, req.NamespacedName, gw); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !gw.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(gw, constants.Finalizer) {
+			return ctrl.Result{}, nil
+		}
+		if err := r.finalize(ctx, gw); err != nil {
+			log.Error(err, "failed to finalize gateway")
+			return ctrl.Result{}, err
+		}
+		controllerutil.RemoveFinalizer(gw, constants.Finalizer)
+		if err := r.Update(ctx, gw); err != nil {
+			log.Error(err, "failed to remove finalizer")
+			return ctrl.Result{}, err
+		}
 		return ctrl.Result{}, nil
 	}
 
-	// Ensure gateway namespace exists
-	if err := r.ensureNamespace(ctx); err != nil {
+	if !controllerutil.ContainsFinalizer(gw, constants.Finalizer) {
+		controllerutil.AddFinalizer(gw, constants.Finalizer)
+		if err := r.Update(ctx, gw); err != nil {
+			log.Error(err, "failed to add finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.ensureNamespace(ctx, gw); err != nil {
 		log.Error(err, "failed to ensure gateway namespace")
 		return ctrl.Result{}, err
 	}
 
-	// Ensure service account exists
-	if err := r.reconcileServiceAccount(ctx); err != nil {
+	if err := r.reconcileServiceAccount(ctx, gw); err != nil {
 		log.Error(err, "failed to reconcile service account")
 		return ctrl.Result{}, err
 	}
 
-	// Reconcile mc-router Deployment
-	if err := r.reconcileDeployment(ctx); err != nil {
+	if err := r.deleteClusterRoleBinding(ctx, gw); err != nil {
+		log.Error(err, "failed to delete cluster role binding")
+		return ctrl.Result{}, err
+	}
+
+	deploy, err := r.reconcileDeployment(ctx, gw)
+	if err != nil {
 		log.Error(err, "failed to reconcile mc-router deployment")
 		return ctrl.Result{}, err
 	}
 
-	// Reconcile mc-router Service
-	if err := r.reconcileService(ctx); err != nil {
+	backends, err := r.backends(ctx, gw)
+	if err != nil {
+		log.Error(err, "failed to list backends")
+		return ctrl.Result{}, err
+	}
+
+	svc, err := r.reconcileService(ctx, gw, backends)
+	if err != nil {
 		log.Error(err, "failed to reconcile mc-router service")
 		return ctrl.Result{}, err
 	}
 
-	// Requeue after interval to ensure gateway resources are always present
-	return ctrl.Result{RequeueAfter: r.config.ReconcileInterval}, nil
+	if err := r.updateStatus(ctx, gw, deploy, svc, backends); err != nil {
+		log.Error(err, "failed to update status")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileDNSEndpoint(ctx, gw); err != nil {
+		log.Error(err, "failed to reconcile DNSEndpoint")
+		return ctrl.Result{}, err
+	}
+
+	desired, err := r.desiredRoutes(ctx, backends)
+	if err != nil {
+		log.Error(err, "failed to list routes")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reportRoutes(ctx, gw, desired); err != nil {
+		log.Error(err, "failed to report routes")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: r.config.RoutesInterval}, nil
 }
 
-func (r *GatewayReconciler) ensureNamespace(ctx context.Context) error {
+// ensureNamespace creates the namespace of the gateway.
+// It is not owned by the gateway because other gateways and resources may share it.
+func (r *GatewayReconciler) ensureNamespace(ctx context.Context, gw *mcingv1alpha1.MinecraftGateway) error {
 	ns := &corev1.Namespace{}
-	err := r.Get(ctx, client.ObjectKey{Name: r.config.Namespace}, ns)
+	err := r.Get(ctx, client.ObjectKey{Name: gw.Spec.Namespace}, ns)
 	if apierrors.IsNotFound(err) {
 		ns = &corev1.Namespace{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:   r.config.Namespace,
-				Labels: r.gatewayLabels(),
+				Name: gw.Spec.Namespace,
+				Labels: map[string]string{
+					constants.LabelAppName:      constants.MCRouterAppName,
+					constants.LabelAppComponent: constants.MCRouterAppComponent,
+					constants.LabelAppCreatedBy: constants.ControllerName,
+				},
 			},
 		}
 		return r.Create(ctx, ns)
@@ -118,29 +198,89 @@ func (r *GatewayReconciler) ensureNamespace(ctx context.Context) error {
 	return err
 }
 
-func (r *GatewayReconciler) reconcileServiceAccount(ctx context.Context) error {
+// finalize removes what the garbage collector does not remove for a deleted gateway.
+// The namespace is deleted if the controller created it and no other gateway uses it.
+func (r *GatewayReconciler) finalize(ctx context.Context, gw *mcingv1alpha1.MinecraftGateway) error {
+	gatewayRoutedMetric.DeletePartialMatch(prometheus.Labels{"gateway": gw.Name})
+	gatewayConnectionsMetric.DeletePartialMatch(prometheus.Labels{"gateway": gw.Name})
+
+	gateways := &mcingv1alpha1.MinecraftGatewayList{}
+	if err := r.List(ctx, gateways); err != nil {
+		return err
+	}
+	for _, other := range gateways.Items {
+		if other.Name != gw.Name && other.DeletionTimestamp.IsZero() && other.Spec.Namespace == gw.Spec.Namespace {
+			return nil
+		}
+	}
+
+	ns := &corev1.Namespace{}
+	err := r.Get(ctx, client.ObjectKey{Name: gw.Spec.Namespace}, ns)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if ns.Labels[constants.LabelAppCreatedBy] != constants.ControllerName ||
+		ns.Labels[constants.LabelAppComponent] != constants.MCRouterAppComponent {
+		return nil
+	}
+	r.log.Info("deleting the namespace of the gateway", "gateway", gw.Name, "namespace", ns.Name)
+	return client.IgnoreNotFound(r.Delete(ctx, ns))
+}
+
+func (r *GatewayReconciler) reconcileServiceAccount(ctx context.Context, gw *mcingv1alpha1.MinecraftGateway) error {
 	sa := &corev1.ServiceAccount{}
-	sa.Namespace = r.config.Namespace
-	sa.Name = r.config.ServiceAccount
+	sa.Namespace = gw.Spec.Namespace
+	sa.Name = gw.PrefixedName()
 
 	_, err := ctrl.CreateOrUpdate(ctx, r.Client, sa, func() error {
-		sa.Labels = r.gatewayLabels()
-		return nil
+		sa.Labels = gatewayLabels(gw)
+		return ctrl.SetControllerReference(gw, sa, r.scheme)
 	})
 	return err
 }
 
-func (r *GatewayReconciler) reconcileDeployment(ctx context.Context) error {
+// deleteClusterRoleBinding removes the ClusterRoleBinding that older versions created to let mc-router
+// discover the Services in the cluster.
+func (r *GatewayReconciler) deleteClusterRoleBinding(ctx context.Context, gw *mcingv1alpha1.MinecraftGateway) error {
+	crb := &rbacv1.ClusterRoleBinding{}
+	err := r.Get(ctx, client.ObjectKey{Name: "mcing-" + gw.PrefixedName()}, crb)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(crb, gw) {
+		return nil
+	}
+	return client.IgnoreNotFound(r.Delete(ctx, crb))
+}
+
+func (r *GatewayReconciler) reconcileDeployment(
+	ctx context.Context,
+	gw *mcingv1alpha1.MinecraftGateway,
+) (*appsv1.Deployment, error) {
 	deploy := &appsv1.Deployment{}
-	deploy.Namespace = r.config.Namespace
-	deploy.Name = constants.MCRouterAppName
+	deploy.Namespace = gw.Spec.Namespace
+	deploy.Name = gw.PrefixedName()
 
-	labels := r.gatewayLabels()
+	labels := gatewayLabels(gw)
+	args := []string{
+		// The controller registers the routes through the API. mc-router does not discover Services by itself,
+		// because it would route every server in the cluster.
+		"--api-binding=:8080",
+		// Serve the connection metrics read by the controller on the API port.
+		"--metrics-backend=prometheus",
+	}
+	args = append(args, gw.Spec.ExtraArgs...)
 
 	_, err := ctrl.CreateOrUpdate(ctx, r.Client, deploy, func() error {
 		deploy.Labels = labels
 		deploy.Spec = appsv1.DeploymentSpec{
-			Replicas: ptr.To[int32](1),
+			Replicas: gw.Spec.Replicas,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels,
 			},
@@ -149,15 +289,15 @@ func (r *GatewayReconciler) reconcileDeployment(ctx context.Context) error {
 					Labels: labels,
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: r.config.ServiceAccount,
+					ServiceAccountName: gw.PrefixedName(),
+					NodeSelector:       gw.Spec.NodeSelector,
+					Tolerations:        gw.Spec.Tolerations,
 					Containers: []corev1.Container{
 						{
-							Name:  constants.MCRouterAppName,
-							Image: r.config.Image,
-							Args: []string{
-								"--in-kube-cluster",
-								"--api-binding=:8080",
-							},
+							Name:      constants.MCRouterAppName,
+							Image:     gw.Spec.Image,
+							Args:      args,
+							Resources: gw.Spec.Resources,
 							Ports: []corev1.ContainerPort{
 								{
 									Name:          constants.MCRouterPortName,
@@ -193,28 +333,39 @@ func (r *GatewayReconciler) reconcileDeployment(ctx context.Context) error {
 				},
 			},
 		}
-		return nil
+		return ctrl.SetControllerReference(gw, deploy, r.scheme)
 	})
-	return err
+	return deploy, err
 }
 
-func (r *GatewayReconciler) reconcileService(ctx context.Context) error {
+func (r *GatewayReconciler) reconcileService(
+	ctx context.Context,
+	gw *mcingv1alpha1.MinecraftGateway,
+	backends []mcingv1alpha1.GatewayBackend,
+) (*corev1.Service, error) {
 	svc := &corev1.Service{}
-	svc.Namespace = r.config.Namespace
-	svc.Name = constants.MCRouterAppName
+	svc.Namespace = gw.Spec.Namespace
+	svc.Name = gw.PrefixedName()
 
-	labels := r.gatewayLabels()
+	labels := gatewayLabels(gw)
 
 	_, err := ctrl.CreateOrUpdate(ctx, r.Client, svc, func() error {
 		svc.Labels = labels
-		svc.Spec.Type = r.config.ServiceType
+		// Remove the external-dns annotations set before, and set them again if they are still enabled.
+		delete(svc.Annotations, constants.ExternalDNSHostnameAnnotation)
+		delete(svc.Annotations, constants.ExternalDNSTTLAnnotation)
+		svc.Annotations = config.MergeMap(svc.Annotations, gw.Spec.Service.Annotations)
+		svc.Annotations = config.MergeMap(svc.Annotations, dnsAnnotations(gw, backends))
+		svc.Spec.Type = gw.Spec.Service.Type
 		svc.Spec.Selector = labels
 
 		// Preserve NodePort if already set
 		var serverNodePort int32
-		for _, p := range svc.Spec.Ports {
-			if p.Name == constants.MCRouterPortName {
-				serverNodePort = p.NodePort
+		if svc.Spec.Type != corev1.ServiceTypeClusterIP {
+			for _, p := range svc.Spec.Ports {
+				if p.Name == constants.MCRouterPortName {
+					serverNodePort = p.NodePort
+				}
 			}
 		}
 
@@ -227,28 +378,147 @@ func (r *GatewayReconciler) reconcileService(ctx context.Context) error {
 				NodePort:   serverNodePort,
 			},
 		}
+		return ctrl.SetControllerReference(gw, svc, r.scheme)
+	})
+	return svc, err
+}
+
+func (r *GatewayReconciler) updateStatus(
+	ctx context.Context,
+	gw *mcingv1alpha1.MinecraftGateway,
+	deploy *appsv1.Deployment,
+	svc *corev1.Service,
+	backends []mcingv1alpha1.GatewayBackend,
+) error {
+	status := mcingv1alpha1.MinecraftGatewayStatus{
+		ReadyReplicas: deploy.Status.ReadyReplicas,
+		Backends:      backends,
+	}
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			status.ExternalAddresses = append(status.ExternalAddresses, ingress.IP)
+		}
+		if ingress.Hostname != "" {
+			status.ExternalAddresses = append(status.ExternalAddresses, ingress.Hostname)
+		}
+	}
+	status.ExternalAddresses = append(status.ExternalAddresses, svc.Spec.ExternalIPs...)
+	if svc.Spec.Type == corev1.ServiceTypeNodePort {
+		for _, p := range svc.Spec.Ports {
+			if p.Name == constants.MCRouterPortName {
+				status.NodePort = p.NodePort
+			}
+		}
+	}
+
+	if equality.Semantic.DeepEqual(gw.Status, status) {
 		return nil
+	}
+	gw.Status = status
+	return r.Status().Update(ctx, gw)
+}
+
+// backends returns the Minecraft servers routed by the gateway.
+func (r *GatewayReconciler) backends(
+	ctx context.Context,
+	gw *mcingv1alpha1.MinecraftGateway,
+) ([]mcingv1alpha1.GatewayBackend, error) {
+	gateways := &mcingv1alpha1.MinecraftGatewayList{}
+	if err := r.List(ctx, gateways); err != nil {
+		return nil, err
+	}
+	mcs := &mcingv1alpha1.MinecraftList{}
+	if err := r.List(ctx, mcs); err != nil {
+		return nil, err
+	}
+
+	var backends []mcingv1alpha1.GatewayBackend
+	for i := range mcs.Items {
+		mc := &mcs.Items[i]
+		selected := selectGateway(mc, gateways.Items)
+		if selected == nil || selected.Name != gw.Name {
+			continue
+		}
+		backends = append(backends, mcingv1alpha1.GatewayBackend{
+			Namespace: mc.Namespace,
+			Name:      mc.Name,
+			Hostname:  mc.GetExternalServerName(gw.Spec.DefaultDomain),
+			Aliases:   mc.Spec.HostnameAliases,
+		})
+	}
+	slices.SortFunc(backends, func(a, b mcingv1alpha1.GatewayBackend) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
 	})
-	return err
+	return backends, nil
+}
+
+// selectGateway returns the gateway that routes to mc, or nil if there is none.
+// spec.gatewayName selects a gateway by name. Otherwise the default gateway with the smallest name is used.
+func selectGateway(
+	mc *mcingv1alpha1.Minecraft,
+	gateways []mcingv1alpha1.MinecraftGateway,
+) *mcingv1alpha1.MinecraftGateway {
+	var selected *mcingv1alpha1.MinecraftGateway
+	for i := range gateways {
+		gw := &gateways[i]
+		if !gw.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if mc.Spec.GatewayName != nil {
+			if gw.Name == *mc.Spec.GatewayName {
+				return gw
+			}
+			continue
+		}
+		if gw.Spec.Default && (selected == nil || gw.Name < selected.Name) {
+			selected = gw
+		}
+	}
+	return selected
 }
 
-func (r *GatewayReconciler) gatewayLabels() map[string]string {
+func gatewayLabels(gw *mcingv1alpha1.MinecraftGateway) map[string]string {
 	return map[string]string{
+		constants.LabelAppInstance:  gw.Name,
 		constants.LabelAppName:      constants.MCRouterAppName,
 		constants.LabelAppComponent: constants.MCRouterAppComponent,
 		constants.LabelAppCreatedBy: constants.ControllerName,
 	}
 }
 
+// enqueueGateways returns a handler that enqueues every gateway, because a change of a Minecraft
+// may move it from one gateway to another.
+func (r *GatewayReconciler) enqueueGateways() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(
+		func(ctx context.Context, _ client.Object) []reconcile.Request {
+			gateways := &mcingv1alpha1.MinecraftGatewayList{}
+			if err := r.List(ctx, gateways); err != nil {
+				r.log.Error(err, "failed to list MinecraftGateways")
+				return nil
+			}
+			reqs := make([]reconcile.Request, 0, len(gateways.Items))
+			for _, gw := range gateways.Items {
+				reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gw)})
+			}
+			return reqs
+		},
+	)
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	if !r.config.Enabled {
-		return nil
-	}
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("gateway").
-		For(&mcingv1alpha1.Minecraft{}).
+		For(&mcingv1alpha1.MinecraftGateway{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
+		Owns(&corev1.ServiceAccount{}).
+		Watches(&mcingv1alpha1.Minecraft{}, r.enqueueGateways()).
+		// Hibernation scales the StatefulSets of servers, which changes their routes.
+		Watches(&appsv1.StatefulSet{}, r.enqueueGateways(), builder.WithPredicates(
+			predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				return obj.GetLabels()[constants.LabelAppName] == constants.AppName
+			}),
+		)).
 		Complete(r)
 }
@@ -2,72 +2,156 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"maps"
+	"sync"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"      //nolint:revive // dot imports for tests
 	. "github.com/onsi/gomega"         //nolint:revive // dot imports for tests
 	. "github.com/onsi/gomega/gstruct" //nolint:revive // dot imports for tests
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/config"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
+	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
 	"github.com/kmdkuk/mcing/pkg/constants"
+	"github.com/kmdkuk/mcing/pkg/mcrouter"
 )
 
+// fakeRouter is an mc-router pod that keeps the routes registered through its API.
+type fakeRouter struct {
+	mu          sync.Mutex
+	routes      map[string]string
+	connections map[string]float64
+	// rejected are the hostnames that the router fails to register.
+	rejected map[string]bool
+}
+
+func (r *fakeRouter) Routes(context.Context) (map[string]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return maps.Clone(r.routes), nil
+}
+
+func (r *fakeRouter) CreateRoute(_ context.Context, host, backend string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.rejected[host] {
+		return errors.New("rejected")
+	}
+	r.routes[host] = backend
+	return nil
+}
+
+func (r *fakeRouter) DeleteRoute(_ context.Context, host string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.routes, host)
+	return nil
+}
+
+func (r *fakeRouter) Connections(context.Context) (map[string]float64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return maps.Clone(r.connections), nil
+}
+
+func (r *fakeRouter) set(routes map[string]string, connections map[string]float64, rejected ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes = routes
+	r.connections = connections
+	r.rejected = map[string]bool{}
+	for _, host := range rejected {
+		r.rejected[host] = true
+	}
+}
+
+// fakeRouterFactory returns the fake router of each mc-router pod by the pod IP.
+type fakeRouterFactory struct {
+	mu      sync.Mutex
+	routers map[string]*fakeRouter
+}
+
+func (f *fakeRouterFactory) New(podIP string) mcrouter.Client {
+	return f.router(podIP)
+}
+
+func (f *fakeRouterFactory) router(podIP string) *fakeRouter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.routers == nil {
+		f.routers = map[string]*fakeRouter{}
+	}
+	r, ok := f.routers[podIP]
+	if !ok {
+		r = &fakeRouter{routes: map[string]string{}}
+		f.routers[podIP] = r
+	}
+	return r
+}
+
 var _ = Describe("Gateway controller", func() {
 	const (
-		gatewayNamespace      = "mcing-gateway"
-		gatewayServiceAccount = "mc-router"
-		defaultDomain         = "minecraft.local"
-		mcRouterImage         = "itzg/mc-router:latest"
+		gatewayNamespace = "mcing-gateway"
+		backendNamespace = "test-gateway-backends"
+		wakerAddress     = "mcing-waker-service.mcing-system.svc:25565"
 	)
 
 	ctx := context.Background()
 	var mgrCtx context.Context
 	var mgrCancel context.CancelFunc
+	routers := &fakeRouterFactory{}
 
-	cleanupGatewayResources := func() {
-		// Delete deployment if exists
-		deploy := &appsv1.Deployment{}
-		err := k8sClient.Get(ctx, types.NamespacedName{
-			Namespace: gatewayNamespace,
-			Name:      constants.MCRouterAppName,
-		}, deploy)
-		if err == nil {
-			_ = k8sClient.Delete(ctx, deploy)
-		}
-
-		// Delete service if exists
-		svc := &corev1.Service{}
-		err = k8sClient.Get(ctx, types.NamespacedName{
-			Namespace: gatewayNamespace,
-			Name:      constants.MCRouterAppName,
-		}, svc)
-		if err == nil {
-			_ = k8sClient.Delete(ctx, svc)
-		}
+	makeGateway := func(name string) *mcingv1alpha1.MinecraftGateway {
+		gw := &mcingv1alpha1.MinecraftGateway{}
+		gw.Name = name
+		gw.Spec.Namespace = gatewayNamespace
+		return gw
+	}
 
-		// Delete service account if exists
-		sa := &corev1.ServiceAccount{}
-		err = k8sClient.Get(ctx, types.NamespacedName{
-			Namespace: gatewayNamespace,
-			Name:      gatewayServiceAccount,
-		}, sa)
-		if err == nil {
-			_ = k8sClient.Delete(ctx, sa)
+	// createRouterPod creates a ready mc-router pod of gw, which is served by the fake router of the IP.
+	createRouterPod := func(gw *mcingv1alpha1.MinecraftGateway, ip string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: gatewayNamespace,
+				Name:      gw.PrefixedName() + "-0",
+				Labels:    gatewayLabels(gw),
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: constants.MCRouterAppName, Image: "itzg/mc-router"}},
+			},
 		}
-
-		// Note: We don't delete the namespace because it takes too long
-		// due to namespace finalizers. The gateway tests will reuse the namespace.
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+		pod.Status.PodIP = ip
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+		return pod
 	}
 
-	setupGatewayController := func(enabled bool, serviceType corev1.ServiceType) {
-		cleanupGatewayResources()
+	BeforeEach(func() {
+		gateways := &mcingv1alpha1.MinecraftGatewayList{}
+		Expect(k8sClient.List(ctx, gateways)).To(Succeed())
+		for i := range gateways.Items {
+			// Remove the finalizer so that the namespace shared by the tests is not deleted.
+			gw := &gateways.Items[i]
+			gw.Finalizers = nil
+			Expect(client.IgnoreNotFound(k8sClient.Update(ctx, gw))).To(Succeed())
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, gw))).To(Succeed())
+		}
 
 		mgr, err := ctrl.NewManager(k8sCfg, ctrl.Options{
 			Scheme:         scheme,
@@ -79,23 +163,15 @@ var _ = Describe("Gateway controller", func() {
 		})
 		Expect(err).ToNot(HaveOccurred())
 
-		log := ctrl.Log.WithName("controllers")
-
-		gatewayConfig := GatewayConfig{
-			Enabled:           enabled,
-			DefaultDomain:     defaultDomain,
-			Namespace:         gatewayNamespace,
-			ServiceAccount:    gatewayServiceAccount,
-			ServiceType:       serviceType,
-			Image:             mcRouterImage,
-			ReconcileInterval: 3 * time.Minute,
-		}
-
 		r := NewGatewayReconciler(
 			mgr.GetClient(),
-			log,
+			ctrl.Log.WithName("controllers"),
 			mgr.GetScheme(),
-			gatewayConfig,
+			GatewayConfig{
+				RouterFactory:  routers,
+				WakerAddress:   wakerAddress,
+				RoutesInterval: time.Second,
+			},
 		)
 		err = r.SetupWithManager(mgr)
 		Expect(err).ToNot(HaveOccurred())
@@ -108,193 +184,494 @@ var _ = Describe("Gateway controller", func() {
 			}
 		}()
 		time.Sleep(time.Second)
-	}
+	})
 
-	teardownManager := func() {
+	AfterEach(func() {
 		mgrCancel()
 		time.Sleep(100 * time.Millisecond)
-	}
+	})
 
-	Context("when mc-router is enabled", func() {
-		BeforeEach(func() {
-			setupGatewayController(true, corev1.ServiceTypeLoadBalancer)
-		})
+	It("should create the backend namespace", func() {
+		createNamespaces(ctx, backendNamespace)
+	})
 
-		AfterEach(func() {
-			teardownManager()
-		})
+	It("should deploy mc-router for a gateway", func() {
+		By("creating a MinecraftGateway")
+		gw := makeGateway("deploy")
+		gw.Spec.Replicas = ptr.To[int32](2)
+		gw.Spec.Image = "itzg/mc-router:1.29.0"
+		gw.Spec.ExtraArgs = []string{"--connection-rate-limit=10"}
+		gw.Spec.Resources = corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+		}
+		gw.Spec.NodeSelector = map[string]string{"node-role.kubernetes.io/edge": ""}
+		gw.Spec.Tolerations = []corev1.Toleration{
+			{Key: "edge", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+		}
+		Expect(k8sClient.Create(ctx, gw)).To(Succeed())
+
+		By("creating the cluster role binding of an older version")
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(gw), gw)).To(Succeed())
+			g.Expect(gw.Finalizers).To(ContainElement(constants.Finalizer))
+		}).Should(Succeed())
+		crb := &rbacv1.ClusterRoleBinding{}
+		crb.Name = "mcing-mc-router-deploy"
+		crb.RoleRef = rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "mcing-mc-router-role"}
+		Expect(ctrl.SetControllerReference(gw, crb, scheme)).To(Succeed())
+		Expect(k8sClient.Create(ctx, crb)).To(Succeed())
+
+		By("checking the namespace")
+		Eventually(func(g Gomega) {
+			ns := &corev1.Namespace{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: gatewayNamespace}, ns)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(ns.Labels).To(HaveKeyWithValue(constants.LabelAppName, constants.MCRouterAppName))
+			g.Expect(ns.Labels).To(HaveKeyWithValue(constants.LabelAppComponent, constants.MCRouterAppComponent))
+		}).Should(Succeed())
+
+		By("checking the service account and the removal of the cluster role binding")
+		Eventually(func(g Gomega) {
+			sa := &corev1.ServiceAccount{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: gatewayNamespace, Name: "mc-router-deploy"}, sa)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(sa.Labels).To(HaveKeyWithValue(constants.LabelAppInstance, "deploy"))
+			g.Expect(sa.OwnerReferences).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+				"Kind": Equal("MinecraftGateway"),
+				"Name": Equal("deploy"),
+			})))
+
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(crb), &rbacv1.ClusterRoleBinding{})
+			g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		}).Should(Succeed())
+
+		By("checking the deployment")
+		Eventually(func(g Gomega) {
+			deploy := &appsv1.Deployment{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: gatewayNamespace, Name: "mc-router-deploy"}, deploy)
+			g.Expect(err).ShouldNot(HaveOccurred())
+
+			g.Expect(deploy.Labels).To(HaveKeyWithValue(constants.LabelAppName, constants.MCRouterAppName))
+			g.Expect(deploy.Spec.Replicas).To(Equal(ptr.To[int32](2)))
+
+			podSpec := deploy.Spec.Template.Spec
+			g.Expect(podSpec.ServiceAccountName).To(Equal("mc-router-deploy"))
+			g.Expect(podSpec.NodeSelector).To(Equal(gw.Spec.NodeSelector))
+			g.Expect(podSpec.Tolerations).To(Equal(gw.Spec.Tolerations))
+
+			g.Expect(podSpec.Containers).To(HaveLen(1))
+			container := podSpec.Containers[0]
+			g.Expect(container.Name).To(Equal(constants.MCRouterAppName))
+			g.Expect(container.Image).To(Equal("itzg/mc-router:1.29.0"))
+			g.Expect(container.Args).To(Equal([]string{
+				"--api-binding=:8080", "--metrics-backend=prometheus", "--connection-rate-limit=10",
+			}))
+			g.Expect(container.Resources.Limits).To(HaveKeyWithValue(corev1.ResourceMemory, resource.MustParse("128Mi")))
+			g.Expect(container.Ports).To(ContainElements(
+				MatchFields(IgnoreExtras, Fields{
+					"Name":          Equal(constants.MCRouterPortName),
+					"ContainerPort": Equal(constants.MCRouterPort),
+				}),
+				MatchFields(IgnoreExtras, Fields{
+					"Name":          Equal(constants.MCRouterAPIPortName),
+					"ContainerPort": Equal(constants.MCRouterAPIPort),
+				}),
+			))
+		}).Should(Succeed())
+	})
 
-		It("should create mcing-gateway namespace", func() {
-			// Create a Minecraft resource to trigger reconciliation
-			mc := makeMinecraft("gateway-test", "default")
-			Expect(k8sClient.Create(ctx, mc)).To(Succeed())
-			defer func() {
-				mc.Finalizers = nil
-				_ = k8sClient.Update(ctx, mc)
-				_ = k8sClient.Delete(ctx, mc)
-			}()
+	It("should delete the namespace when the last gateway in it is deleted", func() {
+		const cleanupNamespace = "test-gateway-cleanup"
 
-			Eventually(func(g Gomega) {
-				ns := &corev1.Namespace{}
-				err := k8sClient.Get(ctx, types.NamespacedName{Name: gatewayNamespace}, ns)
-				g.Expect(err).ShouldNot(HaveOccurred())
-				g.Expect(ns.Labels).To(HaveKeyWithValue(constants.LabelAppName, constants.MCRouterAppName))
-				g.Expect(ns.Labels).To(HaveKeyWithValue(constants.LabelAppComponent, constants.MCRouterAppComponent))
-			}).Should(Succeed())
-		})
+		By("creating two gateways in the same namespace")
+		first := makeGateway("cleanup-first")
+		first.Spec.Namespace = cleanupNamespace
+		Expect(k8sClient.Create(ctx, first)).To(Succeed())
+		second := makeGateway("cleanup-second")
+		second.Spec.Namespace = cleanupNamespace
+		Expect(k8sClient.Create(ctx, second)).To(Succeed())
 
-		It("should create mc-router service account", func() {
-			mc := makeMinecraft("gateway-test-sa", "default")
-			Expect(k8sClient.Create(ctx, mc)).To(Succeed())
-			defer func() {
-				mc.Finalizers = nil
-				_ = k8sClient.Update(ctx, mc)
-				_ = k8sClient.Delete(ctx, mc)
-			}()
-
-			Eventually(func(g Gomega) {
-				sa := &corev1.ServiceAccount{}
-				err := k8sClient.Get(ctx, types.NamespacedName{
-					Namespace: gatewayNamespace,
-					Name:      gatewayServiceAccount,
-				}, sa)
+		Eventually(func(g Gomega) {
+			for _, gw := range []*mcingv1alpha1.MinecraftGateway{first, second} {
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(gw), gw)
 				g.Expect(err).ShouldNot(HaveOccurred())
-				g.Expect(sa.Labels).To(HaveKeyWithValue(constants.LabelAppName, constants.MCRouterAppName))
-			}).Should(Succeed())
-		})
+				g.Expect(gw.Finalizers).To(ContainElement(constants.Finalizer))
+			}
+			deploy := &appsv1.Deployment{}
+			key := types.NamespacedName{Namespace: cleanupNamespace, Name: "mc-router-cleanup-second"}
+			g.Expect(k8sClient.Get(ctx, key, deploy)).To(Succeed())
+		}).Should(Succeed())
+
+		By("deleting the first gateway")
+		Expect(k8sClient.Delete(ctx, first)).To(Succeed())
+		Eventually(func() bool {
+			return apierrors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(first), first))
+		}).Should(BeTrue())
+		ns := &corev1.Namespace{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Name: cleanupNamespace}, ns)).To(Succeed())
+		Expect(ns.DeletionTimestamp).To(BeNil())
+
+		By("deleting the second gateway")
+		Expect(k8sClient.Delete(ctx, second)).To(Succeed())
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(second), second)
+			g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+			// envtest does not run the namespace controller, so the namespace stays terminating.
+			err = k8sClient.Get(ctx, client.ObjectKey{Name: cleanupNamespace}, ns)
+			g.Expect(client.IgnoreNotFound(err)).ShouldNot(HaveOccurred())
+			if err == nil {
+				g.Expect(ns.DeletionTimestamp).NotTo(BeNil())
+			}
+		}).Should(Succeed())
+	})
 
-		It("should create mc-router deployment", func() {
-			mc := makeMinecraft("gateway-test-deploy", "default")
-			Expect(k8sClient.Create(ctx, mc)).To(Succeed())
-			defer func() {
-				mc.Finalizers = nil
-				_ = k8sClient.Update(ctx, mc)
-				_ = k8sClient.Delete(ctx, mc)
-			}()
-
-			Eventually(func(g Gomega) {
-				deploy := &appsv1.Deployment{}
-				err := k8sClient.Get(ctx, types.NamespacedName{
-					Namespace: gatewayNamespace,
-					Name:      constants.MCRouterAppName,
-				}, deploy)
-				g.Expect(err).ShouldNot(HaveOccurred())
+	It("should expose mc-router with the configured service", func() {
+		By("creating a MinecraftGateway with a LoadBalancer service")
+		gw := makeGateway("lb")
+		gw.Spec.Service.Annotations = map[string]string{"metallb.universe.tf/address-pool": "public"}
+		Expect(k8sClient.Create(ctx, gw)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			svc := &corev1.Service{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: gatewayNamespace, Name: "mc-router-lb"}, svc)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(svc.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+			g.Expect(svc.Annotations).To(HaveKeyWithValue("metallb.universe.tf/address-pool", "public"))
+			g.Expect(svc.Spec.Selector).To(HaveKeyWithValue(constants.LabelAppInstance, "lb"))
+			g.Expect(svc.Spec.Ports).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Name": Equal(constants.MCRouterPortName),
+				"Port": Equal(constants.MCRouterPort),
+			})))
+		}).Should(Succeed())
+
+		By("reporting the address of the load balancer")
+		svc := &corev1.Service{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: gatewayNamespace, Name: "mc-router-lb"}, svc)).
+			To(Succeed())
+		svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "203.0.113.10"}}
+		Expect(k8sClient.Status().Update(ctx, svc)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(gw), gw)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(gw.Status.ExternalAddresses).To(Equal([]string{"203.0.113.10"}))
+		}).Should(Succeed())
+	})
 
-				// Check deployment labels
-				g.Expect(deploy.Labels).To(HaveKeyWithValue(constants.LabelAppName, constants.MCRouterAppName))
-
-				// Check container
-				g.Expect(deploy.Spec.Template.Spec.Containers).To(HaveLen(1))
-				container := deploy.Spec.Template.Spec.Containers[0]
-				g.Expect(container.Name).To(Equal(constants.MCRouterAppName))
-				g.Expect(container.Image).To(Equal(mcRouterImage))
-
-				// Check args
-				g.Expect(container.Args).To(ContainElements("--in-kube-cluster", "--api-binding=:8080"))
-
-				// Check ports
-				g.Expect(container.Ports).To(ContainElements(
-					MatchFields(IgnoreExtras, Fields{
-						"Name":          Equal(constants.MCRouterPortName),
-						"ContainerPort": Equal(constants.MCRouterPort),
-					}),
-					MatchFields(IgnoreExtras, Fields{
-						"Name":          Equal(constants.MCRouterAPIPortName),
-						"ContainerPort": Equal(constants.MCRouterAPIPort),
-					}),
-				))
-
-				// Check service account
-				g.Expect(deploy.Spec.Template.Spec.ServiceAccountName).To(Equal(gatewayServiceAccount))
-			}).Should(Succeed())
-		})
+	It("should report the node port of a NodePort service", func() {
+		gw := makeGateway("nodeport")
+		gw.Spec.Service.Type = corev1.ServiceTypeNodePort
+		Expect(k8sClient.Create(ctx, gw)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			svc := &corev1.Service{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: gatewayNamespace, Name: "mc-router-nodeport"}, svc)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(svc.Spec.Type).To(Equal(corev1.ServiceTypeNodePort))
+
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(gw), gw)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(gw.Status.NodePort).To(Equal(svc.Spec.Ports[0].NodePort))
+			g.Expect(gw.Status.NodePort).NotTo(BeZero())
+		}).Should(Succeed())
+	})
 
-		It("should create mc-router service with LoadBalancer type", func() {
-			mc := makeMinecraft("gateway-test-svc", "default")
-			Expect(k8sClient.Create(ctx, mc)).To(Succeed())
-			defer func() {
+	It("should report the Minecraft servers routed by each gateway", func() {
+		By("creating a default gateway and another gateway")
+		defaultGW := makeGateway("backends-default")
+		defaultGW.Spec.Default = true
+		Expect(k8sClient.Create(ctx, defaultGW)).To(Succeed())
+		otherGW := makeGateway("backends-other")
+		otherGW.Spec.DefaultDomain = "mc.example.com"
+		Expect(k8sClient.Create(ctx, otherGW)).To(Succeed())
+
+		By("creating Minecrafts for each gateway")
+		lobby := makeMinecraft("lobby", backendNamespace)
+		Expect(k8sClient.Create(ctx, lobby)).To(Succeed())
+		survival := makeMinecraft("survival", backendNamespace)
+		survival.Spec.GatewayName = ptr.To(otherGW.Name)
+		survival.Spec.ExternalHostname = ptr.To("survival.example.com")
+		survival.Spec.HostnameAliases = []string{"smp.example.com"}
+		Expect(k8sClient.Create(ctx, survival)).To(Succeed())
+		defer func() {
+			for _, mc := range []*mcingv1alpha1.Minecraft{lobby, survival} {
 				mc.Finalizers = nil
 				_ = k8sClient.Update(ctx, mc)
 				_ = k8sClient.Delete(ctx, mc)
-			}()
-
-			Eventually(func(g Gomega) {
-				svc := &corev1.Service{}
-				err := k8sClient.Get(ctx, types.NamespacedName{
-					Namespace: gatewayNamespace,
-					Name:      constants.MCRouterAppName,
-				}, svc)
-				g.Expect(err).ShouldNot(HaveOccurred())
+			}
+		}()
 
-				// Check service type
-				g.Expect(svc.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(defaultGW), defaultGW)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(defaultGW.Status.Backends).To(Equal([]mcingv1alpha1.GatewayBackend{
+				{Namespace: backendNamespace, Name: "lobby", Hostname: "lobby." + backendNamespace + ".minecraft.local"},
+			}))
+
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(otherGW), otherGW)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(otherGW.Status.Backends).To(Equal([]mcingv1alpha1.GatewayBackend{
+				{
+					Namespace: backendNamespace,
+					Name:      "survival",
+					Hostname:  "survival.example.com",
+					Aliases:   []string{"smp.example.com"},
+				},
+			}))
+		}).Should(Succeed())
+	})
 
-				// Check labels
-				g.Expect(svc.Labels).To(HaveKeyWithValue(constants.LabelAppName, constants.MCRouterAppName))
+	It("should publish the hostnames of the servers with external-dns annotations", func() {
+		By("creating a gateway that publishes DNS records by annotations")
+		gw := makeGateway("dns")
+		gw.Spec.DefaultDomain = "mc.example.com"
+		gw.Spec.DNS = &mcingv1alpha1.GatewayDNS{Mode: mcingv1alpha1.GatewayDNSAnnotation, TTL: ptr.To[int64](300)}
+		Expect(k8sClient.Create(ctx, gw)).To(Succeed())
+
+		creative := makeMinecraft("creative", backendNamespace)
+		creative.Spec.GatewayName = ptr.To(gw.Name)
+		creative.Spec.HostnameAliases = []string{"build.example.com"}
+		Expect(k8sClient.Create(ctx, creative)).To(Succeed())
+		defer func() {
+			creative.Finalizers = nil
+			_ = k8sClient.Update(ctx, creative)
+			_ = k8sClient.Delete(ctx, creative)
+		}()
 
-				// Check port
-				g.Expect(svc.Spec.Ports).To(ContainElement(
-					MatchFields(IgnoreExtras, Fields{
-						"Name": Equal(constants.MCRouterPortName),
-						"Port": Equal(constants.MCRouterPort),
-					}),
-				))
-			}).Should(Succeed())
-		})
+		Eventually(func(g Gomega) {
+			svc := &corev1.Service{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: gatewayNamespace, Name: "mc-router-dns"}, svc)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(svc.Annotations).To(HaveKeyWithValue(constants.ExternalDNSHostnameAnnotation,
+				"build.example.com,creative."+backendNamespace+".mc.example.com"))
+			g.Expect(svc.Annotations).To(HaveKeyWithValue(constants.ExternalDNSTTLAnnotation, "300"))
+		}).Should(Succeed())
+
+		By("disabling DNS")
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(gw), gw)).To(Succeed())
+		gw.Spec.DNS = nil
+		Expect(k8sClient.Update(ctx, gw)).To(Succeed())
+		Eventually(func(g Gomega) {
+			svc := &corev1.Service{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: gatewayNamespace, Name: "mc-router-dns"}, svc)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(svc.Annotations).NotTo(HaveKey(constants.ExternalDNSHostnameAnnotation))
+			g.Expect(svc.Annotations).NotTo(HaveKey(constants.ExternalDNSTTLAnnotation))
+		}).Should(Succeed())
 	})
 
-	Context("when mc-router is enabled with NodePort", func() {
-		BeforeEach(func() {
-			setupGatewayController(true, corev1.ServiceTypeNodePort)
-		})
+	It("should register the hostnames of the servers on mc-router and report them", func() {
+		By("creating a gateway, its ready mc-router pod and Minecrafts")
+		gw := makeGateway("routes")
+		gw.Spec.Default = true
+		Expect(k8sClient.Create(ctx, gw)).To(Succeed())
+		pod := createRouterPod(gw, "10.0.0.10")
+		defer func() {
+			_ = k8sClient.Delete(ctx, pod)
+		}()
 
-		AfterEach(func() {
-			teardownManager()
-		})
+		hubHost := "hub." + backendNamespace + ".minecraft.local"
+		pendingHost := "pending." + backendNamespace + ".minecraft.local"
+		router := routers.router("10.0.0.10")
+		router.set(map[string]string{}, map[string]float64{hubHost: 3}, pendingHost)
+		hub := makeMinecraft("hub", backendNamespace)
+		Expect(k8sClient.Create(ctx, hub)).To(Succeed())
+		pending := makeMinecraft("pending", backendNamespace)
+		Expect(k8sClient.Create(ctx, pending)).To(Succeed())
+		defer func() {
+			for _, mc := range []*mcingv1alpha1.Minecraft{hub, pending} {
+				mc.Finalizers = nil
+				_ = k8sClient.Update(ctx, mc)
+				_ = k8sClient.Delete(ctx, mc)
+			}
+		}()
 
-		It("should create mc-router service with NodePort type", func() {
-			mc := makeMinecraft("gateway-test-nodeport", "default")
-			Expect(k8sClient.Create(ctx, mc)).To(Succeed())
-			defer func() {
+		By("checking the routes and the Routed conditions")
+		Eventually(func(g Gomega) {
+			routes, _ := router.Routes(ctx)
+			g.Expect(routes).To(Equal(map[string]string{hubHost: "mcing-hub." + backendNamespace + ".svc:25565"}))
+
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(hub), hub)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			cond := meta.FindStatusCondition(hub.Status.Conditions, mcingv1alpha1.ConditionRouted)
+			g.Expect(cond).NotTo(BeNil())
+			g.Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			g.Expect(cond.Reason).To(Equal("Registered"))
+
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(pending), pending)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			cond = meta.FindStatusCondition(pending.Status.Conditions, mcingv1alpha1.ConditionRouted)
+			g.Expect(cond).NotTo(BeNil())
+			g.Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			g.Expect(cond.Reason).To(Equal("NotRegistered"))
+		}).Should(Succeed())
+
+		By("checking the metrics")
+		hubLabels := prometheus.Labels{
+			"gateway":   "routes",
+			"namespace": backendNamespace,
+			"name":      "hub",
+			"hostname":  hubHost,
+		}
+		Expect(testutil.ToFloat64(gatewayConnectionsMetric.With(hubLabels))).To(Equal(3.0))
+		Expect(testutil.ToFloat64(gatewayRoutedMetric.With(hubLabels))).To(Equal(1.0))
+
+		By("registering the other server once mc-router accepts it")
+		router.set(map[string]string{}, nil)
+		Eventually(func(g Gomega) {
+			routes, _ := router.Routes(ctx)
+			g.Expect(routes).To(HaveKeyWithValue(pendingHost, "mcing-pending."+backendNamespace+".svc:25565"))
+
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(pending), pending)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(meta.IsStatusConditionTrue(pending.Status.Conditions, mcingv1alpha1.ConditionRouted)).To(BeTrue())
+		}).Should(Succeed())
+	})
+
+	It("should route only the servers of each gateway", func() {
+		By("creating two gateways with their mc-router pods")
+		gwA := makeGateway("isolation-a")
+		gwA.Spec.DefaultDomain = "a.example.com"
+		Expect(k8sClient.Create(ctx, gwA)).To(Succeed())
+		gwB := makeGateway("isolation-b")
+		gwB.Spec.DefaultDomain = "b.example.com"
+		Expect(k8sClient.Create(ctx, gwB)).To(Succeed())
+		podA := createRouterPod(gwA, "10.0.0.21")
+		podB := createRouterPod(gwB, "10.0.0.22")
+		defer func() {
+			_ = k8sClient.Delete(ctx, podA)
+			_ = k8sClient.Delete(ctx, podB)
+		}()
+
+		alphaHost := "alpha." + backendNamespace + ".a.example.com"
+		betaHost := "beta." + backendNamespace + ".b.example.com"
+		routerA := routers.router("10.0.0.21")
+		routerB := routers.router("10.0.0.22")
+		// The router of A starts with a route to a server of B, e.g. one moved to B.
+		routerA.set(map[string]string{betaHost: "mcing-beta." + backendNamespace + ".svc:25565"}, nil)
+		routerB.set(map[string]string{}, nil)
+
+		By("creating a Minecraft for each gateway")
+		alpha := makeMinecraft("alpha", backendNamespace)
+		alpha.Spec.GatewayName = ptr.To(gwA.Name)
+		Expect(k8sClient.Create(ctx, alpha)).To(Succeed())
+		beta := makeMinecraft("beta", backendNamespace)
+		beta.Spec.GatewayName = ptr.To(gwB.Name)
+		Expect(k8sClient.Create(ctx, beta)).To(Succeed())
+		defer func() {
+			for _, mc := range []*mcingv1alpha1.Minecraft{alpha, beta} {
 				mc.Finalizers = nil
 				_ = k8sClient.Update(ctx, mc)
 				_ = k8sClient.Delete(ctx, mc)
-			}()
-
-			Eventually(func(g Gomega) {
-				svc := &corev1.Service{}
-				err := k8sClient.Get(ctx, types.NamespacedName{
-					Namespace: gatewayNamespace,
-					Name:      constants.MCRouterAppName,
-				}, svc)
-				g.Expect(err).ShouldNot(HaveOccurred())
+			}
+		}()
 
-				// Check service type
-				g.Expect(svc.Spec.Type).To(Equal(corev1.ServiceTypeNodePort))
-			}).Should(Succeed())
-		})
+		By("checking that neither gateway routes the server of the other")
+		Eventually(func(g Gomega) {
+			routes, _ := routerA.Routes(ctx)
+			g.Expect(routes).To(Equal(map[string]string{alphaHost: "mcing-alpha." + backendNamespace + ".svc:25565"}))
+			routes, _ = routerB.Routes(ctx)
+			g.Expect(routes).To(Equal(map[string]string{betaHost: "mcing-beta." + backendNamespace + ".svc:25565"}))
+		}).Should(Succeed())
+		Consistently(func(g Gomega) {
+			routes, _ := routerA.Routes(ctx)
+			g.Expect(routes).NotTo(HaveKey(betaHost))
+			routes, _ = routerB.Routes(ctx)
+			g.Expect(routes).NotTo(HaveKey(alphaHost))
+		}, 3*time.Second).Should(Succeed())
 	})
 
-	Context("when mc-router is disabled", func() {
-		BeforeEach(func() {
-			setupGatewayController(false, corev1.ServiceTypeLoadBalancer)
-		})
+	It("should route a hibernated server to the waker", func() {
+		By("creating a gateway, its mc-router pod and a hibernated Minecraft")
+		gw := makeGateway("hibernation")
+		gw.Spec.DefaultDomain = "sleep.example.com"
+		Expect(k8sClient.Create(ctx, gw)).To(Succeed())
+		pod := createRouterPod(gw, "10.0.0.30")
+		defer func() {
+			_ = k8sClient.Delete(ctx, pod)
+		}()
+		router := routers.router("10.0.0.30")
+		router.set(map[string]string{}, nil)
+
+		sleepy := makeMinecraft("sleepy", backendNamespace)
+		sleepy.Spec.GatewayName = ptr.To(gw.Name)
+		sleepy.Spec.Hibernation.Enabled = true
+		Expect(k8sClient.Create(ctx, sleepy)).To(Succeed())
+		defer func() {
+			sleepy.Finalizers = nil
+			_ = k8sClient.Update(ctx, sleepy)
+			_ = k8sClient.Delete(ctx, sleepy)
+		}()
+		labels := labelSet(sleepy, constants.AppComponentServer)
+		sts := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: backendNamespace, Name: sleepy.PrefixedName(), Labels: labels},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: ptr.To[int32](0),
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec:       sleepy.Spec.PodTemplate.Spec,
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, sts)).To(Succeed())
+		defer func() {
+			_ = k8sClient.Delete(ctx, sts)
+		}()
 
-		AfterEach(func() {
-			teardownManager()
-		})
+		host := "sleepy." + backendNamespace + ".sleep.example.com"
+		Eventually(func(g Gomega) {
+			routes, _ := router.Routes(ctx)
+			g.Expect(routes).To(Equal(map[string]string{host: wakerAddress}))
+		}).Should(Succeed())
+
+		By("routing the server to its Service once it is ready again")
+		sts.Spec.Replicas = ptr.To[int32](1)
+		Expect(k8sClient.Update(ctx, sts)).To(Succeed())
+		sts.Status.Replicas = 1
+		sts.Status.ReadyReplicas = 1
+		Expect(k8sClient.Status().Update(ctx, sts)).To(Succeed())
+		Eventually(func(g Gomega) {
+			routes, _ := router.Routes(ctx)
+			g.Expect(routes).To(Equal(map[string]string{host: "mcing-sleepy." + backendNamespace + ".svc:25565"}))
+		}).Should(Succeed())
+	})
+})
 
-		It("should not create deployment when disabled", func() {
-			// Wait a bit to ensure nothing is created
-			time.Sleep(2 * time.Second)
+var _ = Describe("dnsEndpoints", func() {
+	hostnames := []string{"lobby.mc.example.com", "survival.example.com"}
 
-			// Verify deployment is not created (don't check namespace as it may exist from previous tests)
-			deploy := &appsv1.Deployment{}
-			err := k8sClient.Get(ctx, types.NamespacedName{
-				Namespace: gatewayNamespace,
-				Name:      constants.MCRouterAppName,
-			}, deploy)
-			Expect(apierrors.IsNotFound(err)).To(BeTrue())
-		})
+	It("should publish A and AAAA records for IP addresses", func() {
+		endpoints := dnsEndpoints(hostnames, []string{"203.0.113.10", "2001:db8::10", "lb.example.net"}, ptr.To[int64](60))
+		Expect(endpoints).To(Equal([]any{
+			map[string]any{
+				"dnsName": "lobby.mc.example.com", "recordType": "A",
+				"targets": []any{"203.0.113.10"}, "recordTTL": int64(60),
+			},
+			map[string]any{
+				"dnsName": "lobby.mc.example.com", "recordType": "AAAA",
+				"targets": []any{"2001:db8::10"}, "recordTTL": int64(60),
+			},
+			map[string]any{
+				"dnsName": "survival.example.com", "recordType": "A",
+				"targets": []any{"203.0.113.10"}, "recordTTL": int64(60),
+			},
+			map[string]any{
+				"dnsName": "survival.example.com", "recordType": "AAAA",
+				"targets": []any{"2001:db8::10"}, "recordTTL": int64(60),
+			},
+		}))
+	})
+
+	It("should publish a CNAME record for a load balancer with a hostname", func() {
+		endpoints := dnsEndpoints(hostnames[:1], []string{"lb.example.net"}, nil)
+		Expect(endpoints).To(Equal([]any{
+			map[string]any{"dnsName": "lobby.mc.example.com", "recordType": "CNAME", "targets": []any{"lb.example.net"}},
+		}))
+	})
+
+	It("should publish nothing without addresses", func() {
+		Expect(dnsEndpoints(hostnames, nil, nil)).To(BeEmpty())
 	})
 })
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"context"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
+	"github.com/kmdkuk/mcing/pkg/constants"
+)
+
+// dnsMode returns how the DNS records of the gateway are published, or "" if they are not.
+func dnsMode(gw *mcingv1alpha1.MinecraftGateway) mcingv1alpha1.GatewayDNSMode {
+	if gw.Spec.DNS == nil {
+		return ""
+	}
+	if gw.Spec.DNS.Mode == "" {
+		return mcingv1alpha1.GatewayDNSAnnotation
+	}
+	return gw.Spec.DNS.Mode
+}
+
+// gatewayHostnames returns the sorted hostnames and aliases of the backends.
+func gatewayHostnames(backends []mcingv1alpha1.GatewayBackend) []string {
+	var hostnames []string
+	for i := range backends {
+		hostnames = append(hostnames, backends[i].Hostnames()...)
+	}
+	slices.Sort(hostnames)
+	return slices.Compact(hostnames)
+}
+
+// dnsAnnotations returns the annotations of the mc-router Service for the service source of external-dns.
+func dnsAnnotations(gw *mcingv1alpha1.MinecraftGateway, backends []mcingv1alpha1.GatewayBackend) map[string]string {
+	if dnsMode(gw) != mcingv1alpha1.GatewayDNSAnnotation {
+		return nil
+	}
+	hostnames := gatewayHostnames(backends)
+	if len(hostnames) == 0 {
+		return nil
+	}
+	annotations := map[string]string{
+		constants.ExternalDNSHostnameAnnotation: strings.Join(hostnames, ","),
+	}
+	if gw.Spec.DNS.TTL != nil {
+		annotations[constants.ExternalDNSTTLAnnotation] = strconv.FormatInt(*gw.Spec.DNS.TTL, 10)
+	}
+	return annotations
+}
+
+type dnsRecord struct {
+	recordType string
+	targets    []any
+}
+
+// dnsEndpoints returns the endpoints of a DNSEndpoint that point the hostnames to the addresses.
+// IP addresses are published as A and AAAA records. A load balancer that only has a hostname is
+// published as a CNAME record, because a CNAME record cannot coexist with other records.
+func dnsEndpoints(hostnames, addresses []string, ttl *int64) []any {
+	var ipv4, ipv6, names []any
+	for _, a := range addresses {
+		ip, err := netip.ParseAddr(a)
+		switch {
+		case err != nil:
+			names = append(names, a)
+		case ip.Is4():
+			ipv4 = append(ipv4, a)
+		default:
+			ipv6 = append(ipv6, a)
+		}
+	}
+	records := []dnsRecord{{"A", ipv4}, {"AAAA", ipv6}}
+	if len(ipv4) == 0 && len(ipv6) == 0 && len(names) != 0 {
+		records = append(records, dnsRecord{"CNAME", names[:1]})
+	}
+
+	endpoints := []any{}
+	for _, h := range hostnames {
+		for _, rec := range records {
+			if len(rec.targets) == 0 {
+				continue
+			}
+			ep := map[string]any{
+				"dnsName":    h,
+				"recordType": rec.recordType,
+				"targets":    rec.targets,
+			}
+			if ttl != nil {
+				ep["recordTTL"] = *ttl
+			}
+			endpoints = append(endpoints, ep)
+		}
+	}
+	return endpoints
+}
+
+// reconcileDNSEndpoint creates the DNSEndpoint of the gateway in the DNSEndpoint mode, and deletes it otherwise.
+// DNSEndpoint is unstructured so that the controller works without the CRD of external-dns.
+func (r *GatewayReconciler) reconcileDNSEndpoint(ctx context.Context, gw *mcingv1alpha1.MinecraftGateway) error {
+	ep := &unstructured.Unstructured{}
+	ep.SetAPIVersion(constants.DNSEndpointAPIVersion)
+	ep.SetKind(constants.DNSEndpointKind)
+	ep.SetNamespace(gw.Spec.Namespace)
+	ep.SetName(gw.PrefixedName())
+
+	if dnsMode(gw) != mcingv1alpha1.GatewayDNSEndpoint {
+		err := r.Delete(ctx, ep)
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+
+	_, err := ctrl.CreateOrUpdate(ctx, r.Client, ep, func() error {
+		ep.SetLabels(gatewayLabels(gw))
+		endpoints := dnsEndpoints(gatewayHostnames(gw.Status.Backends), gw.Status.ExternalAddresses, gw.Spec.DNS.TTL)
+		if err := unstructured.SetNestedSlice(ep.Object, endpoints, "spec", "endpoints"); err != nil {
+			return err
+		}
+		return ctrl.SetControllerReference(gw, ep, r.scheme)
+	})
+	return err
+}
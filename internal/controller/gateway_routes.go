@@ -0,0 +1,273 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
+	"github.com/kmdkuk/mcing/pkg/constants"
+)
+
+const metricsNamespace = "mcing"
+
+var (
+	gatewayRoutedMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "gateway",
+		Name:      "backend_routed",
+		Help:      "1 if every mc-router pod of the gateway has registered the hostname of the server, 0 otherwise.",
+	}, []string{"gateway", "namespace", "name", "hostname"})
+
+	gatewayConnectionsMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "gateway",
+		Name:      "backend_connections",
+		Help: "The number of connections that the mc-router pods of the gateway " +
+			"have routed to the server since they started.",
+	}, []string{"gateway", "namespace", "name", "hostname"})
+)
+
+//nolint:gochecknoinits // metrics are registered once
+func init() {
+	metrics.Registry.MustRegister(gatewayRoutedMetric, gatewayConnectionsMetric)
+}
+
+// routerState is what the mc-router pods of a gateway report.
+type routerState struct {
+	// routes are the routes of each pod that answered.
+	routes []map[string]string
+	// connections are the connections by the hostnames summed over the pods.
+	connections map[string]float64
+}
+
+// routed returns the backend of host if every pod that answered routes it.
+func (s *routerState) routed(host string) (string, bool) {
+	if len(s.routes) == 0 {
+		return "", false
+	}
+	var backend string
+	for _, routes := range s.routes {
+		b, ok := routes[host]
+		if !ok {
+			return "", false
+		}
+		backend = b
+	}
+	return backend, true
+}
+
+// serverBackend returns the address of the Service of mc, which mc-router and the waker connect to.
+func serverBackend(mc *mcingv1alpha1.Minecraft) string {
+	return fmt.Sprintf("%s.%s.svc:%d", mc.PrefixedName(), mc.Namespace, constants.ServerPort)
+}
+
+// desiredRoutes returns the backend of each hostname of the backends of the gateway, lowercased.
+// A hibernated server is routed to the waker until it is ready again, so that a joining player wakes it up.
+func (r *GatewayReconciler) desiredRoutes(
+	ctx context.Context,
+	backends []mcingv1alpha1.GatewayBackend,
+) (map[string]string, error) {
+	routes := map[string]string{}
+	for _, b := range backends {
+		mc := &mcingv1alpha1.Minecraft{}
+		err := r.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: b.Name}, mc)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		backend := serverBackend(mc)
+		if r.config.WakerAddress != "" && mc.Spec.Hibernation.Enabled {
+			hibernated, err := r.hibernated(ctx, mc)
+			if err != nil {
+				return nil, err
+			}
+			if hibernated {
+				backend = r.config.WakerAddress
+			}
+		}
+		for _, h := range b.Hostnames() {
+			routes[strings.ToLower(h)] = backend
+		}
+	}
+	return routes, nil
+}
+
+// hibernated reports whether the StatefulSet of mc is scaled to zero or has not become ready since it was scaled up.
+func (r *GatewayReconciler) hibernated(ctx context.Context, mc *mcingv1alpha1.Minecraft) (bool, error) {
+	sts := &appsv1.StatefulSet{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: mc.Namespace, Name: mc.PrefixedName()}, sts)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return (sts.Spec.Replicas != nil && *sts.Spec.Replicas == 0) || sts.Status.ReadyReplicas == 0, nil
+}
+
+// syncRouters registers the desired routes on the ready mc-router pods of the gateway and removes the others,
+// so that each gateway routes only its own backends. It returns the routes and the connections the pods report.
+// A pod that does not answer is skipped, so the state is empty if no pod answers.
+func (r *GatewayReconciler) syncRouters(
+	ctx context.Context,
+	gw *mcingv1alpha1.MinecraftGateway,
+	desired map[string]string,
+) (*routerState, error) {
+	pods := &corev1.PodList{}
+	err := r.List(ctx, pods, client.InNamespace(gw.Spec.Namespace), client.MatchingLabels(gatewayLabels(gw)))
+	if err != nil {
+		return nil, err
+	}
+
+	state := &routerState{connections: map[string]float64{}}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.PodIP == "" || !podReady(pod) {
+			continue
+		}
+		log := r.log.WithValues("gateway", gw.Name, "pod", client.ObjectKeyFromObject(pod))
+		c := r.config.RouterFactory.New(pod.Status.PodIP)
+		routes, err := c.Routes(ctx)
+		if err != nil {
+			log.Error(err, "failed to get the routes of mc-router")
+			continue
+		}
+		for host, backend := range desired {
+			if routes[host] == backend {
+				continue
+			}
+			if err := c.CreateRoute(ctx, host, backend); err != nil {
+				log.Error(err, "failed to register a route on mc-router", "hostname", host)
+				continue
+			}
+			routes[host] = backend
+		}
+		for host := range routes {
+			if _, ok := desired[host]; ok {
+				continue
+			}
+			if err := c.DeleteRoute(ctx, host); err != nil {
+				log.Error(err, "failed to remove a route from mc-router", "hostname", host)
+				continue
+			}
+			delete(routes, host)
+		}
+		conns, err := c.Connections(ctx)
+		if err != nil {
+			log.Error(err, "failed to get the metrics of mc-router")
+			continue
+		}
+		state.routes = append(state.routes, routes)
+		for host, n := range conns {
+			state.connections[host] += n
+		}
+	}
+	return state, nil
+}
+
+// reportRoutes syncs the routes of mc-router, sets the Routed condition of the backends of the gateway
+// from them, and exports the routes and the connections as metrics.
+// A backend is routed when every hostname of it, including the aliases, is registered.
+func (r *GatewayReconciler) reportRoutes(
+	ctx context.Context,
+	gw *mcingv1alpha1.MinecraftGateway,
+	desired map[string]string,
+) error {
+	state, err := r.syncRouters(ctx, gw, desired)
+	if err != nil {
+		return err
+	}
+
+	gatewayRoutedMetric.DeletePartialMatch(prometheus.Labels{"gateway": gw.Name})
+	gatewayConnectionsMetric.DeletePartialMatch(prometheus.Labels{"gateway": gw.Name})
+	for _, b := range gw.Status.Backends {
+		cond := metav1.Condition{
+			Type:    mcingv1alpha1.ConditionRouted,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "RouterUnavailable",
+			Message: fmt.Sprintf("no mc-router pod of gateway %s answered", gw.Name),
+		}
+		if len(state.routes) != 0 {
+			var missing []string
+			var backend string
+			for _, h := range b.Hostnames() {
+				host := strings.ToLower(h)
+				labels := prometheus.Labels{
+					"gateway":   gw.Name,
+					"namespace": b.Namespace,
+					"name":      b.Name,
+					"hostname":  host,
+				}
+				gatewayConnectionsMetric.With(labels).Set(state.connections[host])
+				if be, ok := state.routed(host); ok {
+					backend = be
+					gatewayRoutedMetric.With(labels).Set(1)
+				} else {
+					missing = append(missing, h)
+					gatewayRoutedMetric.With(labels).Set(0)
+				}
+			}
+			if len(missing) == 0 {
+				cond.Status = metav1.ConditionTrue
+				cond.Reason = "Registered"
+				cond.Message = fmt.Sprintf("mc-router of gateway %s routes %s to %s",
+					gw.Name, strings.Join(b.Hostnames(), ", "), backend)
+			} else {
+				cond.Status = metav1.ConditionFalse
+				cond.Reason = "NotRegistered"
+				cond.Message = fmt.Sprintf("mc-router of gateway %s has not registered %s",
+					gw.Name, strings.Join(missing, ", "))
+			}
+		}
+		if err := r.setRoutedCondition(ctx, b, cond); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *GatewayReconciler) setRoutedCondition(
+	ctx context.Context,
+	b mcingv1alpha1.GatewayBackend,
+	cond metav1.Condition,
+) error {
+	mc := &mcingv1alpha1.Minecraft{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: b.Name}, mc)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	cond.ObservedGeneration = mc.Generation
+	orig := mc.DeepCopy()
+	if !meta.SetStatusCondition(&mc.Status.Conditions, cond) {
+		return nil
+	}
+	patch := client.MergeFromWithOptions(orig, client.MergeFromWithOptimisticLock{})
+	if err := r.Status().Patch(ctx, mc, patch); err != nil {
+		return fmt.Errorf("failed to update the Routed condition of %s/%s: %w", b.Namespace, b.Name, err)
+	}
+	return nil
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
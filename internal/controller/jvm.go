@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
+	"github.com/kmdkuk/mcing/pkg/constants"
+)
+
+// aikarFlags are Aikar's flags for G1GC, the same as USE_AIKAR_FLAGS of itzg/minecraft-server.
+// See https://docs.papermc.io/paper/aikars-flags
+var aikarFlags = []string{
+	"-XX:+UseG1GC",
+	"-XX:+ParallelRefProcEnabled",
+	"-XX:MaxGCPauseMillis=200",
+	"-XX:+UnlockExperimentalVMOptions",
+	"-XX:+DisableExplicitGC",
+	"-XX:+AlwaysPreTouch",
+	"-XX:G1NewSizePercent=30",
+	"-XX:G1MaxNewSizePercent=40",
+	"-XX:G1HeapRegionSize=8M",
+	"-XX:G1ReservePercent=20",
+	"-XX:G1HeapWastePercent=5",
+	"-XX:G1MixedGCCountTarget=4",
+	"-XX:InitiatingHeapOccupancyPercent=15",
+	"-XX:G1MixedGCLiveThresholdPercent=90",
+	"-XX:G1RSetUpdatingPauseTimePercent=5",
+	"-XX:SurvivorRatio=32",
+	"-XX:+PerfDisableSharedMem",
+	"-XX:MaxTenuringThreshold=1",
+	"-Dusing.aikars.flags=https://mcflags.emc.gs",
+	"-Daikars.new.flags=true",
+}
+
+// applyJVM sets the environment variables of itzg/minecraft-server for spec.jvm.
+// The start script of the image reads them, so they also apply when lazymc or the supervisor runs /start.
+func applyJVM(c *corev1.Container, mc *mcingv1alpha1.Minecraft) {
+	jvm := mc.Spec.JVM
+	if heap := jvm.HeapMiB(c); heap > 0 {
+		c.Env = append(c.Env, corev1.EnvVar{Name: constants.MemoryEnvName, Value: strconv.FormatInt(heap, 10) + "M"})
+	}
+	c.Env = append(c.Env, corev1.EnvVar{
+		Name:  constants.AikarFlagsEnvName,
+		Value: strconv.FormatBool(jvm.UseAikarFlags(mc.Spec.Server)),
+	})
+	if len(jvm.ExtraArgs) > 0 {
+		c.Env = append(c.Env, corev1.EnvVar{Name: constants.JVMOptsEnvName, Value: strings.Join(jvm.ExtraArgs, " ")})
+	}
+}
+
+// jvmArgs returns the arguments of java for spec.jvm.
+// They are used when the pod template runs java directly instead of the start script of the image.
+func jvmArgs(mc *mcingv1alpha1.Minecraft, c *corev1.Container) []string {
+	jvm := mc.Spec.JVM
+	var args []string
+	if heap := jvm.HeapMiB(c); heap > 0 {
+		size := strconv.FormatInt(heap, 10) + "M"
+		args = append(args, "-Xms"+size, "-Xmx"+size)
+	}
+	if jvm.UseAikarFlags(mc.Spec.Server) {
+		args = append(args, aikarFlags...)
+	}
+	return append(args, jvm.ExtraArgs...)
+}
+
+// withJVMArgs inserts the arguments for spec.jvm into a command that runs java.
+// Other commands are returned as they are.
+func withJVMArgs(mc *mcingv1alpha1.Minecraft, c *corev1.Container, command []string) []string {
+	if mc.Spec.JVM == nil || len(command) == 0 || path.Base(command[0]) != "java" {
+		return command
+	}
+	return slices.Concat(command[:1], jvmArgs(mc, c), command[1:])
+}
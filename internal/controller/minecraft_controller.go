@@ -2,23 +2,33 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
 	_ "embed"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"path"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -28,7 +38,9 @@ import (
 
 	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
 	"github.com/kmdkuk/mcing/internal/minecraft"
+	"github.com/kmdkuk/mcing/pkg/artifact"
 	"github.com/kmdkuk/mcing/pkg/config"
+	"github.com/kmdkuk/mcing/pkg/configfile"
 	"github.com/kmdkuk/mcing/pkg/constants"
 )
 
@@ -47,6 +59,10 @@ const (
 	autopauseReadinessInitialDelay     = 10
 	autopauseReadinessPeriodSeconds    = 10
 	autopauseReadinessFailureThreshold = 12
+	// snapshotPollInterval is the interval of checking the snapshots in progress.
+	snapshotPollInterval = 5 * time.Second
+	// cloneSourcePollInterval is the interval of checking the PVC of the Minecraft to clone.
+	cloneSourcePollInterval = 10 * time.Second
 )
 
 // MinecraftReconciler reconciles a Minecraft object.
@@ -58,7 +74,7 @@ type MinecraftReconciler struct {
 	initImageName    string
 	agentImageName   string
 	minecraftManager minecraft.MinecraftManager
-	gatewayConfig    GatewayConfig
+	recorder         record.EventRecorder
 }
 
 // NewMinecraftReconciler returns a new MinecraftReconciler.
@@ -68,7 +84,6 @@ func NewMinecraftReconciler(
 	scheme *runtime.Scheme,
 	initImageName, agentImageName string,
 	minecraftManager minecraft.MinecraftManager,
-	gatewayConfig GatewayConfig,
 ) *MinecraftReconciler {
 	l := log.WithName("Minecraft")
 	return &MinecraftReconciler{
@@ -78,23 +93,29 @@ func NewMinecraftReconciler(
 		initImageName:    initImageName,
 		agentImageName:   agentImageName,
 		minecraftManager: minecraftManager,
-		gatewayConfig:    gatewayConfig,
 	}
 }
 
 //+kubebuilder:rbac:groups=mcing.kmdkuk.com,resources=minecrafts,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=mcing.kmdkuk.com,resources=minecrafts/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=mcing.kmdkuk.com,resources=minecrafts/finalizers,verbs=update
+//+kubebuilder:rbac:groups=mcing.kmdkuk.com,resources=minecraftgateways,verbs=get;list;watch
+//+kubebuilder:rbac:groups=mcing.kmdkuk.com,resources=minecraftnetworks,verbs=get;list;watch
 //+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;delete
 //+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create;patch;delete
 
 //go:embed lazymc.toml.tmpl
 var lazymcTomlTmpl string
 
 // LazymcConfig holds configuration for lazymc template rendering.
+// String fields are TOML-quoted, and empty ones are omitted to keep the lazymc defaults.
 type LazymcConfig struct {
 	PublicPort  int32
 	ServerPort  int32
@@ -102,6 +123,122 @@ type LazymcConfig struct {
 	SleepAfter  int32
 	RconEnabled bool
 	RconPort    int32
+
+	FreezeProcess     bool
+	WakeOnStart       bool
+	WakeOnCrash       bool
+	WakeWhitelist     *bool
+	StartTimeout      int32
+	StopTimeout       int32
+	MinimumOnlineTime int32
+
+	MOTDSleeping   string
+	MOTDStarting   string
+	MOTDStopping   string
+	MOTDFromServer bool
+
+	JoinMethods        string
+	KickStarting       string
+	KickStopping       string
+	HoldTimeout        int32
+	ForwardAddress     string
+	ForwardSendProxyV2 bool
+	LobbyTimeout       int32
+	LobbyMessage       string
+
+	LockoutEnabled bool
+	LockoutMessage string
+}
+
+// newLazymcConfig builds the lazymc template values from the Minecraft spec.
+func newLazymcConfig(mc *mcingv1alpha1.Minecraft, command string, rconEnabled bool, rconPort int32) LazymcConfig {
+	var opts mcingv1alpha1.LazymcOptions
+	if mc.Spec.AutoPause.Lazymc != nil {
+		opts = *mc.Spec.AutoPause.Lazymc
+	}
+
+	methods := opts.Join.Methods
+	if len(methods) == 0 {
+		methods = []mcingv1alpha1.LazymcJoinMethod{mcingv1alpha1.LazymcJoinMethodHold, mcingv1alpha1.LazymcJoinMethodKick}
+	}
+	quotedMethods := make([]string, 0, len(methods))
+	for _, m := range methods {
+		quotedMethods = append(quotedMethods, tomlString(string(m)))
+	}
+
+	return LazymcConfig{
+		PublicPort:  constants.ServerPort,
+		ServerPort:  constants.InternalServerPort,
+		Command:     tomlString(command),
+		SleepAfter:  int32(mc.Spec.AutoPause.TimeoutSeconds), //nolint:gosec // timeout is within int32 range
+		RconEnabled: rconEnabled,
+		RconPort:    rconPort,
+
+		FreezeProcess:     opts.FreezeProcess,
+		WakeOnStart:       opts.WakeOnStart,
+		WakeOnCrash:       opts.WakeOnCrash,
+		WakeWhitelist:     opts.WakeWhitelist,
+		StartTimeout:      opts.StartTimeoutSeconds,
+		StopTimeout:       opts.StopTimeoutSeconds,
+		MinimumOnlineTime: opts.MinimumOnlineTimeSeconds,
+
+		MOTDSleeping:   tomlOptionalString(opts.MOTD.Sleeping),
+		MOTDStarting:   tomlOptionalString(opts.MOTD.Starting),
+		MOTDStopping:   tomlOptionalString(opts.MOTD.Stopping),
+		MOTDFromServer: opts.MOTD.FromServer,
+
+		JoinMethods:        strings.Join(quotedMethods, ", "),
+		KickStarting:       tomlOptionalString(opts.Join.Kick.Starting),
+		KickStopping:       tomlOptionalString(opts.Join.Kick.Stopping),
+		HoldTimeout:        opts.Join.Hold.TimeoutSeconds,
+		ForwardAddress:     tomlOptionalString(opts.Join.Forward.Address),
+		ForwardSendProxyV2: opts.Join.Forward.SendProxyV2,
+		LobbyTimeout:       opts.Join.Lobby.TimeoutSeconds,
+		LobbyMessage:       tomlOptionalString(opts.Join.Lobby.Message),
+
+		LockoutEnabled: opts.Lockout.Enabled,
+		LockoutMessage: tomlOptionalString(opts.Lockout.Message),
+	}
+}
+
+// tomlString quotes s as a TOML basic string.
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// tomlOptionalString is like tomlString but keeps an empty string empty.
+func tomlOptionalString(s string) string {
+	if s == "" {
+		return ""
+	}
+	return tomlString(s)
 }
 
 // Reconcile implements Reconciler interface.
@@ -125,7 +262,17 @@ func (r *MinecraftReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 			return ctrl.Result{}, nil
 		}
 
-		log.Info("start finalizing Minecraft")
+		log.Info("start finalizing Minecraft", "deletionPolicy", mc.Spec.DeletionPolicy)
+
+		done, err := r.finalize(ctx, mc)
+		if err != nil {
+			log.Error(err, "failed to finalize Minecraft")
+			return ctrl.Result{}, err
+		}
+		if !done {
+			log.Info("waiting for the final snapshots")
+			return ctrl.Result{RequeueAfter: snapshotPollInterval}, nil
+		}
 
 		controllerutil.RemoveFinalizer(mc, constants.Finalizer)
 		if err := r.Update(ctx, mc); err != nil {
@@ -137,7 +284,32 @@ func (r *MinecraftReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, nil
 	}
 
-	props, err := r.reconcileConfigMap(ctx, mc)
+	if !controllerutil.ContainsFinalizer(mc, constants.Finalizer) {
+		controllerutil.AddFinalizer(mc, constants.Finalizer)
+		if err := r.Update(ctx, mc); err != nil {
+			log.Error(err, "failed to add finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	resolved, err := r.resolveReferences(ctx, mc)
+	if err != nil {
+		log.Error(err, "failed to resolve references")
+		return ctrl.Result{}, err
+	}
+	if !resolved {
+		// The watches on the referenced objects enqueue the Minecraft again once they are created.
+		log.Info("waiting for the referenced objects")
+		return ctrl.Result{}, nil
+	}
+
+	network, err := r.networkFor(ctx, mc)
+	if err != nil {
+		log.Error(err, "failed to get network")
+		return ctrl.Result{}, err
+	}
+
+	props, err := r.reconcileConfigMap(ctx, mc, network)
 	if err != nil {
 		log.Error(err, "failed to reconcile configmap")
 		return ctrl.Result{}, err
@@ -153,17 +325,54 @@ func (r *MinecraftReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
+	restorable, err := r.restoreSnapshotReady(ctx, mc)
+	if err != nil {
+		log.Error(err, "failed to check the snapshot to restore")
+		return ctrl.Result{}, err
+	}
+	if !restorable {
+		log.Info("waiting for the snapshot to restore")
+		return ctrl.Result{RequeueAfter: snapshotPollInterval}, nil
+	}
+
+	cloneable, err := r.cloneSourceReady(ctx, mc)
+	if err != nil {
+		log.Error(err, "failed to check the Minecraft to clone")
+		return ctrl.Result{}, err
+	}
+	if !cloneable {
+		// The PVC of the other Minecraft is not watched, so check it periodically.
+		log.Info("waiting for the Minecraft to clone")
+		return ctrl.Result{RequeueAfter: cloneSourcePollInterval}, nil
+	}
+
 	if err := r.reconcileStatefulSet(ctx, mc, props); err != nil {
 		log.Error(err, "failed to reconcile statefulset")
 		return ctrl.Result{}, err
 	}
 
+	if err := r.reconcileStorage(ctx, mc); err != nil {
+		log.Error(err, "failed to reconcile storage")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.updateStatus(ctx, mc); err != nil {
+		log.Error(err, "failed to update status")
+		return ctrl.Result{}, err
+	}
+
 	if err := r.minecraftManager.Update(client.ObjectKeyFromObject(mc)); err != nil {
 		log.Error(err, "failed to update MinecraftManager")
 		return ctrl.Result{}, err
 	}
+
+	requeueAfter, err := r.reconcileSnapshotBackup(ctx, mc)
+	if err != nil {
+		log.Error(err, "failed to reconcile snapshot backup")
+		return ctrl.Result{}, err
+	}
 	log.Info("finish reconciliation")
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 //nolint:gocognit,funlen // debug logic increases complexity
@@ -174,6 +383,11 @@ func (r *MinecraftReconciler) reconcileStatefulSet(
 ) error {
 	logger := r.log.WithName("statefulset")
 
+	secretRefs, err := r.secretRefs(ctx, mc, props)
+	if err != nil {
+		return err
+	}
+
 	sts := &appsv1.StatefulSet{}
 	sts.Namespace = mc.Namespace
 	sts.Name = mc.PrefixedName()
@@ -187,26 +401,52 @@ func (r *MinecraftReconciler) reconcileStatefulSet(
 		labels := labelSet(mc, constants.AppComponentServer)
 		sts.Labels = config.MergeMap(sts.Labels, labels)
 
-		sts.Spec.Replicas = ptr.To[int32](1)
+		// With hibernation the replicas are scaled to zero by the MinecraftManager
+		// and back to one by the waker, so keep the current value.
+		if !mc.Spec.Hibernation.Enabled || sts.Spec.Replicas == nil {
+			sts.Spec.Replicas = ptr.To[int32](1)
+		}
 		sts.Spec.Selector = &metav1.LabelSelector{
 			MatchLabels: labels,
 		}
 		sts.Spec.ServiceName = mc.HeadlessServiceName()
-		sts.Spec.VolumeClaimTemplates = make([]corev1.PersistentVolumeClaim, len(mc.Spec.VolumeClaimTemplates))
-		for i, v := range mc.Spec.VolumeClaimTemplates {
-			pvc := v.ToCoreV1()
-			pvc.Namespace = mc.Namespace
-			if err := ctrl.SetControllerReference(mc, &pvc, r.scheme); err != nil {
-				panic(err)
+		// The volume claim templates of a StatefulSet are immutable.
+		// The storage request is applied to the existing PVCs by reconcileStorage instead.
+		if sts.CreationTimestamp.IsZero() {
+			sts.Spec.VolumeClaimTemplates = make([]corev1.PersistentVolumeClaim, len(mc.Spec.VolumeClaimTemplates))
+			for i, v := range mc.Spec.VolumeClaimTemplates {
+				pvc := v.ToCoreV1()
+				if pvc.Name == constants.DataVolumeName {
+					if ds := dataVolumeSource(mc); ds != nil {
+						pvc.Spec.DataSource = ds
+					}
+				}
+				pvc.Namespace = mc.Namespace
+				if err := ctrl.SetControllerReference(mc, &pvc, r.scheme); err != nil {
+					panic(err)
+				}
+				pvc.Namespace = ""
+				sts.Spec.VolumeClaimTemplates[i] = pvc
 			}
-			pvc.Namespace = ""
-			sts.Spec.VolumeClaimTemplates[i] = pvc
 		}
+		sts.Spec.PersistentVolumeClaimRetentionPolicy = pvcRetentionPolicy(mc)
 
 		sts.Spec.Template.Annotations = config.MergeMap(sts.Spec.Template.Annotations, mc.Spec.PodTemplate.Annotations)
 		sts.Spec.Template.Labels = config.MergeMap(sts.Spec.Template.Labels, mc.Spec.PodTemplate.Labels)
 		sts.Spec.Template.Labels = config.MergeMap(sts.Spec.Template.Labels, labels)
 
+		// mcing-init installs the mods and plugins and the server loads the resource pack only when the pod starts.
+		hash, err := artifactsHash(props, mc.Spec.ResourcePack)
+		if err != nil {
+			return err
+		}
+		if hash != "" {
+			sts.Spec.Template.Annotations = config.MergeMap(sts.Spec.Template.Annotations,
+				map[string]string{constants.ArtifactsHashAnnotation: hash})
+		} else {
+			delete(sts.Spec.Template.Annotations, constants.ArtifactsHashAnnotation)
+		}
+
 		podSpec := mc.Spec.PodTemplate.Spec.DeepCopy()
 		podSpec.DeprecatedServiceAccount = sts.Spec.Template.Spec.DeprecatedServiceAccount
 		if len(podSpec.RestartPolicy) == 0 {
@@ -238,13 +478,29 @@ func (r *MinecraftReconciler) reconcileStatefulSet(
 			},
 		)
 
-		if *mc.Spec.AutoPause.Enabled {
+		if v := artifactsVolume(mc); v != nil {
+			podSpec.Volumes = append(podSpec.Volumes, *v)
+		}
+		if v := configFilesVolume(mc); v != nil {
+			podSpec.Volumes = append(podSpec.Volumes, *v)
+		}
+		podSpec.Volumes = append(podSpec.Volumes, secretsVolume(secretRefs))
+
+		switch mc.AutoPauseMode() {
+		case mcingv1alpha1.AutoPauseModeLazymc:
 			podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
 				Name: constants.LazymcVolumeName,
 				VolumeSource: corev1.VolumeSource{
 					EmptyDir: &corev1.EmptyDirVolumeSource{},
 				},
 			})
+		case mcingv1alpha1.AutoPauseModeAgent:
+			podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+				Name: constants.AutoPauseVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+				},
+			})
 		}
 
 		containers := make([]corev1.Container, 0)
@@ -303,6 +559,13 @@ func makeMinecraftContainer(
 	c := source.DeepCopy()
 	c.Stdin = true
 	c.TTY = true
+	if mc.Spec.JVM != nil {
+		// Before applyServer so that the GC preset replaces the default flags for Paper.
+		applyJVM(c, mc)
+	}
+	if mc.Spec.Server != nil {
+		applyServer(c, mc.Spec.Server)
+	}
 	c.LivenessProbe = &corev1.Probe{
 		ProbeHandler: corev1.ProbeHandler{
 			Exec: &corev1.ExecAction{
@@ -338,19 +601,22 @@ func makeMinecraftContainer(
 			},
 		},
 	})
-	c.Ports = append(
-		c.Ports,
-		corev1.ContainerPort{
+	if mc.AutoPauseMode() != mcingv1alpha1.AutoPauseModeAgent {
+		// In the agent mode mcing-agent listens on the server port instead.
+		c.Ports = append(c.Ports, corev1.ContainerPort{
 			ContainerPort: constants.ServerPort,
 			Name:          constants.ServerPortName,
 			Protocol:      corev1.ProtocolTCP,
-		},
-		corev1.ContainerPort{
-			ContainerPort: constants.RconPort,
-			Name:          constants.RconPortName,
-			Protocol:      corev1.ProtocolTCP,
-		},
-	)
+		})
+	}
+	c.Ports = append(c.Ports, corev1.ContainerPort{
+		ContainerPort: constants.RconPort,
+		Name:          constants.RconPortName,
+		Protocol:      corev1.ProtocolTCP,
+	})
+	if bedrockEnabled(mc) {
+		c.Ports = append(c.Ports, bedrockContainerPort())
+	}
 	c.VolumeMounts = append(c.VolumeMounts,
 		corev1.VolumeMount{
 			MountPath: constants.DataPath,
@@ -371,39 +637,109 @@ func makeMinecraftContainer(
 		},
 	}
 
-	if *mc.Spec.AutoPause.Enabled {
-		// Override probes to check the public port (lazymc)
-		// Use tcpSocket because lazymc accepts connections even when backend is sleeping
-		c.LivenessProbe = &corev1.Probe{
-			ProbeHandler: corev1.ProbeHandler{
-				TCPSocket: &corev1.TCPSocketAction{
-					Port: intstr.FromInt32(publicPort),
-				},
-			},
-			InitialDelaySeconds: autopauseLivenessInitialDelay,
-			PeriodSeconds:       autopauseLivenessPeriodSeconds,
-		}
-		c.ReadinessProbe = &corev1.Probe{
-			ProbeHandler: corev1.ProbeHandler{
-				TCPSocket: &corev1.TCPSocketAction{
-					Port: intstr.FromInt32(publicPort),
-				},
-			},
-			InitialDelaySeconds: autopauseReadinessInitialDelay,
-			PeriodSeconds:       autopauseReadinessPeriodSeconds,
-			FailureThreshold:    autopauseReadinessFailureThreshold,
-		}
-
+	switch mc.AutoPauseMode() {
+	case mcingv1alpha1.AutoPauseModeLazymc:
+		// lazymc listens on the public port in this container.
+		c.LivenessProbe, c.ReadinessProbe = autoPauseProbes(publicPort)
 		c.Command = []string{filepath.Join(constants.LazymcPath, constants.LazymcBinName)}
 		c.Args = []string{"--config", filepath.Join(constants.LazymcPath, constants.LazymcConfigName)}
 		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
 			Name:      constants.LazymcVolumeName,
 			MountPath: constants.LazymcPath,
 		})
+	case mcingv1alpha1.AutoPauseModeAgent:
+		// The server process stops while sleeping and the supervisor loop keeps the container running,
+		// so the probes are on the agent container, which listens on the public port.
+		c.LivenessProbe = nil
+		c.ReadinessProbe = nil
+		marker := filepath.Join(constants.AutoPausePath, constants.AutoPauseMarkerName)
+		c.Command = []string{"/bin/sh", "-c", buildSupervisorScript(marker, buildServerCommand(mc))}
+		c.Args = nil
+		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+			Name:      constants.AutoPauseVolumeName,
+			MountPath: constants.AutoPausePath,
+		})
+		// Mark the server as sleeping first so that the supervisor does not start it again.
+		c.Lifecycle.PreStop.Exec.Command = []string{
+			"/bin/sh", "-c", fmt.Sprintf("touch %s; rcon-cli stop || true", marker),
+		}
 	}
 	return *c, nil
 }
 
+// autoPauseProbes returns the liveness and readiness probes of the container that listens on the public port
+// with auto-pause. tcpSocket is used because the port accepts connections even while the server is sleeping.
+func autoPauseProbes(port int32) (*corev1.Probe, *corev1.Probe) {
+	liveness := &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			TCPSocket: &corev1.TCPSocketAction{
+				Port: intstr.FromInt32(port),
+			},
+		},
+		InitialDelaySeconds: autopauseLivenessInitialDelay,
+		PeriodSeconds:       autopauseLivenessPeriodSeconds,
+	}
+	readiness := &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			TCPSocket: &corev1.TCPSocketAction{
+				Port: intstr.FromInt32(port),
+			},
+		},
+		InitialDelaySeconds: autopauseReadinessInitialDelay,
+		PeriodSeconds:       autopauseReadinessPeriodSeconds,
+		FailureThreshold:    autopauseReadinessFailureThreshold,
+	}
+	return liveness, readiness
+}
+
+// applyServer sets the image and the environment variables of itzg/minecraft-server for the server software.
+// An image given in the pod template takes precedence.
+func applyServer(c *corev1.Container, server *mcingv1alpha1.Server) {
+	if c.Image == "" {
+		c.Image = server.Image()
+	}
+	c.Env = append(c.Env,
+		corev1.EnvVar{Name: constants.ServerTypeEnvName, Value: server.ServerTypeName()},
+		corev1.EnvVar{Name: constants.ServerVersionEnvName, Value: server.ServerVersion()},
+	)
+	if server.Type == mcingv1alpha1.ServerTypePaper && !slices.ContainsFunc(c.Env, func(e corev1.EnvVar) bool {
+		return e.Name == constants.AikarFlagsEnvName
+	}) {
+		// Aikar's flags tune the garbage collector for Paper.
+		c.Env = append(c.Env, corev1.EnvVar{Name: constants.AikarFlagsEnvName, Value: "true"})
+	}
+}
+
+// buildSupervisorScript returns a shell script that runs the server command
+// again whenever it exits, except while the marker file exists.
+// mcing-agent creates the marker and stops the server over RCON to put it to sleep,
+// and removes the marker to wake it up.
+func buildSupervisorScript(marker, command string) string {
+	return fmt.Sprintf(`trap 'touch %[1]s; kill -TERM "$pid" 2>/dev/null; wait "$pid"; exit 0' TERM INT
+while :; do
+  if [ -e %[1]s ]; then
+    sleep 1
+    continue
+  fi
+  %[3]s
+  %[2]s &
+  pid=$!
+  wait "$pid"
+done
+`, marker, command, recordStartPropsCommand())
+}
+
+// recordStartPropsCommand returns a shell command that records the server.properties in the config
+// before the server starts, as mcing-init does when the pod starts.
+// The server started by auto-pause reads the config at that time, not when the pod started.
+func recordStartPropsCommand() string {
+	return fmt.Sprintf("mkdir -p %s; cp %s %s",
+		filepath.Dir(constants.StartPropsPath),
+		filepath.Join(constants.ConfigPath, constants.ServerPropsName),
+		constants.StartPropsPath,
+	)
+}
+
 func (r *MinecraftReconciler) makeAgentContainer(mc *mcingv1alpha1.Minecraft) corev1.Container {
 	c := corev1.Container{}
 	c.Name = constants.AgentContainerName
@@ -425,11 +761,19 @@ func (r *MinecraftReconciler) makeAgentContainer(mc *mcingv1alpha1.Minecraft) co
 			Name:      constants.ConfigVolumeName,
 			ReadOnly:  true,
 		},
+		corev1.VolumeMount{
+			MountPath: constants.SecretsPath,
+			Name:      constants.SecretsVolumeName,
+			ReadOnly:  true,
+		},
 	)
 
-	rconSecretName := mc.RconSecretName()
-	if mc.Spec.RconPasswordSecretName != nil {
-		rconSecretName = *mc.Spec.RconPasswordSecretName
+	if configFilesVolume(mc) != nil {
+		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+			Name:      constants.ConfigFilesVolumeName,
+			MountPath: constants.ConfigFilesPath,
+			ReadOnly:  true,
+		})
 	}
 
 	c.Env = append(c.Env, corev1.EnvVar{
@@ -437,13 +781,34 @@ func (r *MinecraftReconciler) makeAgentContainer(mc *mcingv1alpha1.Minecraft) co
 		ValueFrom: &corev1.EnvVarSource{
 			SecretKeyRef: &corev1.SecretKeySelector{
 				LocalObjectReference: corev1.LocalObjectReference{
-					Name: rconSecretName,
+					Name: mc.RconSecretName(),
 				},
 				Key: constants.RconPasswordSecretKey,
 			},
 		},
 	})
 
+	if mode := mc.AutoPauseMode(); mode != "" {
+		c.Args = append(c.Args,
+			"--auto-pause-mode="+string(mode),
+			fmt.Sprintf("--auto-pause-timeout=%ds", mc.Spec.AutoPause.TimeoutSeconds),
+		)
+	}
+	if mc.AutoPauseMode() == mcingv1alpha1.AutoPauseModeAgent {
+		c.Ports = append(c.Ports, corev1.ContainerPort{
+			ContainerPort: constants.ServerPort,
+			Name:          constants.ServerPortName,
+			Protocol:      corev1.ProtocolTCP,
+		})
+		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+			Name:      constants.AutoPauseVolumeName,
+			MountPath: constants.AutoPausePath,
+		})
+		// mcing-agent accepts connections on the public port even while the server is sleeping.
+		c.LivenessProbe, c.ReadinessProbe = autoPauseProbes(constants.ServerPort)
+	}
+
+	applyContainerOverride(&c, &mc.Spec.Agent)
 	return c
 }
 
@@ -461,10 +826,15 @@ func (r *MinecraftReconciler) makeInitContainer(mc *mcingv1alpha1.Minecraft) []c
 				MountPath: constants.DataPath,
 				Name:      constants.DataVolumeName,
 			},
+			{
+				MountPath: constants.SecretsPath,
+				Name:      constants.SecretsVolumeName,
+				ReadOnly:  true,
+			},
 		},
 	}
 
-	if *mc.Spec.AutoPause.Enabled {
+	if mc.AutoPauseMode() == mcingv1alpha1.AutoPauseModeLazymc {
 		c.Args = append(c.Args, "--enable-lazymc")
 		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
 			Name:      constants.LazymcVolumeName,
@@ -483,6 +853,36 @@ func (r *MinecraftReconciler) makeInitContainer(mc *mcingv1alpha1.Minecraft) []c
 		})
 	}
 
+	if ds := mc.Spec.DataSource; ds != nil {
+		c.Args = append(c.Args, "--seed-id="+string(mc.UID))
+		if ds.Backup != nil {
+			c.Args = append(c.Args, "--seed-url="+ds.Backup.URL, "--seed-sha256="+ds.Backup.SHA256)
+		}
+		if ds.StripOps {
+			c.Args = append(c.Args, "--strip-ops")
+		}
+		if ds.StripWhitelist {
+			c.Args = append(c.Args, "--strip-whitelist")
+		}
+	}
+
+	if artifactsVolume(mc) != nil {
+		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+			Name:      constants.ArtifactsVolumeName,
+			MountPath: constants.ArtifactsPath,
+			ReadOnly:  true,
+		})
+	}
+	if configFilesVolume(mc) != nil {
+		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+			Name:      constants.ConfigFilesVolumeName,
+			MountPath: constants.ConfigFilesPath,
+			ReadOnly:  true,
+		})
+	}
+
+	applyContainerOverride(&c, &mc.Spec.Init)
+
 	var initContainers []corev1.Container
 	initContainers = append(initContainers, c)
 
@@ -493,20 +893,366 @@ func (r *MinecraftReconciler) makeInitContainer(mc *mcingv1alpha1.Minecraft) []c
 	return initContainers
 }
 
+// artifactManifest returns the files to be installed by mcing-init and the agent, or nil if there are none.
+// Files from ConfigMaps are read from the artifacts volume.
+func artifactManifest(mc *mcingv1alpha1.Minecraft, levelName string) *artifact.Manifest {
+	var resourcePack string
+	if mc.Spec.ResourcePack != nil && mc.Spec.ResourcePack.SHA1 == "" {
+		resourcePack = mc.Spec.ResourcePack.URL
+	}
+	bedrock := bedrockPlugins(mc)
+	if len(mc.Spec.Mods) == 0 && len(mc.Spec.Plugins) == 0 && len(mc.Spec.Datapacks) == 0 && resourcePack == "" &&
+		len(bedrock) == 0 {
+		return nil
+	}
+	convert := func(kind artifact.Kind, artifacts []mcingv1alpha1.Artifact) []artifact.Artifact {
+		var ret []artifact.Artifact
+		for _, a := range artifacts {
+			ia := artifact.Artifact{Name: a.Name, URL: a.URL, OCI: a.OCI, SHA256: a.SHA256}
+			if a.ConfigMap != nil {
+				ia.Path = filepath.Join(constants.ArtifactsPath, string(kind), a.Name)
+			}
+			ret = append(ret, ia)
+		}
+		return ret
+	}
+	m := &artifact.Manifest{
+		Mods:         convert(artifact.KindMod, mc.Spec.Mods),
+		Plugins:      append(convert(artifact.KindPlugin, mc.Spec.Plugins), bedrock...),
+		Datapacks:    convert(artifact.KindDatapack, mc.Spec.Datapacks),
+		ResourcePack: resourcePack,
+	}
+	if len(m.Datapacks) != 0 {
+		m.LevelName = levelName
+	}
+	return m
+}
+
+// artifactsHash returns the digest of the files installed by mcing-init in the generated ConfigMap
+// and the resource pack, or an empty string if there are none.
+// Datapacks are excluded because the agent installs them without restarting the server.
+func artifactsHash(cm *corev1.ConfigMap, rp *mcingv1alpha1.ResourcePack) (string, error) {
+	m := &artifact.Manifest{}
+	if v, ok := cm.Data[constants.ArtifactsName]; ok {
+		if err := json.Unmarshal([]byte(v), m); err != nil {
+			return "", err
+		}
+	}
+	if len(m.Mods) == 0 && len(m.Plugins) == 0 && rp == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(struct {
+		Mods         []artifact.Artifact
+		Plugins      []artifact.Artifact
+		ResourcePack *mcingv1alpha1.ResourcePack
+	}{m.Mods, m.Plugins, rp})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// artifactsVolume returns the volume that projects the mods and plugins stored in ConfigMaps,
+// or nil if there are none.
+func artifactsVolume(mc *mcingv1alpha1.Minecraft) *corev1.Volume {
+	var sources []corev1.VolumeProjection
+	add := func(kind artifact.Kind, artifacts []mcingv1alpha1.Artifact) {
+		for _, a := range artifacts {
+			if a.ConfigMap == nil {
+				continue
+			}
+			sources = append(sources, corev1.VolumeProjection{
+				ConfigMap: &corev1.ConfigMapProjection{
+					LocalObjectReference: a.ConfigMap.LocalObjectReference,
+					Items: []corev1.KeyToPath{
+						{Key: a.ConfigMap.Key, Path: filepath.Join(string(kind), a.Name)},
+					},
+					Optional: a.ConfigMap.Optional,
+				},
+			})
+		}
+	}
+	add(artifact.KindMod, mc.Spec.Mods)
+	add(artifact.KindPlugin, mc.Spec.Plugins)
+	if len(sources) == 0 {
+		return nil
+	}
+	return &corev1.Volume{
+		Name: constants.ArtifactsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources:     sources,
+				DefaultMode: ptr.To[int32](defaultConfigMode),
+			},
+		},
+	}
+}
+
+// secretRefs returns the Secret keys referenced by ${secret:name/key} in the generated ConfigMap
+// and the config files from ConfigMaps.
+func (r *MinecraftReconciler) secretRefs(
+	ctx context.Context,
+	mc *mcingv1alpha1.Minecraft,
+	cm *corev1.ConfigMap,
+) ([]config.SecretRef, error) {
+	var b strings.Builder
+	for _, k := range slices.Sorted(maps.Keys(cm.Data)) {
+		b.WriteString(cm.Data[k])
+		b.WriteString("\n")
+	}
+	for _, f := range mc.Spec.ConfigFiles {
+		if f.ConfigMapKeyRef == nil {
+			continue
+		}
+		src := &corev1.ConfigMap{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: mc.Namespace, Name: f.ConfigMapKeyRef.Name}, src)
+		if apierrors.IsNotFound(err) {
+			// The reference is optional.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(src.Data[f.ConfigMapKeyRef.Key])
+		b.WriteString("\n")
+	}
+	return config.FindSecretRefs(b.String()), nil
+}
+
+// secretsVolume returns the volume that projects the referenced Secret keys at <name>/<key>.
+// The RCON password is always referenced, so the volume always exists.
+func secretsVolume(refs []config.SecretRef) corev1.Volume {
+	var sources []corev1.VolumeProjection
+	for _, ref := range refs {
+		if len(sources) == 0 || sources[len(sources)-1].Secret.Name != ref.Name {
+			sources = append(sources, corev1.VolumeProjection{
+				Secret: &corev1.SecretProjection{
+					LocalObjectReference: corev1.LocalObjectReference{Name: ref.Name},
+				},
+			})
+		}
+		s := sources[len(sources)-1].Secret
+		s.Items = append(s.Items, corev1.KeyToPath{Key: ref.Key, Path: path.Join(ref.Name, ref.Key)})
+	}
+	return corev1.Volume{
+		Name: constants.SecretsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources:     sources,
+				DefaultMode: ptr.To[int32](defaultConfigMode),
+			},
+		},
+	}
+}
+
+// configFilesManifest returns the config files to be placed by mcing-init and the agent, or nil if there are none.
+// Files from ConfigMaps and Secrets are read from the config files volume.
+// The forwarding config of the network is placed after the user files, so it takes precedence.
+func configFilesManifest(mc *mcingv1alpha1.Minecraft, network *mcingv1alpha1.MinecraftNetwork) *configfile.Manifest {
+	forwarding := forwardingConfigFile(mc, network)
+	if len(mc.Spec.ConfigFiles) == 0 && forwarding == nil {
+		return nil
+	}
+	m := &configfile.Manifest{}
+	for _, f := range mc.Spec.ConfigFiles {
+		cf := configfile.File{
+			Path:   f.Path,
+			Inline: f.Inline,
+			Merge:  f.MergeStrategy == mcingv1alpha1.MergeStrategyMerge,
+		}
+		if f.ConfigMapKeyRef != nil || f.SecretKeyRef != nil {
+			cf.Source = filepath.Join(constants.ConfigFilesPath, path.Clean(f.Path))
+		}
+		m.Files = append(m.Files, cf)
+	}
+	if forwarding != nil {
+		m.Files = append(m.Files, *forwarding)
+	}
+	return m
+}
+
+// forwardingConfigFile returns the config file that enables the modern forwarding of Velocity on a Paper backend,
+// or nil if mc is not a Paper server in a network. Other server types need a forwarding mod configured by the user.
+func forwardingConfigFile(mc *mcingv1alpha1.Minecraft, network *mcingv1alpha1.MinecraftNetwork) *configfile.File {
+	if network == nil || mc.Spec.Server == nil || mc.Spec.Server.Type != mcingv1alpha1.ServerTypePaper {
+		return nil
+	}
+	secret := config.SecretRef{Name: network.ForwardingSecretName(), Key: constants.ForwardingSecretKey}
+	return &configfile.File{
+		Path: constants.PaperGlobalConfigPath,
+		Inline: fmt.Sprintf(
+			`{"proxies":{"velocity":{"enabled":true,"online-mode":%t,"secret":%q}}}`,
+			ptr.Deref(network.Spec.OnlineMode, true),
+			secret.Placeholder(),
+		),
+		Merge: true,
+	}
+}
+
+// configFilesVolume returns the volume that projects the config files stored in ConfigMaps and Secrets,
+// or nil if there are none. Each key is projected at the path of its file.
+func configFilesVolume(mc *mcingv1alpha1.Minecraft) *corev1.Volume {
+	var sources []corev1.VolumeProjection
+	for _, f := range mc.Spec.ConfigFiles {
+		switch {
+		case f.ConfigMapKeyRef != nil:
+			sources = append(sources, corev1.VolumeProjection{
+				ConfigMap: &corev1.ConfigMapProjection{
+					LocalObjectReference: f.ConfigMapKeyRef.LocalObjectReference,
+					Items:                []corev1.KeyToPath{{Key: f.ConfigMapKeyRef.Key, Path: path.Clean(f.Path)}},
+					Optional:             f.ConfigMapKeyRef.Optional,
+				},
+			})
+		case f.SecretKeyRef != nil:
+			sources = append(sources, corev1.VolumeProjection{
+				Secret: &corev1.SecretProjection{
+					LocalObjectReference: f.SecretKeyRef.LocalObjectReference,
+					Items:                []corev1.KeyToPath{{Key: f.SecretKeyRef.Key, Path: path.Clean(f.Path)}},
+					Optional:             f.SecretKeyRef.Optional,
+				},
+			})
+		}
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+	return &corev1.Volume{
+		Name: constants.ConfigFilesVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources:     sources,
+				DefaultMode: ptr.To[int32](defaultConfigMode),
+			},
+		},
+	}
+}
+
+// applyContainerOverride applies the user settings to a container managed by MCing.
+// Without a user security context, the container gets one that satisfies the restricted Pod Security Standard.
+func applyContainerOverride(c *corev1.Container, o *mcingv1alpha1.ContainerOverride) {
+	if o.Image != "" {
+		c.Image = o.Image
+	}
+	o.Resources.DeepCopyInto(&c.Resources)
+	if o.SecurityContext != nil {
+		c.SecurityContext = o.SecurityContext.DeepCopy()
+	} else {
+		c.SecurityContext = restrictedSecurityContext()
+	}
+	for i := range o.Env {
+		c.Env = append(c.Env, *o.Env[i].DeepCopy())
+	}
+	for i := range o.VolumeMounts {
+		c.VolumeMounts = append(c.VolumeMounts, *o.VolumeMounts[i].DeepCopy())
+	}
+}
+
+func restrictedSecurityContext() *corev1.SecurityContext {
+	return &corev1.SecurityContext{
+		AllowPrivilegeEscalation: ptr.To(false),
+		RunAsNonRoot:             ptr.To(true),
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+}
+
 func (r *MinecraftReconciler) reconcileAllService(ctx context.Context, mc *mcingv1alpha1.Minecraft) error {
-	err := r.reconcileService(ctx, mc, true)
+	gw, err := r.gatewayFor(ctx, mc)
 	if err != nil {
 		return err
 	}
-	err = r.reconcileService(ctx, mc, false)
+	err = r.reconcileService(ctx, mc, gw, true)
 	if err != nil {
 		return err
 	}
+	err = r.reconcileService(ctx, mc, gw, false)
+	if err != nil {
+		return err
+	}
+	// The Routed condition is reported by the gateway reconciler and is stale once no gateway routes the server.
+	if gw == nil && meta.RemoveStatusCondition(&mc.Status.Conditions, mcingv1alpha1.ConditionRouted) {
+		return r.Status().Update(ctx, mc)
+	}
 	return nil
 }
 
+// gatewayFor returns the MinecraftGateway that routes to mc, or nil if the server is not routed.
+func (r *MinecraftReconciler) gatewayFor(
+	ctx context.Context,
+	mc *mcingv1alpha1.Minecraft,
+) (*mcingv1alpha1.MinecraftGateway, error) {
+	gateways := &mcingv1alpha1.MinecraftGatewayList{}
+	if err := r.List(ctx, gateways); err != nil {
+		return nil, err
+	}
+	return selectGateway(mc, gateways.Items), nil
+}
+
+// networkFor returns the MinecraftNetwork that mc joins, or nil if the server is not in a network.
+func (r *MinecraftReconciler) networkFor(
+	ctx context.Context,
+	mc *mcingv1alpha1.Minecraft,
+) (*mcingv1alpha1.MinecraftNetwork, error) {
+	networks := &mcingv1alpha1.MinecraftNetworkList{}
+	if err := r.List(ctx, networks, client.InNamespace(mc.Namespace)); err != nil {
+		return nil, err
+	}
+	return selectNetwork(mc, networks.Items), nil
+}
+
+// enqueueNetworkBackends returns a handler that enqueues every Minecraft in the namespace of the network,
+// because a change of its selector may add or remove any of them.
+func (r *MinecraftReconciler) enqueueNetworkBackends() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(
+		func(ctx context.Context, obj client.Object) []reconcile.Request {
+			mcs := &mcingv1alpha1.MinecraftList{}
+			if err := r.List(ctx, mcs, client.InNamespace(obj.GetNamespace())); err != nil {
+				r.log.Error(err, "failed to list Minecrafts", "network", client.ObjectKeyFromObject(obj))
+				return nil
+			}
+			reqs := make([]reconcile.Request, 0, len(mcs.Items))
+			for _, mc := range mcs.Items {
+				reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&mc)})
+			}
+			return reqs
+		},
+	)
+}
+
+// enqueueRoutedBy returns a handler that enqueues every Minecraft that the gateway may route to,
+// i.e. those selecting it by name and those using the default gateway.
+func (r *MinecraftReconciler) enqueueRoutedBy() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(
+		func(ctx context.Context, obj client.Object) []reconcile.Request {
+			mcs := &mcingv1alpha1.MinecraftList{}
+			if err := r.List(ctx, mcs); err != nil {
+				r.log.Error(err, "failed to list Minecrafts", "gateway", obj.GetName())
+				return nil
+			}
+			var reqs []reconcile.Request
+			for _, mc := range mcs.Items {
+				if mc.Spec.GatewayName != nil && *mc.Spec.GatewayName != obj.GetName() {
+					continue
+				}
+				reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&mc)})
+			}
+			return reqs
+		},
+	)
+}
+
 //nolint:gocognit,funlen // debug logic increases complexity and length
-func (r *MinecraftReconciler) reconcileService(ctx context.Context, mc *mcingv1alpha1.Minecraft, headless bool) error {
+func (r *MinecraftReconciler) reconcileService(
+	ctx context.Context,
+	mc *mcingv1alpha1.Minecraft,
+	gw *mcingv1alpha1.MinecraftGateway,
+	headless bool,
+) error {
 	logger := r.log.WithName("service")
 
 	svc := &corev1.Service{}
@@ -524,22 +1270,18 @@ func (r *MinecraftReconciler) reconcileService(ctx context.Context, mc *mcingv1a
 		sSpec := &corev1.ServiceSpec{}
 		tmpl := mc.Spec.ServiceTemplate
 
-		// Handle service configuration based on type and mc-router settings
-		switch {
-		case !headless && r.gatewayConfig.Enabled:
-			// When mc-router is enabled and this is NOT the headless service,
-			// force ClusterIP type and add the mc-router annotation
-			externalServerName := mc.GetExternalServerName(r.gatewayConfig.DefaultDomain)
-			if svc.Annotations == nil {
-				svc.Annotations = make(map[string]string)
-			}
-			svc.Annotations[constants.MCRouterAnnotation] = externalServerName
+		// Remove the mc-router annotation set by older versions, which made every mc-router in the cluster
+		// route the server. The gateway controller registers the routes on the mc-router of its gateway instead.
+		// The template may still set it below.
+		delete(svc.Annotations, constants.MCRouterAnnotation)
 
-			// Still allow other annotations, labels, and spec from template
+		// Handle service configuration based on type and gateway settings
+		switch {
+		case !headless && gw != nil:
+			// When a gateway routes to the server and this is NOT the headless service,
+			// force ClusterIP type because players connect through the gateway.
 			if tmpl != nil {
 				svc.Annotations = config.MergeMap(svc.Annotations, tmpl.Annotations)
-				// Ensure mc-router annotation is not overwritten
-				svc.Annotations[constants.MCRouterAnnotation] = externalServerName
 				svc.Labels = config.MergeMap(svc.Labels, tmpl.Labels)
 				// Apply ServiceTemplate.Spec settings (e.g., sessionAffinity, ports)
 				if tmpl.Spec != nil {
@@ -548,7 +1290,7 @@ func (r *MinecraftReconciler) reconcileService(ctx context.Context, mc *mcingv1a
 			}
 			svc.Labels = config.MergeMap(svc.Labels, labels)
 
-			// Force ClusterIP type when mc-router is enabled (overrides template)
+			// Force ClusterIP type when a gateway routes to the server (overrides template)
 			sSpec.Type = corev1.ServiceTypeClusterIP
 		case !headless && tmpl != nil:
 			svc.Annotations = config.MergeMap(svc.Annotations, tmpl.Annotations)
@@ -592,7 +1334,7 @@ func (r *MinecraftReconciler) reconcileService(ctx context.Context, mc *mcingv1a
 		}
 		sSpec.Selector = labels
 
-		var serverNodePort, rconNodePort int32
+		var serverNodePort, rconNodePort, bedrockNodePort int32
 		if sSpec.Type != corev1.ServiceTypeClusterIP {
 			for _, p := range svc.Spec.Ports {
 				switch p.Name {
@@ -600,6 +1342,8 @@ func (r *MinecraftReconciler) reconcileService(ctx context.Context, mc *mcingv1a
 					serverNodePort = p.NodePort
 				case constants.RconPortName:
 					rconNodePort = p.NodePort
+				case constants.BedrockPortName:
+					bedrockNodePort = p.NodePort
 				}
 			}
 		}
@@ -610,6 +1354,10 @@ func (r *MinecraftReconciler) reconcileService(ctx context.Context, mc *mcingv1a
 			TargetPort: intstr.FromString(constants.ServerPortName),
 			NodePort:   serverNodePort,
 		})
+		if bedrockEnabled(mc) {
+			// mc-router only routes Java Edition, so Bedrock Edition players connect to this port directly.
+			sSpec.Ports = append(sSpec.Ports, bedrockServicePort(bedrockNodePort))
+		}
 
 		if headless || sSpec.Type != corev1.ServiceTypeLoadBalancer {
 			sSpec.Ports = append(sSpec.Ports, corev1.ServicePort{
@@ -641,6 +1389,40 @@ func (r *MinecraftReconciler) reconcileService(ctx context.Context, mc *mcingv1a
 	return nil
 }
 
+// mergeSpecProps returns the user properties overridden by spec.serverProperties and spec.resourcePack.
+// Without spec.resourcePack.sha1, the digest computed by mcing-init is used once it is reported in the status.
+func mergeSpecProps(mc *mcingv1alpha1.Minecraft, userProps map[string]string) map[string]string {
+	if mc.Spec.ServerProperties != nil {
+		userProps = config.MergeMap(userProps, mc.Spec.ServerProperties.Properties())
+	}
+	// mcing-init replaces the reference with the password, so it does not appear in the ConfigMap.
+	rconPassword := config.SecretRef{Name: mc.RconSecretName(), Key: constants.RconPasswordSecretKey}
+	userProps = config.MergeMap(userProps, map[string]string{constants.RconPasswordProps: rconPassword.Placeholder()})
+	rp := mc.Spec.ResourcePack
+	if rp == nil {
+		return userProps
+	}
+	sha1 := rp.SHA1
+	if sha1 == "" && mc.Status.ResourcePack != nil && mc.Status.ResourcePack.URL == rp.URL {
+		sha1 = mc.Status.ResourcePack.SHA1
+	}
+	return config.MergeMap(userProps, map[string]string{
+		constants.ResourcePackProps:        rp.URL,
+		constants.ResourcePackSHA1Props:    sha1,
+		constants.RequireResourcePackProps: strconv.FormatBool(rp.Required),
+		constants.ResourcePackPromptProps:  rp.Prompt,
+	})
+}
+
+// mergeNetworkProps returns the properties overridden for a backend of a network.
+// The proxy authenticates the players, so the backend must not.
+func mergeNetworkProps(network *mcingv1alpha1.MinecraftNetwork, props map[string]string) map[string]string {
+	if network == nil {
+		return props
+	}
+	return config.MergeMap(props, map[string]string{constants.OnlineModeProps: "false"})
+}
+
 // buildServerCommand constructs the server command from container spec.
 func buildServerCommand(mc *mcingv1alpha1.Minecraft) string {
 	cmd := "/start"
@@ -654,15 +1436,23 @@ func buildServerCommand(mc *mcingv1alpha1.Minecraft) string {
 		parts := make([]string, 0, len(container.Command)+len(container.Args))
 		parts = append(parts, container.Command...)
 		parts = append(parts, container.Args...)
-		return strings.Join(parts, " ")
+		return strings.Join(withJVMArgs(mc, &container, parts), " ")
 	}
 	return cmd
 }
 
+// buildLazymcCommand returns the server command run by lazymc.
+// lazymc splits the command like a shell, so the script is quoted for sh -c.
+func buildLazymcCommand(mc *mcingv1alpha1.Minecraft) string {
+	script := recordStartPropsCommand() + "; exec " + buildServerCommand(mc)
+	return "/bin/sh -c '" + strings.ReplaceAll(script, "'", `'\''`) + "'"
+}
+
 //nolint:gocognit,funlen // config map reconciliation has many conditional paths
 func (r *MinecraftReconciler) reconcileConfigMap(
 	ctx context.Context,
 	mc *mcingv1alpha1.Minecraft,
+	network *mcingv1alpha1.MinecraftNetwork,
 ) (*corev1.ConfigMap, error) {
 	logger := r.log.WithName("configmap")
 
@@ -681,12 +1471,17 @@ func (r *MinecraftReconciler) reconcileConfigMap(
 		userProps = cm.Data
 	}
 
-	props, err := config.GenServerProps(userProps)
+	props, err := config.GenServerProps(mergeNetworkProps(network, mergeSpecProps(mc, userProps)))
 	if err != nil {
 		return nil, err
 	}
+	parsedProps, err := config.ParseServerProps(strings.NewReader(props))
+	if err != nil {
+		return nil, err
+	}
+	levelName := parsedProps[constants.LevelNameProps]
 
-	if *mc.Spec.AutoPause.Enabled {
+	if mc.AutoPauseEnabled() {
 		// Force internal port by replacing the enforced standard port
 		target := fmt.Sprintf("server-port=%d", constants.ServerPort)
 		replacement := fmt.Sprintf("server-port=%d", constants.InternalServerPort)
@@ -699,6 +1494,7 @@ func (r *MinecraftReconciler) reconcileConfigMap(
 		err = r.Get(ctx, types.NamespacedName{Namespace: mc.Namespace, Name: *mc.Spec.OtherConfigMapName}, cm)
 		if err != nil {
 			logger.Error(err, "failed to get configmap", "configmap", *mc.Spec.OtherConfigMapName)
+			return nil, err
 		}
 		otherProps = cm.Data
 	}
@@ -723,12 +1519,26 @@ func (r *MinecraftReconciler) reconcileConfigMap(
 		if v, ok := otherProps[constants.WhiteListName]; ok {
 			cm.Data[constants.WhiteListName] = v
 		}
+		if m := artifactManifest(mc, levelName); m != nil {
+			data, err := json.Marshal(m)
+			if err != nil {
+				return err
+			}
+			cm.Data[constants.ArtifactsName] = string(data)
+		}
+		if m := configFilesManifest(mc, network); m != nil {
+			data, err := json.Marshal(m)
+			if err != nil {
+				return err
+			}
+			cm.Data[constants.ConfigFilesName] = string(data)
+		}
 
 		// Generate lazymc.toml from templates
 		//nolint:nestif // autopause configuration adds necessary nesting
-		if *mc.Spec.AutoPause.Enabled {
+		if mc.AutoPauseMode() == mcingv1alpha1.AutoPauseModeLazymc {
 			// Determine backend command
-			cmd := buildServerCommand(mc)
+			cmd := buildLazymcCommand(mc)
 
 			rconEnabled := true
 			rconPort := constants.RconPort
@@ -745,14 +1555,7 @@ func (r *MinecraftReconciler) reconcileConfigMap(
 			}
 
 			// The rcon password is injected by mcing-init from secret via env.
-			lazymcConfig := LazymcConfig{
-				PublicPort:  constants.ServerPort,
-				ServerPort:  constants.InternalServerPort,
-				Command:     cmd,
-				SleepAfter:  int32(mc.Spec.AutoPause.TimeoutSeconds), //nolint:gosec // timeout is within int32 range
-				RconEnabled: rconEnabled,
-				RconPort:    rconPort,
-			}
+			lazymcConfig := newLazymcConfig(mc, cmd, rconEnabled, rconPort)
 
 			lazymcToml, lazymcTomlErr := config.ExecuteTemplate(lazymcTomlTmpl, lazymcConfig)
 			if lazymcTomlErr != nil {
@@ -774,6 +1577,67 @@ func (r *MinecraftReconciler) reconcileConfigMap(
 	return cm, nil
 }
 
+// updateStatus reports the mods and plugins installed by mcing-init in the running pod.
+// mcing-init writes them into its termination message.
+func (r *MinecraftReconciler) updateStatus(ctx context.Context, mc *mcingv1alpha1.Minecraft) error {
+	logger := r.log.WithName("status")
+
+	pod := &corev1.Pod{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: mc.Namespace, Name: mc.PodName()}, pod)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var report *artifact.Report
+	for _, s := range pod.Status.InitContainerStatuses {
+		if s.Name != constants.InitContainerName || s.State.Terminated == nil || s.State.Terminated.ExitCode != 0 {
+			continue
+		}
+		report = &artifact.Report{}
+		if msg := s.State.Terminated.Message; msg != "" {
+			if err := json.Unmarshal([]byte(msg), report); err != nil {
+				logger.Error(err, "failed to parse the termination message of mcing-init", "pod", pod.Name)
+				return nil
+			}
+		}
+	}
+	if report == nil {
+		return nil
+	}
+
+	status := mcingv1alpha1.MinecraftStatus{}
+	mc.Status.DeepCopyInto(&status)
+	status.Mods = installedArtifacts(report.Mods)
+	status.Plugins = installedArtifacts(report.Plugins)
+	status.ResourcePack = nil
+	if report.ResourcePack != nil {
+		status.ResourcePack = &mcingv1alpha1.InstalledResourcePack{
+			URL:  report.ResourcePack.URL,
+			SHA1: report.ResourcePack.SHA1,
+		}
+	}
+	if equality.Semantic.DeepEqual(status, mc.Status) {
+		return nil
+	}
+	mc.Status = status
+	if err := r.Status().Update(ctx, mc); err != nil {
+		return err
+	}
+	logger.Info("updated status", "mods", len(status.Mods), "plugins", len(status.Plugins))
+	return nil
+}
+
+func installedArtifacts(installed []artifact.Installed) []mcingv1alpha1.InstalledArtifact {
+	var ret []mcingv1alpha1.InstalledArtifact
+	for _, i := range installed {
+		ret = append(ret, mcingv1alpha1.InstalledArtifact{Name: i.Name, SHA256: i.SHA256})
+	}
+	return ret
+}
+
 func (r *MinecraftReconciler) reconcileRconSecret(ctx context.Context, mc *mcingv1alpha1.Minecraft) error {
 	logger := r.log.WithName("rcon-secret")
 	if mc.Spec.RconPasswordSecretName != nil {
@@ -816,29 +1680,29 @@ func (r *MinecraftReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if err := mgr.Add(r.minecraftManager); err != nil {
 		return err
 	}
-	configMapHandler := handler.EnqueueRequestsFromMapFunc(
-		func(ctx context.Context, a client.Object) []reconcile.Request {
-			mcs := &mcingv1alpha1.MinecraftList{}
-			if err := r.List(ctx, mcs, client.InNamespace(a.GetNamespace())); err != nil {
-				return nil
-			}
-			var reqs []reconcile.Request
-			for _, mc := range mcs.Items {
-				if mc.Spec.ServerPropertiesConfigMapName == nil {
-					continue
-				}
-				if *mc.Spec.ServerPropertiesConfigMapName == a.GetName() {
-					reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&mc)})
-				}
-			}
-			return reqs
-		},
-	)
+	r.recorder = mgr.GetEventRecorderFor("mcing-controller")
+	indexer := mgr.GetFieldIndexer()
+	if err := indexer.IndexField(context.Background(), &mcingv1alpha1.Minecraft{},
+		configMapRefsIndex, referencedNames(kindConfigMap)); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(context.Background(), &mcingv1alpha1.Minecraft{},
+		secretRefsIndex, referencedNames(kindSecret)); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(context.Background(), &mcingv1alpha1.Minecraft{},
+		missingSecretIndex, missingSecretName); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&mcingv1alpha1.Minecraft{}).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.ConfigMap{}).
-		Watches(&corev1.ConfigMap{}, configMapHandler).
+		Owns(&corev1.PersistentVolumeClaim{}).
+		Watches(&corev1.ConfigMap{}, r.enqueueReferencing(configMapRefsIndex)).
+		Watches(&corev1.Secret{}, r.enqueueReferencing(secretRefsIndex, missingSecretIndex)).
+		Watches(&mcingv1alpha1.MinecraftGateway{}, r.enqueueRoutedBy()).
+		Watches(&mcingv1alpha1.MinecraftNetwork{}, r.enqueueNetworkBackends()).
 		Complete(r)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -13,11 +14,19 @@ import (
 	. "github.com/onsi/gomega/gstruct" //nolint:revive // dot imports for tests
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/config"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
@@ -32,6 +41,7 @@ var _ = Describe("Minecraft controller", func() {
 	ctx := context.Background()
 	var mgrCtx context.Context
 	var mgrCancel context.CancelFunc
+	var mockMinecraftMgr *mockManager
 
 	BeforeEach(func() {
 		ms := &mcingv1alpha1.MinecraftList{}
@@ -70,8 +80,9 @@ var _ = Describe("Minecraft controller", func() {
 
 		log := ctrl.Log.WithName("controllers")
 
-		mockMinecraftMgr := &mockManager{ //nolint:exhaustruct // internal struct
+		mockMinecraftMgr = &mockManager{ //nolint:exhaustruct // internal struct
 			minecrafts: make(map[string]struct{}),
+			savedOff:   make(map[string]bool),
 		}
 
 		r := NewMinecraftReconciler(
@@ -81,9 +92,6 @@ var _ = Describe("Minecraft controller", func() {
 			"ghcr.io/kmdkuk/mcing-init:"+strings.TrimPrefix(version.Version, "v"),
 			"ghcr.io/kmdkuk/mcing-agent:"+strings.TrimPrefix(version.Version, "v"),
 			mockMinecraftMgr,
-			GatewayConfig{ //nolint:exhaustruct // mc-router disabled by default for existing tests
-				Enabled: false,
-			},
 		)
 		err = r.SetupWithManager(mgr)
 		Expect(err).ToNot(HaveOccurred())
@@ -201,12 +209,575 @@ var _ = Describe("Minecraft controller", func() {
 					"Name":      Equal(constants.ConfigVolumeName),
 					"MountPath": Equal(constants.ConfigPath),
 				}),
+				"2": MatchFields(IgnoreExtras, Fields{
+					"Name":      Equal(constants.SecretsVolumeName),
+					"MountPath": Equal(constants.SecretsPath),
+				}),
 			}),
 		}))
 		Expect(s.Spec.VolumeClaimTemplates).To(HaveLen(1))
 		Expect(s.Spec.VolumeClaimTemplates[0].ObjectMeta.Name).To(Equal("minecraft-data"))
 	})
 
+	It("should apply the agent and init container overrides", func() {
+		By("deploying Minecraft resource with container overrides")
+		mc := makeMinecraft("container-overrides", namespace)
+		mc.Spec.PodTemplate.Spec.Volumes = []corev1.Volume{
+			{
+				Name:         "extra",
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			},
+		}
+		mc.Spec.Agent = mcingv1alpha1.ContainerOverride{
+			Image: "example.com/mcing-agent:dev",
+			Resources: corev1.ResourceRequirements{
+				//nolint:exhaustive // test data
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("50m"),
+				},
+			},
+			Env:          []corev1.EnvVar{{Name: "FOO", Value: "bar"}},
+			VolumeMounts: []corev1.VolumeMount{{Name: "extra", MountPath: "/extra"}},
+		}
+		mc.Spec.Init = mcingv1alpha1.ContainerOverride{
+			SecurityContext: &corev1.SecurityContext{RunAsUser: ptr.To[int64](2000)},
+		}
+		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+		By("getting the created StatefulSet")
+		s := new(appsv1.StatefulSet)
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: mc.PrefixedName(), Namespace: namespace}, s)
+		}).Should(Succeed())
+
+		agent := s.Spec.Template.Spec.Containers[1]
+		Expect(agent.Name).To(Equal(constants.AgentContainerName))
+		Expect(agent.Image).To(Equal("example.com/mcing-agent:dev"))
+		Expect(agent.Resources.Requests.Cpu().String()).To(Equal("50m"))
+		Expect(agent.Env).To(ContainElement(corev1.EnvVar{Name: "FOO", Value: "bar"}))
+		Expect(agent.VolumeMounts).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+			"Name":      Equal("extra"),
+			"MountPath": Equal("/extra"),
+		})))
+		Expect(agent.SecurityContext).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"AllowPrivilegeEscalation": PointTo(BeFalse()),
+			"RunAsNonRoot":             PointTo(BeTrue()),
+			"Capabilities": PointTo(MatchFields(IgnoreExtras, Fields{
+				"Drop": Equal([]corev1.Capability{"ALL"}),
+			})),
+			"SeccompProfile": PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type": Equal(corev1.SeccompProfileTypeRuntimeDefault),
+			})),
+		})))
+
+		initContainer := s.Spec.Template.Spec.InitContainers[0]
+		Expect(initContainer.Name).To(Equal(constants.InitContainerName))
+		Expect(initContainer.Image).To(Equal("ghcr.io/kmdkuk/mcing-init:" + strings.TrimPrefix(version.Version, "v")))
+		Expect(initContainer.SecurityContext).To(Equal(&corev1.SecurityContext{RunAsUser: ptr.To[int64](2000)}))
+	})
+
+	It("should translate spec.server into the image and environment", func() {
+		By("deploying Minecraft resource with the Paper server")
+		mc := makeMinecraft("server-paper", namespace)
+		mc.Spec.PodTemplate.Spec.Containers[0].Image = ""
+		mc.Spec.Server = &mcingv1alpha1.Server{
+			Type:    mcingv1alpha1.ServerTypePaper,
+			Version: "1.21.1",
+		}
+		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+		By("getting the created StatefulSet")
+		s := new(appsv1.StatefulSet)
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: mc.PrefixedName(), Namespace: namespace}, s)
+		}).Should(Succeed())
+
+		c := s.Spec.Template.Spec.Containers[0]
+		Expect(c.Image).To(Equal("itzg/minecraft-server:java21"))
+		Expect(c.Env).To(ContainElements(
+			corev1.EnvVar{Name: constants.ServerTypeEnvName, Value: "PAPER"},
+			corev1.EnvVar{Name: constants.ServerVersionEnvName, Value: "1.21.1"},
+			corev1.EnvVar{Name: constants.AikarFlagsEnvName, Value: "true"},
+		))
+	})
+
+	It("should translate spec.jvm into the heap size and the JVM flags", func() {
+		By("deploying Minecraft resource with a memory limit and spec.jvm")
+		mc := makeMinecraft("jvm", namespace)
+		mc.Spec.PodTemplate.Spec.Containers[0].Resources.Limits = corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("4Gi"),
+		}
+		mc.Spec.Server = &mcingv1alpha1.Server{Type: mcingv1alpha1.ServerTypePaper}
+		mc.Spec.JVM = &mcingv1alpha1.JVM{
+			HeapPercentage: 75,
+			GCPreset:       mcingv1alpha1.GCPresetDefault,
+			ExtraArgs:      []string{"-XX:+UseZGC", "-XX:+ZGenerational"},
+		}
+		mc.Spec.AutoPause = mcingv1alpha1.AutoPause{
+			TimeoutSeconds: 600,
+			Mode:           mcingv1alpha1.AutoPauseModeAgent,
+		}
+		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+		By("getting the created StatefulSet")
+		s := new(appsv1.StatefulSet)
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: mc.PrefixedName(), Namespace: namespace}, s)
+		}).Should(Succeed())
+
+		c := s.Spec.Template.Spec.Containers[0]
+		Expect(c.Env).To(ContainElements(
+			corev1.EnvVar{Name: constants.MemoryEnvName, Value: "3072M"},
+			corev1.EnvVar{Name: constants.AikarFlagsEnvName, Value: "false"},
+			corev1.EnvVar{Name: constants.JVMOptsEnvName, Value: "-XX:+UseZGC -XX:+ZGenerational"},
+		))
+		Expect(c.Env).NotTo(ContainElement(corev1.EnvVar{Name: constants.AikarFlagsEnvName, Value: "true"}))
+		Expect(c.Command[2]).To(ContainSubstring("/start"))
+	})
+
+	It("should insert the JVM flags into a custom java command", func() {
+		By("deploying Minecraft resource that runs java directly")
+		mc := makeMinecraft("jvm-command", namespace)
+		mc.Spec.PodTemplate.Spec.Containers[0].Command = []string{"java"}
+		mc.Spec.PodTemplate.Spec.Containers[0].Args = []string{"-jar", "server.jar", "nogui"}
+		mc.Spec.PodTemplate.Spec.Containers[0].Resources.Limits = corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("2Gi"),
+		}
+		mc.Spec.JVM = &mcingv1alpha1.JVM{
+			HeapPercentage: 50,
+			GCPreset:       mcingv1alpha1.GCPresetAikar,
+		}
+		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+		By("checking the backend command of lazymc")
+		generatedCm := &corev1.ConfigMap{}
+		Eventually(func() error {
+			return k8sClient.Get(
+				ctx,
+				types.NamespacedName{Namespace: mc.Namespace, Name: mc.PrefixedName()},
+				generatedCm,
+			)
+		}).Should(Succeed())
+		Expect(generatedCm.Data[constants.LazymcConfigName]).To(
+			ContainSubstring("java -Xms1024M -Xmx1024M -XX:+UseG1GC "),
+		)
+		Expect(generatedCm.Data[constants.LazymcConfigName]).To(
+			ContainSubstring("-Daikars.new.flags=true -jar server.jar nogui"),
+		)
+	})
+
+	It("should install mods and plugins and report them in status", func() {
+		By("deploying Minecraft resource with mods and plugins")
+		digest := strings.Repeat("a", 64)
+		mc := makeMinecraft("artifacts", namespace)
+		mc.Spec.Mods = []mcingv1alpha1.Artifact{
+			{Name: "fabric-api.jar", URL: "https://example.com/fabric-api.jar", SHA256: digest},
+		}
+		mc.Spec.Plugins = []mcingv1alpha1.Artifact{
+			{
+				Name: "custom.jar",
+				ConfigMap: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "plugins"},
+					Key:                  "custom.jar",
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+		By("checking the artifact manifest in the generated ConfigMap")
+		generatedCm := &corev1.ConfigMap{}
+		Eventually(func() error {
+			return k8sClient.Get(
+				ctx,
+				types.NamespacedName{Namespace: mc.Namespace, Name: mc.PrefixedName()},
+				generatedCm,
+			)
+		}).Should(Succeed())
+		Expect(generatedCm.Data[constants.ArtifactsName]).To(MatchJSON(`{
+			"mods": [{"name": "fabric-api.jar", "url": "https://example.com/fabric-api.jar", "sha256": "` + digest + `"}],
+			"plugins": [{"name": "custom.jar", "path": "/mcing-artifacts/plugins/custom.jar"}]
+		}`))
+
+		By("checking the StatefulSet")
+		s := new(appsv1.StatefulSet)
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: mc.PrefixedName(), Namespace: namespace}, s)
+		}).Should(Succeed())
+		Expect(s.Spec.Template.Annotations).To(HaveKey(constants.ArtifactsHashAnnotation))
+		Expect(s.Spec.Template.Spec.Volumes).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+			"Name": Equal(constants.ArtifactsVolumeName),
+			"VolumeSource": MatchFields(IgnoreExtras, Fields{
+				"Projected": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Sources": ConsistOf(corev1.VolumeProjection{
+						ConfigMap: &corev1.ConfigMapProjection{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "plugins"},
+							Items:                []corev1.KeyToPath{{Key: "custom.jar", Path: "plugins/custom.jar"}},
+						},
+					}),
+				})),
+			}),
+		})))
+		Expect(s.Spec.Template.Spec.InitContainers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      constants.ArtifactsVolumeName,
+			MountPath: constants.ArtifactsPath,
+			ReadOnly:  true,
+		}))
+
+		By("reporting the installed files from the termination message of mcing-init")
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: mc.PodName(), Namespace: namespace},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: constants.InitContainerName, Image: "mcing-init"}},
+				Containers:     []corev1.Container{{Name: constants.MinecraftContainerName, Image: "minecraft"}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+		pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+			{
+				Name: constants.InitContainerName,
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{
+						ExitCode: 0,
+						Message:  `{"mods":[{"name":"fabric-api.jar","sha256":"` + digest + `"}],"plugins":[{"name":"custom.jar"}]}`,
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+		// Touch the Minecraft to trigger a reconciliation.
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc)).To(Succeed())
+		mc.Annotations = map[string]string{"test": "artifacts"}
+		Expect(k8sClient.Update(ctx, mc)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc)).To(Succeed())
+			g.Expect(mc.Status.Mods).To(Equal([]mcingv1alpha1.InstalledArtifact{{Name: "fabric-api.jar", SHA256: digest}}))
+			g.Expect(mc.Status.Plugins).To(Equal([]mcingv1alpha1.InstalledArtifact{{Name: "custom.jar"}}))
+		}).Should(Succeed())
+		Expect(k8sClient.Delete(ctx, pod)).To(Succeed())
+	})
+
+	It("should install Geyser and expose the Bedrock port", func() {
+		By("deploying Minecraft resource with spec.bedrock")
+		mc := makeMinecraft("bedrock", namespace)
+		mc.Spec.Server = &mcingv1alpha1.Server{Type: mcingv1alpha1.ServerTypePaper, Version: "1.21.1"}
+		mc.Spec.Bedrock = &mcingv1alpha1.Bedrock{Enabled: true, Floodgate: true}
+		mc.Spec.ServiceTemplate = &mcingv1alpha1.ServiceTemplate{
+			Spec: &corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort},
+		}
+		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+		By("checking the plugins in the artifact manifest")
+		generatedCm := &corev1.ConfigMap{}
+		Eventually(func() error {
+			return k8sClient.Get(
+				ctx,
+				types.NamespacedName{Namespace: mc.Namespace, Name: mc.PrefixedName()},
+				generatedCm,
+			)
+		}).Should(Succeed())
+		Expect(generatedCm.Data[constants.ArtifactsName]).To(MatchJSON(`{
+			"plugins": [
+				{"name": "` + constants.GeyserPluginName + `", "url": "` + constants.GeyserPluginURL + `"},
+				{"name": "` + constants.FloodgatePluginName + `", "url": "` + constants.FloodgatePluginURL + `"}
+			]
+		}`))
+
+		By("pinning the build of Geyser")
+		geyserSHA256 := strings.Repeat("a", 64)
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc)).To(Succeed())
+		mc.Spec.Bedrock.GeyserDownload = &mcingv1alpha1.PluginDownload{
+			URL:    "https://example.com/Geyser-Spigot.jar",
+			SHA256: geyserSHA256,
+		}
+		Expect(k8sClient.Update(ctx, mc)).To(Succeed())
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(generatedCm), generatedCm)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(generatedCm.Data[constants.ArtifactsName]).To(MatchJSON(`{
+				"plugins": [
+					{"name": "` + constants.GeyserPluginName + `", "url": "https://example.com/Geyser-Spigot.jar",
+						"sha256": "` + geyserSHA256 + `"},
+					{"name": "` + constants.FloodgatePluginName + `", "url": "` + constants.FloodgatePluginURL + `"}
+				]
+			}`))
+		}).Should(Succeed())
+
+		By("checking the container port")
+		s := new(appsv1.StatefulSet)
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: mc.PrefixedName(), Namespace: namespace}, s)
+		}).Should(Succeed())
+		Expect(s.Spec.Template.Annotations).To(HaveKey(constants.ArtifactsHashAnnotation))
+		Expect(s.Spec.Template.Spec.Containers[0].Ports).To(ContainElement(corev1.ContainerPort{
+			Name:          constants.BedrockPortName,
+			ContainerPort: constants.BedrockPort,
+			Protocol:      corev1.ProtocolUDP,
+		}))
+
+		By("checking the UDP port of the Service")
+		svc := &corev1.Service{}
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: mc.PrefixedName(), Namespace: namespace}, svc)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(svc.Spec.Ports).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Name":     Equal(constants.BedrockPortName),
+				"Protocol": Equal(corev1.ProtocolUDP),
+				"Port":     Equal(constants.BedrockPort),
+				"NodePort": Not(BeZero()),
+			})))
+		}).Should(Succeed())
+		nodePort := svc.Spec.Ports[slices.IndexFunc(svc.Spec.Ports, func(p corev1.ServicePort) bool {
+			return p.Name == constants.BedrockPortName
+		})].NodePort
+
+		By("keeping the node port on update")
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc)).To(Succeed())
+		mc.Annotations = map[string]string{"test": "bedrock"}
+		Expect(k8sClient.Update(ctx, mc)).To(Succeed())
+		Consistently(func(g Gomega) {
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: mc.PrefixedName(), Namespace: namespace}, svc)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(svc.Spec.Ports).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Name":     Equal(constants.BedrockPortName),
+				"NodePort": Equal(nodePort),
+			})))
+		}, 2*time.Second).Should(Succeed())
+	})
+
+	It("should configure datapacks and the resource pack", func() {
+		By("deploying Minecraft resource with datapacks and a resource pack")
+		mc := makeMinecraft("datapacks", namespace)
+		mc.Spec.Datapacks = []mcingv1alpha1.Artifact{
+			{Name: "pack.zip", URL: "https://example.com/pack.zip", SHA256: strings.Repeat("b", 64)},
+		}
+		mc.Spec.ResourcePack = &mcingv1alpha1.ResourcePack{
+			URL:      "https://example.com/resources.zip",
+			Required: true,
+		}
+		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+		By("checking the generated ConfigMap")
+		generatedCm := &corev1.ConfigMap{}
+		Eventually(func() error {
+			return k8sClient.Get(
+				ctx,
+				types.NamespacedName{Namespace: mc.Namespace, Name: mc.PrefixedName()},
+				generatedCm,
+			)
+		}).Should(Succeed())
+		Expect(generatedCm.Data[constants.ArtifactsName]).To(MatchJSON(`{
+			"datapacks": [{"name": "pack.zip", "url": "https://example.com/pack.zip", "sha256": "` +
+			strings.Repeat("b", 64) + `"}],
+			"levelName": "world",
+			"resourcePack": "https://example.com/resources.zip"
+		}`))
+		props := generatedCm.Data[constants.ServerPropsName]
+		Expect(props).To(ContainSubstring("resource-pack=https://example.com/resources.zip\n"))
+		Expect(props).To(ContainSubstring("require-resource-pack=true\n"))
+		Expect(props).To(ContainSubstring("resource-pack-sha1=\n"))
+
+		By("checking that the digest of the resource pack restarts the pod")
+		s := new(appsv1.StatefulSet)
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: mc.PrefixedName(), Namespace: namespace}, s)
+		}).Should(Succeed())
+		Expect(s.Spec.Template.Annotations).To(HaveKey(constants.ArtifactsHashAnnotation))
+
+		By("using the SHA-1 digest reported in the status")
+		sha1 := strings.Repeat("c", 40)
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc)).To(Succeed())
+		mc.Status.ResourcePack = &mcingv1alpha1.InstalledResourcePack{URL: mc.Spec.ResourcePack.URL, SHA1: sha1}
+		Expect(k8sClient.Status().Update(ctx, mc)).To(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(generatedCm), generatedCm)).To(Succeed())
+			g.Expect(generatedCm.Data[constants.ServerPropsName]).To(ContainSubstring("resource-pack-sha1=" + sha1 + "\n"))
+		}).Should(Succeed())
+
+		By("removing the resource pack")
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc)).To(Succeed())
+		mc.Spec.ResourcePack = nil
+		Expect(k8sClient.Update(ctx, mc)).To(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(s), s)).To(Succeed())
+			// Datapacks are installed by the agent without restarting the pod.
+			g.Expect(s.Spec.Template.Annotations).NotTo(HaveKey(constants.ArtifactsHashAnnotation))
+		}).Should(Succeed())
+	})
+
+	It("should place config files", func() {
+		By("deploying Minecraft resource with config files")
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "luckperms", Namespace: namespace},
+			StringData: map[string]string{"config.yml": "storage-method: mysql\n"},
+		}
+		Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+		mc := makeMinecraft("config-files", namespace)
+		mc.Spec.ConfigFiles = []mcingv1alpha1.ConfigFile{
+			{
+				Path:          "config/paper-global.yml",
+				Inline:        "proxies:\n  velocity:\n    enabled: true\n",
+				MergeStrategy: mcingv1alpha1.MergeStrategyMerge,
+			},
+			{
+				Path: "plugins/LuckPerms/config.yml",
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+					Key:                  "config.yml",
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+		By("checking the generated ConfigMap")
+		generatedCm := &corev1.ConfigMap{}
+		Eventually(func() error {
+			return k8sClient.Get(
+				ctx,
+				types.NamespacedName{Namespace: mc.Namespace, Name: mc.PrefixedName()},
+				generatedCm,
+			)
+		}).Should(Succeed())
+		Expect(generatedCm.Data[constants.ConfigFilesName]).To(MatchJSON(`{
+			"files": [
+				{"path": "config/paper-global.yml", "inline": "proxies:\n  velocity:\n    enabled: true\n", "merge": true},
+				{"path": "plugins/LuckPerms/config.yml", "source": "/mcing-config-files/plugins/LuckPerms/config.yml"}
+			]
+		}`))
+
+		By("checking that the Secret is projected into the init and agent containers")
+		s := new(appsv1.StatefulSet)
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: mc.PrefixedName(), Namespace: namespace}, s)
+		}).Should(Succeed())
+		var volume *corev1.Volume
+		for i := range s.Spec.Template.Spec.Volumes {
+			if s.Spec.Template.Spec.Volumes[i].Name == constants.ConfigFilesVolumeName {
+				volume = &s.Spec.Template.Spec.Volumes[i]
+			}
+		}
+		Expect(volume).NotTo(BeNil())
+		Expect(volume.Projected.Sources).To(HaveLen(1))
+		Expect(volume.Projected.Sources[0].Secret.Name).To(Equal(secret.Name))
+		Expect(volume.Projected.Sources[0].Secret.Items).To(Equal([]corev1.KeyToPath{
+			{Key: "config.yml", Path: "plugins/LuckPerms/config.yml"},
+		}))
+		mount := corev1.VolumeMount{
+			Name:      constants.ConfigFilesVolumeName,
+			MountPath: constants.ConfigFilesPath,
+			ReadOnly:  true,
+		}
+		Expect(s.Spec.Template.Spec.InitContainers[0].VolumeMounts).To(ContainElement(mount))
+		for _, c := range s.Spec.Template.Spec.Containers {
+			if c.Name == constants.AgentContainerName {
+				Expect(c.VolumeMounts).To(ContainElement(mount))
+			}
+		}
+	})
+
+	It("should project the Secret keys referenced in the config", func() {
+		By("deploying Minecraft resource that refers to a Secret in a config file")
+		mc := makeMinecraft("secret-refs", namespace)
+		mc.Spec.ConfigFiles = []mcingv1alpha1.ConfigFile{
+			{Path: "plugins/LuckPerms/config.yml", Inline: "password: ${secret:luckperms/db-password}\n"},
+		}
+		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+		By("checking that the RCON password is not in the generated ConfigMap")
+		generatedCm := &corev1.ConfigMap{}
+		Eventually(func() error {
+			return k8sClient.Get(
+				ctx,
+				types.NamespacedName{Namespace: mc.Namespace, Name: mc.PrefixedName()},
+				generatedCm,
+			)
+		}).Should(Succeed())
+		Expect(generatedCm.Data[constants.ServerPropsName]).To(
+			ContainSubstring("rcon.password=${secret:" + mc.RconSecretName() + "/rcon-password}\n"))
+
+		By("checking the projected Secret keys")
+		s := new(appsv1.StatefulSet)
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: mc.PrefixedName(), Namespace: namespace}, s)
+		}).Should(Succeed())
+		Expect(s.Spec.Template.Spec.Volumes).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+			"Name": Equal(constants.SecretsVolumeName),
+			"VolumeSource": MatchFields(IgnoreExtras, Fields{
+				"Projected": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Sources": Equal([]corev1.VolumeProjection{
+						{Secret: &corev1.SecretProjection{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "luckperms"},
+							Items:                []corev1.KeyToPath{{Key: "db-password", Path: "luckperms/db-password"}},
+						}},
+						{Secret: &corev1.SecretProjection{
+							LocalObjectReference: corev1.LocalObjectReference{Name: mc.RconSecretName()},
+							Items: []corev1.KeyToPath{
+								{Key: "rcon-password", Path: mc.RconSecretName() + "/rcon-password"},
+							},
+						}},
+					}),
+				})),
+			}),
+		})))
+		Expect(s.Spec.Template.Spec.InitContainers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      constants.SecretsVolumeName,
+			MountPath: constants.SecretsPath,
+			ReadOnly:  true,
+		}))
+	})
+
+	It("should configure the backends of a network for the modern forwarding", func() {
+		By("deploying a MinecraftNetwork and a Paper server selected by it")
+		nw := &mcingv1alpha1.MinecraftNetwork{}
+		nw.Namespace = namespace
+		nw.Name = "forwarding"
+		nw.Spec.Selector = metav1.LabelSelector{MatchLabels: map[string]string{"network": "forwarding"}}
+		Expect(k8sClient.Create(ctx, nw)).To(Succeed())
+		mc := makeMinecraft("network-backend", namespace)
+		mc.Labels = map[string]string{"network": "forwarding"}
+		mc.Spec.Server = &mcingv1alpha1.Server{Type: mcingv1alpha1.ServerTypePaper, Version: "1.21.1"}
+		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+		By("checking the generated ConfigMap")
+		Eventually(func(g Gomega) {
+			generatedCm := &corev1.ConfigMap{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: mc.Namespace, Name: mc.PrefixedName()}, generatedCm)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(generatedCm.Data[constants.ServerPropsName]).To(ContainSubstring("online-mode=false\n"))
+			g.Expect(generatedCm.Data[constants.ConfigFilesName]).To(MatchJSON(`{
+				"files": [
+					{
+						"path": "config/paper-global.yml",
+						"inline": "{\"proxies\":{\"velocity\":{\"enabled\":true,\"online-mode\":true,\"secret\":\"${secret:mcing-network-forwarding-forwarding/forwarding.secret}\"}}}",
+						"merge": true
+					}
+				]
+			}`))
+		}).Should(Succeed())
+
+		By("checking that the forwarding secret is projected")
+		Eventually(func(g Gomega) {
+			s := new(appsv1.StatefulSet)
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: mc.PrefixedName(), Namespace: namespace}, s)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(s.Spec.Template.Spec.Volumes).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Name": Equal(constants.SecretsVolumeName),
+				"VolumeSource": MatchFields(IgnoreExtras, Fields{
+					"Projected": PointTo(MatchFields(IgnoreExtras, Fields{
+						"Sources": ContainElement(corev1.VolumeProjection{Secret: &corev1.SecretProjection{
+							LocalObjectReference: corev1.LocalObjectReference{Name: nw.ForwardingSecretName()},
+							Items: []corev1.KeyToPath{{
+								Key:  constants.ForwardingSecretKey,
+								Path: nw.ForwardingSecretName() + "/" + constants.ForwardingSecretKey,
+							}},
+						}}),
+					})),
+				}),
+			})))
+		}).Should(Succeed())
+	})
+
 	It("should update generated ConfigMap, when update specified ConfigMap", func() {
 		By("deploying ConfigMap and Minecraft resource")
 		testCmName := "test-configmap"
@@ -257,164 +828,923 @@ var _ = Describe("Minecraft controller", func() {
 				return err
 			}
 
-			if !cmp.Equal(generatedCm.Data[constants.ServerPropsName], cm.Data[constants.ServerPropsName]) {
-				return errors.New("the generated ConfigMap has not been updated")
+			if !cmp.Equal(generatedCm.Data[constants.ServerPropsName], cm.Data[constants.ServerPropsName]) {
+				return errors.New("the generated ConfigMap has not been updated")
+			}
+			return nil
+		}).Should(Succeed())
+	})
+	It("should give typed server properties precedence over the ConfigMap", func() {
+		By("deploying ConfigMap and Minecraft resource with typed server properties")
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "typed-props",
+				Namespace: namespace,
+			},
+			Data: map[string]string{
+				"motd":       "from configmap",
+				"difficulty": "hard",
+				"pvp":        "false",
+			},
+		}
+		mc := makeMinecraft("typed-props", namespace)
+		mc.Spec.ServerPropertiesConfigMapName = &cm.Name
+		mc.Spec.ServerProperties = &mcingv1alpha1.ServerProperties{
+			Difficulty: mcingv1alpha1.DifficultyPeaceful,
+			MaxPlayers: ptr.To[int32](5),
+		}
+		Expect(k8sClient.Create(ctx, cm)).To(Succeed())
+		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+		generatedCm := &corev1.ConfigMap{}
+		key := types.NamespacedName{Namespace: mc.Namespace, Name: mc.PrefixedName()}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, key, generatedCm)).To(Succeed())
+			props := generatedCm.Data[constants.ServerPropsName]
+			g.Expect(props).To(ContainSubstring("difficulty=peaceful\n"))
+			g.Expect(props).To(ContainSubstring("max-players=5\n"))
+			g.Expect(props).To(ContainSubstring("motd=from configmap\n"))
+			g.Expect(props).To(ContainSubstring("pvp=false\n"))
+		}).Should(Succeed())
+
+		By("updating the typed server properties")
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc)).To(Succeed())
+		mc.Spec.ServerProperties.PVP = ptr.To(true)
+		Expect(k8sClient.Update(ctx, mc)).To(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, key, generatedCm)).To(Succeed())
+			g.Expect(generatedCm.Data[constants.ServerPropsName]).To(ContainSubstring("pvp=true\n"))
+		}).Should(Succeed())
+	})
+
+	It("should wait for missing references and reconcile when they are created", func() {
+		By("deploying Minecraft resource referencing a missing ConfigMap")
+		mc := makeMinecraft("missing-ref", namespace)
+		mc.Spec.OtherConfigMapName = ptr.To("missing-other")
+		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc)).To(Succeed())
+			cond := meta.FindStatusCondition(mc.Status.Conditions, mcingv1alpha1.ConditionReferencesResolved)
+			g.Expect(cond).NotTo(BeNil())
+			g.Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			g.Expect(cond.Reason).To(Equal("ConfigMapNotFound"))
+		}).Should(Succeed())
+		key := types.NamespacedName{Namespace: mc.Namespace, Name: mc.PrefixedName()}
+		Consistently(func() error {
+			return k8sClient.Get(ctx, key, &appsv1.StatefulSet{})
+		}).ShouldNot(Succeed())
+
+		By("creating the referenced ConfigMap")
+		other := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "missing-other",
+				Namespace: namespace,
+			},
+			Data: map[string]string{
+				constants.OpsName: "[]",
+			},
+		}
+		Expect(k8sClient.Create(ctx, other)).To(Succeed())
+
+		generatedCm := &corev1.ConfigMap{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, key, generatedCm)).To(Succeed())
+			g.Expect(generatedCm.Data).To(HaveKeyWithValue(constants.OpsName, "[]"))
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc)).To(Succeed())
+			cond := meta.FindStatusCondition(mc.Status.Conditions, mcingv1alpha1.ConditionReferencesResolved)
+			g.Expect(cond).NotTo(BeNil())
+			g.Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		}).Should(Succeed())
+
+		By("updating the referenced ConfigMap")
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(other), other)).To(Succeed())
+		other.Data[constants.OpsName] = `[{"name":"steve"}]`
+		Expect(k8sClient.Update(ctx, other)).To(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, key, generatedCm)).To(Succeed())
+			g.Expect(generatedCm.Data).To(HaveKeyWithValue(constants.OpsName, `[{"name":"steve"}]`))
+		}).Should(Succeed())
+	})
+
+	It("should report a missing RCON secret", func() {
+		mc := makeMinecraft("missing-rcon", namespace)
+		mc.Spec.RconPasswordSecretName = ptr.To("missing-rcon-secret")
+		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc)).To(Succeed())
+			cond := meta.FindStatusCondition(mc.Status.Conditions, mcingv1alpha1.ConditionReferencesResolved)
+			g.Expect(cond).NotTo(BeNil())
+			g.Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			g.Expect(cond.Reason).To(Equal("SecretNotFound"))
+		}).Should(Succeed())
+
+		By("creating the secret")
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "missing-rcon-secret",
+				Namespace: namespace,
+			},
+			Data: map[string][]byte{
+				constants.RconPasswordSecretKey: []byte("password"),
+			},
+		}
+		Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+		key := types.NamespacedName{Namespace: mc.Namespace, Name: mc.PrefixedName()}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, key, &appsv1.StatefulSet{})
+		}).Should(Succeed())
+	})
+
+	It("should wait for a Secret referenced in the content of a ConfigMap", func() {
+		By("deploying Minecraft resource whose properties refer to a missing Secret")
+		props := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "secret-ref-props",
+				Namespace: namespace,
+			},
+			Data: map[string]string{
+				"motd": "${secret:missing-motd/motd}",
+			},
+		}
+		Expect(k8sClient.Create(ctx, props)).To(Succeed())
+		mc := makeMinecraft("missing-content-secret", namespace)
+		mc.Spec.ServerPropertiesConfigMapName = ptr.To(props.Name)
+		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc)).To(Succeed())
+			cond := meta.FindStatusCondition(mc.Status.Conditions, mcingv1alpha1.ConditionReferencesResolved)
+			g.Expect(cond).NotTo(BeNil())
+			g.Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			g.Expect(cond.Reason).To(Equal("SecretNotFound"))
+			g.Expect(cond.Message).To(ContainSubstring("missing-motd"))
+		}).Should(Succeed())
+		// The Secret watch finds the Minecraft by the missing name.
+		Expect(missingSecretName(mc)).To(Equal([]string{"missing-motd"}))
+
+		By("creating the secret")
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "missing-motd",
+				Namespace: namespace,
+			},
+			Data: map[string][]byte{
+				"motd": []byte("hello"),
+			},
+		}
+		Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+		key := types.NamespacedName{Namespace: mc.Namespace, Name: mc.PrefixedName()}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, key, &appsv1.StatefulSet{})
+		}).Should(Succeed())
+	})
+
+	Context("RCON Secret", func() {
+		It("should create default RCON secret if not specified", func() {
+			mc := makeMinecraft("default-rcon", namespace)
+			Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+			By("checking generated Secret")
+			secret := &corev1.Secret{}
+			Eventually(func() error {
+				return k8sClient.Get(
+					ctx,
+					types.NamespacedName{Name: mc.PrefixedName() + "-rcon-password", Namespace: namespace},
+					secret,
+				)
+			}).Should(Succeed())
+			Expect(secret.Data).To(HaveKey(constants.RconPasswordSecretKey))
+
+			By("checking StatefulSet env var")
+			sts := &appsv1.StatefulSet{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, types.NamespacedName{Name: mc.PrefixedName(), Namespace: namespace}, sts)
+			}).Should(Succeed())
+
+			Expect(sts.Spec.Template.Spec.Containers[0].Env).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Name": Equal(constants.RconPasswordEnvName),
+				"ValueFrom": PointTo(MatchFields(IgnoreExtras, Fields{
+					"SecretKeyRef": PointTo(MatchFields(IgnoreExtras, Fields{
+						"LocalObjectReference": MatchFields(IgnoreExtras, Fields{
+							"Name": Equal(secret.Name),
+						}),
+						"Key": Equal(constants.RconPasswordSecretKey),
+					})),
+				})),
+			})))
+		})
+
+		It("should use specified RCON secret", func() {
+			secretName := "my-rcon-secret"
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      secretName,
+					Namespace: namespace,
+				},
+				Data: map[string][]byte{
+					constants.RconPasswordSecretKey: []byte("password"),
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+
+			mc := makeMinecraft("custom-rcon", namespace)
+			mc.Spec.RconPasswordSecretName = &secretName
+			Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+			By("checking StatefulSet env var")
+			sts := &appsv1.StatefulSet{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, types.NamespacedName{Name: mc.PrefixedName(), Namespace: namespace}, sts)
+			}).Should(Succeed())
+
+			Expect(sts.Spec.Template.Spec.Containers[0].Env).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Name": Equal(constants.RconPasswordEnvName),
+				"ValueFrom": PointTo(MatchFields(IgnoreExtras, Fields{
+					"SecretKeyRef": PointTo(MatchFields(IgnoreExtras, Fields{
+						"LocalObjectReference": MatchFields(IgnoreExtras, Fields{
+							"Name": Equal(secretName),
+						}),
+						"Key": Equal(constants.RconPasswordSecretKey),
+					})),
+				})),
+			})))
+
+			By("ensuring default secret is not created")
+			Consistently(func() error {
+				return k8sClient.Get(
+					ctx,
+					types.NamespacedName{Name: mc.PrefixedName() + "-rcon-password", Namespace: namespace},
+					&corev1.Secret{},
+				)
+			}).ShouldNot(Succeed())
+		})
+	})
+	It("should enable auto-pause configurations", func() {
+		By("creating ConfigMap with custom port")
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "autopause-config",
+				Namespace: namespace,
+			},
+			Data: map[string]string{
+				"server-port": "12345", // This property setting is ignored.
+				"motd":        "AutoPause Test",
+			},
+		}
+		Expect(k8sClient.Create(ctx, cm)).To(Succeed())
+
+		By("deploying Minecraft resource with AutoPause enabled")
+		mc := makeMinecraft("autopause-test", namespace)
+		mc.Spec.ServerPropertiesConfigMapName = &cm.Name
+		mc.Spec.AutoPause = mcingv1alpha1.AutoPause{
+			TimeoutSeconds: 600,
+		}
+		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+		By("getting the created StatefulSet")
+		s := new(appsv1.StatefulSet)
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: mc.PrefixedName(), Namespace: namespace}, s)
+		}).Should(Succeed())
+
+		// Verify ConfigMap content for lazymc.toml
+		generatedCm := &corev1.ConfigMap{}
+		Eventually(func() error {
+			return k8sClient.Get(
+				ctx,
+				types.NamespacedName{Namespace: mc.Namespace, Name: mc.PrefixedName()},
+				generatedCm,
+			)
+		}).Should(Succeed())
+		val, ok := generatedCm.Data[constants.LazymcConfigName]
+		Expect(ok).To(BeTrue())
+		Expect(val).To(ContainSubstring(fmt.Sprintf("address = \"0.0.0.0:%d\"", constants.ServerPort)))
+		Expect(val).To(ContainSubstring(fmt.Sprintf("address = \"127.0.0.1:%d\"", constants.InternalServerPort)))
+		Expect(val).To(ContainSubstring("sleep_after = 600"))
+		Expect(val).To(ContainSubstring("command = \"/bin/sh -c 'mkdir -p /data/.mcing; " +
+			"cp /mcing-config/server.properties /data/.mcing/start.properties; exec /start'\""))
+
+		// Verify Main Container Command
+		Expect(s.Spec.Template.Spec.Containers[0].Command).To(Equal([]string{"/opt/lazymc/lazymc"}))
+		Expect(s.Spec.Template.Spec.Containers[0].Args).To(
+			Equal([]string{"--config", "/opt/lazymc/lazymc.toml"}),
+		)
+
+		// Verify Probes use tcpSocket
+		Expect(s.Spec.Template.Spec.Containers[0].LivenessProbe.TCPSocket).NotTo(BeNil())
+		Expect(
+			s.Spec.Template.Spec.Containers[0].LivenessProbe.TCPSocket.Port.IntVal,
+		).To(Equal(constants.ServerPort))
+		Expect(s.Spec.Template.Spec.Containers[0].ReadinessProbe.TCPSocket).NotTo(BeNil())
+		Expect(
+			s.Spec.Template.Spec.Containers[0].ReadinessProbe.TCPSocket.Port.IntVal,
+		).To(Equal(constants.ServerPort))
+
+		// Verify ConfigMap override
+		generatedCm = &corev1.ConfigMap{}
+		Eventually(func() error {
+			return k8sClient.Get(
+				ctx,
+				types.NamespacedName{Namespace: mc.Namespace, Name: mc.PrefixedName()},
+				generatedCm,
+			)
+		}).Should(Succeed())
+		val, ok = generatedCm.Data[constants.ServerPropsName]
+		Expect(ok).To(BeTrue())
+		Expect(val).To(ContainSubstring(fmt.Sprintf("server-port=%d", constants.InternalServerPort)))
+		Expect(val).To(ContainSubstring("motd=AutoPause Test"))
+	})
+
+	It("should render lazymc options", func() {
+		By("deploying Minecraft resource with lazymc options")
+		mc := makeMinecraft("lazymc-options", namespace)
+		mc.Spec.AutoPause = mcingv1alpha1.AutoPause{
+			TimeoutSeconds: 300,
+			Lazymc: &mcingv1alpha1.LazymcOptions{
+				FreezeProcess:            true,
+				WakeWhitelist:            ptr.To(false),
+				MinimumOnlineTimeSeconds: 120,
+				MOTD: mcingv1alpha1.LazymcMOTD{
+					Sleeping: "Zzz \"quiet\"\nJoin to wake",
+				},
+				Join: mcingv1alpha1.LazymcJoin{
+					Methods: []mcingv1alpha1.LazymcJoinMethod{
+						mcingv1alpha1.LazymcJoinMethodHold,
+						mcingv1alpha1.LazymcJoinMethodForward,
+					},
+					Hold:    mcingv1alpha1.LazymcJoinHold{TimeoutSeconds: 20},
+					Forward: mcingv1alpha1.LazymcJoinForward{Address: "lobby.example.com:25565"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+		By("checking the rendered lazymc.toml")
+		generatedCm := &corev1.ConfigMap{}
+		Eventually(func() error {
+			return k8sClient.Get(
+				ctx,
+				types.NamespacedName{Namespace: mc.Namespace, Name: mc.PrefixedName()},
+				generatedCm,
+			)
+		}).Should(Succeed())
+		val, ok := generatedCm.Data[constants.LazymcConfigName]
+		Expect(ok).To(BeTrue())
+		Expect(val).To(ContainSubstring("freeze_process = true"))
+		Expect(val).To(ContainSubstring("wake_whitelist = false"))
+		Expect(val).To(ContainSubstring("minimum_online_time = 120"))
+		Expect(val).To(ContainSubstring(`sleeping = "Zzz \"quiet\"\nJoin to wake"`))
+		Expect(val).To(ContainSubstring(`methods = ["hold", "forward"]`))
+		Expect(val).To(ContainSubstring("timeout = 20"))
+		Expect(val).To(ContainSubstring(`address = "lobby.example.com:25565"`))
+	})
+
+	It("should enable auto-pause with the agent mode", func() {
+		By("deploying Minecraft resource with the agent auto-pause mode")
+		mc := makeMinecraft("agent-autopause-test", namespace)
+		mc.Spec.AutoPause = mcingv1alpha1.AutoPause{
+			TimeoutSeconds: 600,
+			Mode:           mcingv1alpha1.AutoPauseModeAgent,
+		}
+		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+		By("getting the created StatefulSet")
+		s := new(appsv1.StatefulSet)
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: mc.PrefixedName(), Namespace: namespace}, s)
+		}).Should(Succeed())
+
+		// Verify lazymc is not used
+		generatedCm := &corev1.ConfigMap{}
+		Eventually(func() error {
+			return k8sClient.Get(
+				ctx,
+				types.NamespacedName{Namespace: mc.Namespace, Name: mc.PrefixedName()},
+				generatedCm,
+			)
+		}).Should(Succeed())
+		Expect(generatedCm.Data).NotTo(HaveKey(constants.LazymcConfigName))
+		Expect(generatedCm.Data[constants.ServerPropsName]).To(
+			ContainSubstring(fmt.Sprintf("server-port=%d", constants.InternalServerPort)),
+		)
+		Expect(s.Spec.Template.Spec.InitContainers[0].Args).NotTo(ContainElement("--enable-lazymc"))
+
+		// Verify the minecraft container runs the server under the supervisor loop
+		mcContainer := s.Spec.Template.Spec.Containers[0]
+		Expect(mcContainer.Command).To(HaveLen(3))
+		Expect(mcContainer.Command[:2]).To(Equal([]string{"/bin/sh", "-c"}))
+		Expect(mcContainer.Command[2]).To(ContainSubstring("/opt/mcing-autopause/sleeping"))
+		Expect(mcContainer.Command[2]).To(ContainSubstring("/start"))
+		Expect(mcContainer.Command[2]).To(ContainSubstring(
+			"cp /mcing-config/server.properties /data/.mcing/start.properties",
+		))
+		Expect(mcContainer.Ports).NotTo(ContainElement(MatchFields(IgnoreExtras, Fields{
+			"Name": Equal(constants.ServerPortName),
+		})))
+		// The server process is stopped while sleeping, so the minecraft container has no probes.
+		Expect(mcContainer.LivenessProbe).To(BeNil())
+		Expect(mcContainer.ReadinessProbe).To(BeNil())
+
+		// Verify the agent container proxies the server port
+		agentContainer := s.Spec.Template.Spec.Containers[1]
+		Expect(agentContainer.Args).To(Equal([]string{"--auto-pause-mode=agent", "--auto-pause-timeout=600s"}))
+		Expect(agentContainer.Ports).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+			"Name":          Equal(constants.ServerPortName),
+			"ContainerPort": Equal(constants.ServerPort),
+		})))
+		Expect(agentContainer.VolumeMounts).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+			"Name": Equal(constants.AutoPauseVolumeName),
+		})))
+		Expect(agentContainer.LivenessProbe.TCPSocket.Port.IntVal).To(Equal(constants.ServerPort))
+		Expect(agentContainer.ReadinessProbe.TCPSocket.Port.IntVal).To(Equal(constants.ServerPort))
+	})
+
+	It("should keep the replicas scaled by hibernation", func() {
+		By("deploying Minecraft resource with hibernation enabled")
+		mc := makeMinecraft("hibernation-test", namespace)
+		mc.Spec.Hibernation = mcingv1alpha1.Hibernation{
+			Enabled:     true,
+			IdleSeconds: 600,
+		}
+		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+		s := new(appsv1.StatefulSet)
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: mc.PrefixedName(), Namespace: namespace}, s)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(s.Spec.Replicas).To(PointTo(BeNumerically("==", 1)))
+		}).Should(Succeed())
+		Expect(s.Spec.ServiceName).To(Equal(mc.HeadlessServiceName()))
+
+		By("scaling the StatefulSet to zero")
+		patch := client.MergeFrom(s.DeepCopy())
+		s.Spec.Replicas = ptr.To[int32](0)
+		Expect(k8sClient.Patch(ctx, s, patch)).To(Succeed())
+
+		By("triggering reconciliation")
+		Eventually(func() error {
+			if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc); err != nil {
+				return err
+			}
+			mc.Spec.Ops.Users = []string{"op1"}
+			return k8sClient.Update(ctx, mc)
+		}).Should(Succeed())
+
+		Consistently(func(g Gomega) {
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: mc.PrefixedName(), Namespace: namespace}, s)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(s.Spec.Replicas).To(PointTo(BeNumerically("==", 0)))
+		}).Should(Succeed())
+	})
+
+	It("should keep the StatefulSet of a server without hibernation", func() {
+		By("deploying Minecraft resource")
+		mc := makeMinecraft("keep-sts-test", namespace)
+		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+		s := new(appsv1.StatefulSet)
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: mc.PrefixedName(), Namespace: namespace}, s)
+		}).Should(Succeed())
+		uid := s.UID
+		Expect(s.Spec.ServiceName).To(Equal(mc.HeadlessServiceName()))
+
+		By("triggering reconciliation")
+		Eventually(func() error {
+			if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc); err != nil {
+				return err
+			}
+			mc.Spec.Ops.Users = []string{"op1"}
+			return k8sClient.Update(ctx, mc)
+		}).Should(Succeed())
+
+		Consistently(func(g Gomega) {
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: mc.PrefixedName(), Namespace: namespace}, s)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(s.UID).To(Equal(uid))
+			g.Expect(s.DeletionTimestamp).To(BeNil())
+		}).Should(Succeed())
+	})
+
+	Context("deletion", func() {
+		createClaim := func(mc *mcingv1alpha1.Minecraft) *corev1.PersistentVolumeClaim {
+			// envtest does not run the StatefulSet controller, so the PVC is created here.
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: mc.ClaimNames()[0]},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+					},
+				},
+			}
+			// The StatefulSet controller copies the owner reference from the volume claim template.
+			Expect(ctrl.SetControllerReference(mc, pvc, scheme)).To(Succeed())
+			Expect(k8sClient.Create(ctx, pvc)).To(Succeed())
+			return pvc
+		}
+
+		It("should retain the PVCs by default", func() {
+			mc := makeMinecraft("retain-test", namespace)
+			Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc)).To(Succeed())
+				g.Expect(mc.Finalizers).To(ContainElement(constants.Finalizer))
+				g.Expect(mc.Spec.DeletionPolicy).To(Equal(mcingv1alpha1.DeletionPolicyRetain))
+			}).Should(Succeed())
+			pvc := createClaim(mc)
+
+			By("deleting the Minecraft")
+			Expect(k8sClient.Delete(ctx, mc)).To(Succeed())
+			Eventually(func() bool {
+				return apierrors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc))
+			}).Should(BeTrue())
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pvc), pvc)).To(Succeed())
+			Expect(pvc.DeletionTimestamp).To(BeNil())
+			Expect(pvc.OwnerReferences).To(BeEmpty())
+			Expect(k8sClient.Delete(ctx, pvc)).To(Succeed())
+		})
+
+		It("should delete the PVCs with the Delete policy", func() {
+			mc := makeMinecraft("delete-test", namespace)
+			mc.Spec.DeletionPolicy = mcingv1alpha1.DeletionPolicyDelete
+			Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc)).To(Succeed())
+				g.Expect(mc.Finalizers).To(ContainElement(constants.Finalizer))
+			}).Should(Succeed())
+			pvc := createClaim(mc)
+
+			By("deleting the Minecraft")
+			Expect(k8sClient.Delete(ctx, mc)).To(Succeed())
+			Eventually(func(g Gomega) {
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc)
+				g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+				// The PVC is kept by the pvc-protection finalizer if the admission plugin adds it.
+				err = k8sClient.Get(ctx, client.ObjectKeyFromObject(pvc), pvc)
+				g.Expect(client.IgnoreNotFound(err)).NotTo(HaveOccurred())
+				if err == nil {
+					g.Expect(pvc.DeletionTimestamp).NotTo(BeNil())
+				}
+			}).Should(Succeed())
+		})
+	})
+
+	Context("deletion without the VolumeSnapshot API", func() {
+		It("should retain the PVCs instead of taking the final snapshots", func() {
+			mc := makeMinecraft("no-snapshot-api-test", namespace)
+			mc.UID = "no-snapshot-api-uid"
+			mc.Spec.DeletionPolicy = mcingv1alpha1.DeletionPolicySnapshot
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: mc.ClaimNames()[0]},
+			}
+			Expect(ctrl.SetControllerReference(mc, pvc, scheme)).To(Succeed())
+
+			// The API server of a cluster without the snapshot controller does not serve VolumeSnapshot.
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mc, pvc).Build()
+			noSnapshotClient := interceptor.NewClient(fakeClient, interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object,
+					opts ...client.GetOption) error {
+					if u, ok := obj.(*unstructured.Unstructured); ok && u.GetKind() == constants.VolumeSnapshotKind {
+						return &meta.NoKindMatchError{GroupKind: u.GroupVersionKind().GroupKind()}
+					}
+					return c.Get(ctx, key, obj, opts...)
+				},
+			})
+			recorder := record.NewFakeRecorder(10)
+			r := NewMinecraftReconciler(noSnapshotClient, ctrl.Log, scheme, "mcing-init", "mcing-agent", mockMinecraftMgr)
+			r.recorder = recorder
+
+			done, err := r.finalize(ctx, mc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(done).To(BeTrue())
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(pvc), pvc)).To(Succeed())
+			Expect(pvc.DeletionTimestamp).To(BeNil())
+			Expect(pvc.OwnerReferences).To(BeEmpty())
+			Expect(recorder.Events).To(Receive(ContainSubstring("SnapshotUnavailable")))
+		})
+	})
+
+	Context("storage", func() {
+		createBoundClaim := func(mc *mcingv1alpha1.Minecraft, class string) *corev1.PersistentVolumeClaim {
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: mc.ClaimNames()[0]},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					StorageClassName: ptr.To(class),
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+					},
+				},
+			}
+			Expect(ctrl.SetControllerReference(mc, pvc, scheme)).To(Succeed())
+			Expect(k8sClient.Create(ctx, pvc)).To(Succeed())
+			pvc.Status.Phase = corev1.ClaimBound
+			pvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")}
+			Expect(k8sClient.Status().Update(ctx, pvc)).To(Succeed())
+			return pvc
+		}
+
+		createMinecraft := func(name, class string) *mcingv1alpha1.Minecraft {
+			mc := makeMinecraft(name, namespace)
+			mc.Spec.VolumeClaimTemplates[0].Spec.StorageClassName = ptr.To(class)
+			Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+			Eventually(func() error {
+				return k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: mc.PrefixedName()}, &appsv1.StatefulSet{})
+			}).Should(Succeed())
+			return mc
+		}
+
+		increaseStorage := func(mc *mcingv1alpha1.Minecraft, storage string) {
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc); err != nil {
+					return err
+				}
+				mc.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests = corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse(storage),
+				}
+				return k8sClient.Update(ctx, mc)
+			}).Should(Succeed())
+		}
+
+		It("should expand the data PVC and report the storage", func() {
+			sc := &storagev1.StorageClass{
+				ObjectMeta:           metav1.ObjectMeta{Name: "expandable"},
+				Provisioner:          "example.com/csi",
+				AllowVolumeExpansion: ptr.To(true),
+			}
+			Expect(k8sClient.Create(ctx, sc)).To(Succeed())
+			mc := createMinecraft("expand-test", sc.Name)
+			pvc := createBoundClaim(mc, sc.Name)
+
+			By("reporting the capacity")
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc)).To(Succeed())
+				g.Expect(mc.Status.Storage).NotTo(BeNil())
+				g.Expect(mc.Status.Storage.Capacity.String()).To(Equal("1Gi"))
+				cond := meta.FindStatusCondition(mc.Status.Conditions, mcingv1alpha1.ConditionStorageResized)
+				g.Expect(cond).NotTo(BeNil())
+				g.Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			}).Should(Succeed())
+
+			By("increasing the storage request")
+			increaseStorage(mc, "2Gi")
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pvc), pvc)).To(Succeed())
+				g.Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("2Gi"))
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc)).To(Succeed())
+				g.Expect(mc.Status.Storage.Requested.String()).To(Equal("2Gi"))
+				cond := meta.FindStatusCondition(mc.Status.Conditions, mcingv1alpha1.ConditionStorageResized)
+				g.Expect(cond).NotTo(BeNil())
+				g.Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				g.Expect(cond.Reason).To(Equal("Resizing"))
+			}).Should(Succeed())
+
+			By("completing the expansion")
+			pvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("2Gi")}
+			Expect(k8sClient.Status().Update(ctx, pvc)).To(Succeed())
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc)).To(Succeed())
+				g.Expect(mc.Status.Storage.Capacity.String()).To(Equal("2Gi"))
+				cond := meta.FindStatusCondition(mc.Status.Conditions, mcingv1alpha1.ConditionStorageResized)
+				g.Expect(cond).NotTo(BeNil())
+				g.Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			}).Should(Succeed())
+		})
+
+		It("should not expand the data PVC if the StorageClass does not allow it", func() {
+			sc := &storagev1.StorageClass{
+				ObjectMeta:  metav1.ObjectMeta{Name: "fixed"},
+				Provisioner: "example.com/csi",
+			}
+			Expect(k8sClient.Create(ctx, sc)).To(Succeed())
+			mc := createMinecraft("fixed-test", sc.Name)
+			pvc := createBoundClaim(mc, sc.Name)
+
+			increaseStorage(mc, "2Gi")
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc)).To(Succeed())
+				cond := meta.FindStatusCondition(mc.Status.Conditions, mcingv1alpha1.ConditionStorageResized)
+				g.Expect(cond).NotTo(BeNil())
+				g.Expect(cond.Reason).To(Equal("ExpansionNotAllowed"))
+			}).Should(Succeed())
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pvc), pvc)).To(Succeed())
+			Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("1Gi"))
+		})
+
+		It("should set the PVC retention policy to the StatefulSet", func() {
+			mc := makeMinecraft("retention-test", namespace)
+			mc.Spec.PersistentVolumeClaimRetentionPolicy = &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+				WhenDeleted: appsv1.DeletePersistentVolumeClaimRetentionPolicyType,
 			}
-			return nil
-		}).Should(Succeed())
-	})
-	Context("RCON Secret", func() {
-		It("should create default RCON secret if not specified", func() {
-			mc := makeMinecraft("default-rcon", namespace)
 			Expect(k8sClient.Create(ctx, mc)).To(Succeed())
 
-			By("checking generated Secret")
-			secret := &corev1.Secret{}
+			sts := &appsv1.StatefulSet{}
 			Eventually(func() error {
-				return k8sClient.Get(
-					ctx,
-					types.NamespacedName{Name: mc.PrefixedName() + "-rcon-password", Namespace: namespace},
-					secret,
-				)
+				return k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: mc.PrefixedName()}, sts)
 			}).Should(Succeed())
-			Expect(secret.Data).To(HaveKey(constants.RconPasswordSecretKey))
+			Expect(sts.Spec.PersistentVolumeClaimRetentionPolicy).NotTo(BeNil())
+			Expect(sts.Spec.PersistentVolumeClaimRetentionPolicy.WhenDeleted).
+				To(Equal(appsv1.DeletePersistentVolumeClaimRetentionPolicyType))
+			Expect(sts.Spec.PersistentVolumeClaimRetentionPolicy.WhenScaled).
+				To(Equal(appsv1.RetainPersistentVolumeClaimRetentionPolicyType))
+		})
+	})
 
-			By("checking StatefulSet env var")
-			sts := &appsv1.StatefulSet{}
-			Eventually(func() error {
-				return k8sClient.Get(ctx, types.NamespacedName{Name: mc.PrefixedName(), Namespace: namespace}, sts)
+	Context("snapshot backup", func() {
+		listSnapshots := func(g Gomega, mc *mcingv1alpha1.Minecraft) []unstructured.Unstructured {
+			snaps := &unstructured.UnstructuredList{}
+			snaps.SetAPIVersion(constants.VolumeSnapshotAPIVersion)
+			snaps.SetKind(constants.VolumeSnapshotListKind)
+			g.Expect(k8sClient.List(ctx, snaps, client.InNamespace(namespace), client.MatchingLabels{
+				constants.LabelAppInstance:  mc.Name,
+				constants.SnapshotTypeLabel: constants.SnapshotTypeScheduled,
+			})).To(Succeed())
+			return snaps.Items
+		}
+
+		createDataClaim := func(mc *mcingv1alpha1.Minecraft) string {
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: mc.ClaimNames()[0]},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+					},
+				},
+			}
+			Expect(ctrl.SetControllerReference(mc, pvc, scheme)).To(Succeed())
+			Expect(k8sClient.Create(ctx, pvc)).To(Succeed())
+			return pvc.Name
+		}
+
+		It("should take a snapshot while auto-save is disabled", func() {
+			mc := makeMinecraft("snapshot-test", namespace)
+			mc.Spec.Backup.Snapshot = &mcingv1alpha1.SnapshotBackup{IntervalSeconds: 3600, Keep: 2}
+			Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+			claim := createDataClaim(mc)
+
+			By("taking a snapshot")
+			var snap *unstructured.Unstructured
+			Eventually(func(g Gomega) {
+				snaps := listSnapshots(g, mc)
+				g.Expect(snaps).To(HaveLen(1))
+				snap = &snaps[0]
+				source, _, _ := unstructured.NestedString(snap.Object, "spec", "source", "persistentVolumeClaimName")
+				g.Expect(source).To(Equal(claim))
+				g.Expect(snap.GetAnnotations()).To(HaveKey(constants.SaveOffAnnotation))
 			}).Should(Succeed())
+			Expect(mockMinecraftMgr.isSavedOff(client.ObjectKeyFromObject(mc))).To(BeTrue())
 
-			Expect(sts.Spec.Template.Spec.Containers[0].Env).To(ContainElement(MatchFields(IgnoreExtras, Fields{
-				"Name": Equal(constants.RconPasswordEnvName),
-				"ValueFrom": PointTo(MatchFields(IgnoreExtras, Fields{
-					"SecretKeyRef": PointTo(MatchFields(IgnoreExtras, Fields{
-						"LocalObjectReference": MatchFields(IgnoreExtras, Fields{
-							"Name": Equal(secret.Name),
-						}),
-						"Key": Equal(constants.RconPasswordSecretKey),
-					})),
-				})),
-			})))
+			By("enabling auto-save after the snapshot is cut")
+			Expect(unstructured.SetNestedField(snap.Object, time.Now().UTC().Format(time.RFC3339),
+				"status", "creationTime")).To(Succeed())
+			Expect(k8sClient.Status().Update(ctx, snap)).To(Succeed())
+			Eventually(func(g Gomega) {
+				g.Expect(mockMinecraftMgr.isSavedOff(client.ObjectKeyFromObject(mc))).To(BeFalse())
+				snaps := listSnapshots(g, mc)
+				g.Expect(snaps).To(HaveLen(1))
+				g.Expect(snaps[0].GetAnnotations()).NotTo(HaveKey(constants.SaveOffAnnotation))
+			}).Should(Succeed())
 		})
 
-		It("should use specified RCON secret", func() {
-			secretName := "my-rcon-secret"
-			secret := &corev1.Secret{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      secretName,
-					Namespace: namespace,
-				},
-				Data: map[string][]byte{
-					constants.RconPasswordSecretKey: []byte("password"),
-				},
+		It("should delete old snapshots", func() {
+			mc := makeMinecraft("snapshot-prune-test", namespace)
+			mc.Spec.Backup.Snapshot = &mcingv1alpha1.SnapshotBackup{IntervalSeconds: 3600, Keep: 2}
+			Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+			for _, suffix := range []string{"1", "2", "3"} {
+				snap := volumeSnapshotFor(mc, mc.ClaimNames()[0]+"-"+suffix, mc.ClaimNames()[0],
+					constants.SnapshotTypeScheduled)
+				Expect(k8sClient.Create(ctx, snap)).To(Succeed())
 			}
-			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+			createDataClaim(mc)
 
-			mc := makeMinecraft("custom-rcon", namespace)
-			mc.Spec.RconPasswordSecretName = &secretName
+			Eventually(func(g Gomega) {
+				g.Expect(listSnapshots(g, mc)).To(HaveLen(2))
+			}).Should(Succeed())
+			Consistently(func(g Gomega) {
+				g.Expect(listSnapshots(g, mc)).To(HaveLen(2))
+			}, 2*time.Second).Should(Succeed())
+		})
+
+		It("should wait for the snapshot to restore", func() {
+			mc := makeMinecraft("restore-test", namespace)
+			mc.Spec.VolumeClaimTemplates[0].Spec.DataSource = &corev1.TypedLocalObjectReference{
+				APIGroup: ptr.To(constants.VolumeSnapshotGroup),
+				Kind:     constants.VolumeSnapshotKind,
+				Name:     "world",
+			}
 			Expect(k8sClient.Create(ctx, mc)).To(Succeed())
 
-			By("checking StatefulSet env var")
+			expectCondition := func(status metav1.ConditionStatus, reason string) {
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc)).To(Succeed())
+					cond := meta.FindStatusCondition(mc.Status.Conditions, mcingv1alpha1.ConditionRestoreSnapshotReady)
+					g.Expect(cond).NotTo(BeNil())
+					g.Expect(cond.Status).To(Equal(status))
+					g.Expect(cond.Reason).To(Equal(reason))
+				}).Should(Succeed())
+			}
+			stsKey := client.ObjectKey{Namespace: namespace, Name: mc.PrefixedName()}
+
+			By("waiting for the snapshot to be created")
+			expectCondition(metav1.ConditionFalse, "SnapshotNotFound")
+			Expect(apierrors.IsNotFound(k8sClient.Get(ctx, stsKey, &appsv1.StatefulSet{}))).To(BeTrue())
+
+			By("waiting for the snapshot to be ready")
+			snap := newVolumeSnapshot(namespace, "world")
+			snap.Object["spec"] = map[string]any{
+				"source": map[string]any{"volumeSnapshotContentName": "world-content"},
+			}
+			Expect(k8sClient.Create(ctx, snap)).To(Succeed())
+			expectCondition(metav1.ConditionFalse, "SnapshotNotReady")
+			Expect(apierrors.IsNotFound(k8sClient.Get(ctx, stsKey, &appsv1.StatefulSet{}))).To(BeTrue())
+
+			By("creating the StatefulSet once the snapshot is ready")
+			Expect(unstructured.SetNestedField(snap.Object, true, "status", "readyToUse")).To(Succeed())
+			Expect(k8sClient.Status().Update(ctx, snap)).To(Succeed())
+			expectCondition(metav1.ConditionTrue, "Ready")
 			sts := &appsv1.StatefulSet{}
 			Eventually(func() error {
-				return k8sClient.Get(ctx, types.NamespacedName{Name: mc.PrefixedName(), Namespace: namespace}, sts)
+				return k8sClient.Get(ctx, stsKey, sts)
 			}).Should(Succeed())
-
-			Expect(sts.Spec.Template.Spec.Containers[0].Env).To(ContainElement(MatchFields(IgnoreExtras, Fields{
-				"Name": Equal(constants.RconPasswordEnvName),
-				"ValueFrom": PointTo(MatchFields(IgnoreExtras, Fields{
-					"SecretKeyRef": PointTo(MatchFields(IgnoreExtras, Fields{
-						"LocalObjectReference": MatchFields(IgnoreExtras, Fields{
-							"Name": Equal(secretName),
-						}),
-						"Key": Equal(constants.RconPasswordSecretKey),
-					})),
-				})),
-			})))
-
-			By("ensuring default secret is not created")
-			Consistently(func() error {
-				return k8sClient.Get(
-					ctx,
-					types.NamespacedName{Name: mc.PrefixedName() + "-rcon-password", Namespace: namespace},
-					&corev1.Secret{},
-				)
-			}).ShouldNot(Succeed())
+			Expect(sts.Spec.VolumeClaimTemplates[0].Spec.DataSource.Name).To(Equal("world"))
 		})
 	})
-	It("should enable auto-pause configurations", func() {
-		By("creating ConfigMap with custom port")
-		cm := &corev1.ConfigMap{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "autopause-config",
-				Namespace: namespace,
-			},
-			Data: map[string]string{
-				"server-port": "12345", // This property setting is ignored.
-				"motd":        "AutoPause Test",
-			},
-		}
-		Expect(k8sClient.Create(ctx, cm)).To(Succeed())
 
-		By("deploying Minecraft resource with AutoPause enabled")
-		mc := makeMinecraft("autopause-test", namespace)
-		mc.Spec.ServerPropertiesConfigMapName = &cm.Name
-		mc.Spec.AutoPause = mcingv1alpha1.AutoPause{
-			TimeoutSeconds: 600,
-		}
-		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+	Context("data source", func() {
+		It("should clone the data volume of another Minecraft", func() {
+			mc := makeMinecraft("clone-test", namespace)
+			mc.Spec.DataSource = &mcingv1alpha1.DataSource{Minecraft: "clone-source", StripOps: true}
+			Expect(k8sClient.Create(ctx, mc)).To(Succeed())
 
-		By("getting the created StatefulSet")
-		s := new(appsv1.StatefulSet)
-		Eventually(func() error {
-			return k8sClient.Get(ctx, types.NamespacedName{Name: mc.PrefixedName(), Namespace: namespace}, s)
-		}).Should(Succeed())
+			expectCondition := func(status metav1.ConditionStatus, reason string) {
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mc), mc)).To(Succeed())
+					cond := meta.FindStatusCondition(mc.Status.Conditions, mcingv1alpha1.ConditionCloneSourceReady)
+					g.Expect(cond).NotTo(BeNil())
+					g.Expect(cond.Status).To(Equal(status))
+					g.Expect(cond.Reason).To(Equal(reason))
+				}).Should(Succeed())
+			}
+			stsKey := client.ObjectKey{Namespace: namespace, Name: mc.PrefixedName()}
 
-		// Verify ConfigMap content for lazymc.toml
-		generatedCm := &corev1.ConfigMap{}
-		Eventually(func() error {
-			return k8sClient.Get(
-				ctx,
-				types.NamespacedName{Namespace: mc.Namespace, Name: mc.PrefixedName()},
-				generatedCm,
-			)
-		}).Should(Succeed())
-		val, ok := generatedCm.Data[constants.LazymcConfigName]
-		Expect(ok).To(BeTrue())
-		Expect(val).To(ContainSubstring(fmt.Sprintf("address = \"0.0.0.0:%d\"", constants.ServerPort)))
-		Expect(val).To(ContainSubstring(fmt.Sprintf("address = \"127.0.0.1:%d\"", constants.InternalServerPort)))
-		Expect(val).To(ContainSubstring("sleep_after = 600"))
+			By("waiting for the PVC of the source")
+			expectCondition(metav1.ConditionFalse, "SourceNotFound")
+			Expect(apierrors.IsNotFound(k8sClient.Get(ctx, stsKey, &appsv1.StatefulSet{}))).To(BeTrue())
 
-		// Verify Main Container Command
-		Expect(s.Spec.Template.Spec.Containers[0].Command).To(Equal([]string{"/opt/lazymc/lazymc"}))
-		Expect(s.Spec.Template.Spec.Containers[0].Args).To(
-			Equal([]string{"--config", "/opt/lazymc/lazymc.toml"}),
-		)
+			By("creating the StatefulSet once the PVC of the source is bound")
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "minecraft-data-mcing-clone-source-0"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pvc)).To(Succeed())
+			pvc.Status.Phase = corev1.ClaimBound
+			Expect(k8sClient.Status().Update(ctx, pvc)).To(Succeed())
+			expectCondition(metav1.ConditionTrue, "Ready")
 
-		// Verify Probes use tcpSocket
-		Expect(s.Spec.Template.Spec.Containers[0].LivenessProbe.TCPSocket).NotTo(BeNil())
-		Expect(
-			s.Spec.Template.Spec.Containers[0].LivenessProbe.TCPSocket.Port.IntVal,
-		).To(Equal(constants.ServerPort))
-		Expect(s.Spec.Template.Spec.Containers[0].ReadinessProbe.TCPSocket).NotTo(BeNil())
-		Expect(
-			s.Spec.Template.Spec.Containers[0].ReadinessProbe.TCPSocket.Port.IntVal,
-		).To(Equal(constants.ServerPort))
+			sts := &appsv1.StatefulSet{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, stsKey, sts)
+			}).Should(Succeed())
+			Expect(sts.Spec.VolumeClaimTemplates[0].Spec.DataSource).To(Equal(&corev1.TypedLocalObjectReference{
+				Kind: "PersistentVolumeClaim",
+				Name: pvc.Name,
+			}))
+			Expect(sts.Spec.Template.Spec.InitContainers[0].Args).To(ContainElements(
+				"--seed-id="+string(mc.UID), "--strip-ops"))
+			Expect(sts.Spec.Template.Spec.InitContainers[0].Args).NotTo(ContainElement("--strip-whitelist"))
+		})
 
-		// Verify ConfigMap override
-		generatedCm = &corev1.ConfigMap{}
-		Eventually(func() error {
-			return k8sClient.Get(
-				ctx,
-				types.NamespacedName{Namespace: mc.Namespace, Name: mc.PrefixedName()},
-				generatedCm,
-			)
-		}).Should(Succeed())
-		val, ok = generatedCm.Data[constants.ServerPropsName]
-		Expect(ok).To(BeTrue())
-		Expect(val).To(ContainSubstring(fmt.Sprintf("server-port=%d", constants.InternalServerPort)))
-		Expect(val).To(ContainSubstring("motd=AutoPause Test"))
+		It("should seed the data volume from a backup archive", func() {
+			digest := strings.Repeat("0", 64)
+			mc := makeMinecraft("seed-test", namespace)
+			mc.Spec.DataSource = &mcingv1alpha1.DataSource{
+				Backup:         &mcingv1alpha1.BackupArchive{URL: "https://example.com/world.tar.gz", SHA256: digest},
+				StripWhitelist: true,
+			}
+			Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+			sts := &appsv1.StatefulSet{}
+			Eventually(func() error {
+				return k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: mc.PrefixedName()}, sts)
+			}).Should(Succeed())
+			Expect(sts.Spec.VolumeClaimTemplates[0].Spec.DataSource).To(BeNil())
+			Expect(sts.Spec.Template.Spec.InitContainers[0].Args).To(ContainElements(
+				"--seed-id="+string(mc.UID),
+				"--seed-url=https://example.com/world.tar.gz",
+				"--seed-sha256="+digest,
+				"--strip-whitelist",
+			))
+		})
 	})
 
 	It("should disable auto-pause configurations", func() {
@@ -462,6 +1792,7 @@ var _ = Describe("Minecraft controller", func() {
 var _ = Describe("Minecraft controller with mc-router", func() {
 	const (
 		mcRouterNamespace = "test-mcrouter"
+		gatewayName       = "test-default"
 		defaultDomain     = "minecraft.local"
 	)
 
@@ -508,17 +1839,15 @@ var _ = Describe("Minecraft controller with mc-router", func() {
 
 		mockMinecraftMgr := &mockManager{ //nolint:exhaustruct // internal struct
 			minecrafts: make(map[string]struct{}),
+			savedOff:   make(map[string]bool),
 		}
 
-		gatewayConfig := GatewayConfig{
-			Enabled:           true,
-			DefaultDomain:     defaultDomain,
-			Namespace:         "mcing-gateway",
-			ServiceAccount:    "mc-router",
-			ServiceType:       corev1.ServiceTypeLoadBalancer,
-			Image:             "itzg/mc-router:latest",
-			ReconcileInterval: 3 * time.Minute,
-		}
+		By("creating the default gateway")
+		gw := &mcingv1alpha1.MinecraftGateway{}
+		gw.Name = gatewayName
+		gw.Spec.Default = true
+		gw.Spec.DefaultDomain = defaultDomain
+		Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, gw))).To(Succeed())
 
 		r := NewMinecraftReconciler(
 			mgr.GetClient(),
@@ -527,7 +1856,6 @@ var _ = Describe("Minecraft controller with mc-router", func() {
 			"ghcr.io/kmdkuk/mcing-init:"+strings.TrimPrefix(version.Version, "v"),
 			"ghcr.io/kmdkuk/mcing-agent:"+strings.TrimPrefix(version.Version, "v"),
 			mockMinecraftMgr,
-			gatewayConfig,
 		)
 		err = r.SetupWithManager(mgr)
 		Expect(err).ToNot(HaveOccurred())
@@ -545,27 +1873,39 @@ var _ = Describe("Minecraft controller with mc-router", func() {
 	AfterEach(func() {
 		mgrCancel()
 		time.Sleep(100 * time.Millisecond)
+
+		gw := &mcingv1alpha1.MinecraftGateway{}
+		gw.Name = gatewayName
+		Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, gw))).To(Succeed())
 	})
 
 	It("should create namespace", func() {
 		createNamespaces(ctx, mcRouterNamespace)
 	})
 
-	It("should add mc-router annotation to service", func() {
+	It("should remove the mc-router annotation set by older versions", func() {
 		mc := makeMinecraft("mc-router-test", mcRouterNamespace)
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        mc.PrefixedName(),
+				Namespace:   mcRouterNamespace,
+				Annotations: map[string]string{constants.MCRouterAnnotation: "mc-router-test.example.com"},
+			},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{Name: constants.ServerPortName, Port: constants.ServerPort}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, svc)).To(Succeed())
 		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
 
-		svc := &corev1.Service{}
 		Eventually(func(g Gomega) {
 			err := k8sClient.Get(ctx, types.NamespacedName{
 				Name:      mc.PrefixedName(),
 				Namespace: mcRouterNamespace,
 			}, svc)
 			g.Expect(err).ShouldNot(HaveOccurred())
-			g.Expect(svc.Annotations).To(HaveKeyWithValue(
-				constants.MCRouterAnnotation,
-				fmt.Sprintf("%s.%s.%s", mc.Name, mcRouterNamespace, defaultDomain),
-			))
+			g.Expect(svc.Labels).To(HaveKeyWithValue(constants.LabelAppInstance, mc.Name))
+			g.Expect(svc.Annotations).NotTo(HaveKey(constants.MCRouterAnnotation))
 		}).Should(Succeed())
 	})
 
@@ -632,26 +1972,6 @@ var _ = Describe("Minecraft controller with mc-router", func() {
 		}).Should(Succeed())
 	})
 
-	It("should use custom ExternalHostname when specified", func() {
-		customHostname := "my-custom-server.example.com"
-		mc := makeMinecraft("mc-router-custom", mcRouterNamespace)
-		mc.Spec.ExternalHostname = &customHostname
-		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
-
-		svc := &corev1.Service{}
-		Eventually(func(g Gomega) {
-			err := k8sClient.Get(ctx, types.NamespacedName{
-				Name:      mc.PrefixedName(),
-				Namespace: mcRouterNamespace,
-			}, svc)
-			g.Expect(err).ShouldNot(HaveOccurred())
-			g.Expect(svc.Annotations).To(HaveKeyWithValue(
-				constants.MCRouterAnnotation,
-				customHostname,
-			))
-		}).Should(Succeed())
-	})
-
 	It("should preserve other annotations from ServiceTemplate", func() {
 		mc := makeMinecraft("mc-router-annotations", mcRouterNamespace)
 		mc.Spec.ServiceTemplate = &mcingv1alpha1.ServiceTemplate{
@@ -670,9 +1990,7 @@ var _ = Describe("Minecraft controller with mc-router", func() {
 				Namespace: mcRouterNamespace,
 			}, svc)
 			g.Expect(err).ShouldNot(HaveOccurred())
-			// Should have both mc-router annotation and custom annotation
-			expectedFQDN := fmt.Sprintf("%s.%s.%s", mc.Name, mcRouterNamespace, defaultDomain)
-			g.Expect(svc.Annotations).To(HaveKeyWithValue(constants.MCRouterAnnotation, expectedFQDN))
+			g.Expect(svc.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
 			g.Expect(svc.Annotations).To(HaveKeyWithValue("custom-annotation", "custom-value"))
 		}).Should(Succeed())
 	})
@@ -701,4 +2019,85 @@ var _ = Describe("Minecraft controller with mc-router", func() {
 			g.Expect(svc.Spec.SessionAffinity).To(Equal(corev1.ServiceAffinityClientIP))
 		}).Should(Succeed())
 	})
+
+	It("should restore the service type of the template when the gateway is deleted", func() {
+		other := &mcingv1alpha1.MinecraftGateway{}
+		other.Name = "test-removed"
+		Expect(k8sClient.Create(ctx, other)).To(Succeed())
+
+		mc := makeMinecraft("mc-router-removed", mcRouterNamespace)
+		mc.Spec.GatewayName = ptr.To(other.Name)
+		mc.Spec.ServiceTemplate = &mcingv1alpha1.ServiceTemplate{
+			Spec: &corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+		}
+		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+		svc := &corev1.Service{}
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, types.NamespacedName{
+				Name:      mc.PrefixedName(),
+				Namespace: mcRouterNamespace,
+			}, svc)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(svc.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+		}).Should(Succeed())
+
+		By("deleting the gateway")
+		Expect(k8sClient.Delete(ctx, other)).To(Succeed())
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, types.NamespacedName{
+				Name:      mc.PrefixedName(),
+				Namespace: mcRouterNamespace,
+			}, svc)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(svc.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+		}).Should(Succeed())
+	})
+
+	It("should not route a server whose gateway does not exist", func() {
+		mc := makeMinecraft("mc-router-missing", mcRouterNamespace)
+		mc.Spec.GatewayName = ptr.To("missing")
+		mc.Spec.ServiceTemplate = &mcingv1alpha1.ServiceTemplate{
+			Spec: &corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+		}
+		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+
+		svc := &corev1.Service{}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{
+				Name:      mc.PrefixedName(),
+				Namespace: mcRouterNamespace,
+			}, svc)
+		}).Should(Succeed())
+		Consistently(func(g Gomega) {
+			err := k8sClient.Get(ctx, types.NamespacedName{
+				Name:      mc.PrefixedName(),
+				Namespace: mcRouterNamespace,
+			}, svc)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(svc.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+		}, 2*time.Second).Should(Succeed())
+	})
+})
+
+var _ = Describe("referencedNames", func() {
+	It("should index the Secrets referenced in the properties and the inline config files", func() {
+		mc := &mcingv1alpha1.Minecraft{
+			Spec: mcingv1alpha1.MinecraftSpec{
+				ServerProperties: &mcingv1alpha1.ServerProperties{MOTD: "${secret:motd/text}"},
+				ConfigFiles: []mcingv1alpha1.ConfigFile{
+					{Path: "plugins/db.yml", Inline: "password: ${secret:db/password}\nuser: ${secret:db/user}\n"},
+					{
+						Path: "bukkit.yml",
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "bukkit"},
+							Key:                  "bukkit.yml",
+						},
+					},
+				},
+				RconPasswordSecretName: ptr.To("rcon"),
+			},
+		}
+		Expect(referencedNames(kindSecret)(mc)).To(ConsistOf("bukkit", "rcon", "motd", "db"))
+	})
 })
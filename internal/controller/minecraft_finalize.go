@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
+	"github.com/kmdkuk/mcing/pkg/constants"
+)
+
+// finalize shuts down the server and handles its PVCs by spec.deletionPolicy.
+// It returns false while the final snapshots are not ready to use.
+func (r *MinecraftReconciler) finalize(ctx context.Context, mc *mcingv1alpha1.Minecraft) (bool, error) {
+	log := r.log.WithValues("minecraft", client.ObjectKeyFromObject(mc))
+
+	policy := mc.Spec.DeletionPolicy
+	snapshots := map[string]*unstructured.Unstructured{}
+	if policy == mcingv1alpha1.DeletionPolicySnapshot {
+		for _, claim := range mc.ClaimNames() {
+			snap := newVolumeSnapshot(mc.Namespace, claim+constants.FinalSnapshotSuffix)
+			err := r.Get(ctx, client.ObjectKeyFromObject(snap), snap)
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if meta.IsNoMatchError(err) {
+				// Without the snapshot controller, the PVCs are retained rather than deleted without a backup.
+				log.Info("VolumeSnapshot is not served, retaining the PVCs")
+				r.recorder.Event(mc, corev1.EventTypeWarning, "SnapshotUnavailable",
+					"VolumeSnapshot is not served, so the PVCs are retained instead of the final snapshots")
+				policy = mcingv1alpha1.DeletionPolicyRetain
+				break
+			}
+			if err != nil {
+				return false, fmt.Errorf("failed to get the final snapshot of %s: %w", claim, err)
+			}
+			snapshots[claim] = snap
+		}
+	}
+
+	// The shutdown is announced once, before the first snapshot is taken.
+	if len(snapshots) == 0 {
+		if err := r.minecraftManager.Shutdown(ctx, mc); err != nil {
+			// The server may be stopped already, e.g. by hibernation or auto-pause.
+			log.Error(err, "failed to shut down the server gracefully")
+		}
+	}
+
+	switch policy {
+	case mcingv1alpha1.DeletionPolicySnapshot:
+		ready, err := r.takeFinalSnapshots(ctx, mc, snapshots)
+		if err != nil || !ready {
+			return false, err
+		}
+		return true, r.deleteClaims(ctx, mc)
+	case mcingv1alpha1.DeletionPolicyDelete:
+		return true, r.deleteClaims(ctx, mc)
+	default:
+		return true, r.releaseClaims(ctx, mc)
+	}
+}
+
+// takeFinalSnapshots takes a snapshot of each PVC of the server that has not been taken yet,
+// and returns whether all of them are ready to use.
+func (r *MinecraftReconciler) takeFinalSnapshots(
+	ctx context.Context,
+	mc *mcingv1alpha1.Minecraft,
+	snapshots map[string]*unstructured.Unstructured,
+) (bool, error) {
+	log := r.log.WithValues("minecraft", client.ObjectKeyFromObject(mc))
+
+	allReady := true
+	for _, claim := range mc.ClaimNames() {
+		if snap, ok := snapshots[claim]; ok {
+			ready, msg := snapshotReady(snap)
+			if msg != "" {
+				log.Info("the final snapshot has an error", "snapshot", snap.GetName(), "error", msg)
+			}
+			allReady = allReady && ready
+			continue
+		}
+
+		pvc := &corev1.PersistentVolumeClaim{}
+		err := r.Get(ctx, client.ObjectKey{Namespace: mc.Namespace, Name: claim}, pvc)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		snap := volumeSnapshotFor(mc, claim+constants.FinalSnapshotSuffix, claim, constants.SnapshotTypeFinal)
+		if err := r.Create(ctx, snap); err != nil {
+			return false, fmt.Errorf("failed to take the final snapshot of %s: %w", claim, err)
+		}
+		log.Info("took the final snapshot", "snapshot", snap.GetName())
+		allReady = false
+	}
+	return allReady, nil
+}
+
+// releaseClaims removes the owner references to mc from the PVCs of the server.
+// The PVCs inherit them from the volume claim templates of the StatefulSet,
+// so the garbage collector would delete them along with mc otherwise.
+func (r *MinecraftReconciler) releaseClaims(ctx context.Context, mc *mcingv1alpha1.Minecraft) error {
+	for _, claim := range mc.ClaimNames() {
+		pvc := &corev1.PersistentVolumeClaim{}
+		err := r.Get(ctx, client.ObjectKey{Namespace: mc.Namespace, Name: claim}, pvc)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		refs := slices.DeleteFunc(slices.Clone(pvc.OwnerReferences), func(ref metav1.OwnerReference) bool {
+			return ref.UID == mc.UID
+		})
+		if len(refs) == len(pvc.OwnerReferences) {
+			continue
+		}
+		patch := client.MergeFrom(pvc.DeepCopy())
+		pvc.OwnerReferences = refs
+		if err := r.Patch(ctx, pvc, patch); err != nil {
+			return fmt.Errorf("failed to release PVC %s: %w", claim, err)
+		}
+	}
+	return nil
+}
+
+// deleteClaims deletes the PVCs of the server.
+// A PVC is removed after the pod of the server is removed.
+func (r *MinecraftReconciler) deleteClaims(ctx context.Context, mc *mcingv1alpha1.Minecraft) error {
+	for _, claim := range mc.ClaimNames() {
+		pvc := &corev1.PersistentVolumeClaim{}
+		pvc.Namespace = mc.Namespace
+		pvc.Name = claim
+		if err := r.Delete(ctx, pvc); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete PVC %s: %w", claim, err)
+		}
+	}
+	return nil
+}
@@ -5,13 +5,16 @@ import (
 	"sync"
 
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
 	"github.com/kmdkuk/mcing/internal/minecraft"
 )
 
 type mockManager struct {
 	mu         sync.Mutex
 	minecrafts map[string]struct{}
+	savedOff   map[string]bool
 }
 
 var _ minecraft.MinecraftManager = &mockManager{} //nolint:exhaustruct // interface check
@@ -30,6 +33,35 @@ func (m *mockManager) Stop(key types.NamespacedName) {
 	delete(m.minecrafts, key.String())
 }
 
+func (m *mockManager) Shutdown(_ context.Context, mc *mcingv1alpha1.Minecraft) error {
+	m.Stop(client.ObjectKeyFromObject(mc))
+	return nil
+}
+
+func (m *mockManager) SaveOff(_ context.Context, mc *mcingv1alpha1.Minecraft) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.savedOff[client.ObjectKeyFromObject(mc).String()] = true
+	return true, nil
+}
+
+func (m *mockManager) SaveOn(_ context.Context, mc *mcingv1alpha1.Minecraft) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.savedOff[client.ObjectKeyFromObject(mc).String()] = false
+	return nil
+}
+
+// isSavedOff returns whether auto-save of the server is disabled.
+func (m *mockManager) isSavedOff(key types.NamespacedName) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.savedOff[key.String()]
+}
+
 func (m *mockManager) Start(ctx context.Context) error {
 	<-ctx.Done()
 	return nil
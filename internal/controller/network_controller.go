@@ -0,0 +1,471 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/rand"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
+	"github.com/kmdkuk/mcing/pkg/config"
+	"github.com/kmdkuk/mcing/pkg/constants"
+)
+
+const (
+	forwardingSecretLength = 32
+
+	proxyReadinessInitialDelaySeconds = 5
+	proxyReadinessPeriodSeconds       = 10
+
+	// reservedServerName is the key of velocity.toml that lists the servers to try, which cannot be a server name.
+	reservedServerName = "try"
+)
+
+//go:embed velocity.toml.tmpl
+var velocityTomlTmpl string
+
+// VelocityConfig holds configuration for velocity.toml template rendering.
+// String fields are TOML-quoted, and empty ones are omitted to keep the Velocity defaults.
+type VelocityConfig struct {
+	Port                 int32
+	MOTD                 string
+	ShowMaxPlayers       string
+	OnlineMode           bool
+	ForwardingSecretFile string
+	Servers              []VelocityServer
+	Try                  string
+}
+
+// VelocityServer is a backend server in velocity.toml.
+type VelocityServer struct {
+	Name    string
+	Address string
+}
+
+// newVelocityConfig builds the velocity.toml template values from the network and its backends.
+func newVelocityConfig(nw *mcingv1alpha1.MinecraftNetwork, backends []mcingv1alpha1.NetworkBackend) VelocityConfig {
+	c := VelocityConfig{
+		Port:                 constants.ProxyPort,
+		MOTD:                 tomlOptionalString(nw.Spec.MOTD),
+		OnlineMode:           nw.Spec.OnlineMode == nil || *nw.Spec.OnlineMode,
+		ForwardingSecretFile: constants.ForwardingSecretKey,
+	}
+	if nw.Spec.MaxPlayers != nil {
+		c.ShowMaxPlayers = strconv.Itoa(int(*nw.Spec.MaxPlayers))
+	}
+	try := nw.Spec.Try
+	if len(try) == 0 {
+		for _, b := range backends {
+			try = append(try, b.Name)
+		}
+	}
+	quoted := make([]string, 0, len(try))
+	for _, name := range try {
+		quoted = append(quoted, tomlString(name))
+	}
+	c.Try = strings.Join(quoted, ", ")
+	for _, b := range backends {
+		c.Servers = append(c.Servers, VelocityServer{Name: tomlString(b.Name), Address: tomlString(b.Address)})
+	}
+	return c
+}
+
+// NetworkReconciler reconciles a MinecraftNetwork by deploying a Velocity proxy.
+type NetworkReconciler struct {
+	client.Client
+
+	log    logr.Logger
+	scheme *runtime.Scheme
+}
+
+// NewNetworkReconciler returns a new NetworkReconciler.
+func NewNetworkReconciler(
+	client client.Client,
+	log logr.Logger,
+	scheme *runtime.Scheme,
+) *NetworkReconciler {
+	return &NetworkReconciler{
+		Client: client,
+		log:    log.WithName("Network"),
+		scheme: scheme,
+	}
+}
+
+//+kubebuilder:rbac:groups=mcing.kmdkuk.com,resources=minecraftnetworks,verbs=get;list;watch
+//+kubebuilder:rbac:groups=mcing.kmdkuk.com,resources=minecraftnetworks/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile deploys the Velocity proxy of a MinecraftNetwork and registers the selected servers in it.
+// The backends are configured for the modern forwarding by the Minecraft reconciler.
+func (r *NetworkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("network", req.NamespacedName)
+
+	nw := &mcingv1alpha1.MinecraftNetwork{}
+	if err := r.Get(ctx, req.NamespacedName, nw); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !nw.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.reconcileForwardingSecret(ctx, nw); err != nil {
+		log.Error(err, "failed to reconcile forwarding secret")
+		return ctrl.Result{}, err
+	}
+
+	backends, err := r.backends(ctx, nw)
+	if err != nil {
+		log.Error(err, "failed to list backends")
+		return ctrl.Result{}, err
+	}
+
+	hash, err := r.reconcileConfigMap(ctx, nw, backends)
+	if err != nil {
+		log.Error(err, "failed to reconcile proxy configmap")
+		return ctrl.Result{}, err
+	}
+
+	deploy, err := r.reconcileDeployment(ctx, nw, hash)
+	if err != nil {
+		log.Error(err, "failed to reconcile proxy deployment")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileService(ctx, nw); err != nil {
+		log.Error(err, "failed to reconcile proxy service")
+		return ctrl.Result{}, err
+	}
+
+	status := mcingv1alpha1.MinecraftNetworkStatus{
+		Backends:      backends,
+		ReadyReplicas: deploy.Status.ReadyReplicas,
+	}
+	if !equality.Semantic.DeepEqual(nw.Status, status) {
+		nw.Status = status
+		if err := r.Status().Update(ctx, nw); err != nil {
+			log.Error(err, "failed to update status")
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcileForwardingSecret generates the secret shared by the proxy and the backends once.
+func (r *NetworkReconciler) reconcileForwardingSecret(ctx context.Context, nw *mcingv1alpha1.MinecraftNetwork) error {
+	secret := &corev1.Secret{}
+	secret.Namespace = nw.Namespace
+	secret.Name = nw.ForwardingSecretName()
+	_, err := ctrl.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		secret.Labels = networkLabels(nw)
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		if _, ok := secret.Data[constants.ForwardingSecretKey]; !ok {
+			secret.Data[constants.ForwardingSecretKey] = []byte(rand.String(forwardingSecretLength))
+		}
+		return ctrl.SetControllerReference(nw, secret, r.scheme)
+	})
+	return err
+}
+
+// backends returns the Minecraft servers that join the network, sorted by name.
+func (r *NetworkReconciler) backends(
+	ctx context.Context,
+	nw *mcingv1alpha1.MinecraftNetwork,
+) ([]mcingv1alpha1.NetworkBackend, error) {
+	networks := &mcingv1alpha1.MinecraftNetworkList{}
+	if err := r.List(ctx, networks, client.InNamespace(nw.Namespace)); err != nil {
+		return nil, err
+	}
+	mcs := &mcingv1alpha1.MinecraftList{}
+	if err := r.List(ctx, mcs, client.InNamespace(nw.Namespace)); err != nil {
+		return nil, err
+	}
+
+	var backends []mcingv1alpha1.NetworkBackend
+	for i := range mcs.Items {
+		mc := &mcs.Items[i]
+		if !mc.DeletionTimestamp.IsZero() {
+			continue
+		}
+		selected := selectNetwork(mc, networks.Items)
+		if selected == nil || selected.Name != nw.Name {
+			continue
+		}
+		backends = append(backends, mcingv1alpha1.NetworkBackend{
+			Name:    mc.Name,
+			Address: serverBackend(mc),
+		})
+	}
+	slices.SortFunc(backends, func(a, b mcingv1alpha1.NetworkBackend) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return backends, nil
+}
+
+// reconcileConfigMap renders velocity.toml and returns its digest.
+func (r *NetworkReconciler) reconcileConfigMap(
+	ctx context.Context,
+	nw *mcingv1alpha1.MinecraftNetwork,
+	backends []mcingv1alpha1.NetworkBackend,
+) (string, error) {
+	velocityToml, err := config.ExecuteTemplate(velocityTomlTmpl, newVelocityConfig(nw, backends))
+	if err != nil {
+		return "", err
+	}
+
+	cm := &corev1.ConfigMap{}
+	cm.Namespace = nw.Namespace
+	cm.Name = nw.PrefixedName()
+	_, err = ctrl.CreateOrUpdate(ctx, r.Client, cm, func() error {
+		cm.Labels = networkLabels(nw)
+		cm.Data = map[string]string{
+			constants.VelocityConfigName: velocityToml,
+		}
+		return ctrl.SetControllerReference(nw, cm, r.scheme)
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(velocityToml))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// reconcileDeployment deploys itzg/mc-proxy running Velocity.
+// The pod is restarted when velocity.toml changes, because Velocity reads the servers only when it starts.
+func (r *NetworkReconciler) reconcileDeployment(
+	ctx context.Context,
+	nw *mcingv1alpha1.MinecraftNetwork,
+	configHash string,
+) (*appsv1.Deployment, error) {
+	deploy := &appsv1.Deployment{}
+	deploy.Namespace = nw.Namespace
+	deploy.Name = nw.PrefixedName()
+
+	labels := networkLabels(nw)
+	env := []corev1.EnvVar{
+		{Name: constants.ProxyTypeEnvName, Value: constants.ProxyTypeVelocity},
+	}
+	if nw.Spec.Version != "" {
+		env = append(env, corev1.EnvVar{Name: constants.VelocityVersionEnvName, Value: nw.Spec.Version})
+	}
+
+	_, err := ctrl.CreateOrUpdate(ctx, r.Client, deploy, func() error {
+		deploy.Labels = labels
+		deploy.Spec = appsv1.DeploymentSpec{
+			Replicas: nw.Spec.Replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+					Annotations: map[string]string{
+						constants.ProxyConfigHashAnnotation: configHash,
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:      constants.ProxyContainerName,
+							Image:     nw.Spec.Image,
+							Env:       env,
+							Resources: nw.Spec.Resources,
+							Ports: []corev1.ContainerPort{
+								{
+									Name:          constants.ProxyPortName,
+									ContainerPort: constants.ProxyPort,
+									Protocol:      corev1.ProtocolTCP,
+								},
+							},
+							ReadinessProbe: &corev1.Probe{
+								ProbeHandler: corev1.ProbeHandler{
+									TCPSocket: &corev1.TCPSocketAction{
+										Port: intstr.FromString(constants.ProxyPortName),
+									},
+								},
+								InitialDelaySeconds: proxyReadinessInitialDelaySeconds,
+								PeriodSeconds:       proxyReadinessPeriodSeconds,
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: constants.ProxyConfigVolumeName, MountPath: constants.ProxyConfigPath, ReadOnly: true},
+								{Name: constants.ProxyServerVolumeName, MountPath: constants.ProxyServerPath},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: constants.ProxyConfigVolumeName,
+							VolumeSource: corev1.VolumeSource{
+								Projected: &corev1.ProjectedVolumeSource{
+									Sources: []corev1.VolumeProjection{
+										{
+											ConfigMap: &corev1.ConfigMapProjection{
+												LocalObjectReference: corev1.LocalObjectReference{Name: nw.PrefixedName()},
+											},
+										},
+										{
+											Secret: &corev1.SecretProjection{
+												LocalObjectReference: corev1.LocalObjectReference{
+													Name: nw.ForwardingSecretName(),
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+						{
+							Name:         constants.ProxyServerVolumeName,
+							VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+						},
+					},
+				},
+			},
+		}
+		return ctrl.SetControllerReference(nw, deploy, r.scheme)
+	})
+	return deploy, err
+}
+
+// reconcileService exposes the proxy to the players with the settings of the service template.
+func (r *NetworkReconciler) reconcileService(ctx context.Context, nw *mcingv1alpha1.MinecraftNetwork) error {
+	svc := &corev1.Service{}
+	svc.Namespace = nw.Namespace
+	svc.Name = nw.PrefixedName()
+
+	labels := networkLabels(nw)
+	_, err := ctrl.CreateOrUpdate(ctx, r.Client, svc, func() error {
+		sSpec := &corev1.ServiceSpec{}
+		if tmpl := nw.Spec.ServiceTemplate; tmpl != nil {
+			svc.Annotations = config.MergeMap(svc.Annotations, tmpl.Annotations)
+			svc.Labels = config.MergeMap(svc.Labels, tmpl.Labels)
+			if tmpl.Spec != nil {
+				tmpl.Spec.DeepCopyInto(sSpec)
+			}
+		}
+		svc.Labels = config.MergeMap(svc.Labels, labels)
+
+		sSpec.ClusterIP = svc.Spec.ClusterIP
+		sSpec.ClusterIPs = svc.Spec.ClusterIPs
+		if len(sSpec.Type) == 0 {
+			sSpec.Type = svc.Spec.Type
+		}
+		if sSpec.IPFamilies == nil {
+			sSpec.IPFamilies = svc.Spec.IPFamilies
+		}
+		if sSpec.IPFamilyPolicy == nil {
+			sSpec.IPFamilyPolicy = svc.Spec.IPFamilyPolicy
+		}
+		sSpec.Selector = labels
+
+		// Preserve NodePort if already set
+		var nodePort int32
+		if sSpec.Type != corev1.ServiceTypeClusterIP {
+			for _, p := range svc.Spec.Ports {
+				if p.Name == constants.ProxyPortName {
+					nodePort = p.NodePort
+				}
+			}
+		}
+		sSpec.Ports = []corev1.ServicePort{
+			{
+				Name:       constants.ProxyPortName,
+				Protocol:   corev1.ProtocolTCP,
+				Port:       constants.ServerPort,
+				TargetPort: intstr.FromString(constants.ProxyPortName),
+				NodePort:   nodePort,
+			},
+		}
+		sSpec.DeepCopyInto(&svc.Spec)
+		return ctrl.SetControllerReference(nw, svc, r.scheme)
+	})
+	return err
+}
+
+// selectNetwork returns the network that mc joins, or nil if there is none.
+// If more than one network in the namespace selects mc, the one with the smallest name is used.
+func selectNetwork(
+	mc *mcingv1alpha1.Minecraft,
+	networks []mcingv1alpha1.MinecraftNetwork,
+) *mcingv1alpha1.MinecraftNetwork {
+	if mc.Name == reservedServerName {
+		return nil
+	}
+	var selected *mcingv1alpha1.MinecraftNetwork
+	for i := range networks {
+		nw := &networks[i]
+		if !nw.DeletionTimestamp.IsZero() || nw.Namespace != mc.Namespace {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(&nw.Spec.Selector)
+		if err != nil || !selector.Matches(labels.Set(mc.Labels)) {
+			continue
+		}
+		if selected == nil || nw.Name < selected.Name {
+			selected = nw
+		}
+	}
+	return selected
+}
+
+func networkLabels(nw *mcingv1alpha1.MinecraftNetwork) map[string]string {
+	return map[string]string{
+		constants.LabelAppInstance:  nw.Name,
+		constants.LabelAppName:      constants.AppName,
+		constants.LabelAppComponent: constants.ProxyAppComponent,
+		constants.LabelAppCreatedBy: constants.ControllerName,
+	}
+}
+
+// enqueueNetworks returns a handler that enqueues every network in the namespace of a Minecraft,
+// because a change of its labels may move it from one network to another.
+func (r *NetworkReconciler) enqueueNetworks() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(
+		func(ctx context.Context, obj client.Object) []reconcile.Request {
+			networks := &mcingv1alpha1.MinecraftNetworkList{}
+			if err := r.List(ctx, networks, client.InNamespace(obj.GetNamespace())); err != nil {
+				r.log.Error(err, "failed to list MinecraftNetworks", "namespace", obj.GetNamespace())
+				return nil
+			}
+			reqs := make([]reconcile.Request, 0, len(networks.Items))
+			for _, nw := range networks.Items {
+				reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&nw)})
+			}
+			return reqs
+		},
+	)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NetworkReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("network").
+		For(&mcingv1alpha1.MinecraftNetwork{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.Service{}).
+		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Secret{}).
+		Watches(&mcingv1alpha1.Minecraft{}, r.enqueueNetworks()).
+		Complete(r)
+}
@@ -0,0 +1,240 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"      //nolint:revive // dot imports for tests
+	. "github.com/onsi/gomega"         //nolint:revive // dot imports for tests
+	. "github.com/onsi/gomega/gstruct" //nolint:revive // dot imports for tests
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/config"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
+	"github.com/kmdkuk/mcing/pkg/constants"
+)
+
+var _ = Describe("Network controller", func() {
+	const networkNamespace = "test-network"
+
+	ctx := context.Background()
+	var mgrCtx context.Context
+	var mgrCancel context.CancelFunc
+
+	makeNetwork := func(name string) *mcingv1alpha1.MinecraftNetwork {
+		nw := &mcingv1alpha1.MinecraftNetwork{}
+		nw.Namespace = networkNamespace
+		nw.Name = name
+		nw.Spec.Selector = metav1.LabelSelector{MatchLabels: map[string]string{"network": name}}
+		return nw
+	}
+
+	BeforeEach(func() {
+		mgr, err := ctrl.NewManager(k8sCfg, ctrl.Options{
+			Scheme:         scheme,
+			LeaderElection: false,
+			Metrics:        metricsserver.Options{BindAddress: "0"},
+			Controller: config.Controller{
+				SkipNameValidation: ptr.To(true),
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		r := NewNetworkReconciler(
+			mgr.GetClient(),
+			ctrl.Log.WithName("controllers"),
+			mgr.GetScheme(),
+		)
+		err = r.SetupWithManager(mgr)
+		Expect(err).ToNot(HaveOccurred())
+
+		mgrCtx, mgrCancel = context.WithCancel(context.Background()) //nolint:fatcontext // test logic
+		go func() {
+			err := mgr.Start(mgrCtx)
+			if err != nil {
+				panic(err)
+			}
+		}()
+		time.Sleep(time.Second)
+	})
+
+	AfterEach(func() {
+		mgrCancel()
+		time.Sleep(100 * time.Millisecond)
+	})
+
+	It("should create the namespace", func() {
+		createNamespaces(ctx, networkNamespace)
+	})
+
+	It("should deploy a Velocity proxy for a network", func() {
+		By("creating a MinecraftNetwork")
+		nw := makeNetwork("deploy")
+		nw.Spec.Version = "3.4.0-SNAPSHOT"
+		nw.Spec.MOTD = "<green>mcing"
+		nw.Spec.MaxPlayers = ptr.To[int32](100)
+		nw.Spec.ServiceTemplate = &mcingv1alpha1.ServiceTemplate{
+			Spec: &corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort},
+		}
+		Expect(k8sClient.Create(ctx, nw)).To(Succeed())
+
+		By("checking the forwarding secret")
+		var secret []byte
+		Eventually(func(g Gomega) {
+			s := &corev1.Secret{}
+			err := k8sClient.Get(ctx, types.NamespacedName{
+				Namespace: networkNamespace,
+				Name:      "mcing-network-deploy-forwarding",
+			}, s)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(s.Data[constants.ForwardingSecretKey]).To(HaveLen(forwardingSecretLength))
+			g.Expect(s.OwnerReferences).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+				"Kind": Equal("MinecraftNetwork"),
+				"Name": Equal("deploy"),
+			})))
+			secret = s.Data[constants.ForwardingSecretKey]
+		}).Should(Succeed())
+
+		By("checking velocity.toml")
+		Eventually(func(g Gomega) {
+			cm := &corev1.ConfigMap{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: networkNamespace, Name: "mcing-network-deploy"}, cm)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			velocityToml := cm.Data[constants.VelocityConfigName]
+			g.Expect(velocityToml).To(ContainSubstring(`bind = "0.0.0.0:25577"`))
+			g.Expect(velocityToml).To(ContainSubstring(`motd = "<green>mcing"`))
+			g.Expect(velocityToml).To(ContainSubstring(`show-max-players = 100`))
+			g.Expect(velocityToml).To(ContainSubstring(`online-mode = true`))
+			g.Expect(velocityToml).To(ContainSubstring(`player-info-forwarding-mode = "modern"`))
+			g.Expect(velocityToml).To(ContainSubstring(`forwarding-secret-file = "forwarding.secret"`))
+		}).Should(Succeed())
+
+		By("checking the deployment")
+		Eventually(func(g Gomega) {
+			deploy := &appsv1.Deployment{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: networkNamespace, Name: "mcing-network-deploy"}, deploy)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(deploy.Labels).To(HaveKeyWithValue(constants.LabelAppComponent, constants.ProxyAppComponent))
+			g.Expect(deploy.Spec.Replicas).To(Equal(ptr.To[int32](1)))
+
+			podSpec := deploy.Spec.Template.Spec
+			g.Expect(deploy.Spec.Template.Annotations).To(HaveKey(constants.ProxyConfigHashAnnotation))
+			g.Expect(podSpec.Containers).To(HaveLen(1))
+			container := podSpec.Containers[0]
+			g.Expect(container.Name).To(Equal(constants.ProxyContainerName))
+			g.Expect(container.Image).To(Equal("itzg/mc-proxy:latest"))
+			g.Expect(container.Env).To(ConsistOf(
+				corev1.EnvVar{Name: "TYPE", Value: "VELOCITY"},
+				corev1.EnvVar{Name: "VELOCITY_VERSION", Value: "3.4.0-SNAPSHOT"},
+			))
+			g.Expect(podSpec.Volumes).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Name": Equal(constants.ProxyConfigVolumeName),
+				"VolumeSource": MatchFields(IgnoreExtras, Fields{
+					"Projected": PointTo(MatchFields(IgnoreExtras, Fields{
+						"Sources": HaveLen(2),
+					})),
+				}),
+			})))
+		}).Should(Succeed())
+
+		By("checking the service")
+		Eventually(func(g Gomega) {
+			svc := &corev1.Service{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: networkNamespace, Name: "mcing-network-deploy"}, svc)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(svc.Spec.Type).To(Equal(corev1.ServiceTypeNodePort))
+			g.Expect(svc.Spec.Selector).To(HaveKeyWithValue(constants.LabelAppInstance, "deploy"))
+			g.Expect(svc.Spec.Ports).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+				"Name": Equal(constants.ProxyPortName),
+				"Port": Equal(constants.ServerPort),
+			})))
+		}).Should(Succeed())
+
+		By("keeping the forwarding secret")
+		Consistently(func(g Gomega) {
+			s := &corev1.Secret{}
+			err := k8sClient.Get(ctx, types.NamespacedName{
+				Namespace: networkNamespace,
+				Name:      "mcing-network-deploy-forwarding",
+			}, s)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(s.Data[constants.ForwardingSecretKey]).To(Equal(secret))
+		}, 2*time.Second).Should(Succeed())
+	})
+
+	It("should register the selected Minecraft servers", func() {
+		By("creating a MinecraftNetwork")
+		nw := makeNetwork("backends")
+		Expect(k8sClient.Create(ctx, nw)).To(Succeed())
+
+		By("creating Minecrafts in and out of the network")
+		var mcs []*mcingv1alpha1.Minecraft
+		for _, name := range []string{"survival", "lobby", "other"} {
+			mc := makeMinecraft(name, networkNamespace)
+			if name != "other" {
+				mc.Labels = map[string]string{"network": "backends"}
+			}
+			Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+			mcs = append(mcs, mc)
+		}
+		defer func() {
+			for _, mc := range mcs {
+				mc.Finalizers = nil
+				_ = k8sClient.Update(ctx, mc)
+				_ = k8sClient.Delete(ctx, mc)
+			}
+		}()
+
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(nw), nw)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(nw.Status.Backends).To(Equal([]mcingv1alpha1.NetworkBackend{
+				{Name: "lobby", Address: "mcing-lobby." + networkNamespace + ".svc:25565"},
+				{Name: "survival", Address: "mcing-survival." + networkNamespace + ".svc:25565"},
+			}))
+
+			cm := &corev1.ConfigMap{}
+			err = k8sClient.Get(ctx, types.NamespacedName{Namespace: networkNamespace, Name: "mcing-network-backends"}, cm)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			velocityToml := cm.Data[constants.VelocityConfigName]
+			g.Expect(velocityToml).To(ContainSubstring(`"lobby" = "mcing-lobby.` + networkNamespace + `.svc:25565"`))
+			g.Expect(velocityToml).To(ContainSubstring(`"survival" = "mcing-survival.` + networkNamespace + `.svc:25565"`))
+			g.Expect(velocityToml).NotTo(ContainSubstring(`"other"`))
+			g.Expect(velocityToml).To(ContainSubstring(`try = ["lobby", "survival"]`))
+		}).Should(Succeed())
+
+		By("removing a server from the network")
+		survival := mcs[0]
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(survival), survival)).To(Succeed())
+		survival.Labels = nil
+		Expect(k8sClient.Update(ctx, survival)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(nw), nw)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(nw.Status.Backends).To(Equal([]mcingv1alpha1.NetworkBackend{
+				{Name: "lobby", Address: "mcing-lobby." + networkNamespace + ".svc:25565"},
+			}))
+		}).Should(Succeed())
+	})
+
+	It("should use the try list of the network", func() {
+		nw := makeNetwork("try")
+		nw.Spec.Try = []string{"survival", "lobby"}
+		Expect(k8sClient.Create(ctx, nw)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			cm := &corev1.ConfigMap{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: networkNamespace, Name: "mcing-network-try"}, cm)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(cm.Data[constants.VelocityConfigName]).To(ContainSubstring(`try = ["survival", "lobby"]`))
+		}).Should(Succeed())
+	})
+})
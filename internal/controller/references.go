@@ -0,0 +1,269 @@
+package controller
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
+	"github.com/kmdkuk/mcing/pkg/config"
+)
+
+const (
+	// configMapRefsIndex indexes Minecrafts by the names of the ConfigMaps referenced by the spec.
+	configMapRefsIndex = ".spec.configMapRefs"
+	// secretRefsIndex indexes Minecrafts by the names of the Secrets referenced by the spec.
+	secretRefsIndex = ".spec.secretRefs"
+	// missingSecretIndex indexes Minecrafts by the name of the Secret that the ReferencesResolved condition reports
+	// as missing. The Secret may be referenced in the content of a ConfigMap, which secretRefsIndex does not cover.
+	missingSecretIndex = ".status.missingSecret"
+
+	kindConfigMap = "ConfigMap"
+	kindSecret    = "Secret"
+
+	// missingReferenceSuffix ends the message of the ReferencesResolved condition for a missing object.
+	missingReferenceSuffix = " is not found"
+)
+
+// objectReference is a ConfigMap or a Secret referenced by a Minecraft.
+type objectReference struct {
+	kind string
+	name string
+	// optional references do not have to exist.
+	optional bool
+}
+
+func (ref objectReference) object() client.Object {
+	if ref.kind == kindSecret {
+		return &corev1.Secret{}
+	}
+	return &corev1.ConfigMap{}
+}
+
+// references returns the ConfigMaps and Secrets referenced by the spec,
+// including the Secrets referenced by ${secret:name/key} in the properties and the inline config files.
+// ConfigMaps and Secrets created by the controller are not included.
+func references(mc *mcingv1alpha1.Minecraft) []objectReference {
+	var refs []objectReference
+	if mc.Spec.ServerPropertiesConfigMapName != nil {
+		refs = append(refs, objectReference{kind: kindConfigMap, name: *mc.Spec.ServerPropertiesConfigMapName})
+	}
+	if mc.Spec.OtherConfigMapName != nil {
+		refs = append(refs, objectReference{kind: kindConfigMap, name: *mc.Spec.OtherConfigMapName})
+	}
+	for _, artifacts := range [][]mcingv1alpha1.Artifact{mc.Spec.Mods, mc.Spec.Plugins} {
+		for _, a := range artifacts {
+			if a.ConfigMap == nil {
+				continue
+			}
+			refs = append(refs, objectReference{
+				kind:     kindConfigMap,
+				name:     a.ConfigMap.Name,
+				optional: a.ConfigMap.Optional != nil && *a.ConfigMap.Optional,
+			})
+		}
+	}
+	for _, f := range mc.Spec.ConfigFiles {
+		switch {
+		case f.ConfigMapKeyRef != nil:
+			refs = append(refs, objectReference{
+				kind:     kindConfigMap,
+				name:     f.ConfigMapKeyRef.Name,
+				optional: f.ConfigMapKeyRef.Optional != nil && *f.ConfigMapKeyRef.Optional,
+			})
+		case f.SecretKeyRef != nil:
+			refs = append(refs, objectReference{
+				kind:     kindSecret,
+				name:     f.SecretKeyRef.Name,
+				optional: f.SecretKeyRef.Optional != nil && *f.SecretKeyRef.Optional,
+			})
+		}
+	}
+	if mc.Spec.RconPasswordSecretName != nil {
+		refs = append(refs, objectReference{kind: kindSecret, name: *mc.Spec.RconPasswordSecretName})
+	}
+	var content []string
+	if mc.Spec.ServerProperties != nil {
+		content = slices.AppendSeq(content, maps.Values(mc.Spec.ServerProperties.Properties()))
+	}
+	for _, f := range mc.Spec.ConfigFiles {
+		content = append(content, f.Inline)
+	}
+	return append(refs, secretReferences(strings.Join(content, "\n"))...)
+}
+
+// secretReferences returns the Secrets referenced by ${secret:name/key} in content.
+func secretReferences(content string) []objectReference {
+	var refs []objectReference
+	for _, ref := range config.FindSecretRefs(content) {
+		refs = append(refs, objectReference{kind: kindSecret, name: ref.Name})
+	}
+	return refs
+}
+
+// contentSecretReferences returns the Secrets referenced by ${secret:name/key} in the referenced ConfigMaps.
+// They are not in the index, because it is built from the Minecraft alone.
+// A missing ConfigMap is skipped, because it is reported by resolveReferences.
+func (r *MinecraftReconciler) contentSecretReferences(
+	ctx context.Context,
+	mc *mcingv1alpha1.Minecraft,
+) ([]objectReference, error) {
+	var content []string
+	read := func(name string, keys ...string) error {
+		cm := &corev1.ConfigMap{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: mc.Namespace, Name: name}, cm)
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			keys = slices.Sorted(maps.Keys(cm.Data))
+		}
+		for _, k := range keys {
+			content = append(content, cm.Data[k])
+		}
+		return nil
+	}
+	for _, name := range []*string{mc.Spec.ServerPropertiesConfigMapName, mc.Spec.OtherConfigMapName} {
+		if name == nil {
+			continue
+		}
+		if err := read(*name); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range mc.Spec.ConfigFiles {
+		if f.ConfigMapKeyRef == nil {
+			continue
+		}
+		if err := read(f.ConfigMapKeyRef.Name, f.ConfigMapKeyRef.Key); err != nil {
+			return nil, err
+		}
+	}
+	return secretReferences(strings.Join(content, "\n")), nil
+}
+
+// referencedNames returns the names of the referenced objects of the kind for a field index.
+func referencedNames(kind string) client.IndexerFunc {
+	return func(obj client.Object) []string {
+		mc, ok := obj.(*mcingv1alpha1.Minecraft)
+		if !ok {
+			return nil
+		}
+		var names []string
+		seen := map[string]bool{}
+		for _, ref := range references(mc) {
+			if ref.kind != kind || seen[ref.name] {
+				continue
+			}
+			seen[ref.name] = true
+			names = append(names, ref.name)
+		}
+		return names
+	}
+}
+
+// missingSecretName returns the name of the missing Secret for a field index.
+// It is parsed from the ReferencesResolved condition, whose message is written by missingReferenceMessage.
+func missingSecretName(obj client.Object) []string {
+	mc, ok := obj.(*mcingv1alpha1.Minecraft)
+	if !ok {
+		return nil
+	}
+	cond := meta.FindStatusCondition(mc.Status.Conditions, mcingv1alpha1.ConditionReferencesResolved)
+	if cond == nil || cond.Reason != kindSecret+"NotFound" {
+		return nil
+	}
+	name, ok := strings.CutPrefix(cond.Message, kindSecret+" ")
+	if !ok {
+		return nil
+	}
+	name, ok = strings.CutSuffix(name, missingReferenceSuffix)
+	if !ok {
+		return nil
+	}
+	return []string{name}
+}
+
+// enqueueReferencing returns a handler that enqueues every Minecraft found by the name of the object
+// in any of the indexes.
+func (r *MinecraftReconciler) enqueueReferencing(indexes ...string) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(
+		func(ctx context.Context, obj client.Object) []reconcile.Request {
+			var reqs []reconcile.Request
+			for _, index := range indexes {
+				mcs := &mcingv1alpha1.MinecraftList{}
+				if err := r.List(ctx, mcs,
+					client.InNamespace(obj.GetNamespace()),
+					client.MatchingFields{index: obj.GetName()},
+				); err != nil {
+					r.log.Error(err, "failed to list Minecrafts", "index", index, "name", obj.GetName())
+					return nil
+				}
+				for _, mc := range mcs.Items {
+					req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&mc)}
+					if !slices.Contains(reqs, req) {
+						reqs = append(reqs, req)
+					}
+				}
+			}
+			return reqs
+		},
+	)
+}
+
+// resolveReferences checks that the referenced ConfigMaps and Secrets exist and
+// records the result in the ReferencesResolved condition.
+// It returns false if a required reference is missing.
+func (r *MinecraftReconciler) resolveReferences(ctx context.Context, mc *mcingv1alpha1.Minecraft) (bool, error) {
+	cond := metav1.Condition{
+		Type:               mcingv1alpha1.ConditionReferencesResolved,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Resolved",
+		Message:            "all referenced ConfigMaps and Secrets exist",
+		ObservedGeneration: mc.Generation,
+	}
+	contentRefs, err := r.contentSecretReferences(ctx, mc)
+	if err != nil {
+		return false, err
+	}
+	for _, ref := range slices.Concat(references(mc), contentRefs) {
+		if ref.optional {
+			continue
+		}
+		err := r.Get(ctx, types.NamespacedName{Namespace: mc.Namespace, Name: ref.name}, ref.object())
+		if apierrors.IsNotFound(err) {
+			cond.Status = metav1.ConditionFalse
+			cond.Reason = ref.kind + "NotFound"
+			cond.Message = missingReferenceMessage(ref)
+			break
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if meta.SetStatusCondition(&mc.Status.Conditions, cond) {
+		if err := r.Status().Update(ctx, mc); err != nil {
+			return false, err
+		}
+	}
+	return cond.Status == metav1.ConditionTrue, nil
+}
+
+// missingReferenceMessage returns the message of the ReferencesResolved condition for a missing object.
+func missingReferenceMessage(ref objectReference) string {
+	return ref.kind + " " + ref.name + missingReferenceSuffix
+}
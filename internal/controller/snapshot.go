@@ -0,0 +1,227 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
+	"github.com/kmdkuk/mcing/pkg/constants"
+)
+
+// saveOffTimeout is how long auto-save is kept disabled while waiting for a snapshot to be taken.
+const saveOffTimeout = 5 * time.Minute
+
+// newVolumeSnapshot returns an empty VolumeSnapshot to get or delete.
+// VolumeSnapshot is unstructured so that the controller works without the CRDs of the snapshot controller.
+func newVolumeSnapshot(namespace, name string) *unstructured.Unstructured {
+	snap := &unstructured.Unstructured{}
+	snap.SetAPIVersion(constants.VolumeSnapshotAPIVersion)
+	snap.SetKind(constants.VolumeSnapshotKind)
+	snap.SetNamespace(namespace)
+	snap.SetName(name)
+	return snap
+}
+
+// volumeSnapshotFor returns a VolumeSnapshot of the PVC claim of mc.
+// It is not owned by mc so that it outlives the server.
+func volumeSnapshotFor(mc *mcingv1alpha1.Minecraft, name, claim, snapshotType string) *unstructured.Unstructured {
+	snap := newVolumeSnapshot(mc.Namespace, name)
+	snapLabels := labelSet(mc, constants.AppComponentBackup)
+	snapLabels[constants.SnapshotTypeLabel] = snapshotType
+	snap.SetLabels(snapLabels)
+	spec := map[string]any{
+		"source": map[string]any{
+			"persistentVolumeClaimName": claim,
+		},
+	}
+	if mc.Spec.Backup.VolumeSnapshotClassName != nil {
+		spec["volumeSnapshotClassName"] = *mc.Spec.Backup.VolumeSnapshotClassName
+	}
+	snap.Object["spec"] = spec
+	return snap
+}
+
+// snapshotReady returns whether the snapshot is ready to use, and the error reported by the snapshot controller.
+func snapshotReady(snap *unstructured.Unstructured) (bool, string) {
+	ready, _, _ := unstructured.NestedBool(snap.Object, "status", "readyToUse")
+	msg, _, _ := unstructured.NestedString(snap.Object, "status", "error", "message")
+	return ready, msg
+}
+
+// snapshotTaken returns whether the point-in-time snapshot has been cut.
+// The data may still be uploaded after that, but the volume can be written again.
+func snapshotTaken(snap *unstructured.Unstructured) bool {
+	created, _, _ := unstructured.NestedString(snap.Object, "status", "creationTime")
+	ready, _ := snapshotReady(snap)
+	return created != "" || ready
+}
+
+// scheduledSnapshots returns the snapshots taken by spec.backup.snapshot, newest first.
+func (r *MinecraftReconciler) scheduledSnapshots(
+	ctx context.Context,
+	mc *mcingv1alpha1.Minecraft,
+) ([]unstructured.Unstructured, error) {
+	selector := labelSet(mc, constants.AppComponentBackup)
+	selector[constants.SnapshotTypeLabel] = constants.SnapshotTypeScheduled
+
+	snaps := &unstructured.UnstructuredList{}
+	snaps.SetAPIVersion(constants.VolumeSnapshotAPIVersion)
+	snaps.SetKind(constants.VolumeSnapshotListKind)
+	if err := r.List(ctx, snaps, client.InNamespace(mc.Namespace),
+		client.MatchingLabelsSelector{Selector: labels.SelectorFromSet(selector)}); err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	items := snaps.Items
+	slices.SortFunc(items, func(a, b unstructured.Unstructured) int {
+		return b.GetCreationTimestamp().Compare(a.GetCreationTimestamp().Time)
+	})
+	return items, nil
+}
+
+// reconcileSnapshotBackup takes a snapshot of the data volume every spec.backup.snapshot.intervalSeconds
+// and deletes the old ones. The world is saved and auto-save is disabled until the snapshot is cut,
+// which spans multiple reconciliations, so the snapshot is annotated while auto-save is disabled.
+// It returns when to reconcile mc again.
+func (r *MinecraftReconciler) reconcileSnapshotBackup(
+	ctx context.Context,
+	mc *mcingv1alpha1.Minecraft,
+) (time.Duration, error) {
+	backup := mc.Spec.Backup.Snapshot
+	if backup == nil {
+		return 0, nil
+	}
+	log := r.log.WithValues("minecraft", client.ObjectKeyFromObject(mc))
+
+	snapshots, err := r.scheduledSnapshots(ctx, mc)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(snapshots) != 0 {
+		latest := &snapshots[0]
+		if _, ok := latest.GetAnnotations()[constants.SaveOffAnnotation]; ok {
+			if !snapshotTaken(latest) && time.Since(latest.GetCreationTimestamp().Time) < saveOffTimeout {
+				return snapshotPollInterval, nil
+			}
+			if err := r.minecraftManager.SaveOn(ctx, mc); err != nil {
+				return 0, err
+			}
+			patch := client.MergeFrom(latest.DeepCopy())
+			annotations := latest.GetAnnotations()
+			delete(annotations, constants.SaveOffAnnotation)
+			latest.SetAnnotations(annotations)
+			if err := r.Patch(ctx, latest, patch); err != nil {
+				return 0, fmt.Errorf("failed to update snapshot %s: %w", latest.GetName(), err)
+			}
+			log.Info("enabled auto-save after the snapshot", "snapshot", latest.GetName())
+		}
+	}
+
+	// The latest snapshot is never deleted because keep is at least 1.
+	for i := backup.Keep; i < len(snapshots); i++ {
+		if err := r.Delete(ctx, &snapshots[i]); client.IgnoreNotFound(err) != nil {
+			return 0, fmt.Errorf("failed to delete snapshot %s: %w", snapshots[i].GetName(), err)
+		}
+		log.Info("deleted an old snapshot", "snapshot", snapshots[i].GetName())
+	}
+
+	interval := time.Duration(backup.IntervalSeconds) * time.Second
+	if len(snapshots) != 0 {
+		if wait := time.Until(snapshots[0].GetCreationTimestamp().Add(interval)); wait > 0 {
+			return wait, nil
+		}
+	}
+
+	claim := constants.DataVolumeName + "-" + mc.PodName()
+	err = r.Get(ctx, client.ObjectKey{Namespace: mc.Namespace, Name: claim}, &corev1.PersistentVolumeClaim{})
+	if apierrors.IsNotFound(err) {
+		// The PVC is watched, so mc is reconciled again when it is created.
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	savedOff, err := r.minecraftManager.SaveOff(ctx, mc)
+	if err != nil {
+		// The server may be paused by auto-pause, which saves the world before pausing.
+		log.Error(err, "failed to save the world before the snapshot")
+	}
+	snap := volumeSnapshotFor(mc, claim+"-"+time.Now().UTC().Format("20060102150405"), claim,
+		constants.SnapshotTypeScheduled)
+	if savedOff {
+		snap.SetAnnotations(map[string]string{constants.SaveOffAnnotation: "true"})
+	}
+	if err := r.Create(ctx, snap); err != nil {
+		if savedOff {
+			if err := r.minecraftManager.SaveOn(ctx, mc); err != nil {
+				log.Error(err, "failed to enable auto-save")
+			}
+		}
+		return 0, fmt.Errorf("failed to take a snapshot of %s: %w", claim, err)
+	}
+	log.Info("took a snapshot", "snapshot", snap.GetName(), "savedOff", savedOff)
+	// Check the snapshot again to enable auto-save and delete the old snapshots.
+	return snapshotPollInterval, nil
+}
+
+// restoreSnapshotReady returns whether the snapshot that the data volume is restored from is ready to use.
+// The snapshot is checked only before the StatefulSet is created, because the PVC is created from it only once.
+func (r *MinecraftReconciler) restoreSnapshotReady(ctx context.Context, mc *mcingv1alpha1.Minecraft) (bool, error) {
+	name := mc.Spec.RestoreSnapshotName()
+	if name == "" {
+		return true, nil
+	}
+	err := r.Get(ctx, client.ObjectKey{Namespace: mc.Namespace, Name: mc.PrefixedName()}, &appsv1.StatefulSet{})
+	if err == nil {
+		return true, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return false, err
+	}
+
+	cond := metav1.Condition{
+		Type:               mcingv1alpha1.ConditionRestoreSnapshotReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Ready",
+		Message:            fmt.Sprintf("VolumeSnapshot %s is ready to use", name),
+		ObservedGeneration: mc.Generation,
+	}
+	snap := newVolumeSnapshot(mc.Namespace, name)
+	err = r.Get(ctx, client.ObjectKeyFromObject(snap), snap)
+	switch {
+	case apierrors.IsNotFound(err) || meta.IsNoMatchError(err):
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "SnapshotNotFound"
+		cond.Message = fmt.Sprintf("VolumeSnapshot %s is not found", name)
+	case err != nil:
+		return false, err
+	default:
+		if ready, msg := snapshotReady(snap); !ready {
+			cond.Status = metav1.ConditionFalse
+			cond.Reason = "SnapshotNotReady"
+			cond.Message = fmt.Sprintf("VolumeSnapshot %s is not ready to use", name)
+			if msg != "" {
+				cond.Message += ": " + msg
+			}
+		}
+	}
+
+	if meta.SetStatusCondition(&mc.Status.Conditions, cond) {
+		if err := r.Status().Update(ctx, mc); err != nil {
+			return false, err
+		}
+	}
+	return cond.Status == metav1.ConditionTrue, nil
+}
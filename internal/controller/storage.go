@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
+	"github.com/kmdkuk/mcing/pkg/constants"
+)
+
+// pvcRetentionPolicy returns the PVC retention policy of the StatefulSet.
+// Unset fields are filled with Retain as the API server does, so that the StatefulSet is not updated every time.
+func pvcRetentionPolicy(mc *mcingv1alpha1.Minecraft) *appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy {
+	policy := &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+		WhenDeleted: appsv1.RetainPersistentVolumeClaimRetentionPolicyType,
+		WhenScaled:  appsv1.RetainPersistentVolumeClaimRetentionPolicyType,
+	}
+	if p := mc.Spec.PersistentVolumeClaimRetentionPolicy; p != nil {
+		if p.WhenDeleted != "" {
+			policy.WhenDeleted = p.WhenDeleted
+		}
+		if p.WhenScaled != "" {
+			policy.WhenScaled = p.WhenScaled
+		}
+	}
+	return policy
+}
+
+// reconcileStorage expands the data PVC when the storage request in spec.volumeClaimTemplates is increased,
+// and reports the storage of the PVC in the status.
+func (r *MinecraftReconciler) reconcileStorage(ctx context.Context, mc *mcingv1alpha1.Minecraft) error {
+	logger := r.log.WithName("storage")
+
+	var template *mcingv1alpha1.PersistentVolumeClaim
+	for i := range mc.Spec.VolumeClaimTemplates {
+		if mc.Spec.VolumeClaimTemplates[i].Name == constants.DataVolumeName {
+			template = &mc.Spec.VolumeClaimTemplates[i]
+		}
+	}
+	if template == nil {
+		return nil
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: mc.Namespace, Name: template.Name + "-" + mc.PodName()}, pvc)
+	if apierrors.IsNotFound(err) {
+		// The StatefulSet controller creates the PVC along with the pod.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	desired := template.Spec.Resources.Requests[corev1.ResourceStorage]
+	requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	cond := metav1.Condition{
+		Type:               mcingv1alpha1.ConditionStorageResized,
+		Status:             metav1.ConditionTrue,
+		Reason:             "UpToDate",
+		Message:            "the PVC has the requested storage",
+		ObservedGeneration: mc.Generation,
+	}
+	if desired.Cmp(requested) > 0 {
+		allowed, err := r.expansionAllowed(ctx, pvc)
+		if err != nil {
+			return err
+		}
+		if allowed {
+			patch := client.MergeFrom(pvc.DeepCopy())
+			if pvc.Spec.Resources.Requests == nil {
+				pvc.Spec.Resources.Requests = corev1.ResourceList{}
+			}
+			pvc.Spec.Resources.Requests[corev1.ResourceStorage] = desired
+			if err := r.Patch(ctx, pvc, patch); err != nil {
+				return fmt.Errorf("failed to expand PVC %s: %w", pvc.Name, err)
+			}
+			logger.Info("expanding the PVC", "pvc", pvc.Name, "from", requested.String(), "to", desired.String())
+			requested = desired
+		} else {
+			cond.Status = metav1.ConditionFalse
+			cond.Reason = "ExpansionNotAllowed"
+			cond.Message = fmt.Sprintf("the StorageClass of PVC %s does not allow volume expansion", pvc.Name)
+		}
+	}
+	capacity, bound := pvc.Status.Capacity[corev1.ResourceStorage]
+	if cond.Status == metav1.ConditionTrue && bound && capacity.Cmp(requested) < 0 {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "Resizing"
+		cond.Message = fmt.Sprintf("expanding PVC %s from %s to %s", pvc.Name, capacity.String(), requested.String())
+	}
+
+	orig := mc.DeepCopy()
+	if mc.Status.Storage == nil {
+		mc.Status.Storage = &mcingv1alpha1.StorageStatus{}
+	}
+	mc.Status.Storage.Requested = &requested
+	if bound {
+		mc.Status.Storage.Capacity = &capacity
+	}
+	meta.SetStatusCondition(&mc.Status.Conditions, cond)
+	if equality.Semantic.DeepEqual(orig.Status, mc.Status) {
+		return nil
+	}
+	// The usage is reported by the MinecraftManager, so only the changed fields are patched.
+	if err := r.Status().Patch(ctx, mc, client.MergeFrom(orig)); err != nil {
+		return fmt.Errorf("failed to update the storage status: %w", err)
+	}
+	return nil
+}
+
+// expansionAllowed returns whether the StorageClass of the PVC allows volume expansion.
+func (r *MinecraftReconciler) expansionAllowed(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	name := ptr.Deref(pvc.Spec.StorageClassName, "")
+	if name == "" {
+		return false, nil
+	}
+	sc := &storagev1.StorageClass{}
+	if err := r.Get(ctx, client.ObjectKey{Name: name}, sc); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return ptr.Deref(sc.AllowVolumeExpansion, false), nil
+}
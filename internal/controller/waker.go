@@ -0,0 +1,205 @@
+package controller
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
+	"github.com/kmdkuk/mcing/pkg/slp"
+)
+
+const (
+	defaultWakerPollInterval = 2 * time.Second
+	defaultWakerStartTimeout = 3 * time.Minute
+)
+
+// Waker accepts the connections that mc-router routes to hibernated servers.
+// It answers status pings with a placeholder, and when a player logs in, it scales the StatefulSet
+// of the server back to one, waits for the server to start and forwards the connection to it.
+type Waker struct {
+	client client.Client
+	log    logr.Logger
+	addr   string
+
+	pollInterval time.Duration
+	startTimeout time.Duration
+}
+
+var (
+	_ manager.Runnable               = &Waker{} //nolint:exhaustruct // interface check
+	_ manager.LeaderElectionRunnable = &Waker{} //nolint:exhaustruct // interface check
+)
+
+// NewWaker returns a new Waker that listens on addr.
+func NewWaker(client client.Client, log logr.Logger, addr string) *Waker {
+	return &Waker{
+		client:       client,
+		log:          log.WithName("Waker"),
+		addr:         addr,
+		pollInterval: defaultWakerPollInterval,
+		startTimeout: defaultWakerStartTimeout,
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+// Every replica of the controller accepts connections, because the Service of the waker selects all of them.
+func (w *Waker) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements manager.Runnable. It accepts connections until ctx is canceled.
+func (w *Waker) Start(ctx context.Context) error {
+	var lc net.ListenConfig
+	l, err := lc.Listen(ctx, "tcp", w.addr)
+	if err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { _ = l.Close() })
+	defer stop()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		wg.Go(func() { w.handle(ctx, conn) })
+	}
+}
+
+func (w *Waker) handle(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	r := bufio.NewReader(conn)
+	hs, raw, err := slp.ReadHandshake(r)
+	if err != nil {
+		w.log.V(1).Info("failed to read handshake", "error", err.Error())
+		return
+	}
+	host := serverAddress(hs.Address)
+	log := w.log.WithValues("hostname", host)
+
+	mc, err := w.lookup(ctx, host)
+	if err != nil {
+		log.Error(err, "failed to find the server")
+		return
+	}
+	if hs.NextState != slp.NextStateLogin {
+		status := slp.TextStatus(hs.ProtocolVersion, "The server is hibernating. Join to wake it up.")
+		if err := slp.ServeStatus(r, conn, status); err != nil {
+			log.V(1).Info("failed to serve status", "error", err.Error())
+		}
+		return
+	}
+
+	log.Info("waking up the hibernated server for a joining player", "minecraft", client.ObjectKeyFromObject(mc))
+	if err := w.scaleUp(ctx, mc); err != nil {
+		log.Error(err, "failed to scale up the server")
+		return
+	}
+	backend, err := w.waitForServer(ctx, mc)
+	if err != nil {
+		log.Error(err, "failed to connect to the server")
+		return
+	}
+	defer backend.Close()
+
+	if _, err := backend.Write(raw); err != nil {
+		return
+	}
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		_, _ = io.Copy(backend, r)
+		_ = backend.Close()
+	})
+	_, _ = io.Copy(conn, backend)
+	_ = conn.Close()
+	wg.Wait()
+}
+
+// lookup returns the Minecraft whose hostnames include host among the backends of the gateways.
+// Only a server with hibernation enabled is returned, so the waker never starts other servers.
+func (w *Waker) lookup(ctx context.Context, host string) (*mcingv1alpha1.Minecraft, error) {
+	gateways := &mcingv1alpha1.MinecraftGatewayList{}
+	if err := w.client.List(ctx, gateways); err != nil {
+		return nil, err
+	}
+	for _, gw := range gateways.Items {
+		for _, b := range gw.Status.Backends {
+			for _, h := range b.Hostnames() {
+				if strings.ToLower(h) != host {
+					continue
+				}
+				mc := &mcingv1alpha1.Minecraft{}
+				if err := w.client.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: b.Name}, mc); err != nil {
+					return nil, err
+				}
+				if !mc.Spec.Hibernation.Enabled {
+					return nil, fmt.Errorf("hibernation of %s/%s is disabled", mc.Namespace, mc.Name)
+				}
+				return mc, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no gateway routes %s", host)
+}
+
+// scaleUp scales the StatefulSet of a hibernated server back to one.
+func (w *Waker) scaleUp(ctx context.Context, mc *mcingv1alpha1.Minecraft) error {
+	sts := &appsv1.StatefulSet{}
+	if err := w.client.Get(ctx, client.ObjectKey{Namespace: mc.Namespace, Name: mc.PrefixedName()}, sts); err != nil {
+		return err
+	}
+	if sts.Spec.Replicas != nil && *sts.Spec.Replicas > 0 {
+		return nil
+	}
+	patch := client.MergeFrom(sts.DeepCopy())
+	sts.Spec.Replicas = ptr.To[int32](1)
+	return w.client.Patch(ctx, sts, patch)
+}
+
+// waitForServer waits until the server answers a status ping and connects to it.
+func (w *Waker) waitForServer(ctx context.Context, mc *mcingv1alpha1.Minecraft) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, w.startTimeout)
+	defer cancel()
+
+	addr := serverBackend(mc)
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := slp.Ping(ctx, addr); err == nil {
+			var d net.Dialer
+			return d.DialContext(ctx, "tcp", addr)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("the server did not start within %s: %w", w.startTimeout, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// serverAddress normalizes the server address in a handshake.
+// Forge clients append a null-separated suffix, and some clients send a fully qualified name.
+func serverAddress(addr string) string {
+	addr, _, _ = strings.Cut(addr, "\x00")
+	return strings.TrimSuffix(strings.ToLower(addr), ".")
+}
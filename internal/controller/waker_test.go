@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"context"
+	"io"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2" //nolint:revive // dot imports for tests
+	. "github.com/onsi/gomega"    //nolint:revive // dot imports for tests
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
+	"github.com/kmdkuk/mcing/pkg/constants"
+	"github.com/kmdkuk/mcing/pkg/slp"
+)
+
+var _ = Describe("Waker", func() {
+	const wakerNamespace = "test-waker"
+
+	ctx := context.Background()
+	var waker *Waker
+
+	// connect sends a handshake for host to the waker and returns the client side of the connection.
+	connect := func(host string, nextState int32) net.Conn {
+		clientConn, serverConn := net.Pipe()
+		go waker.handle(ctx, serverConn)
+		Expect(slp.WriteHandshake(clientConn, host+":25565", 765, nextState)).To(Succeed())
+		return clientConn
+	}
+
+	createServer := func(name string, hibernation bool) *appsv1.StatefulSet {
+		mc := makeMinecraft(name, wakerNamespace)
+		mc.Spec.Hibernation.Enabled = hibernation
+		Expect(k8sClient.Create(ctx, mc)).To(Succeed())
+		DeferCleanup(func() {
+			mc.Finalizers = nil
+			_ = k8sClient.Update(ctx, mc)
+			_ = k8sClient.Delete(ctx, mc)
+		})
+
+		labels := labelSet(mc, constants.AppComponentServer)
+		sts := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: wakerNamespace, Name: mc.PrefixedName(), Labels: labels},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: ptr.To[int32](0),
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec:       mc.Spec.PodTemplate.Spec,
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, sts)).To(Succeed())
+		DeferCleanup(func() {
+			_ = k8sClient.Delete(ctx, sts)
+		})
+		return sts
+	}
+
+	replicas := func(sts *appsv1.StatefulSet) int32 {
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(sts), sts)).To(Succeed())
+		return *sts.Spec.Replicas
+	}
+
+	It("should create the namespace", func() {
+		createNamespaces(ctx, wakerNamespace)
+	})
+
+	BeforeEach(func() {
+		waker = NewWaker(k8sClient, ctrl.Log.WithName("controllers"), "")
+		waker.pollInterval = 100 * time.Millisecond
+		waker.startTimeout = time.Second
+
+		gw := &mcingv1alpha1.MinecraftGateway{}
+		gw.Name = "waker"
+		gw.Spec.Namespace = "mcing-gateway"
+		Expect(k8sClient.Create(ctx, gw)).To(Succeed())
+		gw.Status.Backends = []mcingv1alpha1.GatewayBackend{
+			{Namespace: wakerNamespace, Name: "lazy", Hostname: "lazy.example.com", Aliases: []string{"Nap.example.com"}},
+			{Namespace: wakerNamespace, Name: "awake", Hostname: "awake.example.com"},
+		}
+		Expect(k8sClient.Status().Update(ctx, gw)).To(Succeed())
+		DeferCleanup(func() {
+			gw.Finalizers = nil
+			_ = k8sClient.Update(ctx, gw)
+			_ = k8sClient.Delete(ctx, gw)
+		})
+	})
+
+	It("should show a placeholder in the server list without waking the server", func() {
+		sts := createServer("lazy", true)
+
+		conn := connect("lazy.example.com", slp.NextStateStatus)
+		defer conn.Close()
+		_, err := conn.Write([]byte{0x01, 0x00}) // status request
+		Expect(err).NotTo(HaveOccurred())
+		buf := make([]byte, 1024)
+		n, err := conn.Read(buf)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(buf[:n])).To(ContainSubstring("The server is hibernating."))
+
+		Expect(replicas(sts)).To(Equal(int32(0)))
+	})
+
+	It("should scale up a hibernated server when a player joins", func() {
+		sts := createServer("lazy", true)
+
+		conn := connect("NAP.example.com.", slp.NextStateLogin)
+		defer conn.Close()
+		Eventually(func() int32 { return replicas(sts) }).Should(Equal(int32(1)))
+
+		By("closing the connection when the server does not start in time")
+		Eventually(func() error {
+			_, err := conn.Read(make([]byte, 1))
+			return err
+		}).Should(MatchError(io.EOF))
+	})
+
+	It("should not scale up a server without hibernation", func() {
+		sts := createServer("awake", false)
+
+		conn := connect("awake.example.com", slp.NextStateLogin)
+		defer conn.Close()
+		Consistently(func() int32 { return replicas(sts) }, 2*time.Second).Should(Equal(int32(0)))
+	})
+})
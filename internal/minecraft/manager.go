@@ -2,21 +2,41 @@ package minecraft
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
 	"github.com/kmdkuk/mcing/pkg/agent"
+	"github.com/kmdkuk/mcing/pkg/proto"
+)
+
+const (
+	shutdownMessage = "The server is shutting down."
+	shutdownTimeout = 30 * time.Second
 )
 
 // MinecraftManager manages the lifecycle of Minecraft server.
 type MinecraftManager interface { //nolint:revive // MinecraftManager is exported identifier
 	Update(types.NamespacedName) error
 	Stop(types.NamespacedName)
+	// Shutdown stops the process of the server, announces the shutdown to the players and
+	// saves the world to the disk, so that the data is consistent when the server is deleted.
+	// It only stops the process if the server is not running.
+	Shutdown(context.Context, *mcingv1alpha1.Minecraft) error
+	// SaveOff saves the world to the disk and disables auto-save, so that a snapshot of the data is consistent.
+	// It returns false if the server is not running.
+	SaveOff(context.Context, *mcingv1alpha1.Minecraft) (bool, error)
+	// SaveOn enables auto-save again after SaveOff.
+	SaveOn(context.Context, *mcingv1alpha1.Minecraft) error
 	Start(context.Context) error
 }
 
@@ -86,6 +106,91 @@ func (m *minecraftManager) Stop(name types.NamespacedName) {
 	}
 }
 
+func (m *minecraftManager) Shutdown(ctx context.Context, mc *mcingv1alpha1.Minecraft) error {
+	m.Stop(client.ObjectKeyFromObject(mc))
+
+	ctx, cancel := context.WithTimeout(ctx, shutdownTimeout)
+	defer cancel()
+	conn, err := m.connect(ctx, mc)
+	if errors.Is(err, errNotRunning) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if _, err := conn.Announce(ctx, &proto.AnnounceRequest{Message: shutdownMessage}); err != nil {
+		return fmt.Errorf("failed to announce the shutdown: %w", err)
+	}
+	return saveOff(ctx, conn)
+}
+
+func (m *minecraftManager) SaveOff(ctx context.Context, mc *mcingv1alpha1.Minecraft) (bool, error) {
+	conn, err := m.connect(ctx, mc)
+	if errors.Is(err, errNotRunning) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	if err := saveOff(ctx, conn); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (m *minecraftManager) SaveOn(ctx context.Context, mc *mcingv1alpha1.Minecraft) error {
+	conn, err := m.connect(ctx, mc)
+	if errors.Is(err, errNotRunning) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	if _, err := conn.SaveOn(ctx, &proto.SaveOnRequest{}); err != nil {
+		return fmt.Errorf("failed to enable auto-save: %w", err)
+	}
+	return nil
+}
+
+// errNotRunning is returned by connect if the server is not running.
+var errNotRunning = errors.New("the server is not running")
+
+// connect connects to the agent of the server.
+func (m *minecraftManager) connect(ctx context.Context, mc *mcingv1alpha1.Minecraft) (agent.Conn, error) {
+	pod := &corev1.Pod{}
+	err := m.k8sclient.Get(ctx, client.ObjectKey{Namespace: mc.Namespace, Name: mc.PodName()}, pod)
+	if apierrors.IsNotFound(err) {
+		return nil, errNotRunning
+	}
+	if err != nil {
+		return nil, err
+	}
+	if pod.Status.PodIP == "" || !pod.DeletionTimestamp.IsZero() {
+		return nil, errNotRunning
+	}
+	return m.af.New(ctx, pod.Status.PodIP)
+}
+
+func saveOff(ctx context.Context, conn agent.Conn) error {
+	if _, err := conn.SaveOff(ctx, &proto.SaveOffRequest{}); err != nil {
+		return fmt.Errorf("failed to disable auto-save: %w", err)
+	}
+	if _, err := conn.SaveAllFlush(ctx, &proto.SaveAllFlushRequest{}); err != nil {
+		return fmt.Errorf("failed to save the world: %w", err)
+	}
+	return nil
+}
+
 func (m *minecraftManager) stopAll() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -0,0 +1,124 @@
+package minecraft
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
+)
+
+func Test_minecraftManager_Shutdown(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	tests := []struct {
+		name          string
+		podIP         string
+		noPod         bool
+		wantAnnounced []string
+	}{
+		{
+			name:          "running server",
+			podIP:         "10.0.0.1",
+			wantAnnounced: []string{shutdownMessage},
+		},
+		{
+			name: "pod without IP",
+		},
+		{
+			name:  "no pod",
+			noPod: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := &mcingv1alpha1.Minecraft{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			if !tt.noPod {
+				builder = builder.WithObjects(&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: mc.PodName(), Namespace: mc.Namespace},
+					Status:     corev1.PodStatus{PodIP: tt.podIP},
+				})
+			}
+			conn := &mockAgentConn{} //nolint:exhaustruct // test
+			canceled := false
+			m := &minecraftManager{ //nolint:exhaustruct // internal struct
+				af:        mockAgentFactory{conn: conn},
+				k8sclient: builder.Build(),
+				log:       logr.Discard(),
+				processes: map[string]*managerProcess{
+					client.ObjectKeyFromObject(mc).String(): {cancel: func() { canceled = true }}, //nolint:exhaustruct // test
+				},
+			}
+
+			if err := m.Shutdown(context.Background(), mc); err != nil {
+				t.Fatalf("Shutdown() error = %v", err)
+			}
+			if !canceled || len(m.processes) != 0 {
+				t.Error("Shutdown() should stop the process of the server")
+			}
+			if !slices.Equal(conn.announced, tt.wantAnnounced) {
+				t.Errorf("announced = %v, want %v", conn.announced, tt.wantAnnounced)
+			}
+			if conn.flushed != (tt.podIP != "") {
+				t.Errorf("flushed = %v, want %v", conn.flushed, tt.podIP != "")
+			}
+		})
+	}
+}
+
+func Test_minecraftManager_SaveOffOn(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	tests := []struct {
+		name        string
+		podIP       string
+		wantRunning bool
+	}{
+		{
+			name:        "running server",
+			podIP:       "10.0.0.1",
+			wantRunning: true,
+		},
+		{
+			name: "pod without IP",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := &mcingv1alpha1.Minecraft{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+			conn := &mockAgentConn{} //nolint:exhaustruct // test
+			m := &minecraftManager{  //nolint:exhaustruct // internal struct
+				af: mockAgentFactory{conn: conn},
+				k8sclient: fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: mc.PodName(), Namespace: mc.Namespace},
+					Status:     corev1.PodStatus{PodIP: tt.podIP},
+				}).Build(),
+				log: logr.Discard(),
+			}
+
+			running, err := m.SaveOff(context.Background(), mc)
+			if err != nil {
+				t.Fatalf("SaveOff() error = %v", err)
+			}
+			if running != tt.wantRunning || conn.flushed != tt.wantRunning {
+				t.Errorf("SaveOff() = %v, flushed = %v, want %v", running, conn.flushed, tt.wantRunning)
+			}
+			if err := m.SaveOn(context.Background(), mc); err != nil {
+				t.Fatalf("SaveOn() error = %v", err)
+			}
+			if conn.savedOn != tt.wantRunning {
+				t.Errorf("savedOn = %v, want %v", conn.savedOn, tt.wantRunning)
+			}
+		})
+	}
+}
@@ -13,6 +13,13 @@ type mockAgentConn struct {
 	reloadFunc        func(ctx context.Context, in *proto.ReloadRequest, opts ...grpc.CallOption) (*proto.ReloadResponse, error)
 	syncWhitelistFunc func(ctx context.Context, in *proto.SyncWhitelistRequest, opts ...grpc.CallOption) (*proto.SyncWhitelistResponse, error)
 	syncOpsFunc       func(ctx context.Context, in *proto.SyncOpsRequest, opts ...grpc.CallOption) (*proto.SyncOpsResponse, error)
+	wakeFunc          func(ctx context.Context, in *proto.WakeRequest, opts ...grpc.CallOption) (*proto.WakeResponse, error)
+	pendingRestart    []string
+	syncedFiles       []*proto.FileSyncResult
+	announced         []string
+	flushed           bool
+	savedOn           bool
+	usedBytes         uint64
 }
 
 func (m *mockAgentConn) Reload(
@@ -61,6 +68,7 @@ func (m *mockAgentConn) SaveAllFlush(
 	_ *proto.SaveAllFlushRequest,
 	_ ...grpc.CallOption,
 ) (*proto.SaveAllFlushResponse, error) {
+	m.flushed = true
 	return &proto.SaveAllFlushResponse{}, nil
 }
 
@@ -69,11 +77,74 @@ func (m *mockAgentConn) SaveOn(
 	_ *proto.SaveOnRequest,
 	_ ...grpc.CallOption,
 ) (*proto.SaveOnResponse, error) {
+	m.savedOn = true
 	return &proto.SaveOnResponse{}, nil
 }
 
+func (m *mockAgentConn) Wake(
+	ctx context.Context,
+	in *proto.WakeRequest,
+	opts ...grpc.CallOption,
+) (*proto.WakeResponse, error) {
+	if m.wakeFunc != nil {
+		return m.wakeFunc(ctx, in, opts...)
+	}
+	return &proto.WakeResponse{}, nil
+}
+
+func (m *mockAgentConn) Sleep(
+	_ context.Context,
+	_ *proto.SleepRequest,
+	_ ...grpc.CallOption,
+) (*proto.SleepResponse, error) {
+	return &proto.SleepResponse{}, nil
+}
+
+func (m *mockAgentConn) PendingRestart(
+	_ context.Context,
+	_ *proto.PendingRestartRequest,
+	_ ...grpc.CallOption,
+) (*proto.PendingRestartResponse, error) {
+	return &proto.PendingRestartResponse{Properties: m.pendingRestart}, nil
+}
+
+func (m *mockAgentConn) SyncStatus(
+	_ context.Context,
+	_ *proto.SyncStatusRequest,
+	_ ...grpc.CallOption,
+) (*proto.SyncStatusResponse, error) {
+	return &proto.SyncStatusResponse{Files: m.syncedFiles}, nil
+}
+
+func (m *mockAgentConn) Announce(
+	_ context.Context,
+	in *proto.AnnounceRequest,
+	_ ...grpc.CallOption,
+) (*proto.AnnounceResponse, error) {
+	m.announced = append(m.announced, in.GetMessage())
+	return &proto.AnnounceResponse{}, nil
+}
+
+func (m *mockAgentConn) DiskUsage(
+	_ context.Context,
+	_ *proto.DiskUsageRequest,
+	_ ...grpc.CallOption,
+) (*proto.DiskUsageResponse, error) {
+	return &proto.DiskUsageResponse{UsedBytes: m.usedBytes, TotalBytes: 2 * m.usedBytes}, nil
+}
+
 func (m *mockAgentConn) Close() error {
 	return nil
 }
 
 var _ agent.Conn = &mockAgentConn{} //nolint:exhaustruct // interface check
+
+type mockAgentFactory struct {
+	conn *mockAgentConn
+}
+
+func (f mockAgentFactory) New(context.Context, string) (agent.Conn, error) {
+	return f.conn, nil
+}
+
+var _ agent.Factory = mockAgentFactory{} //nolint:exhaustruct // interface check
@@ -2,26 +2,42 @@ package minecraft
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"net"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
 	"github.com/kmdkuk/mcing/pkg/agent"
+	"github.com/kmdkuk/mcing/pkg/constants"
 	"github.com/kmdkuk/mcing/pkg/proto"
+	"github.com/kmdkuk/mcing/pkg/slp"
 )
 
+const mebibyte = 1024 * 1024
+
 type managerProcess struct {
 	agentf    agent.Factory
 	k8sclient client.Client
 	name      types.NamespacedName
 	log       logr.Logger
 	cancel    func()
+
+	// statusf queries the server status. It is replaced in tests.
+	statusf func(ctx context.Context, addr string) (*slp.Status, error)
 }
 
 func newManagerProcess(
@@ -37,6 +53,7 @@ func newManagerProcess(
 		name:      name,
 		log:       log,
 		cancel:    cancel,
+		statusf:   slp.Ping,
 	}
 }
 
@@ -72,15 +89,124 @@ func (p *managerProcess) do(ctx context.Context) error {
 	}
 	p.log.Info("get Minecraft", ".spec.whitelist", mc.Spec.Whitelist, ".spec.ops", mc.Spec.Ops)
 
-	agent, err := p.newAgent(ctx, mc)
+	sts := &appsv1.StatefulSet{}
+	if err := p.k8sclient.Get(ctx, client.ObjectKey{Namespace: mc.Namespace, Name: mc.PrefixedName()}, sts); err != nil {
+		return fmt.Errorf("failed to get StatefulSet: %w", err)
+	}
+	if sts.Spec.Replicas != nil && *sts.Spec.Replicas == 0 {
+		p.log.Info("the server is hibernating")
+		return nil
+	}
+
+	podIP, err := p.podIP(ctx, mc)
 	if err != nil {
 		return err
 	}
+	agent, err := p.agentf.New(ctx, podIP)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = agent.Close()
+	}()
 
-	return p.sync(ctx, mc, agent)
+	if err := p.sync(ctx, mc, sts, agent); err != nil {
+		return err
+	}
+	if err := p.reportRestartRequired(ctx, mc, agent); err != nil {
+		return err
+	}
+	if err := p.reportConfigSynced(ctx, mc, agent); err != nil {
+		return err
+	}
+	if err := p.reportStorageUsage(ctx, mc, agent); err != nil {
+		return err
+	}
+	if mc.Spec.Hibernation.Enabled {
+		return p.hibernateIfIdle(ctx, mc, sts, podIP)
+	}
+	return nil
 }
 
-func (p *managerProcess) sync(ctx context.Context, mc *mcingv1alpha1.Minecraft, agent agent.Conn) error {
+// hibernateIfIdle scales the StatefulSet to zero when no players have been online for the idle period.
+// The start of the idle period is recorded in the StatefulSet so that it survives restarts of the controller.
+func (p *managerProcess) hibernateIfIdle(
+	ctx context.Context,
+	mc *mcingv1alpha1.Minecraft,
+	sts *appsv1.StatefulSet,
+	podIP string,
+) error {
+	now := time.Now()
+	// A server paused by auto-pause still answers status pings with no players online.
+	st, err := p.statusf(ctx, net.JoinHostPort(podIP, strconv.Itoa(int(constants.ServerPort))))
+	if err != nil || st.Players.Online > 0 {
+		// The server is starting up or players are online.
+		return p.patchIdleSince(ctx, sts, "")
+	}
+	idleSince, err := time.Parse(time.RFC3339, sts.Annotations[constants.IdleSinceAnnotation])
+	if err != nil {
+		// The server has just become idle.
+		return p.patchIdleSince(ctx, sts, now.UTC().Format(time.RFC3339))
+	}
+	idle := now.Sub(idleSince)
+	if idle < time.Duration(mc.Spec.Hibernation.IdleSeconds)*time.Second {
+		return nil
+	}
+
+	p.log.Info("scaling the server to zero", "idle", idle.String())
+	patch := client.MergeFrom(sts.DeepCopy())
+	sts.Spec.Replicas = ptr.To[int32](0)
+	delete(sts.Annotations, constants.IdleSinceAnnotation)
+	if err := p.k8sclient.Patch(ctx, sts, patch); err != nil {
+		return fmt.Errorf("failed to scale StatefulSet to zero: %w", err)
+	}
+	return nil
+}
+
+// patchIdleSince sets the annotation of the idle start time on the StatefulSet, or removes it when value is empty.
+func (p *managerProcess) patchIdleSince(ctx context.Context, sts *appsv1.StatefulSet, value string) error {
+	if sts.Annotations[constants.IdleSinceAnnotation] == value {
+		return nil
+	}
+	patch := client.MergeFrom(sts.DeepCopy())
+	if value == "" {
+		delete(sts.Annotations, constants.IdleSinceAnnotation)
+	} else {
+		if sts.Annotations == nil {
+			sts.Annotations = map[string]string{}
+		}
+		sts.Annotations[constants.IdleSinceAnnotation] = value
+	}
+	if err := p.k8sclient.Patch(ctx, sts, patch); err != nil {
+		return fmt.Errorf("failed to record the idle time of StatefulSet: %w", err)
+	}
+	return nil
+}
+
+// sync applies the whitelist and ops to the server.
+// With auto-pause, the digest of the applied state is recorded in the StatefulSet,
+// so that a sleeping server is woken up only when the state changes, even after the controller restarts.
+func (p *managerProcess) sync(
+	ctx context.Context,
+	mc *mcingv1alpha1.Minecraft,
+	sts *appsv1.StatefulSet,
+	agent agent.Conn,
+) error {
+	desired := desiredState(mc)
+	if mc.AutoPauseEnabled() {
+		if desired == sts.Annotations[constants.SyncedStateAnnotation] {
+			return nil
+		}
+		// whitelist and ops are applied through RCON, which is only available while the server is running.
+		res, err := agent.Wake(ctx, &proto.WakeRequest{})
+		if err != nil {
+			return fmt.Errorf("failed to wake the server: %w", err)
+		}
+		if res.GetWasSleeping() {
+			p.log.Info("woke the server up to apply changes")
+		}
+	}
+
 	err := p.syncWhitelist(ctx, mc, agent)
 	if err != nil {
 		return err
@@ -89,6 +215,126 @@ func (p *managerProcess) sync(ctx context.Context, mc *mcingv1alpha1.Minecraft,
 	if err != nil {
 		return err
 	}
+	if !mc.AutoPauseEnabled() {
+		return nil
+	}
+
+	patch := client.MergeFrom(sts.DeepCopy())
+	if sts.Annotations == nil {
+		sts.Annotations = map[string]string{}
+	}
+	sts.Annotations[constants.SyncedStateAnnotation] = desired
+	if err := p.k8sclient.Patch(ctx, sts, patch); err != nil {
+		return fmt.Errorf("failed to record the synced state in StatefulSet: %w", err)
+	}
+	return nil
+}
+
+// desiredState returns the digest of the whitelist and ops of mc.
+func desiredState(mc *mcingv1alpha1.Minecraft) string {
+	state := fmt.Sprintf("whitelist=%t:%v ops=%v", mc.Spec.Whitelist.Enabled, mc.Spec.Whitelist.Users, mc.Spec.Ops.Users)
+	sum := sha256.Sum256([]byte(state))
+	return hex.EncodeToString(sum[:])
+}
+
+// reportRestartRequired sets the RestartRequired condition from the properties waiting for a restart,
+// and restarts the pod when the policy is Rollout.
+func (p *managerProcess) reportRestartRequired(ctx context.Context, mc *mcingv1alpha1.Minecraft, agent agent.Conn) error {
+	res, err := agent.PendingRestart(ctx, &proto.PendingRestartRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to get the properties waiting for a restart: %w", err)
+	}
+	props := res.GetProperties()
+
+	cond := metav1.Condition{
+		Type:               mcingv1alpha1.ConditionRestartRequired,
+		Status:             metav1.ConditionFalse,
+		Reason:             "UpToDate",
+		Message:            "the server runs with the current server.properties",
+		ObservedGeneration: mc.Generation,
+	}
+	if len(props) != 0 {
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "PropertiesChanged"
+		cond.Message = "restart the server to apply " + strings.Join(props, ", ")
+	}
+	orig := mc.DeepCopy()
+	if meta.SetStatusCondition(&mc.Status.Conditions, cond) {
+		patch := client.MergeFromWithOptions(orig, client.MergeFromWithOptimisticLock{})
+		if err := p.k8sclient.Status().Patch(ctx, mc, patch); err != nil {
+			return fmt.Errorf("failed to update the RestartRequired condition: %w", err)
+		}
+	}
+
+	if len(props) == 0 || mc.Spec.PropertiesRestartPolicy != mcingv1alpha1.PropertiesRestartPolicyRollout {
+		return nil
+	}
+	p.log.Info("restarting the server to apply server.properties", "properties", props)
+	pod := &corev1.Pod{}
+	pod.Namespace = mc.Namespace
+	pod.Name = mc.PodName()
+	if err := p.k8sclient.Delete(ctx, pod); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to restart the pod: %w", err)
+	}
+	return nil
+}
+
+// reportConfigSynced sets the ConfigSynced condition from the results of the config watcher in mcing-agent.
+func (p *managerProcess) reportConfigSynced(ctx context.Context, mc *mcingv1alpha1.Minecraft, agent agent.Conn) error {
+	res, err := agent.SyncStatus(ctx, &proto.SyncStatusRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to get the sync status: %w", err)
+	}
+
+	var failed []string
+	for _, f := range res.GetFiles() {
+		if f.GetError() != "" {
+			failed = append(failed, fmt.Sprintf("%s: %s", f.GetName(), f.GetError()))
+		}
+	}
+	cond := metav1.Condition{
+		Type:               mcingv1alpha1.ConditionConfigSynced,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Synced",
+		Message:            "all config files are applied to the server",
+		ObservedGeneration: mc.Generation,
+	}
+	if len(failed) != 0 {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "SyncFailed"
+		cond.Message = "failed to apply " + strings.Join(failed, "; ")
+	}
+	orig := mc.DeepCopy()
+	if meta.SetStatusCondition(&mc.Status.Conditions, cond) {
+		patch := client.MergeFromWithOptions(orig, client.MergeFromWithOptimisticLock{})
+		if err := p.k8sclient.Status().Patch(ctx, mc, patch); err != nil {
+			return fmt.Errorf("failed to update the ConfigSynced condition: %w", err)
+		}
+	}
+	return nil
+}
+
+// reportStorageUsage reports the space used in the data volume, rounded down to MiB
+// so that the status is not updated every time a chunk is saved.
+func (p *managerProcess) reportStorageUsage(ctx context.Context, mc *mcingv1alpha1.Minecraft, agent agent.Conn) error {
+	res, err := agent.DiskUsage(ctx, &proto.DiskUsageRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to get the disk usage: %w", err)
+	}
+	usedBytes := int64(res.GetUsedBytes() / mebibyte * mebibyte) //nolint:gosec // a disk size fits in int64
+	used := resource.NewQuantity(usedBytes, resource.BinarySI)
+	if mc.Status.Storage != nil && mc.Status.Storage.Used != nil && mc.Status.Storage.Used.Cmp(*used) == 0 {
+		return nil
+	}
+
+	orig := mc.DeepCopy()
+	if mc.Status.Storage == nil {
+		mc.Status.Storage = &mcingv1alpha1.StorageStatus{} //nolint:exhaustruct // the others are set by the controller
+	}
+	mc.Status.Storage.Used = used
+	if err := p.k8sclient.Status().Patch(ctx, mc, client.MergeFrom(orig)); err != nil {
+		return fmt.Errorf("failed to update the storage usage: %w", err)
+	}
 	return nil
 }
 
@@ -120,14 +366,14 @@ func (p *managerProcess) Cancel() {
 	p.cancel()
 }
 
-func (p *managerProcess) newAgent(ctx context.Context, mc *mcingv1alpha1.Minecraft) (agent.Conn, error) {
+func (p *managerProcess) podIP(ctx context.Context, mc *mcingv1alpha1.Minecraft) (string, error) {
 	pod := &corev1.Pod{}
 	err := p.k8sclient.Get(ctx, client.ObjectKey{Namespace: mc.Namespace, Name: mc.PodName()}, pod)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 	if pod.Status.PodIP == "" {
-		return nil, fmt.Errorf("pod %s/%s has no IP", pod.Namespace, pod.Name)
+		return "", fmt.Errorf("pod %s/%s has no IP", pod.Namespace, pod.Name)
 	}
-	return p.agentf.New(ctx, pod.Status.PodIP)
+	return pod.Status.PodIP, nil
 }
@@ -4,15 +4,30 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
 	"google.golang.org/grpc"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	mcingv1alpha1 "github.com/kmdkuk/mcing/api/v1alpha1"
+	"github.com/kmdkuk/mcing/pkg/constants"
 	"github.com/kmdkuk/mcing/pkg/proto"
+	"github.com/kmdkuk/mcing/pkg/slp"
 )
 
 func Test_managerProcess_sync(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = appsv1.AddToScheme(scheme)
+
 	type args struct {
 		mc *mcingv1alpha1.Minecraft
 	}
@@ -78,16 +93,368 @@ func Test_managerProcess_sync(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			sts := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "mcing-test", Namespace: "default"},
+			}
 			p := &managerProcess{ //nolint:exhaustruct // internal struct
-				log: logr.Discard(),
+				k8sclient: fake.NewClientBuilder().WithScheme(scheme).WithObjects(sts).Build(),
+				log:       logr.Discard(),
 			}
 			agent := &mockAgentConn{ //nolint:exhaustruct // internal struct
 				syncWhitelistFunc: tt.syncWhitelistFunc,
 				syncOpsFunc:       tt.syncOpsFunc,
 			}
-			if err := p.sync(context.Background(), tt.args.mc, agent); (err != nil) != tt.wantErr {
+			if err := p.sync(context.Background(), tt.args.mc, sts, agent); (err != nil) != tt.wantErr {
 				t.Errorf("managerProcess.sync() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func Test_managerProcess_syncAutoPause(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = appsv1.AddToScheme(scheme)
+
+	enabled := true
+	disabled := false
+	newMinecraft := func(autoPause *bool, ops ...string) *mcingv1alpha1.Minecraft {
+		return &mcingv1alpha1.Minecraft{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: mcingv1alpha1.MinecraftSpec{
+				AutoPause: mcingv1alpha1.AutoPause{Enabled: autoPause},
+				Ops:       mcingv1alpha1.Ops{Users: ops},
+			},
+		}
+	}
+	tests := []struct {
+		name      string
+		steps     []*mcingv1alpha1.Minecraft
+		synced    *mcingv1alpha1.Minecraft
+		wakeErr   error
+		wantWakes int
+		wantSyncs int
+		wantErr   bool
+	}{
+		{
+			name:      "wake only when the desired state drifts",
+			steps:     []*mcingv1alpha1.Minecraft{newMinecraft(&enabled, "op1"), newMinecraft(&enabled, "op1"), newMinecraft(&enabled, "op2")},
+			wantWakes: 2,
+			wantSyncs: 2,
+		},
+		{
+			name:      "do not wake when the state was synced by another process",
+			steps:     []*mcingv1alpha1.Minecraft{newMinecraft(&enabled, "op1")},
+			synced:    newMinecraft(&enabled, "op1"),
+			wantWakes: 0,
+			wantSyncs: 0,
+		},
+		{
+			name:      "always sync without auto pause",
+			steps:     []*mcingv1alpha1.Minecraft{newMinecraft(&disabled, "op1"), newMinecraft(&disabled, "op1")},
+			wantWakes: 0,
+			wantSyncs: 2,
+		},
+		{
+			name:      "wake error",
+			steps:     []*mcingv1alpha1.Minecraft{newMinecraft(&enabled, "op1")},
+			wakeErr:   errors.New("wake error"),
+			wantWakes: 1,
+			wantSyncs: 0,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sts := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "mcing-test", Namespace: "default"},
+			}
+			if tt.synced != nil {
+				sts.Annotations = map[string]string{constants.SyncedStateAnnotation: desiredState(tt.synced)}
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sts).Build()
+			wakes, syncs := 0, 0
+			agent := &mockAgentConn{ //nolint:exhaustruct // internal struct
+				wakeFunc: func(_ context.Context, _ *proto.WakeRequest, _ ...grpc.CallOption) (*proto.WakeResponse, error) {
+					wakes++
+					if tt.wakeErr != nil {
+						return nil, tt.wakeErr
+					}
+					return &proto.WakeResponse{WasSleeping: true}, nil
+				},
+				syncOpsFunc: func(_ context.Context, _ *proto.SyncOpsRequest, _ ...grpc.CallOption) (*proto.SyncOpsResponse, error) {
+					syncs++
+					return &proto.SyncOpsResponse{}, nil
+				},
+			}
+			var err error
+			for _, mc := range tt.steps {
+				// Each step is run by a new process, such as after a restart of the controller.
+				p := &managerProcess{ //nolint:exhaustruct // internal struct
+					k8sclient: c,
+					log:       logr.Discard(),
+				}
+				if err = c.Get(context.Background(), client.ObjectKeyFromObject(sts), sts); err != nil {
+					t.Fatal(err)
+				}
+				if err = p.sync(context.Background(), mc, sts, agent); err != nil {
+					break
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("managerProcess.sync() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if wakes != tt.wantWakes {
+				t.Errorf("Wake called %d times, want %d", wakes, tt.wantWakes)
+			}
+			if syncs != tt.wantSyncs {
+				t.Errorf("SyncOps called %d times, want %d", syncs, tt.wantSyncs)
+			}
+		})
+	}
+}
+
+func Test_managerProcess_hibernateIfIdle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = appsv1.AddToScheme(scheme)
+
+	tests := []struct {
+		name          string
+		idleFor       time.Duration
+		online        int32
+		statusErr     error
+		wantReplicas  int32
+		wantIdleSince bool
+	}{
+		{
+			name:         "idle long enough",
+			idleFor:      2 * time.Hour,
+			wantReplicas: 0,
+		},
+		{
+			name:          "idle but not long enough",
+			idleFor:       time.Minute,
+			wantReplicas:  1,
+			wantIdleSince: true,
+		},
+		{
+			name:          "just became idle",
+			wantReplicas:  1,
+			wantIdleSince: true,
+		},
+		{
+			name:         "players online",
+			idleFor:      2 * time.Hour,
+			online:       1,
+			wantReplicas: 1,
+		},
+		{
+			name:         "server starting",
+			idleFor:      2 * time.Hour,
+			statusErr:    errors.New("connection refused"),
+			wantReplicas: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := &mcingv1alpha1.Minecraft{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec: mcingv1alpha1.MinecraftSpec{
+					Hibernation: mcingv1alpha1.Hibernation{Enabled: true, IdleSeconds: 3600},
+				},
+			}
+			sts := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: mc.PrefixedName(), Namespace: mc.Namespace},
+				Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To[int32](1)},
+			}
+			if tt.idleFor > 0 {
+				sts.Annotations = map[string]string{
+					constants.IdleSinceAnnotation: time.Now().Add(-tt.idleFor).UTC().Format(time.RFC3339),
+				}
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sts).Build()
+			p := &managerProcess{ //nolint:exhaustruct // internal struct
+				k8sclient: c,
+				log:       logr.Discard(),
+				statusf: func(_ context.Context, _ string) (*slp.Status, error) {
+					if tt.statusErr != nil {
+						return nil, tt.statusErr
+					}
+					st := &slp.Status{} //nolint:exhaustruct // test
+					st.Players.Online = tt.online
+					return st, nil
+				},
+			}
+
+			if err := p.hibernateIfIdle(context.Background(), mc, sts, "10.0.0.1"); err != nil {
+				t.Fatalf("hibernateIfIdle() error = %v", err)
+			}
+			got := &appsv1.StatefulSet{}
+			if err := c.Get(context.Background(), client.ObjectKeyFromObject(sts), got); err != nil {
+				t.Fatal(err)
+			}
+			if *got.Spec.Replicas != tt.wantReplicas {
+				t.Errorf("replicas = %d, want %d", *got.Spec.Replicas, tt.wantReplicas)
+			}
+			if _, ok := got.Annotations[constants.IdleSinceAnnotation]; ok != tt.wantIdleSince {
+				t.Errorf("idle-since annotation exists = %t, want %t", ok, tt.wantIdleSince)
+			}
+		})
+	}
+}
+
+func Test_managerProcess_reportRestartRequired(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = mcingv1alpha1.AddToScheme(scheme)
+
+	tests := []struct {
+		name           string
+		policy         mcingv1alpha1.PropertiesRestartPolicy
+		pendingRestart []string
+		wantStatus     metav1.ConditionStatus
+		wantPod        bool
+	}{
+		{
+			name:       "up to date",
+			policy:     mcingv1alpha1.PropertiesRestartPolicyRollout,
+			wantStatus: metav1.ConditionFalse,
+			wantPod:    true,
+		},
+		{
+			name:           "manual",
+			policy:         mcingv1alpha1.PropertiesRestartPolicyManual,
+			pendingRestart: []string{"max-players"},
+			wantStatus:     metav1.ConditionTrue,
+			wantPod:        true,
+		},
+		{
+			name:           "rollout",
+			policy:         mcingv1alpha1.PropertiesRestartPolicyRollout,
+			pendingRestart: []string{"max-players"},
+			wantStatus:     metav1.ConditionTrue,
+			wantPod:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := &mcingv1alpha1.Minecraft{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec:       mcingv1alpha1.MinecraftSpec{PropertiesRestartPolicy: tt.policy},
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: mc.PodName(), Namespace: mc.Namespace},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mc, pod).WithStatusSubresource(mc).Build()
+			p := &managerProcess{ //nolint:exhaustruct // internal struct
+				k8sclient: c,
+				log:       logr.Discard(),
+			}
+
+			agent := &mockAgentConn{pendingRestart: tt.pendingRestart} //nolint:exhaustruct // test
+			if err := p.reportRestartRequired(context.Background(), mc, agent); err != nil {
+				t.Fatalf("reportRestartRequired() error = %v", err)
+			}
+
+			got := &mcingv1alpha1.Minecraft{}
+			if err := c.Get(context.Background(), client.ObjectKeyFromObject(mc), got); err != nil {
+				t.Fatal(err)
+			}
+			cond := meta.FindStatusCondition(got.Status.Conditions, mcingv1alpha1.ConditionRestartRequired)
+			if cond == nil || cond.Status != tt.wantStatus {
+				t.Errorf("RestartRequired condition = %v, want %s", cond, tt.wantStatus)
+			}
+			err := c.Get(context.Background(), client.ObjectKeyFromObject(pod), &corev1.Pod{})
+			if gotPod := err == nil; gotPod != tt.wantPod {
+				t.Errorf("pod exists = %v, want %v (error: %v)", gotPod, tt.wantPod, err)
+			}
+		})
+	}
+}
+
+func Test_managerProcess_reportConfigSynced(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = mcingv1alpha1.AddToScheme(scheme)
+
+	tests := []struct {
+		name        string
+		files       []*proto.FileSyncResult
+		wantStatus  metav1.ConditionStatus
+		wantMessage string
+	}{
+		{
+			name:        "synced",
+			files:       []*proto.FileSyncResult{{Name: "ops.json", SyncedAt: 1}},
+			wantStatus:  metav1.ConditionTrue,
+			wantMessage: "all config files are applied to the server",
+		},
+		{
+			name: "failed",
+			files: []*proto.FileSyncResult{
+				{Name: "ops.json", Error: "rcon is not available"},
+				{Name: "server.properties", SyncedAt: 1},
+			},
+			wantStatus:  metav1.ConditionFalse,
+			wantMessage: "failed to apply ops.json: rcon is not available",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := &mcingv1alpha1.Minecraft{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mc).WithStatusSubresource(mc).Build()
+			p := &managerProcess{ //nolint:exhaustruct // internal struct
+				k8sclient: c,
+				log:       logr.Discard(),
+			}
+
+			agent := &mockAgentConn{syncedFiles: tt.files} //nolint:exhaustruct // test
+			if err := p.reportConfigSynced(context.Background(), mc, agent); err != nil {
+				t.Fatalf("reportConfigSynced() error = %v", err)
+			}
+
+			got := &mcingv1alpha1.Minecraft{}
+			if err := c.Get(context.Background(), client.ObjectKeyFromObject(mc), got); err != nil {
+				t.Fatal(err)
+			}
+			cond := meta.FindStatusCondition(got.Status.Conditions, mcingv1alpha1.ConditionConfigSynced)
+			if cond == nil || cond.Status != tt.wantStatus || cond.Message != tt.wantMessage {
+				t.Errorf("ConfigSynced condition = %v, want %s %q", cond, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func Test_managerProcess_reportStorageUsage(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = mcingv1alpha1.AddToScheme(scheme)
+
+	capacity := resource.MustParse("10Gi")
+	mc := &mcingv1alpha1.Minecraft{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Status: mcingv1alpha1.MinecraftStatus{
+			Storage: &mcingv1alpha1.StorageStatus{Capacity: &capacity},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mc).WithStatusSubresource(mc).Build()
+	p := &managerProcess{ //nolint:exhaustruct // internal struct
+		k8sclient: c,
+		log:       logr.Discard(),
+	}
+
+	agent := &mockAgentConn{usedBytes: 3*1024*1024*1024 + 12345} //nolint:exhaustruct // test
+	if err := p.reportStorageUsage(context.Background(), mc, agent); err != nil {
+		t.Fatalf("reportStorageUsage() error = %v", err)
+	}
+
+	got := &mcingv1alpha1.Minecraft{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(mc), got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.Storage == nil || got.Status.Storage.Used == nil || got.Status.Storage.Used.String() != "3Gi" {
+		t.Errorf("status.storage.used = %v, want 3Gi", got.Status.Storage)
+	}
+	if got.Status.Storage.Capacity == nil || !got.Status.Storage.Capacity.Equal(capacity) {
+		t.Errorf("status.storage.capacity = %v, want %s", got.Status.Storage.Capacity, capacity.String())
+	}
+}
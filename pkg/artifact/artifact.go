@@ -0,0 +1,457 @@
+// Package artifact installs mods, plugins and datapacks on the data volume.
+package artifact
+
+import (
+	"context"
+	"crypto/sha1" //nolint:gosec // Minecraft verifies resource packs with SHA-1.
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Kind is the directory in the data volume that an artifact is installed into.
+type Kind string
+
+const (
+	// KindMod is the directory for mods.
+	KindMod Kind = "mods"
+	// KindPlugin is the directory for plugins.
+	KindPlugin Kind = "plugins"
+	// KindDatapack is the directory for datapacks in the level directory.
+	KindDatapack Kind = "datapacks"
+)
+
+// defaultLevelName is the default of the level-name property.
+const defaultLevelName = "world"
+
+const (
+	stateDirName  = ".mcing"
+	cacheDirName  = "cache"
+	stateFileName = "artifacts.json"
+)
+
+// Artifact is a file to install. Exactly one of URL, Path and OCI is set.
+type Artifact struct {
+	// Name is the file name in the mods or plugins directory.
+	Name string `json:"name"`
+	// URL is downloaded with HTTP GET.
+	URL string `json:"url,omitempty"`
+	// Path is a local file such as a key of a mounted ConfigMap.
+	Path string `json:"path,omitempty"`
+	// OCI is a reference to an OCI artifact that contains the file.
+	OCI string `json:"oci,omitempty"`
+	// SHA256 is the expected hex-encoded SHA-256 digest of the file.
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// Manifest is the set of artifacts to install.
+type Manifest struct {
+	Mods      []Artifact `json:"mods,omitempty"`
+	Plugins   []Artifact `json:"plugins,omitempty"`
+	Datapacks []Artifact `json:"datapacks,omitempty"`
+
+	// LevelName is the level directory that holds the datapacks directory. It defaults to world.
+	LevelName string `json:"levelName,omitempty"`
+
+	// ResourcePack is the URL of a resource pack whose SHA-1 digest is to be computed.
+	ResourcePack string `json:"resourcePack,omitempty"`
+}
+
+// Installed is an installed file.
+type Installed struct {
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// ResourcePack is a resource pack and its SHA-1 digest.
+type ResourcePack struct {
+	URL  string `json:"url"`
+	SHA1 string `json:"sha1"`
+}
+
+// Report is the set of installed files.
+type Report struct {
+	Mods      []Installed `json:"mods,omitempty"`
+	Plugins   []Installed `json:"plugins,omitempty"`
+	Datapacks []Installed `json:"datapacks,omitempty"`
+
+	ResourcePack *ResourcePack `json:"resourcePack,omitempty"`
+}
+
+// maxReportSize is the size limit of a termination message of Kubernetes.
+const maxReportSize = 4096
+
+// EncodeReport encodes r into JSON that fits in a termination message.
+// The digests are dropped when there are too many files to fit.
+func EncodeReport(r *Report) ([]byte, error) {
+	data, err := json.Marshal(r)
+	if err != nil || len(data) <= maxReportSize {
+		return data, err
+	}
+	short := &Report{ResourcePack: r.ResourcePack}
+	for _, i := range r.Mods {
+		short.Mods = append(short.Mods, Installed{Name: i.Name})
+	}
+	for _, i := range r.Plugins {
+		short.Plugins = append(short.Plugins, Installed{Name: i.Name})
+	}
+	for _, i := range r.Datapacks {
+		short.Datapacks = append(short.Datapacks, Installed{Name: i.Name})
+	}
+	return json.Marshal(short)
+}
+
+// ReadManifest reads a Manifest from a JSON file. A missing file is an empty Manifest.
+func ReadManifest(path string) (*Manifest, error) {
+	m := &Manifest{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// Installer downloads artifacts into a cache on the data volume and installs them.
+// Files installed by a previous run that are no longer desired are removed.
+type Installer struct {
+	dataPath string
+	client   *http.Client
+	registry *registryClient
+}
+
+// NewInstaller returns a new Installer for the data volume at dataPath.
+func NewInstaller(dataPath string, client *http.Client) *Installer {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Installer{
+		dataPath: dataPath,
+		client:   client,
+		registry: newRegistryClient(client),
+	}
+}
+
+func (i *Installer) stateDir() string {
+	return filepath.Join(i.dataPath, stateDirName)
+}
+
+func (i *Installer) cacheDir() string {
+	return filepath.Join(i.stateDir(), cacheDirName)
+}
+
+// kindDir returns the directory for the kind.
+func (i *Installer) kindDir(kind Kind, levelName string) string {
+	if kind != KindDatapack {
+		return filepath.Join(i.dataPath, string(kind))
+	}
+	if levelName == "" {
+		levelName = defaultLevelName
+	}
+	return filepath.Join(i.dataPath, levelName, string(kind))
+}
+
+// Install installs the artifacts of m and returns the installed files.
+// It also computes the SHA-1 digest of the resource pack, if any.
+func (i *Installer) Install(ctx context.Context, m *Manifest) (*Report, error) {
+	report, err := i.installKinds(ctx, m, KindMod, KindPlugin, KindDatapack)
+	if err != nil {
+		return nil, err
+	}
+	if m.ResourcePack != "" {
+		digest, err := i.resourcePackSHA1(ctx, m.ResourcePack)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute the SHA-1 digest of the resource pack: %w", err)
+		}
+		report.ResourcePack = &ResourcePack{URL: m.ResourcePack, SHA1: digest}
+	}
+	return report, nil
+}
+
+// InstallDatapacks installs only the datapacks of m. The other installed files are kept as they are.
+func (i *Installer) InstallDatapacks(ctx context.Context, m *Manifest) (*Report, error) {
+	return i.installKinds(ctx, m, KindDatapack)
+}
+
+func (i *Installer) installKinds(ctx context.Context, m *Manifest, kinds ...Kind) (*Report, error) {
+	if err := os.MkdirAll(i.cacheDir(), 0o750); err != nil {
+		return nil, err
+	}
+
+	previous, err := i.readState()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Mods: previous.Mods, Plugins: previous.Plugins, Datapacks: previous.Datapacks}
+	for _, set := range []struct {
+		kind      Kind
+		artifacts []Artifact
+		installed *[]Installed
+		previous  []Installed
+	}{
+		{KindMod, m.Mods, &report.Mods, previous.Mods},
+		{KindPlugin, m.Plugins, &report.Plugins, previous.Plugins},
+		{KindDatapack, m.Datapacks, &report.Datapacks, previous.Datapacks},
+	} {
+		if !slices.Contains(kinds, set.kind) {
+			continue
+		}
+		dir := i.kindDir(set.kind, m.LevelName)
+		*set.installed = nil
+		for _, a := range set.artifacts {
+			if a.Name == "" || a.Name != filepath.Base(a.Name) || strings.HasPrefix(a.Name, ".") {
+				return nil, fmt.Errorf("invalid file name %q", a.Name)
+			}
+			digest, err := i.fetch(ctx, a)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch %s/%s: %w", set.kind, a.Name, err)
+			}
+			if err := i.install(dir, a.Name, digest); err != nil {
+				return nil, fmt.Errorf("failed to install %s/%s: %w", set.kind, a.Name, err)
+			}
+			*set.installed = append(*set.installed, Installed{Name: a.Name, SHA256: digest})
+		}
+		if err := i.prune(dir, set.previous, *set.installed); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := i.pruneCache(report); err != nil {
+		return nil, err
+	}
+	if err := i.writeState(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// fetch stores the artifact in the cache and returns its digest.
+func (i *Installer) fetch(ctx context.Context, a Artifact) (string, error) {
+	if a.SHA256 != "" && i.cached(a.SHA256) {
+		return a.SHA256, nil
+	}
+
+	switch {
+	case a.URL != "":
+		return i.fetchURL(ctx, a)
+	case a.Path != "":
+		f, err := os.Open(a.Path)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		return i.store(f, a.SHA256)
+	case a.OCI != "":
+		return i.fetchOCI(ctx, a)
+	default:
+		return "", errors.New("no source is specified")
+	}
+}
+
+func (i *Installer) fetchURL(ctx context.Context, a Artifact) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.URL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s from %s", resp.Status, a.URL)
+	}
+	return i.store(resp.Body, a.SHA256)
+}
+
+func (i *Installer) fetchOCI(ctx context.Context, a Artifact) (string, error) {
+	layer, err := i.registry.resolve(ctx, a.OCI, a.Name)
+	if err != nil {
+		return "", err
+	}
+	digest := layer.sha256()
+	if a.SHA256 != "" && a.SHA256 != digest {
+		return "", fmt.Errorf("digest mismatch: expected %s, but the artifact has %s", a.SHA256, digest)
+	}
+	if i.cached(digest) {
+		return digest, nil
+	}
+	body, err := i.registry.blob(ctx, a.OCI, layer.Digest)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	return i.store(body, digest)
+}
+
+// resourcePackSHA1 downloads the resource pack and returns its SHA-1 digest without keeping it.
+func (i *Installer) resourcePackSHA1(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s from %s", resp.Status, url)
+	}
+	h := sha1.New() //nolint:gosec // Minecraft verifies resource packs with SHA-1.
+	if _, err := io.Copy(h, resp.Body); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (i *Installer) cached(digest string) bool {
+	_, err := os.Stat(filepath.Join(i.cacheDir(), digest))
+	return err == nil
+}
+
+// store writes r into the cache and verifies it against the expected digest, if any.
+func (i *Installer) store(r io.Reader, expected string) (string, error) {
+	tmp, err := os.CreateTemp(i.cacheDir(), ".download-")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, h), r); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	digest := hex.EncodeToString(h.Sum(nil))
+	if expected != "" && expected != digest {
+		return "", fmt.Errorf("sha256 mismatch: expected %s, got %s", expected, digest)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(i.cacheDir(), digest)); err != nil {
+		return "", err
+	}
+	return digest, nil
+}
+
+// install copies the cached file into dir unless it is already there.
+func (i *Installer) install(dir, name, digest string) error {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+	dst := filepath.Join(dir, name)
+	if current, err := fileSHA256(dst); err == nil && current == digest {
+		return nil
+	}
+
+	src, err := os.Open(filepath.Join(i.cacheDir(), digest))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp, err := os.CreateTemp(dir, ".install-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, src); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o640); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+// prune removes the files installed by the previous run that are no longer desired.
+// Files that were not installed by the Installer are kept.
+func (i *Installer) prune(dir string, previous, current []Installed) error {
+	for _, p := range previous {
+		if slices.ContainsFunc(current, func(c Installed) bool { return c.Name == p.Name }) {
+			continue
+		}
+		err := os.Remove(filepath.Join(dir, p.Name))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *Installer) pruneCache(report *Report) error {
+	entries, err := os.ReadDir(i.cacheDir())
+	if err != nil {
+		return err
+	}
+	used := map[string]bool{}
+	for _, installed := range slices.Concat(report.Mods, report.Plugins, report.Datapacks) {
+		used[installed.SHA256] = true
+	}
+	for _, e := range entries {
+		if used[e.Name()] {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(i.cacheDir(), e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *Installer) readState() (*Report, error) {
+	r := &Report{}
+	data, err := os.ReadFile(filepath.Join(i.stateDir(), stateFileName))
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (i *Installer) writeState(r *Report) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(i.stateDir(), stateFileName), data, 0o600)
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,314 @@
+package artifact
+
+import (
+	"context"
+	"crypto/sha1" //nolint:gosec // Minecraft verifies resource packs with SHA-1.
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func digestOf(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// fileServer serves files and counts the requests per path.
+type fileServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	files    map[string]string
+	requests map[string]int
+}
+
+func newFileServer(t *testing.T, files map[string]string) *fileServer {
+	t.Helper()
+	s := &fileServer{files: files, requests: map[string]int{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests[r.URL.Path]++
+		data, ok := s.files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(data))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fileServer) count(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestInstaller_Install(t *testing.T) {
+	dataPath := t.TempDir()
+	srv := newFileServer(t, map[string]string{
+		"/a.jar": "mod-a",
+		"/b.jar": "plugin-b",
+	})
+
+	local := filepath.Join(t.TempDir(), "c.jar")
+	if err := os.WriteFile(local, []byte("mod-c"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A file put by the user must not be pruned.
+	if err := os.MkdirAll(filepath.Join(dataPath, "mods"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dataPath, "mods", "manual.jar"), []byte("manual"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	installer := NewInstaller(dataPath, srv.Client())
+	manifest := &Manifest{
+		Mods: []Artifact{
+			{Name: "a.jar", URL: srv.URL + "/a.jar", SHA256: digestOf("mod-a")},
+			{Name: "c.jar", Path: local},
+		},
+		Plugins: []Artifact{
+			{Name: "b.jar", URL: srv.URL + "/b.jar", SHA256: digestOf("plugin-b")},
+		},
+	}
+	report, err := installer.Install(context.Background(), manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Report{
+		Mods: []Installed{
+			{Name: "a.jar", SHA256: digestOf("mod-a")},
+			{Name: "c.jar", SHA256: digestOf("mod-c")},
+		},
+		Plugins: []Installed{
+			{Name: "b.jar", SHA256: digestOf("plugin-b")},
+		},
+	}
+	if diff := cmp.Diff(want, report); diff != "" {
+		t.Errorf("Install() mismatch (-want +got):\n%s", diff)
+	}
+	if got := readFile(t, filepath.Join(dataPath, "mods", "a.jar")); got != "mod-a" {
+		t.Errorf("mods/a.jar = %q", got)
+	}
+	if got := readFile(t, filepath.Join(dataPath, "plugins", "b.jar")); got != "plugin-b" {
+		t.Errorf("plugins/b.jar = %q", got)
+	}
+
+	t.Run("cached", func(t *testing.T) {
+		if _, err := installer.Install(context.Background(), manifest); err != nil {
+			t.Fatal(err)
+		}
+		if got := srv.count("/a.jar"); got != 1 {
+			t.Errorf("a.jar was downloaded %d times, want 1", got)
+		}
+	})
+
+	t.Run("pruned", func(t *testing.T) {
+		manifest := &Manifest{
+			Mods: []Artifact{
+				{Name: "a.jar", URL: srv.URL + "/a.jar", SHA256: digestOf("mod-a")},
+			},
+		}
+		if _, err := installer.Install(context.Background(), manifest); err != nil {
+			t.Fatal(err)
+		}
+		for _, removed := range []string{
+			filepath.Join(dataPath, "mods", "c.jar"),
+			filepath.Join(dataPath, "plugins", "b.jar"),
+			filepath.Join(dataPath, stateDirName, cacheDirName, digestOf("plugin-b")),
+		} {
+			if _, err := os.Stat(removed); !os.IsNotExist(err) {
+				t.Errorf("%s is not removed: %v", removed, err)
+			}
+		}
+		if got := readFile(t, filepath.Join(dataPath, "mods", "manual.jar")); got != "manual" {
+			t.Errorf("mods/manual.jar = %q", got)
+		}
+	})
+}
+
+func TestInstaller_InstallErrors(t *testing.T) {
+	srv := newFileServer(t, map[string]string{"/a.jar": "mod-a"})
+
+	tests := []struct {
+		name     string
+		artifact Artifact
+		wantErr  string
+	}{
+		{
+			name:     "sha256 mismatch",
+			artifact: Artifact{Name: "a.jar", URL: srv.URL + "/a.jar", SHA256: digestOf("other")},
+			wantErr:  "sha256 mismatch",
+		},
+		{
+			name:     "not found",
+			artifact: Artifact{Name: "a.jar", URL: srv.URL + "/missing.jar"},
+			wantErr:  "404 Not Found",
+		},
+		{
+			name:     "invalid name",
+			artifact: Artifact{Name: "../a.jar", URL: srv.URL + "/a.jar"},
+			wantErr:  "invalid file name",
+		},
+		{
+			name:     "no source",
+			artifact: Artifact{Name: "a.jar"},
+			wantErr:  "no source is specified",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataPath := t.TempDir()
+			installer := NewInstaller(dataPath, srv.Client())
+			_, err := installer.Install(context.Background(), &Manifest{Mods: []Artifact{tt.artifact}})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Install() error = %v, want %q", err, tt.wantErr)
+			}
+			if _, err := os.Stat(filepath.Join(dataPath, "mods", "a.jar")); !os.IsNotExist(err) {
+				t.Errorf("mods/a.jar is installed: %v", err)
+			}
+		})
+	}
+}
+
+func TestInstaller_InstallDatapacks(t *testing.T) {
+	dataPath := t.TempDir()
+	srv := newFileServer(t, map[string]string{
+		"/mod.jar":      "mod",
+		"/pack.zip":     "datapack",
+		"/new-pack.zip": "new-datapack",
+	})
+	installer := NewInstaller(dataPath, srv.Client())
+
+	report, err := installer.Install(context.Background(), &Manifest{
+		Mods:      []Artifact{{Name: "mod.jar", URL: srv.URL + "/mod.jar", SHA256: digestOf("mod")}},
+		Datapacks: []Artifact{{Name: "pack.zip", URL: srv.URL + "/pack.zip", SHA256: digestOf("datapack")}},
+		LevelName: "survival",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]Installed{{Name: "pack.zip", SHA256: digestOf("datapack")}}, report.Datapacks); diff != "" {
+		t.Errorf("Install() datapacks mismatch (-want +got):\n%s", diff)
+	}
+	if got := readFile(t, filepath.Join(dataPath, "survival", "datapacks", "pack.zip")); got != "datapack" {
+		t.Errorf("survival/datapacks/pack.zip = %q", got)
+	}
+
+	// Only the datapacks are changed, even if the manifest has no mods.
+	report, err = installer.InstallDatapacks(context.Background(), &Manifest{
+		Datapacks: []Artifact{{Name: "new-pack.zip", URL: srv.URL + "/new-pack.zip", SHA256: digestOf("new-datapack")}},
+		LevelName: "survival",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Report{
+		Mods:      []Installed{{Name: "mod.jar", SHA256: digestOf("mod")}},
+		Datapacks: []Installed{{Name: "new-pack.zip", SHA256: digestOf("new-datapack")}},
+	}
+	if diff := cmp.Diff(want, report); diff != "" {
+		t.Errorf("InstallDatapacks() mismatch (-want +got):\n%s", diff)
+	}
+	if got := readFile(t, filepath.Join(dataPath, "mods", "mod.jar")); got != "mod" {
+		t.Errorf("mods/mod.jar = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dataPath, "survival", "datapacks", "pack.zip")); !os.IsNotExist(err) {
+		t.Errorf("survival/datapacks/pack.zip is not removed: %v", err)
+	}
+}
+
+func TestInstaller_InstallResourcePack(t *testing.T) {
+	srv := newFileServer(t, map[string]string{"/pack.zip": "resource-pack"})
+	installer := NewInstaller(t.TempDir(), srv.Client())
+
+	report, err := installer.Install(context.Background(), &Manifest{ResourcePack: srv.URL + "/pack.zip"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha1.Sum([]byte("resource-pack")) //nolint:gosec // test data
+	want := &ResourcePack{URL: srv.URL + "/pack.zip", SHA1: hex.EncodeToString(sum[:])}
+	if diff := cmp.Diff(want, report.ResourcePack); diff != "" {
+		t.Errorf("Install() resource pack mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = installer.Install(context.Background(), &Manifest{ResourcePack: srv.URL + "/missing.zip"})
+	if err == nil || !strings.Contains(err.Error(), "404 Not Found") {
+		t.Errorf("Install() error = %v", err)
+	}
+}
+
+func TestReadManifest(t *testing.T) {
+	dir := t.TempDir()
+
+	m, err := ReadManifest(filepath.Join(dir, "missing.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&Manifest{}, m); diff != "" {
+		t.Errorf("ReadManifest() mismatch (-want +got):\n%s", diff)
+	}
+
+	path := filepath.Join(dir, "artifacts.json")
+	data := `{"mods":[{"name":"a.jar","url":"https://example.com/a.jar","sha256":"abc"}]}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m, err = ReadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Manifest{Mods: []Artifact{{Name: "a.jar", URL: "https://example.com/a.jar", SHA256: "abc"}}}
+	if diff := cmp.Diff(want, m); diff != "" {
+		t.Errorf("ReadManifest() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestEncodeReport(t *testing.T) {
+	small := &Report{Mods: []Installed{{Name: "a.jar", SHA256: digestOf("a")}}}
+	data, err := EncodeReport(small)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), digestOf("a")) {
+		t.Errorf("EncodeReport() = %s, want the digest", data)
+	}
+
+	large := &Report{}
+	for i := range 100 {
+		large.Plugins = append(large.Plugins, Installed{Name: fmt.Sprintf("plugin-%d.jar", i), SHA256: digestOf("p")})
+	}
+	data, err = EncodeReport(large)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) > maxReportSize {
+		t.Errorf("len(EncodeReport()) = %d, want <= %d", len(data), maxReportSize)
+	}
+	if strings.Contains(string(data), "sha256") || !strings.Contains(string(data), "plugin-99.jar") {
+		t.Errorf("EncodeReport() = %s, want names without digests", data)
+	}
+}
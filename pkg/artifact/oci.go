@@ -0,0 +1,240 @@
+package artifact
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const (
+	defaultRegistry = "registry-1.docker.io"
+
+	// titleAnnotation is the file name of a layer set by tools such as oras.
+	titleAnnotation = "org.opencontainers.image.title"
+)
+
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// reference is a parsed OCI reference such as ghcr.io/example/mod:1.0.0.
+type reference struct {
+	registry   string
+	repository string
+	// tag is a tag or a digest.
+	tag string
+}
+
+func parseReference(ref string) (reference, error) {
+	r := reference{registry: defaultRegistry, tag: "latest"}
+
+	rest := ref
+	if first, after, ok := strings.Cut(ref, "/"); ok &&
+		(strings.ContainsAny(first, ".:") || first == "localhost") {
+		r.registry = first
+		rest = after
+	}
+	if repo, digest, ok := strings.Cut(rest, "@"); ok {
+		r.repository, r.tag = repo, digest
+	} else if i := strings.LastIndex(rest, ":"); i >= 0 {
+		r.repository, r.tag = rest[:i], rest[i+1:]
+	} else {
+		r.repository = rest
+	}
+	if r.repository == "" || r.tag == "" {
+		return reference{}, fmt.Errorf("invalid OCI reference %q", ref)
+	}
+	if r.registry == defaultRegistry && !strings.Contains(r.repository, "/") {
+		r.repository = "library/" + r.repository
+	}
+	return r, nil
+}
+
+// baseURL returns the URL of the registry. Registries on the loopback address are accessed with plain HTTP.
+func (r reference) baseURL() string {
+	host, _, _ := strings.Cut(r.registry, ":")
+	if host == "localhost" || host == "127.0.0.1" {
+		return "http://" + r.registry
+	}
+	return "https://" + r.registry
+}
+
+type layer struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+func (l layer) sha256() string {
+	return strings.TrimPrefix(l.Digest, "sha256:")
+}
+
+type imageManifest struct {
+	Layers []layer `json:"layers"`
+}
+
+// registryClient is a minimal client of the OCI distribution API that supports anonymous bearer tokens.
+type registryClient struct {
+	client *http.Client
+
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+func newRegistryClient(client *http.Client) *registryClient {
+	return &registryClient{
+		client: client,
+		tokens: map[string]string{},
+	}
+}
+
+// resolve returns the layer that holds the file name.
+// An artifact with a single layer is used regardless of its title.
+func (c *registryClient) resolve(ctx context.Context, ref, name string) (layer, error) {
+	r, err := parseReference(ref)
+	if err != nil {
+		return layer{}, err
+	}
+	resp, err := c.get(ctx, r, "/manifests/"+r.tag, strings.Join(manifestMediaTypes, ", "))
+	if err != nil {
+		return layer{}, err
+	}
+	defer resp.Body.Close()
+
+	var m imageManifest
+	if err := json.NewDecoder(resp.Body).Decode(&m); err != nil {
+		return layer{}, fmt.Errorf("failed to decode the manifest of %s: %w", ref, err)
+	}
+	switch {
+	case len(m.Layers) == 0:
+		return layer{}, fmt.Errorf("%s has no layers", ref)
+	case len(m.Layers) == 1:
+		return m.Layers[0], nil
+	}
+	for _, l := range m.Layers {
+		if l.Annotations[titleAnnotation] == name {
+			return l, nil
+		}
+	}
+	return layer{}, fmt.Errorf("%s has no layer titled %s", ref, name)
+}
+
+func (c *registryClient) blob(ctx context.Context, ref, digest string) (io.ReadCloser, error) {
+	r, err := parseReference(ref)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.get(ctx, r, "/blobs/"+digest, "")
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// get sends a GET request to the repository, fetching an anonymous token when the registry asks for one.
+func (c *registryClient) get(ctx context.Context, r reference, path, accept string) (*http.Response, error) {
+	u := r.baseURL() + "/v2/" + r.repository + path
+	do := func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		c.mu.Lock()
+		token := c.tokens[r.registry+"/"+r.repository]
+		c.mu.Unlock()
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return c.client.Do(req)
+	}
+
+	resp, err := do()
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		token, err := c.token(ctx, challenge)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		c.tokens[r.registry+"/"+r.repository] = token
+		c.mu.Unlock()
+		if resp, err = do(); err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %s from %s", resp.Status, u)
+	}
+	return resp, nil
+}
+
+// token fetches an anonymous token for a Bearer challenge.
+func (c *registryClient) token(ctx context.Context, challenge string) (string, error) {
+	scheme, params, ok := strings.Cut(challenge, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+	values := url.Values{}
+	var realm string
+	for _, param := range strings.Split(params, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok {
+			continue
+		}
+		v = strings.Trim(v, `"`)
+		if k == "realm" {
+			realm = v
+			continue
+		}
+		values.Set(k, v)
+	}
+	if realm == "" {
+		return "", errors.New("the authentication challenge has no realm")
+	}
+
+	u := realm
+	if len(values) != 0 {
+		u += "?" + values.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s from %s", resp.Status, realm)
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+	return "", errors.New("the token response has no token")
+}
@@ -0,0 +1,144 @@
+package artifact
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    reference
+		wantErr bool
+	}{
+		{
+			ref:  "ghcr.io/example/mod:1.0.0",
+			want: reference{registry: "ghcr.io", repository: "example/mod", tag: "1.0.0"},
+		},
+		{
+			ref:  "localhost:5000/mod",
+			want: reference{registry: "localhost:5000", repository: "mod", tag: "latest"},
+		},
+		{
+			ref:  "example/mod@sha256:abc",
+			want: reference{registry: defaultRegistry, repository: "example/mod", tag: "sha256:abc"},
+		},
+		{
+			ref:  "mod:1",
+			want: reference{registry: defaultRegistry, repository: "library/mod", tag: "1"},
+		},
+		{
+			ref:     "ghcr.io/example/mod:",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := parseReference(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseReference() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(reference{})); diff != "" {
+				t.Errorf("parseReference() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// newRegistry starts a registry stand-in that requires an anonymous bearer token.
+func newRegistry(t *testing.T, repository string, layers map[string]string) *httptest.Server {
+	t.Helper()
+	const token = "anonymous-token"
+
+	manifest := imageManifest{}
+	blobs := map[string]string{}
+	for title, data := range layers {
+		digest := "sha256:" + digestOf(data)
+		manifest.Layers = append(manifest.Layers, layer{
+			MediaType:   "application/java-archive",
+			Digest:      digest,
+			Annotations: map[string]string{titleAnnotation: title},
+		})
+		blobs[digest] = data
+	}
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if r.URL.Query().Get("scope") != "repository:"+repository+":pull" {
+				http.Error(w, "bad scope", http.StatusBadRequest)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": token})
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+srv.URL+`/token",service="test",scope="repository:`+repository+`:pull"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		prefix := "/v2/" + repository
+		switch {
+		case r.URL.Path == prefix+"/manifests/1.0.0":
+			w.Header().Set("Content-Type", manifestMediaTypes[0])
+			_ = json.NewEncoder(w).Encode(manifest)
+		case strings.HasPrefix(r.URL.Path, prefix+"/blobs/"):
+			data, ok := blobs[strings.TrimPrefix(r.URL.Path, prefix+"/blobs/")]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte(data))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestInstaller_InstallOCI(t *testing.T) {
+	srv := newRegistry(t, "example/mods", map[string]string{
+		"a.jar": "mod-a",
+		"b.jar": "mod-b",
+	})
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	dataPath := t.TempDir()
+	installer := NewInstaller(dataPath, srv.Client())
+	report, err := installer.Install(context.Background(), &Manifest{
+		Mods: []Artifact{
+			{Name: "b.jar", OCI: host + "/example/mods:1.0.0"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Report{Mods: []Installed{{Name: "b.jar", SHA256: digestOf("mod-b")}}}
+	if diff := cmp.Diff(want, report); diff != "" {
+		t.Errorf("Install() mismatch (-want +got):\n%s", diff)
+	}
+	if got := readFile(t, filepath.Join(dataPath, "mods", "b.jar")); got != "mod-b" {
+		t.Errorf("mods/b.jar = %q", got)
+	}
+
+	_, err = installer.Install(context.Background(), &Manifest{
+		Mods: []Artifact{
+			{Name: "c.jar", OCI: host + "/example/mods:1.0.0"},
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), "has no layer titled c.jar") {
+		t.Errorf("Install() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dataPath, "mods", "b.jar")); err != nil {
+		t.Errorf("mods/b.jar is removed by a failed install: %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+// Package autopause implements the backends that stop an idle Minecraft server
+// and start it again on demand.
+package autopause
+
+import (
+	"context"
+	"time"
+
+	"github.com/kmdkuk/mcing/pkg/slp"
+)
+
+const (
+	// ModeLazymc runs lazymc as the main process of the minecraft container.
+	ModeLazymc = "lazymc"
+	// ModeAgent lets mcing-agent proxy the server port and start/stop the server process.
+	ModeAgent = "agent"
+
+	defaultPollInterval = time.Second
+	knockPlayerName     = "mcing-agent"
+)
+
+// Controller wakes up and stops the server process on behalf of an auto-pause backend.
+type Controller interface {
+	// Wake starts the server process if it is sleeping and waits until it accepts connections.
+	// It reports whether the server was sleeping.
+	Wake(ctx context.Context) (bool, error)
+	// Sleep stops the server process. It reports whether the server was already sleeping.
+	Sleep(ctx context.Context) (bool, error)
+}
+
+func backendRunning(ctx context.Context, addr string) bool {
+	_, err := slp.Ping(ctx, addr)
+	return err == nil
+}
+
+func waitForBackend(ctx context.Context, addr string, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for !backendRunning(ctx, addr) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
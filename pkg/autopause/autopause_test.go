@@ -0,0 +1,86 @@
+package autopause
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/kmdkuk/mcing/pkg/slp"
+)
+
+type mockConsole struct {
+	commands []string
+}
+
+func (m *mockConsole) Write(cmd string) (int, error) {
+	m.commands = append(m.commands, cmd)
+	return 1, nil
+}
+
+func (m *mockConsole) Read() (string, int, error) {
+	return "", 1, nil
+}
+
+// fakeBackend answers status requests like a running server and counts login attempts.
+type fakeBackend struct {
+	addr   string
+	name   string
+	logins atomic.Int32
+}
+
+func newFakeBackend(t *testing.T, name string) *fakeBackend {
+	t.Helper()
+	return newFakeBackendAt(t, name, "127.0.0.1:0")
+}
+
+func newFakeBackendAt(t *testing.T, name, addr string) *fakeBackend {
+	t.Helper()
+	var lc net.ListenConfig
+	l, err := lc.Listen(context.Background(), "tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	b := &fakeBackend{addr: l.Addr().String(), name: name} //nolint:exhaustruct // test
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(conn)
+		}
+	}()
+	return b
+}
+
+func (b *fakeBackend) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	hs, _, err := slp.ReadHandshake(r)
+	if err != nil {
+		return
+	}
+	if hs.NextState == slp.NextStateLogin {
+		b.logins.Add(1)
+		return
+	}
+	st := &slp.Status{} //nolint:exhaustruct // test
+	st.Version.Name = b.name
+	_ = slp.ServeStatus(r, conn, st)
+}
+
+// freeAddr returns an address nothing listens on.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	var lc net.ListenConfig
+	l, err := lc.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	_ = l.Close()
+	return addr
+}
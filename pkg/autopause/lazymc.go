@@ -0,0 +1,74 @@
+package autopause
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kmdkuk/mcing/pkg/rcon"
+	"github.com/kmdkuk/mcing/pkg/slp"
+)
+
+// Lazymc controls a server process owned by lazymc.
+type Lazymc struct {
+	logger       *zap.Logger
+	console      rcon.Console
+	publicAddr   string
+	backendAddr  string
+	pollInterval time.Duration
+}
+
+var _ Controller = &Lazymc{} //nolint:exhaustruct // interface check
+
+// NewLazymc creates a new Lazymc.
+// publicAddr is the address lazymc listens on and backendAddr is the address of the server process.
+func NewLazymc(logger *zap.Logger, console rcon.Console, publicAddr, backendAddr string) *Lazymc {
+	return &Lazymc{
+		logger:       logger,
+		console:      console,
+		publicAddr:   publicAddr,
+		backendAddr:  backendAddr,
+		pollInterval: defaultPollInterval,
+	}
+}
+
+// Wake makes lazymc start the server process.
+func (l *Lazymc) Wake(ctx context.Context) (bool, error) {
+	if backendRunning(ctx, l.backendAddr) {
+		return false, nil
+	}
+
+	// lazymc answers status pings itself while sleeping, so use it to learn the protocol
+	// version it expects and then start a login to make it spawn the server.
+	protocol := int32(slp.DefaultProtocolVersion)
+	if st, err := slp.Ping(ctx, l.publicAddr); err == nil {
+		protocol = st.Version.Protocol
+	}
+	l.logger.Info("waking up the server", zap.Int32("protocol", protocol))
+	if err := slp.Knock(ctx, l.publicAddr, protocol, knockPlayerName); err != nil {
+		return false, fmt.Errorf("failed to knock on lazymc: %w", err)
+	}
+	if err := waitForBackend(ctx, l.backendAddr, l.pollInterval); err != nil {
+		return false, err
+	}
+	l.logger.Info("the server is awake")
+	return true, nil
+}
+
+// Sleep stops the server process. lazymc starts it again when a player joins.
+func (l *Lazymc) Sleep(ctx context.Context) (bool, error) {
+	if !backendRunning(ctx, l.backendAddr) {
+		return true, nil
+	}
+	if err := rcon.Stop(l.console); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// Sleeping reports whether the server process is stopped.
+func (l *Lazymc) Sleeping(ctx context.Context) bool {
+	return !backendRunning(ctx, l.backendAddr)
+}
@@ -0,0 +1,71 @@
+package autopause
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestLazymcWake(t *testing.T) {
+	backend := newFakeBackend(t, "backend")
+	l := NewLazymc(zap.NewNop(), &mockConsole{}, freeAddr(t), backend.addr)
+	wasSleeping, err := l.Wake(context.Background())
+	if err != nil {
+		t.Fatalf("Wake() error = %v", err)
+	}
+	if wasSleeping {
+		t.Error("Wake() should report a running server as awake")
+	}
+
+	l = NewLazymc(zap.NewNop(), &mockConsole{}, freeAddr(t), freeAddr(t))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := l.Wake(ctx); err == nil {
+		t.Error("Wake() should fail when lazymc is unreachable")
+	}
+}
+
+func TestLazymcSleep(t *testing.T) {
+	tests := []struct {
+		name            string
+		running         bool
+		wantWasSleeping bool
+		wantCommands    []string
+	}{
+		{
+			name:            "running",
+			running:         true,
+			wantWasSleeping: false,
+			wantCommands:    []string{"stop"},
+		},
+		{
+			name:            "already sleeping",
+			running:         false,
+			wantWasSleeping: true,
+			wantCommands:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backendAddr := freeAddr(t)
+			if tt.running {
+				backendAddr = newFakeBackend(t, "backend").addr
+			}
+			console := &mockConsole{}
+			l := NewLazymc(zap.NewNop(), console, freeAddr(t), backendAddr)
+			wasSleeping, err := l.Sleep(context.Background())
+			if err != nil {
+				t.Fatalf("Sleep() error = %v", err)
+			}
+			if wasSleeping != tt.wantWasSleeping {
+				t.Errorf("Sleep() = %v, want %v", wasSleeping, tt.wantWasSleeping)
+			}
+			if !reflect.DeepEqual(console.commands, tt.wantCommands) {
+				t.Errorf("commands = %v, want %v", console.commands, tt.wantCommands)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package autopause
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kmdkuk/mcing/pkg/rcon"
+	"github.com/kmdkuk/mcing/pkg/slp"
+)
+
+const (
+	defaultIdleCheckInterval = 10 * time.Second
+	defaultStartTimeout      = 3 * time.Minute
+)
+
+// Proxy is a minimal replacement of lazymc built into mcing-agent.
+// It forwards the public server port to the server process, answers status pings
+// while the server is sleeping and starts the server when a player logs in.
+//
+// The server process itself is run by a loop in the minecraft container,
+// which does not start it while the marker file exists.
+type Proxy struct {
+	logger      *zap.Logger
+	console     rcon.Console
+	listenAddr  string
+	backendAddr string
+	markerPath  string
+	sleepAfter  time.Duration
+
+	pollInterval      time.Duration
+	idleCheckInterval time.Duration
+	startTimeout      time.Duration
+
+	mu         sync.Mutex
+	lastActive time.Time
+}
+
+var _ Controller = &Proxy{} //nolint:exhaustruct // interface check
+
+// NewProxy creates a new Proxy.
+// The server is put to sleep after no player has been online for sleepAfter.
+func NewProxy(
+	logger *zap.Logger,
+	console rcon.Console,
+	listenAddr, backendAddr, markerPath string,
+	sleepAfter time.Duration,
+) *Proxy {
+	return &Proxy{ //nolint:exhaustruct // mu and lastActive are zero values
+		logger:            logger,
+		console:           console,
+		listenAddr:        listenAddr,
+		backendAddr:       backendAddr,
+		markerPath:        markerPath,
+		sleepAfter:        sleepAfter,
+		pollInterval:      defaultPollInterval,
+		idleCheckInterval: defaultIdleCheckInterval,
+		startTimeout:      defaultStartTimeout,
+	}
+}
+
+// Run accepts connections until ctx is canceled.
+func (p *Proxy) Run(ctx context.Context) error {
+	var lc net.ListenConfig
+	l, err := lc.Listen(ctx, "tcp", p.listenAddr)
+	if err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { _ = l.Close() })
+	defer stop()
+
+	p.touch()
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	wg.Go(func() { p.watchIdle(ctx) })
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		wg.Go(func() { p.handle(ctx, conn) })
+	}
+}
+
+// Wake removes the marker file and waits until the server accepts connections.
+func (p *Proxy) Wake(ctx context.Context) (bool, error) {
+	p.mu.Lock()
+	wasSleeping := p.sleeping()
+	if wasSleeping {
+		if err := os.Remove(p.markerPath); err != nil && !os.IsNotExist(err) {
+			p.mu.Unlock()
+			return false, err
+		}
+		p.lastActive = time.Now()
+		p.logger.Info("waking up the server")
+	}
+	p.mu.Unlock()
+
+	if err := waitForBackend(ctx, p.backendAddr, p.pollInterval); err != nil {
+		return wasSleeping, err
+	}
+	return wasSleeping, nil
+}
+
+// Sleep creates the marker file and stops the server process.
+func (p *Proxy) Sleep(_ context.Context) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.sleeping() {
+		return true, nil
+	}
+	if err := os.WriteFile(p.markerPath, nil, 0o600); err != nil {
+		return false, err
+	}
+	if err := rcon.Stop(p.console); err != nil {
+		// The server keeps running, so do not leave it marked as sleeping.
+		_ = os.Remove(p.markerPath)
+		return false, err
+	}
+	p.logger.Info("the server is going to sleep")
+	return false, nil
+}
+
+// Sleeping reports whether the server is put to sleep.
+func (p *Proxy) Sleeping() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.sleeping()
+}
+
+func (p *Proxy) sleeping() bool {
+	_, err := os.Stat(p.markerPath)
+	return err == nil
+}
+
+func (p *Proxy) touch() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastActive = time.Now()
+}
+
+func (p *Proxy) idleFor() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return time.Since(p.lastActive)
+}
+
+func (p *Proxy) watchIdle(ctx context.Context) {
+	ticker := time.NewTicker(p.idleCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if p.Sleeping() {
+			continue
+		}
+		st, err := slp.Ping(ctx, p.backendAddr)
+		if err != nil || st.Players.Online > 0 {
+			// The server is starting up or players are online.
+			p.touch()
+			continue
+		}
+		if p.idleFor() < p.sleepAfter {
+			continue
+		}
+		p.logger.Info("no players online, putting the server to sleep", zap.Duration("sleepAfter", p.sleepAfter))
+		if _, err := p.Sleep(ctx); err != nil {
+			p.logger.Error("failed to put the server to sleep", zap.Error(err))
+		}
+	}
+}
+
+func (p *Proxy) handle(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	r := bufio.NewReader(conn)
+	hs, raw, err := slp.ReadHandshake(r)
+	if err != nil {
+		p.logger.Debug("failed to read handshake", zap.Error(err))
+		return
+	}
+
+	backend, err := p.dialBackend(ctx)
+	if err != nil {
+		if hs.NextState != slp.NextStateLogin {
+			if err := slp.ServeStatus(r, conn, p.placeholderStatus(hs.ProtocolVersion)); err != nil {
+				p.logger.Debug("failed to serve status", zap.Error(err))
+			}
+			return
+		}
+		wakeCtx, cancel := context.WithTimeout(ctx, p.startTimeout)
+		defer cancel()
+		if _, err := p.Wake(wakeCtx); err != nil {
+			p.logger.Error("failed to wake up the server for a joining player", zap.Error(err))
+			return
+		}
+		if backend, err = p.dialBackend(ctx); err != nil {
+			p.logger.Error("failed to connect to the server", zap.Error(err))
+			return
+		}
+	}
+	defer backend.Close()
+
+	if _, err := backend.Write(raw); err != nil {
+		return
+	}
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		_, _ = io.Copy(backend, r)
+		_ = backend.Close()
+	})
+	_, _ = io.Copy(conn, backend)
+	_ = conn.Close()
+	wg.Wait()
+	if hs.NextState == slp.NextStateLogin {
+		p.touch()
+	}
+}
+
+func (p *Proxy) dialBackend(ctx context.Context) (net.Conn, error) {
+	var d net.Dialer
+	return d.DialContext(ctx, "tcp", p.backendAddr)
+}
+
+// placeholderStatus is shown in the server list while the server process is not running.
+func (p *Proxy) placeholderStatus(protocol int32) *slp.Status {
+	text := "The server is starting..."
+	if p.Sleeping() {
+		text = "The server is sleeping. Join to wake it up."
+	}
+	return slp.TextStatus(protocol, text)
+}
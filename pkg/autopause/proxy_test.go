@@ -0,0 +1,159 @@
+package autopause
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kmdkuk/mcing/pkg/slp"
+)
+
+// startProxy runs a Proxy in front of backendAddr and returns it with its listen address.
+func startProxy(t *testing.T, backendAddr string, console *mockConsole) (*Proxy, string) {
+	t.Helper()
+	listenAddr := freeAddr(t)
+	p := NewProxy(zap.NewNop(), console, listenAddr, backendAddr, filepath.Join(t.TempDir(), "sleeping"), time.Hour)
+	p.pollInterval = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := p.Run(ctx); err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	// Wait for the listener.
+	for range 100 {
+		if _, err := slp.Ping(context.Background(), listenAddr); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return p, listenAddr
+}
+
+func TestProxyForwardsStatus(t *testing.T) {
+	backend := newFakeBackend(t, "backend")
+	_, addr := startProxy(t, backend.addr, &mockConsole{})
+
+	st, err := slp.Ping(context.Background(), addr)
+	if err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	if st.Version.Name != "backend" {
+		t.Errorf("version = %q, want the backend status", st.Version.Name)
+	}
+}
+
+func TestProxySleepingStatus(t *testing.T) {
+	p, addr := startProxy(t, freeAddr(t), &mockConsole{})
+	if err := os.WriteFile(p.markerPath, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := slp.Ping(context.Background(), addr)
+	if err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	if st.Version.Name != "mcing" {
+		t.Errorf("version = %q, want the placeholder status", st.Version.Name)
+	}
+	if !p.Sleeping() {
+		t.Error("a status ping should not wake the server")
+	}
+}
+
+func TestProxyForwardsLogin(t *testing.T) {
+	backend := newFakeBackend(t, "backend")
+	p, addr := startProxy(t, backend.addr, &mockConsole{})
+	if err := os.WriteFile(p.markerPath, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// The backend is reachable, so the proxy forwards the login without waking.
+	if err := slp.Knock(context.Background(), addr, slp.DefaultProtocolVersion, "player"); err != nil {
+		t.Fatalf("Knock() error = %v", err)
+	}
+	for range 100 {
+		if backend.logins.Load() == 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := backend.logins.Load(); got != 1 {
+		t.Errorf("backend logins = %d, want 1", got)
+	}
+}
+
+func TestProxyLoginWakes(t *testing.T) {
+	backendAddr := freeAddr(t)
+	p, addr := startProxy(t, backendAddr, &mockConsole{})
+	if err := os.WriteFile(p.markerPath, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := slp.Knock(context.Background(), addr, slp.DefaultProtocolVersion, "player"); err != nil {
+		t.Fatalf("Knock() error = %v", err)
+	}
+
+	// The server process starts once the marker file is removed.
+	for range 100 {
+		if !p.Sleeping() {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if p.Sleeping() {
+		t.Fatal("a login should wake the server")
+	}
+	backend := newFakeBackendAt(t, "backend", backendAddr)
+	for range 100 {
+		if backend.logins.Load() == 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := backend.logins.Load(); got != 1 {
+		t.Errorf("backend logins = %d, want the held login to be forwarded", got)
+	}
+}
+
+func TestProxyWakeAndSleep(t *testing.T) {
+	backend := newFakeBackend(t, "backend")
+	console := &mockConsole{}
+	p := NewProxy(zap.NewNop(), console, freeAddr(t), backend.addr, filepath.Join(t.TempDir(), "sleeping"), time.Hour)
+	ctx := context.Background()
+
+	wasSleeping, err := p.Sleep(ctx)
+	if err != nil || wasSleeping {
+		t.Fatalf("Sleep() = %v, %v, want false, nil", wasSleeping, err)
+	}
+	if !p.Sleeping() {
+		t.Error("Sleep() should create the marker file")
+	}
+	if !reflect.DeepEqual(console.commands, []string{"stop"}) {
+		t.Errorf("commands = %v, want [stop]", console.commands)
+	}
+	if wasSleeping, _ = p.Sleep(ctx); !wasSleeping {
+		t.Error("Sleep() should report a sleeping server")
+	}
+
+	wasSleeping, err = p.Wake(ctx)
+	if err != nil || !wasSleeping {
+		t.Fatalf("Wake() = %v, %v, want true, nil", wasSleeping, err)
+	}
+	if p.Sleeping() {
+		t.Error("Wake() should remove the marker file")
+	}
+	if wasSleeping, _ = p.Wake(ctx); wasSleeping {
+		t.Error("Wake() should report a running server")
+	}
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"sort"
+
+	"github.com/kmdkuk/mcing/pkg/constants"
+)
+
+// hotReloadableProps are the properties that mcing-agent applies to the running server with commands.
+// The server reads the other properties only when it starts.
+var hotReloadableProps = map[string]bool{
+	constants.DifficultyProps: true,
+	constants.GamemodeProps:   true,
+	constants.WhitelistProps:  true,
+}
+
+// IsHotReloadable returns true if a change of the property takes effect without restarting the server.
+func IsHotReloadable(key string) bool {
+	return hotReloadableProps[key]
+}
+
+// DiffServerProps compares the properties of the running server with the desired ones.
+// It returns the sorted keys of the changed properties, split into the hot-reloadable ones
+// and the ones that require a restart. An added or removed property is also a change.
+func DiffServerProps(running, desired map[string]string) ([]string, []string) {
+	var reloadable, restartRequired []string
+	changed := func(k string) {
+		if IsHotReloadable(k) {
+			reloadable = append(reloadable, k)
+		} else {
+			restartRequired = append(restartRequired, k)
+		}
+	}
+	for k, v := range desired {
+		if old, ok := running[k]; !ok || old != v {
+			changed(k)
+		}
+	}
+	for k := range running {
+		if _, ok := desired[k]; !ok {
+			changed(k)
+		}
+	}
+	sort.Strings(reloadable)
+	sort.Strings(restartRequired)
+	return reloadable, restartRequired
+}
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// secretRefPattern matches a reference to a key of a Secret, such as ${secret:db/password}.
+var secretRefPattern = regexp.MustCompile(`\$\{secret:([a-z0-9]([-a-z0-9.]*[a-z0-9])?)/([-._a-zA-Z0-9]+)\}`)
+
+// SecretRef is a reference to a key of a Secret in a config file.
+type SecretRef struct {
+	Name string
+	Key  string
+}
+
+// Placeholder returns the text that refers to the key in a config file.
+func (r SecretRef) Placeholder() string {
+	return "${secret:" + r.Name + "/" + r.Key + "}"
+}
+
+// FindSecretRefs returns the Secret keys referenced in content, sorted and without duplicates.
+func FindSecretRefs(content string) []SecretRef {
+	seen := map[SecretRef]bool{}
+	var refs []SecretRef
+	for _, m := range secretRefPattern.FindAllStringSubmatch(content, -1) {
+		ref := SecretRef{Name: m[1], Key: m[3]}
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Name != refs[j].Name {
+			return refs[i].Name < refs[j].Name
+		}
+		return refs[i].Key < refs[j].Key
+	})
+	return refs
+}
+
+// ResolveSecretRefs replaces the Secret references in content with the values of the keys.
+// The value of a key is read from dir/<name>/<key>, and a trailing newline is removed.
+//
+// The value is escaped for the format of the file at path, which is told by the extension,
+// so that a value cannot add lines or break the syntax of the file:
+//   - .properties: the escapes of Java properties
+//   - .json: the escapes of a JSON string. The reference must be in a string.
+//   - .yaml and .yml: the escapes of the quoted scalar the reference is in.
+//     A reference that is a whole plain scalar is replaced with a double-quoted scalar.
+//
+// The value is inserted as it is into files of other formats.
+func ResolveSecretRefs(content []byte, dir, path string) ([]byte, error) {
+	escape := escaperFor(path)
+	var resolveErr error
+	var out bytes.Buffer
+	last := 0
+	for _, loc := range secretRefPattern.FindAllSubmatchIndex(content, -1) {
+		match := content[loc[0]:loc[1]]
+		out.Write(content[last:loc[0]])
+		last = loc[1]
+
+		data, err := os.ReadFile(filepath.Join(dir, string(content[loc[2]:loc[3]]), string(content[loc[6]:loc[7]])))
+		if err != nil {
+			if resolveErr == nil {
+				resolveErr = fmt.Errorf("failed to resolve %s: %w", match, err)
+			}
+			continue
+		}
+		value := strings.TrimRight(string(data), "\r\n")
+		escaped, err := escape(value, lineBefore(content, loc[0]), lineAfter(content, loc[1]))
+		if err != nil {
+			if resolveErr == nil {
+				resolveErr = fmt.Errorf("failed to resolve %s in %s: %w", match, path, err)
+			}
+			continue
+		}
+		out.WriteString(escaped)
+	}
+	if resolveErr != nil {
+		return nil, resolveErr
+	}
+	out.Write(content[last:])
+	return out.Bytes(), nil
+}
+
+// escaper escapes a value for the context of a reference, which is given by the line around it.
+type escaper func(value string, before, after []byte) (string, error)
+
+func escaperFor(path string) escaper {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".properties":
+		return escapeProperties
+	case ".json":
+		return escapeJSON
+	case ".yaml", ".yml":
+		return escapeYAML
+	default:
+		return func(value string, _, _ []byte) (string, error) { return value, nil }
+	}
+}
+
+var propertiesEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	"\n", "\\n",
+	"\r", "\\r",
+	"\t", "\\t",
+	"\f", "\\f",
+)
+
+func escapeProperties(value string, _, _ []byte) (string, error) {
+	return propertiesEscaper.Replace(value), nil
+}
+
+func escapeJSON(value string, before, _ []byte) (string, error) {
+	if quoteContext(before) != '"' {
+		return "", errors.New("the reference must be in a JSON string")
+	}
+	return jsonStringContent(value), nil
+}
+
+func escapeYAML(value string, before, after []byte) (string, error) {
+	switch quoteContext(before) {
+	case '"':
+		// The escapes of a JSON string are valid in a double-quoted YAML scalar.
+		return jsonStringContent(value), nil
+	case '\'':
+		if strings.ContainsFunc(value, unicode.IsControl) {
+			return "", errors.New("the value has a control character, which cannot be in a single-quoted scalar")
+		}
+		return strings.ReplaceAll(value, "'", "''"), nil
+	}
+	if !wholePlainScalar(before, after) {
+		return "", errors.New("the reference must be a whole value or in a quoted string")
+	}
+	return `"` + jsonStringContent(value) + `"`, nil
+}
+
+// jsonStringContent returns value as the content of a JSON string without the quotes.
+func jsonStringContent(value string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(value)
+	quoted := strings.TrimSuffix(buf.String(), "\n")
+	return quoted[1 : len(quoted)-1]
+}
+
+// quoteContext returns the quote character of the string that is open at the end of line,
+// or 0 if no string is open.
+func quoteContext(line []byte) byte {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch quote {
+		case '"':
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				quote = 0
+			}
+		case '\'':
+			if c == '\'' {
+				if i+1 < len(line) && line[i+1] == '\'' {
+					i++
+				} else {
+					quote = 0
+				}
+			}
+		default:
+			// A quote starts a string only at the beginning of a value.
+			if (c == '"' || c == '\'') && (i == 0 || strings.IndexByte(" \t:[{,-", line[i-1]) >= 0) {
+				quote = c
+			}
+		}
+	}
+	return quote
+}
+
+// wholePlainScalar reports whether a reference between before and after in a YAML line is a whole value,
+// such as "key: ${secret:name/key}" or "- ${secret:name/key} # comment".
+func wholePlainScalar(before, after []byte) bool {
+	b := strings.TrimRight(string(before), " \t")
+	if len(b) == len(before) && strings.TrimSpace(b) != "" {
+		// The reference follows other characters of the value.
+		return false
+	}
+	if b = strings.TrimSpace(b); b != "" && !strings.HasSuffix(b, ":") && !strings.HasSuffix(b, "-") {
+		return false
+	}
+	a := string(after)
+	trimmed := strings.TrimLeft(a, " \t")
+	return trimmed == "" || (strings.HasPrefix(trimmed, "#") && len(trimmed) < len(a))
+}
+
+// lineBefore returns the part of the line of content before i.
+func lineBefore(content []byte, i int) []byte {
+	return content[bytes.LastIndexByte(content[:i], '\n')+1 : i]
+}
+
+// lineAfter returns the part of the line of content after i, without the line break.
+func lineAfter(content []byte, i int) []byte {
+	rest := content[i:]
+	if j := bytes.IndexByte(rest, '\n'); j >= 0 {
+		rest = rest[:j]
+	}
+	return bytes.TrimSuffix(rest, []byte("\r"))
+}
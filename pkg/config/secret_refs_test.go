@@ -0,0 +1,165 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFindSecretRefs(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []SecretRef
+	}{
+		{
+			name:    "none",
+			content: "motd=hello\nrcon.password=minecraft\n",
+		},
+		{
+			name:    "sorted without duplicates",
+			content: "rcon.password=${secret:rcon/password}\nurl=${secret:db/url}?p=${secret:db/password}\nx=${secret:db/url}\n",
+			want: []SecretRef{
+				{Name: "db", Key: "password"},
+				{Name: "db", Key: "url"},
+				{Name: "rcon", Key: "password"},
+			},
+		},
+		{
+			name:    "invalid names",
+			content: "a=${secret:DB/password}\nb=${secret:db}\nc=${secret:db/pass word}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FindSecretRefs(tt.content)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("FindSecretRefs() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestResolveSecretRefs(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "db"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range map[string]string{
+		"password":   "s3cr3t\n",
+		"multiline":  "line1\nmotd=injected\n",
+		"quote":      `say "hi" \ bye`,
+		"apostrophe": "it's",
+		"colon":      ":admin",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, "db", key), []byte(value), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "resolved",
+			path:    "config.txt",
+			content: "password: ${secret:db/password}\nagain: ${secret:db/password}\n",
+			want:    "password: s3cr3t\nagain: s3cr3t\n",
+		},
+		{
+			name:    "no references",
+			path:    "config.txt",
+			content: "password: plain\n",
+			want:    "password: plain\n",
+		},
+		{
+			name:    "missing key",
+			path:    "config.txt",
+			content: "password: ${secret:db/missing}\n",
+			wantErr: true,
+		},
+		{
+			name:    "multi-line properties",
+			path:    "server.properties",
+			content: "rcon.password=${secret:db/multiline}\nmotd=hello\n",
+			want:    "rcon.password=line1\\nmotd=injected\nmotd=hello\n",
+		},
+		{
+			name:    "properties with a quote",
+			path:    "server.properties",
+			content: "rcon.password=${secret:db/quote}\n",
+			want:    `rcon.password=say "hi" \\ bye` + "\n",
+		},
+		{
+			name:    "multi-line JSON",
+			path:    "ops.json",
+			content: `[{"name": "${secret:db/multiline}"}]`,
+			want:    `[{"name": "line1\nmotd=injected"}]`,
+		},
+		{
+			name:    "JSON with a quote",
+			path:    "config/mod.json",
+			content: `{"password":"${secret:db/quote}"}`,
+			want:    `{"password":"say \"hi\" \\ bye"}`,
+		},
+		{
+			name:    "JSON outside a string",
+			path:    "config/mod.json",
+			content: `{"password": ${secret:db/quote}}`,
+			wantErr: true,
+		},
+		{
+			name:    "multi-line YAML plain scalar",
+			path:    "plugins/LuckPerms/config.yml",
+			content: "data:\n  password: ${secret:db/multiline} # comment\n",
+			want:    "data:\n  password: \"line1\\nmotd=injected\" # comment\n",
+		},
+		{
+			name:    "YAML plain scalar with a quote and a colon",
+			path:    "config.yaml",
+			content: "- ${secret:db/quote}\n- ${secret:db/colon}\n",
+			want:    "- \"say \\\"hi\\\" \\\\ bye\"\n- \":admin\"\n",
+		},
+		{
+			name:    "YAML double-quoted scalar",
+			path:    "config.yml",
+			content: `url: "jdbc://${secret:db/quote}/db"`,
+			want:    `url: "jdbc://say \"hi\" \\ bye/db"`,
+		},
+		{
+			name:    "YAML single-quoted scalar",
+			path:    "config.yml",
+			content: `password: '${secret:db/apostrophe}'`,
+			want:    `password: 'it''s'`,
+		},
+		{
+			name:    "multi-line YAML single-quoted scalar",
+			path:    "config.yml",
+			content: `password: '${secret:db/multiline}'`,
+			wantErr: true,
+		},
+		{
+			name:    "YAML reference in a plain scalar",
+			path:    "config.yml",
+			content: "url: jdbc://${secret:db/password}/db\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveSecretRefs([]byte(tt.content), dir, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveSecretRefs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("ResolveSecretRefs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
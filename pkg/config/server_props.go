@@ -52,7 +52,6 @@ func DefaultServerProps() map[string]string {
 		"resource-pack":                     "",
 		"entity-broadcast-range-percentage": "100",
 		"player-idle-timeout":               "0",
-		"rcon.password":                     "minecraft",
 		"force-gamemode":                    "false",
 		"rate-limit":                        "0",
 		"hardcore":                          "false",
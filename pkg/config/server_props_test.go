@@ -79,3 +79,32 @@ func testSuccess(t *testing.T) {
 		t.Error("The output is different when nil is given.", cmp.Diff(actual, expect))
 	}
 }
+
+func TestDiffServerProps(t *testing.T) {
+	running := map[string]string{
+		"difficulty":  "easy",
+		"gamemode":    "survival",
+		"max-players": "20",
+		"motd":        "hello",
+		"level-seed":  "1",
+	}
+	desired := map[string]string{
+		"difficulty":  "hard",
+		"gamemode":    "survival",
+		"max-players": "10",
+		"motd":        "hello",
+		"white-list":  "true",
+	}
+	reloadable, restartRequired := DiffServerProps(running, desired)
+	if diff := cmp.Diff([]string{"difficulty", "white-list"}, reloadable); diff != "" {
+		t.Errorf("reloadable mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"level-seed", "max-players"}, restartRequired); diff != "" {
+		t.Errorf("restartRequired mismatch (-want +got):\n%s", diff)
+	}
+
+	reloadable, restartRequired = DiffServerProps(running, running)
+	if len(reloadable) != 0 || len(restartRequired) != 0 {
+		t.Errorf("DiffServerProps() = %v, %v, want no changes", reloadable, restartRequired)
+	}
+}
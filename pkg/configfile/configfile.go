@@ -0,0 +1,169 @@
+// Package configfile places extra config files such as paper-global.yml on the data volume.
+package configfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kmdkuk/mcing/pkg/config"
+	"github.com/kmdkuk/mcing/pkg/constants"
+)
+
+// File is a config file to place. Exactly one of Source and Inline is set.
+type File struct {
+	// Path is the path of the file relative to the data directory.
+	Path string `json:"path"`
+	// Source is a local file with the content, such as a key of a mounted ConfigMap or Secret.
+	// A missing Source is skipped because it refers to an optional key.
+	Source string `json:"source,omitempty"`
+	// Inline is the content.
+	Inline string `json:"inline,omitempty"`
+	// Merge applies the content to the existing file as a JSON merge patch instead of replacing it.
+	Merge bool `json:"merge,omitempty"`
+}
+
+// Manifest is the set of config files to place.
+type Manifest struct {
+	Files []File `json:"files,omitempty"`
+}
+
+// ReadManifest reads a Manifest from a JSON file. A missing file is an empty Manifest.
+func ReadManifest(path string) (*Manifest, error) {
+	m := &Manifest{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// State returns a string that changes when the manifest or the content of a source changes.
+func State(m *Manifest) (string, error) {
+	contents := make([]string, len(m.Files))
+	for i, f := range m.Files {
+		if f.Source == "" {
+			continue
+		}
+		data, err := os.ReadFile(f.Source)
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		contents[i] = string(data)
+	}
+	data, err := json.Marshal(struct {
+		Files    []File
+		Contents []string
+	}{m.Files, contents})
+	return string(data), err
+}
+
+// Place writes the files into dataPath. The files cannot be placed outside dataPath.
+// References to Secret keys in the content are resolved with the keys in secretsPath.
+// It places as many files as possible and returns the errors of the others.
+func Place(dataPath, secretsPath string, m *Manifest) error {
+	root, err := os.OpenRoot(dataPath)
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+
+	var errs []error
+	for _, f := range m.Files {
+		if err := place(root, secretsPath, f); err != nil {
+			errs = append(errs, fmt.Errorf("failed to place %s: %w", f.Path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func place(root *os.Root, secretsPath string, f File) error {
+	if !filepath.IsLocal(f.Path) {
+		return errors.New("the path must be relative to the data directory")
+	}
+	content := []byte(f.Inline)
+	if f.Source != "" {
+		data, err := os.ReadFile(f.Source)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		content = data
+	}
+	content, err := config.ResolveSecretRefs(content, secretsPath, f.Path)
+	if err != nil {
+		return err
+	}
+
+	if f.Merge {
+		current, err := root.ReadFile(f.Path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		content, err = merge(f.Path, current, content)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := root.MkdirAll(filepath.Dir(f.Path), 0o755); err != nil {
+		return err
+	}
+	return root.WriteFile(f.Path, content, 0o644)
+}
+
+// IsMergeable returns true if the file at path can be merged. It is decided by the extension.
+func IsMergeable(path string) bool {
+	return slices.Contains(constants.MergeableConfigExtensions, strings.ToLower(filepath.Ext(path)))
+}
+
+// merge applies patch to current as a JSON merge patch (RFC 7386).
+// YAML files are converted to JSON and back, so their comments and key order are not kept.
+func merge(path string, current, patch []byte) ([]byte, error) {
+	if !IsMergeable(path) {
+		return nil, errors.New("only JSON and YAML files can be merged")
+	}
+	isYAML := strings.ToLower(filepath.Ext(path)) != ".json"
+
+	if len(bytes.TrimSpace(current)) == 0 {
+		current = []byte("{}")
+	}
+	if isYAML {
+		var err error
+		if current, err = yaml.YAMLToJSON(current); err != nil {
+			return nil, fmt.Errorf("failed to parse the existing file: %w", err)
+		}
+		if patch, err = yaml.YAMLToJSON(patch); err != nil {
+			return nil, fmt.Errorf("failed to parse the patch: %w", err)
+		}
+	}
+	merged, err := jsonpatch.MergePatch(current, patch)
+	if err != nil {
+		return nil, err
+	}
+	if isYAML {
+		return yaml.JSONToYAML(merged)
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, merged, "", "  "); err != nil {
+		return nil, err
+	}
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
@@ -0,0 +1,167 @@
+package configfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPlace(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		file     File
+		source   string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "inline",
+			file: File{Path: "config/paper-global.yml", Inline: "proxies:\n  velocity:\n    enabled: true\n"},
+			want: "proxies:\n  velocity:\n    enabled: true\n",
+		},
+		{
+			name:     "replace",
+			existing: "a: 1\n",
+			file:     File{Path: "bukkit.yml", Source: "source"},
+			source:   "b: 2\n",
+			want:     "b: 2\n",
+		},
+		{
+			name: "secret reference",
+			file: File{Path: "plugins/LuckPerms/config.yml", Inline: "password: ${secret:db/password}\n"},
+			// A reference that is a whole YAML value is replaced with a quoted scalar.
+			want: "password: \"s3cr3t\"\n",
+		},
+		{
+			name:    "missing secret",
+			file:    File{Path: "plugins/LuckPerms/config.yml", Inline: "password: ${secret:db/missing}\n"},
+			wantErr: true,
+		},
+		{
+			name:     "merge yaml",
+			existing: "settings:\n  allow-end: true\n  connection-throttle: 4000\n",
+			file:     File{Path: "bukkit.yml", Inline: "settings:\n  allow-end: false\n", Merge: true},
+			want:     "settings:\n  allow-end: false\n  connection-throttle: 4000\n",
+		},
+		{
+			name:     "merge json",
+			existing: `{"a": 1, "b": {"c": 2, "d": 3}}`,
+			file:     File{Path: "config/x.json", Inline: `{"b": {"d": null}}`, Merge: true},
+			want:     "{\n  \"a\": 1,\n  \"b\": {\n    \"c\": 2\n  }\n}\n",
+		},
+		{
+			name: "merge into missing file",
+			file: File{Path: "config/new.yaml", Inline: "a: 1\n", Merge: true},
+			want: "a: 1\n",
+		},
+		{
+			name:    "merge toml",
+			file:    File{Path: "config/mod.toml", Inline: "a = 1\n", Merge: true},
+			wantErr: true,
+		},
+		{
+			name:    "outside the data directory",
+			file:    File{Path: "../escape.yml", Inline: "a: 1\n"},
+			wantErr: true,
+		},
+		{
+			name:    "absolute path",
+			file:    File{Path: "/etc/escape.yml", Inline: "a: 1\n"},
+			wantErr: true,
+		},
+		{
+			name: "missing optional source",
+			file: File{Path: "bukkit.yml", Source: "missing"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataPath := t.TempDir()
+			sourceDir := t.TempDir()
+			target := filepath.Join(dataPath, filepath.FromSlash(tt.file.Path))
+			if tt.existing != "" {
+				if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(target, []byte(tt.existing), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.file.Source != "" {
+				tt.file.Source = filepath.Join(sourceDir, tt.file.Source)
+				if tt.source != "" {
+					if err := os.WriteFile(tt.file.Source, []byte(tt.source), 0o600); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			secretsPath := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(secretsPath, "db"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(secretsPath, "db", "password"), []byte("s3cr3t\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			err := Place(dataPath, secretsPath, &Manifest{Files: []File{tt.file}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Place() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got, err := os.ReadFile(target)
+			if tt.want == "" {
+				if !os.IsNotExist(err) {
+					t.Errorf("the file is placed: %q, %v", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Place() wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlace_Symlink(t *testing.T) {
+	dataPath := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(dataPath, "config")); err != nil {
+		t.Fatal(err)
+	}
+	err := Place(dataPath, t.TempDir(), &Manifest{Files: []File{{Path: "config/escape.yml", Inline: "a: 1\n"}}})
+	if err == nil {
+		t.Fatal("Place() followed a symlink out of the data directory")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "escape.yml")); !os.IsNotExist(err) {
+		t.Errorf("the file is placed outside the data directory: %v", err)
+	}
+}
+
+func TestState(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "source")
+	if err := os.WriteFile(source, []byte("a: 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m := &Manifest{Files: []File{{Path: "bukkit.yml", Source: source}}}
+	before, err := State(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(source, []byte("a: 2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	after, err := State(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before == after {
+		t.Error("State() did not change when the source changed")
+	}
+}
@@ -5,6 +5,15 @@ const (
 	MetaPrefix = "mcing.kmdkuk.com/"
 	Finalizer  = MetaPrefix + "finalizer"
 
+	// ArtifactsHashAnnotation is the pod annotation that restarts the server when the mods or plugins change.
+	ArtifactsHashAnnotation = MetaPrefix + "artifacts-hash"
+	// IdleSinceAnnotation is the StatefulSet annotation that records when no players were first seen online.
+	// Hibernation scales the server to zero when the idle period has passed since this time.
+	IdleSinceAnnotation = MetaPrefix + "idle-since"
+	// SyncedStateAnnotation is the StatefulSet annotation that records the digest of the whitelist and ops
+	// last applied to the server. With auto-pause, the server is woken up only when they differ from it.
+	SyncedStateAnnotation = MetaPrefix + "synced-state"
+
 	LabelAppInstance  = "app.kubernetes.io/instance"
 	LabelAppName      = "app.kubernetes.io/name"
 	LabelAppComponent = "app.kubernetes.io/component"
@@ -12,6 +21,7 @@ const (
 
 	AppName            = "mcing"
 	AppComponentServer = "server"
+	AppComponentBackup = "backup"
 	ControllerName     = "mcing-controller"
 )
 
@@ -23,6 +33,8 @@ const (
 	InternalServerPort     = int32(25566)
 	RconPortName           = "rcon-port"
 	RconPort               = int32(25575)
+	BedrockPortName        = "bedrock-port"
+	BedrockPort            = int32(19132)
 	DataVolumeName         = "minecraft-data"
 	DataPath               = "/data"
 	ServerPropsName        = "server.properties"
@@ -37,6 +49,12 @@ const (
 	WhiteListPath          = DataPath + "/" + WhiteListName
 	ConfigVolumeName       = "config"
 	ConfigPath             = "/mcing-config"
+	// StateDirName is the directory in the data volume where mcing keeps its state.
+	StateDirName = ".mcing"
+	// StartPropsName is a copy of server.properties in the config taken when the server starts.
+	// mcing-agent compares it with the config to find the properties waiting for a restart.
+	StartPropsName = "start.properties"
+	StartPropsPath = DataPath + "/" + StateDirName + "/" + StartPropsName
 
 	LazymcVolumeName       = "lazymc"
 	LazymcConfigVolumeName = "lazymc-config"
@@ -46,6 +64,21 @@ const (
 	LazymcBinName          = "lazymc"
 	LazymcLicenseName      = "LICENSE"
 
+	ArtifactsName       = "artifacts.json"
+	ArtifactsVolumeName = "artifacts"
+	ArtifactsPath       = "/mcing-artifacts"
+
+	ConfigFilesName       = "config-files.json"
+	ConfigFilesVolumeName = "config-files"
+	ConfigFilesPath       = "/mcing-config-files"
+
+	SecretsVolumeName = "secrets"
+	SecretsPath       = "/mcing-secrets"
+
+	AutoPauseVolumeName = "autopause"
+	AutoPausePath       = "/opt/mcing-autopause"
+	AutoPauseMarkerName = "sleeping"
+
 	AgentContainerName = "mcing-agent"
 	AgentPort          = int32(9080)
 	AgentPortName      = "agent-port"
@@ -56,11 +89,27 @@ const (
 	InitCommand        = "mcing-init"
 
 	DefaultServerImage = "itzg/minecraft-server:java8"
+	// ServerImageRepository is the image used for the server software selected in spec.server.
+	ServerImageRepository = "itzg/minecraft-server"
 )
 
 const (
 	// EulaEnvName is the environment variable name for EULA.
 	EulaEnvName = "EULA"
+	// ServerTypeEnvName is the environment variable name for the server software.
+	ServerTypeEnvName = "TYPE"
+	// ServerVersionEnvName is the environment variable name for the Minecraft version.
+	ServerVersionEnvName = "VERSION"
+	// AikarFlagsEnvName is the environment variable name to enable Aikar's JVM flags.
+	AikarFlagsEnvName = "USE_AIKAR_FLAGS"
+	// MemoryEnvName is the environment variable name for the initial and maximum heap size.
+	MemoryEnvName = "MEMORY"
+	// InitMemoryEnvName is the environment variable name for the initial heap size.
+	InitMemoryEnvName = "INIT_MEMORY"
+	// MaxMemoryEnvName is the environment variable name for the maximum heap size.
+	MaxMemoryEnvName = "MAX_MEMORY"
+	// JVMOptsEnvName is the environment variable name for additional JVM arguments.
+	JVMOptsEnvName = "JVM_OPTS"
 	// RconPasswordEnvName is the environment variable name for RCON password.
 	RconPasswordEnvName = "RCON_PASSWORD"
 	// RconPasswordSecretKey is the secret key for RCON password.
@@ -71,6 +120,16 @@ const (
 const (
 	WhitelistProps = "white-list"
 	RconPortProps  = "rcon.port"
+	// RconPasswordProps is set to a reference to the RCON password Secret.
+	RconPasswordProps = "rcon.password"
+	DifficultyProps   = "difficulty"
+	GamemodeProps     = "gamemode"
+
+	LevelNameProps           = "level-name"
+	ResourcePackProps        = "resource-pack"
+	ResourcePackSHA1Props    = "resource-pack-sha1"
+	ResourcePackPromptProps  = "resource-pack-prompt"
+	RequireResourcePackProps = "require-resource-pack"
 )
 
 // mc-router.
@@ -90,3 +149,90 @@ const (
 	// MCRouterAPIPortName is the API port name for mc-router.
 	MCRouterAPIPortName = "api"
 )
+
+// external-dns.
+const (
+	// ExternalDNSHostnameAnnotation is the annotation key for the hostnames published by external-dns.
+	ExternalDNSHostnameAnnotation = "external-dns.alpha.kubernetes.io/hostname"
+	// ExternalDNSTTLAnnotation is the annotation key for the TTL of the records published by external-dns.
+	ExternalDNSTTLAnnotation = "external-dns.alpha.kubernetes.io/ttl"
+	// DNSEndpointAPIVersion is the API version of DNSEndpoint.
+	DNSEndpointAPIVersion = "externaldns.k8s.io/v1alpha1"
+	// DNSEndpointKind is the kind of DNSEndpoint.
+	DNSEndpointKind = "DNSEndpoint"
+)
+
+// VolumeSnapshot.
+const (
+	// VolumeSnapshotGroup is the API group of VolumeSnapshot.
+	VolumeSnapshotGroup = "snapshot.storage.k8s.io"
+	// VolumeSnapshotAPIVersion is the API version of VolumeSnapshot.
+	VolumeSnapshotAPIVersion = VolumeSnapshotGroup + "/v1"
+	// VolumeSnapshotKind is the kind of VolumeSnapshot.
+	VolumeSnapshotKind = "VolumeSnapshot"
+	// VolumeSnapshotListKind is the kind of the list of VolumeSnapshots.
+	VolumeSnapshotListKind = "VolumeSnapshotList"
+	// FinalSnapshotSuffix is the suffix of the snapshots taken when a server is deleted.
+	FinalSnapshotSuffix = "-final"
+	// SnapshotTypeLabel is the label to tell the scheduled snapshots from the final snapshots.
+	SnapshotTypeLabel = MetaPrefix + "snapshot-type"
+	// SnapshotTypeScheduled is the value of SnapshotTypeLabel for the snapshots taken by spec.backup.snapshot.
+	SnapshotTypeScheduled = "scheduled"
+	// SnapshotTypeFinal is the value of SnapshotTypeLabel for the snapshots taken when a server is deleted.
+	SnapshotTypeFinal = "final"
+	// SaveOffAnnotation is the annotation of a snapshot taken while auto-save of the server is disabled.
+	// It is removed when auto-save is enabled again.
+	SaveOffAnnotation = MetaPrefix + "save-off"
+)
+
+// Bedrock Edition.
+const (
+	// GeyserPluginName is the file name of the Geyser plugin installed for spec.bedrock.
+	GeyserPluginName = "Geyser-Spigot.jar"
+	// GeyserPluginURL is the URL of the latest build of the Geyser plugin.
+	GeyserPluginURL = "https://download.geysermc.org/v2/projects/geyser/versions/latest/builds/latest/downloads/spigot"
+	// FloodgatePluginName is the file name of the Floodgate plugin installed for spec.bedrock.
+	FloodgatePluginName = "floodgate-spigot.jar"
+	// FloodgatePluginURL is the URL of the latest build of the Floodgate plugin.
+	FloodgatePluginURL = "https://download.geysermc.org/v2/projects/floodgate/versions/latest/builds/latest/downloads/spigot"
+)
+
+// Velocity proxy of a MinecraftNetwork.
+const (
+	// ProxyAppComponent is the app component for the proxy.
+	ProxyAppComponent = "proxy"
+	// ProxyContainerName is the name of the proxy container.
+	ProxyContainerName = "velocity"
+	// ProxyPort is the port that the proxy listens on.
+	ProxyPort = int32(25577)
+	// ProxyPortName is the port name for the proxy.
+	ProxyPortName = "proxy-port"
+	// ProxyTypeEnvName is the environment variable name for the proxy software of itzg/mc-proxy.
+	ProxyTypeEnvName = "TYPE"
+	// ProxyTypeVelocity is the value of ProxyTypeEnvName for Velocity.
+	ProxyTypeVelocity = "VELOCITY"
+	// VelocityVersionEnvName is the environment variable name for the Velocity version.
+	VelocityVersionEnvName = "VELOCITY_VERSION"
+	// ProxyConfigVolumeName is the volume of the files copied into the proxy directory on start.
+	ProxyConfigVolumeName = "config"
+	// ProxyConfigPath is the directory whose files itzg/mc-proxy copies into the proxy directory on start.
+	ProxyConfigPath = "/config"
+	// ProxyServerVolumeName is the writable volume of the proxy directory.
+	ProxyServerVolumeName = "server"
+	// ProxyServerPath is the working directory of the proxy.
+	ProxyServerPath = "/server"
+	// VelocityConfigName is the name of the config file of Velocity.
+	VelocityConfigName = "velocity.toml"
+	// ForwardingSecretKey is the key of the forwarding secret, which is also the file name read by Velocity.
+	ForwardingSecretKey = "forwarding.secret"
+	// ProxyConfigHashAnnotation is the pod annotation that restarts the proxy when its config changes.
+	ProxyConfigHashAnnotation = MetaPrefix + "proxy-config-hash"
+	// PaperGlobalConfigPath is the config file of Paper that enables the modern forwarding of Velocity.
+	PaperGlobalConfigPath = "config/paper-global.yml"
+	// OnlineModeProps is the server.properties key for authenticating players.
+	OnlineModeProps = "online-mode"
+)
+
+// MergeableConfigExtensions are the extensions of the extra config files that can be merged into the existing file.
+// Both the validation of the Minecraft and mcing-init use this list.
+var MergeableConfigExtensions = []string{".json", ".yaml", ".yml"}
@@ -0,0 +1,173 @@
+// Package mcrouter implements a client of the API of mc-router.
+package mcrouter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/kmdkuk/mcing/pkg/constants"
+)
+
+const (
+	defaultTimeout = 5 * time.Second
+
+	// BackendConnectionsMetric is the counter of the connections that mc-router has made to the backends.
+	// It is labeled with the requested hostname.
+	BackendConnectionsMetric = "mc_router_backend_connections"
+
+	hostLabel = "host"
+)
+
+// Client queries and updates the routes of an mc-router through its API.
+type Client interface {
+	// Routes returns the backends of mc-router by the hostnames, which are lowercased.
+	Routes(ctx context.Context) (map[string]string, error)
+	// CreateRoute routes host to backend. It replaces the backend if host is already routed.
+	CreateRoute(ctx context.Context, host, backend string) error
+	// DeleteRoute removes the route of host.
+	DeleteRoute(ctx context.Context, host string) error
+	// Connections returns the number of connections routed to each hostname since mc-router started.
+	Connections(ctx context.Context) (map[string]float64, error)
+}
+
+// Factory represents the interface of a factory to create Client.
+type Factory interface {
+	New(podIP string) Client
+}
+
+// NewFactory returns a new Factory.
+func NewFactory() Factory {
+	return defaultFactory{}
+}
+
+type defaultFactory struct{}
+
+var _ Factory = defaultFactory{}
+
+func (f defaultFactory) New(podIP string) Client {
+	return &httpClient{
+		baseURL: "http://" + net.JoinHostPort(podIP, strconv.Itoa(int(constants.MCRouterAPIPort))),
+		client:  &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+type httpClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+func (c *httpClient) get(ctx context.Context, path string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		_ = res.Body.Close()
+		return nil, fmt.Errorf("GET %s returned %s", path, res.Status)
+	}
+	return res.Body, nil
+}
+
+func (c *httpClient) do(ctx context.Context, method, path string, body io.Reader) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%s %s returned %s", method, path, res.Status)
+	}
+	return nil
+}
+
+func (c *httpClient) Routes(ctx context.Context) (map[string]string, error) {
+	body, err := c.get(ctx, "/routes")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+
+	routes := map[string]string{}
+	if err := json.NewDecoder(body).Decode(&routes); err != nil {
+		return nil, fmt.Errorf("failed to parse the routes: %w", err)
+	}
+	lowered := make(map[string]string, len(routes))
+	for host, backend := range routes {
+		lowered[strings.ToLower(host)] = backend
+	}
+	return lowered, nil
+}
+
+func (c *httpClient) CreateRoute(ctx context.Context, host, backend string) error {
+	body, err := json.Marshal(map[string]string{"serverAddress": host, "backend": backend})
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodPost, "/routes", bytes.NewReader(body))
+}
+
+func (c *httpClient) DeleteRoute(ctx context.Context, host string) error {
+	return c.do(ctx, http.MethodDelete, "/routes/"+url.PathEscape(host), nil)
+}
+
+func (c *httpClient) Connections(ctx context.Context) (map[string]float64, error) {
+	body, err := c.get(ctx, "/metrics")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+	return ParseConnections(body)
+}
+
+// ParseConnections reads the backend connections by the hostnames from metrics in the Prometheus text format.
+// It returns an empty map if mc-router has not made any connection yet.
+func ParseConnections(r io.Reader) (map[string]float64, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the metrics: %w", err)
+	}
+
+	conns := map[string]float64{}
+	family, ok := families[BackendConnectionsMetric]
+	if !ok {
+		return conns, nil
+	}
+	for _, m := range family.GetMetric() {
+		for _, l := range m.GetLabel() {
+			if l.GetName() == hostLabel {
+				// The value is untyped when the metrics have no TYPE line.
+				conns[strings.ToLower(l.GetValue())] += m.GetCounter().GetValue() + m.GetUntyped().GetValue()
+			}
+		}
+	}
+	return conns, nil
+}
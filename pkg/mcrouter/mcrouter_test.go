@@ -0,0 +1,124 @@
+package mcrouter
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseConnections(t *testing.T) {
+	tests := []struct {
+		name    string
+		metrics string
+		want    map[string]float64
+		wantErr bool
+	}{
+		{
+			name: "connections by host",
+			metrics: `# HELP mc_router_backend_connections The total number of backend connections
+# TYPE mc_router_backend_connections counter
+mc_router_backend_connections{host="lobby.default.minecraft.local"} 3
+mc_router_backend_connections{host="Survival.example.com"} 1
+# HELP mc_router_errors The total number of errors
+# TYPE mc_router_errors counter
+mc_router_errors{type="backend_failed"} 2
+`,
+			want: map[string]float64{
+				"lobby.default.minecraft.local": 3,
+				"survival.example.com":          1,
+			},
+		},
+		{
+			name: "no connections yet",
+			metrics: `# TYPE mc_router_errors counter
+mc_router_errors{type="backend_failed"} 2
+`,
+			want: map[string]float64{},
+		},
+		{
+			name:    "invalid",
+			metrics: "mc_router_backend_connections{host=\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseConnections(strings.NewReader(tt.metrics))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseConnections() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !maps.Equal(got, tt.want) {
+				t.Errorf("ParseConnections() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/routes", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"Lobby.default.minecraft.local":"mcing-lobby.default.svc:25565"}`))
+	})
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("mc_router_backend_connections{host=\"lobby.default.minecraft.local\"} 5\n"))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := &httpClient{baseURL: srv.URL, client: srv.Client()}
+	routes, err := c.Routes(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRoutes := map[string]string{"lobby.default.minecraft.local": "mcing-lobby.default.svc:25565"}
+	if !maps.Equal(routes, wantRoutes) {
+		t.Errorf("Routes() = %v, want %v", routes, wantRoutes)
+	}
+
+	conns, err := c.Connections(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantConns := map[string]float64{"lobby.default.minecraft.local": 5}
+	if !maps.Equal(conns, wantConns) {
+		t.Errorf("Connections() = %v, want %v", conns, wantConns)
+	}
+
+	var created, deleted string
+	mux.HandleFunc("POST /routes", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		created = body["serverAddress"] + "=" + body["backend"]
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("DELETE /routes/{host}", func(_ http.ResponseWriter, r *http.Request) {
+		deleted = r.PathValue("host")
+	})
+	if err := c.CreateRoute(context.Background(), "hub.example.com", "mcing-hub.default.svc:25565"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "hub.example.com=mcing-hub.default.svc:25565"; created != want {
+		t.Errorf("CreateRoute() sent %q, want %q", created, want)
+	}
+	if err := c.DeleteRoute(context.Background(), "hub.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "hub.example.com"; deleted != want {
+		t.Errorf("DeleteRoute() deleted %q, want %q", deleted, want)
+	}
+
+	notFound := &httpClient{baseURL: srv.URL + "/missing", client: srv.Client()}
+	if _, err := notFound.Routes(context.Background()); err == nil {
+		t.Error("Routes() should fail when the API returns an error")
+	}
+	if err := notFound.DeleteRoute(context.Background(), "hub.example.com"); err == nil {
+		t.Error("DeleteRoute() should fail when the API returns an error")
+	}
+}
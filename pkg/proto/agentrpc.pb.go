@@ -489,6 +489,586 @@ func (*SaveOnResponse) Descriptor() ([]byte, []int) {
 	return file_pkg_proto_agentrpc_proto_rawDescGZIP(), []int{11}
 }
 
+// *
+// WakeRequest is the request message to start the server process stopped by lazymc.
+type WakeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WakeRequest) Reset() {
+	*x = WakeRequest{}
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WakeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WakeRequest) ProtoMessage() {}
+
+func (x *WakeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WakeRequest.ProtoReflect.Descriptor instead.
+func (*WakeRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_agentrpc_proto_rawDescGZIP(), []int{12}
+}
+
+// *
+// WakeResponse is the response message of Wake
+type WakeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WasSleeping   bool                   `protobuf:"varint,1,opt,name=was_sleeping,json=wasSleeping,proto3" json:"was_sleeping,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WakeResponse) Reset() {
+	*x = WakeResponse{}
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WakeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WakeResponse) ProtoMessage() {}
+
+func (x *WakeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WakeResponse.ProtoReflect.Descriptor instead.
+func (*WakeResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_agentrpc_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WakeResponse) GetWasSleeping() bool {
+	if x != nil {
+		return x.WasSleeping
+	}
+	return false
+}
+
+// *
+// SleepRequest is the request message to stop the server process and hand it back to lazymc.
+type SleepRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SleepRequest) Reset() {
+	*x = SleepRequest{}
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SleepRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SleepRequest) ProtoMessage() {}
+
+func (x *SleepRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SleepRequest.ProtoReflect.Descriptor instead.
+func (*SleepRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_agentrpc_proto_rawDescGZIP(), []int{14}
+}
+
+// *
+// SleepResponse is the response message of Sleep
+type SleepResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WasSleeping   bool                   `protobuf:"varint,1,opt,name=was_sleeping,json=wasSleeping,proto3" json:"was_sleeping,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SleepResponse) Reset() {
+	*x = SleepResponse{}
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SleepResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SleepResponse) ProtoMessage() {}
+
+func (x *SleepResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SleepResponse.ProtoReflect.Descriptor instead.
+func (*SleepResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_agentrpc_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SleepResponse) GetWasSleeping() bool {
+	if x != nil {
+		return x.WasSleeping
+	}
+	return false
+}
+
+// *
+// PendingRestartRequest is the request message to get the properties waiting for a restart.
+type PendingRestartRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PendingRestartRequest) Reset() {
+	*x = PendingRestartRequest{}
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PendingRestartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PendingRestartRequest) ProtoMessage() {}
+
+func (x *PendingRestartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PendingRestartRequest.ProtoReflect.Descriptor instead.
+func (*PendingRestartRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_agentrpc_proto_rawDescGZIP(), []int{16}
+}
+
+// *
+// PendingRestartResponse is the response message of PendingRestart
+type PendingRestartResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// properties are the keys of the changed properties in server.properties that take effect after a restart.
+	Properties    []string `protobuf:"bytes,1,rep,name=properties,proto3" json:"properties,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PendingRestartResponse) Reset() {
+	*x = PendingRestartResponse{}
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PendingRestartResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PendingRestartResponse) ProtoMessage() {}
+
+func (x *PendingRestartResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PendingRestartResponse.ProtoReflect.Descriptor instead.
+func (*PendingRestartResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_agentrpc_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PendingRestartResponse) GetProperties() []string {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+// *
+// SyncStatusRequest is the request message to get the results of syncing the config files to the server.
+type SyncStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncStatusRequest) Reset() {
+	*x = SyncStatusRequest{}
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncStatusRequest) ProtoMessage() {}
+
+func (x *SyncStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncStatusRequest.ProtoReflect.Descriptor instead.
+func (*SyncStatusRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_agentrpc_proto_rawDescGZIP(), []int{18}
+}
+
+// *
+// FileSyncResult is the result of the last sync of a config file.
+type FileSyncResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// name is the file name.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// synced_at is the time of the last successful sync in Unix seconds.
+	SyncedAt int64 `protobuf:"varint,2,opt,name=synced_at,json=syncedAt,proto3" json:"synced_at,omitempty"`
+	// error is the error of the last sync. It is empty when the sync succeeded.
+	Error         string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileSyncResult) Reset() {
+	*x = FileSyncResult{}
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileSyncResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileSyncResult) ProtoMessage() {}
+
+func (x *FileSyncResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileSyncResult.ProtoReflect.Descriptor instead.
+func (*FileSyncResult) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_agentrpc_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *FileSyncResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FileSyncResult) GetSyncedAt() int64 {
+	if x != nil {
+		return x.SyncedAt
+	}
+	return 0
+}
+
+func (x *FileSyncResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// *
+// SyncStatusResponse is the response message of SyncStatus
+type SyncStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Files         []*FileSyncResult      `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncStatusResponse) Reset() {
+	*x = SyncStatusResponse{}
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncStatusResponse) ProtoMessage() {}
+
+func (x *SyncStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncStatusResponse.ProtoReflect.Descriptor instead.
+func (*SyncStatusResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_agentrpc_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SyncStatusResponse) GetFiles() []*FileSyncResult {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+// *
+// AnnounceRequest is the request message to broadcast a message to the players by `/say` via rcon.
+type AnnounceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnnounceRequest) Reset() {
+	*x = AnnounceRequest{}
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnnounceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnnounceRequest) ProtoMessage() {}
+
+func (x *AnnounceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnnounceRequest.ProtoReflect.Descriptor instead.
+func (*AnnounceRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_agentrpc_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *AnnounceRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// *
+// AnnounceResponse is the response message of Announce
+type AnnounceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnnounceResponse) Reset() {
+	*x = AnnounceResponse{}
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnnounceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnnounceResponse) ProtoMessage() {}
+
+func (x *AnnounceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnnounceResponse.ProtoReflect.Descriptor instead.
+func (*AnnounceResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_agentrpc_proto_rawDescGZIP(), []int{22}
+}
+
+// *
+// DiskUsageRequest is the request message to get the usage of the file system of the data directory.
+type DiskUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiskUsageRequest) Reset() {
+	*x = DiskUsageRequest{}
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiskUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiskUsageRequest) ProtoMessage() {}
+
+func (x *DiskUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiskUsageRequest.ProtoReflect.Descriptor instead.
+func (*DiskUsageRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_agentrpc_proto_rawDescGZIP(), []int{23}
+}
+
+// *
+// DiskUsageResponse is the response message of DiskUsage
+type DiskUsageResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// used_bytes is the size of the space used in the file system.
+	UsedBytes uint64 `protobuf:"varint,1,opt,name=used_bytes,json=usedBytes,proto3" json:"used_bytes,omitempty"`
+	// total_bytes is the size of the file system.
+	TotalBytes    uint64 `protobuf:"varint,2,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiskUsageResponse) Reset() {
+	*x = DiskUsageResponse{}
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiskUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiskUsageResponse) ProtoMessage() {}
+
+func (x *DiskUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_agentrpc_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiskUsageResponse.ProtoReflect.Descriptor instead.
+func (*DiskUsageResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_agentrpc_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *DiskUsageResponse) GetUsedBytes() uint64 {
+	if x != nil {
+		return x.UsedBytes
+	}
+	return 0
+}
+
+func (x *DiskUsageResponse) GetTotalBytes() uint64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
 var File_pkg_proto_agentrpc_proto protoreflect.FileDescriptor
 
 const file_pkg_proto_agentrpc_proto_rawDesc = "" +
@@ -508,14 +1088,48 @@ const file_pkg_proto_agentrpc_proto_rawDesc = "" +
 	"\x13SaveAllFlushRequest\"\x16\n" +
 	"\x14SaveAllFlushResponse\"\x0f\n" +
 	"\rSaveOnRequest\"\x10\n" +
-	"\x0eSaveOnResponse2\xfe\x02\n" +
+	"\x0eSaveOnResponse\"\r\n" +
+	"\vWakeRequest\"1\n" +
+	"\fWakeResponse\x12!\n" +
+	"\fwas_sleeping\x18\x01 \x01(\bR\vwasSleeping\"\x0e\n" +
+	"\fSleepRequest\"2\n" +
+	"\rSleepResponse\x12!\n" +
+	"\fwas_sleeping\x18\x01 \x01(\bR\vwasSleeping\"\x17\n" +
+	"\x15PendingRestartRequest\"8\n" +
+	"\x16PendingRestartResponse\x12\x1e\n" +
+	"\n" +
+	"properties\x18\x01 \x03(\tR\n" +
+	"properties\"\x13\n" +
+	"\x11SyncStatusRequest\"W\n" +
+	"\x0eFileSyncResult\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n" +
+	"\tsynced_at\x18\x02 \x01(\x03R\bsyncedAt\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"A\n" +
+	"\x12SyncStatusResponse\x12+\n" +
+	"\x05files\x18\x01 \x03(\v2\x15.mcing.FileSyncResultR\x05files\"+\n" +
+	"\x0fAnnounceRequest\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\x12\n" +
+	"\x10AnnounceResponse\"\x12\n" +
+	"\x10DiskUsageRequest\"S\n" +
+	"\x11DiskUsageResponse\x12\x1d\n" +
+	"\n" +
+	"used_bytes\x18\x01 \x01(\x04R\tusedBytes\x12\x1f\n" +
+	"\vtotal_bytes\x18\x02 \x01(\x04R\n" +
+	"totalBytes2\xf2\x05\n" +
 	"\x05Agent\x125\n" +
 	"\x06Reload\x12\x14.mcing.ReloadRequest\x1a\x15.mcing.ReloadResponse\x12J\n" +
 	"\rSyncWhitelist\x12\x1b.mcing.SyncWhitelistRequest\x1a\x1c.mcing.SyncWhitelistResponse\x128\n" +
 	"\aSyncOps\x12\x15.mcing.SyncOpsRequest\x1a\x16.mcing.SyncOpsResponse\x128\n" +
 	"\aSaveOff\x12\x15.mcing.SaveOffRequest\x1a\x16.mcing.SaveOffResponse\x12G\n" +
 	"\fSaveAllFlush\x12\x1a.mcing.SaveAllFlushRequest\x1a\x1b.mcing.SaveAllFlushResponse\x125\n" +
-	"\x06SaveOn\x12\x14.mcing.SaveOnRequest\x1a\x15.mcing.SaveOnResponseB#Z!github.com/kmdkuk/mcing/pkg/protob\x06proto3"
+	"\x06SaveOn\x12\x14.mcing.SaveOnRequest\x1a\x15.mcing.SaveOnResponse\x12/\n" +
+	"\x04Wake\x12\x12.mcing.WakeRequest\x1a\x13.mcing.WakeResponse\x122\n" +
+	"\x05Sleep\x12\x13.mcing.SleepRequest\x1a\x14.mcing.SleepResponse\x12M\n" +
+	"\x0ePendingRestart\x12\x1c.mcing.PendingRestartRequest\x1a\x1d.mcing.PendingRestartResponse\x12A\n" +
+	"\n" +
+	"SyncStatus\x12\x18.mcing.SyncStatusRequest\x1a\x19.mcing.SyncStatusResponse\x12;\n" +
+	"\bAnnounce\x12\x16.mcing.AnnounceRequest\x1a\x17.mcing.AnnounceResponse\x12>\n" +
+	"\tDiskUsage\x12\x17.mcing.DiskUsageRequest\x1a\x18.mcing.DiskUsageResponseB#Z!github.com/kmdkuk/mcing/pkg/protob\x06proto3"
 
 var (
 	file_pkg_proto_agentrpc_proto_rawDescOnce sync.Once
@@ -529,39 +1143,65 @@ func file_pkg_proto_agentrpc_proto_rawDescGZIP() []byte {
 	return file_pkg_proto_agentrpc_proto_rawDescData
 }
 
-var file_pkg_proto_agentrpc_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_pkg_proto_agentrpc_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
 var file_pkg_proto_agentrpc_proto_goTypes = []any{
-	(*ReloadRequest)(nil),         // 0: mcing.ReloadRequest
-	(*ReloadResponse)(nil),        // 1: mcing.ReloadResponse
-	(*SyncWhitelistRequest)(nil),  // 2: mcing.SyncWhitelistRequest
-	(*SyncWhitelistResponse)(nil), // 3: mcing.SyncWhitelistResponse
-	(*SyncOpsRequest)(nil),        // 4: mcing.SyncOpsRequest
-	(*SyncOpsResponse)(nil),       // 5: mcing.SyncOpsResponse
-	(*SaveOffRequest)(nil),        // 6: mcing.SaveOffRequest
-	(*SaveOffResponse)(nil),       // 7: mcing.SaveOffResponse
-	(*SaveAllFlushRequest)(nil),   // 8: mcing.SaveAllFlushRequest
-	(*SaveAllFlushResponse)(nil),  // 9: mcing.SaveAllFlushResponse
-	(*SaveOnRequest)(nil),         // 10: mcing.SaveOnRequest
-	(*SaveOnResponse)(nil),        // 11: mcing.SaveOnResponse
+	(*ReloadRequest)(nil),          // 0: mcing.ReloadRequest
+	(*ReloadResponse)(nil),         // 1: mcing.ReloadResponse
+	(*SyncWhitelistRequest)(nil),   // 2: mcing.SyncWhitelistRequest
+	(*SyncWhitelistResponse)(nil),  // 3: mcing.SyncWhitelistResponse
+	(*SyncOpsRequest)(nil),         // 4: mcing.SyncOpsRequest
+	(*SyncOpsResponse)(nil),        // 5: mcing.SyncOpsResponse
+	(*SaveOffRequest)(nil),         // 6: mcing.SaveOffRequest
+	(*SaveOffResponse)(nil),        // 7: mcing.SaveOffResponse
+	(*SaveAllFlushRequest)(nil),    // 8: mcing.SaveAllFlushRequest
+	(*SaveAllFlushResponse)(nil),   // 9: mcing.SaveAllFlushResponse
+	(*SaveOnRequest)(nil),          // 10: mcing.SaveOnRequest
+	(*SaveOnResponse)(nil),         // 11: mcing.SaveOnResponse
+	(*WakeRequest)(nil),            // 12: mcing.WakeRequest
+	(*WakeResponse)(nil),           // 13: mcing.WakeResponse
+	(*SleepRequest)(nil),           // 14: mcing.SleepRequest
+	(*SleepResponse)(nil),          // 15: mcing.SleepResponse
+	(*PendingRestartRequest)(nil),  // 16: mcing.PendingRestartRequest
+	(*PendingRestartResponse)(nil), // 17: mcing.PendingRestartResponse
+	(*SyncStatusRequest)(nil),      // 18: mcing.SyncStatusRequest
+	(*FileSyncResult)(nil),         // 19: mcing.FileSyncResult
+	(*SyncStatusResponse)(nil),     // 20: mcing.SyncStatusResponse
+	(*AnnounceRequest)(nil),        // 21: mcing.AnnounceRequest
+	(*AnnounceResponse)(nil),       // 22: mcing.AnnounceResponse
+	(*DiskUsageRequest)(nil),       // 23: mcing.DiskUsageRequest
+	(*DiskUsageResponse)(nil),      // 24: mcing.DiskUsageResponse
 }
 var file_pkg_proto_agentrpc_proto_depIdxs = []int32{
-	0,  // 0: mcing.Agent.Reload:input_type -> mcing.ReloadRequest
-	2,  // 1: mcing.Agent.SyncWhitelist:input_type -> mcing.SyncWhitelistRequest
-	4,  // 2: mcing.Agent.SyncOps:input_type -> mcing.SyncOpsRequest
-	6,  // 3: mcing.Agent.SaveOff:input_type -> mcing.SaveOffRequest
-	8,  // 4: mcing.Agent.SaveAllFlush:input_type -> mcing.SaveAllFlushRequest
-	10, // 5: mcing.Agent.SaveOn:input_type -> mcing.SaveOnRequest
-	1,  // 6: mcing.Agent.Reload:output_type -> mcing.ReloadResponse
-	3,  // 7: mcing.Agent.SyncWhitelist:output_type -> mcing.SyncWhitelistResponse
-	5,  // 8: mcing.Agent.SyncOps:output_type -> mcing.SyncOpsResponse
-	7,  // 9: mcing.Agent.SaveOff:output_type -> mcing.SaveOffResponse
-	9,  // 10: mcing.Agent.SaveAllFlush:output_type -> mcing.SaveAllFlushResponse
-	11, // 11: mcing.Agent.SaveOn:output_type -> mcing.SaveOnResponse
-	6,  // [6:12] is the sub-list for method output_type
-	0,  // [0:6] is the sub-list for method input_type
-	0,  // [0:0] is the sub-list for extension type_name
-	0,  // [0:0] is the sub-list for extension extendee
-	0,  // [0:0] is the sub-list for field type_name
+	19, // 0: mcing.SyncStatusResponse.files:type_name -> mcing.FileSyncResult
+	0,  // 1: mcing.Agent.Reload:input_type -> mcing.ReloadRequest
+	2,  // 2: mcing.Agent.SyncWhitelist:input_type -> mcing.SyncWhitelistRequest
+	4,  // 3: mcing.Agent.SyncOps:input_type -> mcing.SyncOpsRequest
+	6,  // 4: mcing.Agent.SaveOff:input_type -> mcing.SaveOffRequest
+	8,  // 5: mcing.Agent.SaveAllFlush:input_type -> mcing.SaveAllFlushRequest
+	10, // 6: mcing.Agent.SaveOn:input_type -> mcing.SaveOnRequest
+	12, // 7: mcing.Agent.Wake:input_type -> mcing.WakeRequest
+	14, // 8: mcing.Agent.Sleep:input_type -> mcing.SleepRequest
+	16, // 9: mcing.Agent.PendingRestart:input_type -> mcing.PendingRestartRequest
+	18, // 10: mcing.Agent.SyncStatus:input_type -> mcing.SyncStatusRequest
+	21, // 11: mcing.Agent.Announce:input_type -> mcing.AnnounceRequest
+	23, // 12: mcing.Agent.DiskUsage:input_type -> mcing.DiskUsageRequest
+	1,  // 13: mcing.Agent.Reload:output_type -> mcing.ReloadResponse
+	3,  // 14: mcing.Agent.SyncWhitelist:output_type -> mcing.SyncWhitelistResponse
+	5,  // 15: mcing.Agent.SyncOps:output_type -> mcing.SyncOpsResponse
+	7,  // 16: mcing.Agent.SaveOff:output_type -> mcing.SaveOffResponse
+	9,  // 17: mcing.Agent.SaveAllFlush:output_type -> mcing.SaveAllFlushResponse
+	11, // 18: mcing.Agent.SaveOn:output_type -> mcing.SaveOnResponse
+	13, // 19: mcing.Agent.Wake:output_type -> mcing.WakeResponse
+	15, // 20: mcing.Agent.Sleep:output_type -> mcing.SleepResponse
+	17, // 21: mcing.Agent.PendingRestart:output_type -> mcing.PendingRestartResponse
+	20, // 22: mcing.Agent.SyncStatus:output_type -> mcing.SyncStatusResponse
+	22, // 23: mcing.Agent.Announce:output_type -> mcing.AnnounceResponse
+	24, // 24: mcing.Agent.DiskUsage:output_type -> mcing.DiskUsageResponse
+	13, // [13:25] is the sub-list for method output_type
+	1,  // [1:13] is the sub-list for method input_type
+	1,  // [1:1] is the sub-list for extension type_name
+	1,  // [1:1] is the sub-list for extension extendee
+	0,  // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_pkg_proto_agentrpc_proto_init() }
@@ -575,7 +1215,7 @@ func file_pkg_proto_agentrpc_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_proto_agentrpc_proto_rawDesc), len(file_pkg_proto_agentrpc_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   25,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
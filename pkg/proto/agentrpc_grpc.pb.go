@@ -19,12 +19,18 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Agent_Reload_FullMethodName        = "/mcing.Agent/Reload"
-	Agent_SyncWhitelist_FullMethodName = "/mcing.Agent/SyncWhitelist"
-	Agent_SyncOps_FullMethodName       = "/mcing.Agent/SyncOps"
-	Agent_SaveOff_FullMethodName       = "/mcing.Agent/SaveOff"
-	Agent_SaveAllFlush_FullMethodName  = "/mcing.Agent/SaveAllFlush"
-	Agent_SaveOn_FullMethodName        = "/mcing.Agent/SaveOn"
+	Agent_Reload_FullMethodName         = "/mcing.Agent/Reload"
+	Agent_SyncWhitelist_FullMethodName  = "/mcing.Agent/SyncWhitelist"
+	Agent_SyncOps_FullMethodName        = "/mcing.Agent/SyncOps"
+	Agent_SaveOff_FullMethodName        = "/mcing.Agent/SaveOff"
+	Agent_SaveAllFlush_FullMethodName   = "/mcing.Agent/SaveAllFlush"
+	Agent_SaveOn_FullMethodName         = "/mcing.Agent/SaveOn"
+	Agent_Wake_FullMethodName           = "/mcing.Agent/Wake"
+	Agent_Sleep_FullMethodName          = "/mcing.Agent/Sleep"
+	Agent_PendingRestart_FullMethodName = "/mcing.Agent/PendingRestart"
+	Agent_SyncStatus_FullMethodName     = "/mcing.Agent/SyncStatus"
+	Agent_Announce_FullMethodName       = "/mcing.Agent/Announce"
+	Agent_DiskUsage_FullMethodName      = "/mcing.Agent/DiskUsage"
 )
 
 // AgentClient is the client API for Agent service.
@@ -40,6 +46,12 @@ type AgentClient interface {
 	SaveOff(ctx context.Context, in *SaveOffRequest, opts ...grpc.CallOption) (*SaveOffResponse, error)
 	SaveAllFlush(ctx context.Context, in *SaveAllFlushRequest, opts ...grpc.CallOption) (*SaveAllFlushResponse, error)
 	SaveOn(ctx context.Context, in *SaveOnRequest, opts ...grpc.CallOption) (*SaveOnResponse, error)
+	Wake(ctx context.Context, in *WakeRequest, opts ...grpc.CallOption) (*WakeResponse, error)
+	Sleep(ctx context.Context, in *SleepRequest, opts ...grpc.CallOption) (*SleepResponse, error)
+	PendingRestart(ctx context.Context, in *PendingRestartRequest, opts ...grpc.CallOption) (*PendingRestartResponse, error)
+	SyncStatus(ctx context.Context, in *SyncStatusRequest, opts ...grpc.CallOption) (*SyncStatusResponse, error)
+	Announce(ctx context.Context, in *AnnounceRequest, opts ...grpc.CallOption) (*AnnounceResponse, error)
+	DiskUsage(ctx context.Context, in *DiskUsageRequest, opts ...grpc.CallOption) (*DiskUsageResponse, error)
 }
 
 type agentClient struct {
@@ -110,6 +122,66 @@ func (c *agentClient) SaveOn(ctx context.Context, in *SaveOnRequest, opts ...grp
 	return out, nil
 }
 
+func (c *agentClient) Wake(ctx context.Context, in *WakeRequest, opts ...grpc.CallOption) (*WakeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WakeResponse)
+	err := c.cc.Invoke(ctx, Agent_Wake_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) Sleep(ctx context.Context, in *SleepRequest, opts ...grpc.CallOption) (*SleepResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SleepResponse)
+	err := c.cc.Invoke(ctx, Agent_Sleep_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) PendingRestart(ctx context.Context, in *PendingRestartRequest, opts ...grpc.CallOption) (*PendingRestartResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PendingRestartResponse)
+	err := c.cc.Invoke(ctx, Agent_PendingRestart_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) SyncStatus(ctx context.Context, in *SyncStatusRequest, opts ...grpc.CallOption) (*SyncStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SyncStatusResponse)
+	err := c.cc.Invoke(ctx, Agent_SyncStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) Announce(ctx context.Context, in *AnnounceRequest, opts ...grpc.CallOption) (*AnnounceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnnounceResponse)
+	err := c.cc.Invoke(ctx, Agent_Announce_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) DiskUsage(ctx context.Context, in *DiskUsageRequest, opts ...grpc.CallOption) (*DiskUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DiskUsageResponse)
+	err := c.cc.Invoke(ctx, Agent_DiskUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AgentServer is the server API for Agent service.
 // All implementations must embed UnimplementedAgentServer
 // for forward compatibility.
@@ -123,6 +195,12 @@ type AgentServer interface {
 	SaveOff(context.Context, *SaveOffRequest) (*SaveOffResponse, error)
 	SaveAllFlush(context.Context, *SaveAllFlushRequest) (*SaveAllFlushResponse, error)
 	SaveOn(context.Context, *SaveOnRequest) (*SaveOnResponse, error)
+	Wake(context.Context, *WakeRequest) (*WakeResponse, error)
+	Sleep(context.Context, *SleepRequest) (*SleepResponse, error)
+	PendingRestart(context.Context, *PendingRestartRequest) (*PendingRestartResponse, error)
+	SyncStatus(context.Context, *SyncStatusRequest) (*SyncStatusResponse, error)
+	Announce(context.Context, *AnnounceRequest) (*AnnounceResponse, error)
+	DiskUsage(context.Context, *DiskUsageRequest) (*DiskUsageResponse, error)
 	mustEmbedUnimplementedAgentServer()
 }
 
@@ -151,6 +229,24 @@ func (UnimplementedAgentServer) SaveAllFlush(context.Context, *SaveAllFlushReque
 func (UnimplementedAgentServer) SaveOn(context.Context, *SaveOnRequest) (*SaveOnResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method SaveOn not implemented")
 }
+func (UnimplementedAgentServer) Wake(context.Context, *WakeRequest) (*WakeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Wake not implemented")
+}
+func (UnimplementedAgentServer) Sleep(context.Context, *SleepRequest) (*SleepResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Sleep not implemented")
+}
+func (UnimplementedAgentServer) PendingRestart(context.Context, *PendingRestartRequest) (*PendingRestartResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PendingRestart not implemented")
+}
+func (UnimplementedAgentServer) SyncStatus(context.Context, *SyncStatusRequest) (*SyncStatusResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SyncStatus not implemented")
+}
+func (UnimplementedAgentServer) Announce(context.Context, *AnnounceRequest) (*AnnounceResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Announce not implemented")
+}
+func (UnimplementedAgentServer) DiskUsage(context.Context, *DiskUsageRequest) (*DiskUsageResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DiskUsage not implemented")
+}
 func (UnimplementedAgentServer) mustEmbedUnimplementedAgentServer() {}
 func (UnimplementedAgentServer) testEmbeddedByValue()               {}
 
@@ -280,6 +376,114 @@ func _Agent_SaveOn_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Agent_Wake_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).Wake(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_Wake_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).Wake(ctx, req.(*WakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_Sleep_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SleepRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).Sleep(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_Sleep_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).Sleep(ctx, req.(*SleepRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_PendingRestart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PendingRestartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).PendingRestart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_PendingRestart_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).PendingRestart(ctx, req.(*PendingRestartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_SyncStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).SyncStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_SyncStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).SyncStatus(ctx, req.(*SyncStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_Announce_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnnounceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).Announce(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_Announce_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).Announce(ctx, req.(*AnnounceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_DiskUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiskUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).DiskUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_DiskUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).DiskUsage(ctx, req.(*DiskUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Agent_ServiceDesc is the grpc.ServiceDesc for Agent service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -311,6 +515,30 @@ var Agent_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SaveOn",
 			Handler:    _Agent_SaveOn_Handler,
 		},
+		{
+			MethodName: "Wake",
+			Handler:    _Agent_Wake_Handler,
+		},
+		{
+			MethodName: "Sleep",
+			Handler:    _Agent_Sleep_Handler,
+		},
+		{
+			MethodName: "PendingRestart",
+			Handler:    _Agent_PendingRestart_Handler,
+		},
+		{
+			MethodName: "SyncStatus",
+			Handler:    _Agent_SyncStatus_Handler,
+		},
+		{
+			MethodName: "Announce",
+			Handler:    _Agent_Announce_Handler,
+		},
+		{
+			MethodName: "DiskUsage",
+			Handler:    _Agent_DiskUsage_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/proto/agentrpc.proto",
@@ -1,8 +1,10 @@
 package rcon
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/james4k/rcon"
 )
@@ -22,6 +24,95 @@ func NewConn(hostPort, password string) (*rcon.RemoteConsole, error) {
 	return remoteConsole, nil
 }
 
+// ReconnectingConsole is a Console that dials the RCON server on first use and
+// dials again after an I/O error.
+// When auto-pause is enabled the backend server is stopped and started by lazymc,
+// so a single connection opened at agent startup does not survive the first sleep.
+type ReconnectingConsole struct {
+	mu       sync.Mutex
+	hostPort string
+	password string
+	dial     func(hostPort, password string) (Console, error)
+	conn     Console
+}
+
+var _ Console = &ReconnectingConsole{} //nolint:exhaustruct // interface check
+
+// NewReconnectingConsole creates a new ReconnectingConsole. It does not connect until it is used.
+func NewReconnectingConsole(hostPort, password string) *ReconnectingConsole {
+	return &ReconnectingConsole{ //nolint:exhaustruct // conn is set lazily
+		hostPort: hostPort,
+		password: password,
+		dial: func(hostPort, password string) (Console, error) {
+			return NewConn(hostPort, password)
+		},
+	}
+}
+
+// Connect establishes the connection if it is not connected yet.
+func (c *ReconnectingConsole) Connect() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := c.connectLocked()
+	return err
+}
+
+func (c *ReconnectingConsole) connectLocked() (Console, error) {
+	if c.conn != nil {
+		return c.conn, nil
+	}
+	conn, err := c.dial(c.hostPort, c.password)
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	return conn, nil
+}
+
+func (c *ReconnectingConsole) resetLocked() {
+	if closer, ok := c.conn.(interface{ Close() error }); ok {
+		_ = closer.Close()
+	}
+	c.conn = nil
+}
+
+// Write sends a command, connecting first if needed.
+func (c *ReconnectingConsole) Write(cmd string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	conn, err := c.connectLocked()
+	if err != nil {
+		return 0, err
+	}
+	reqID, err := conn.Write(cmd)
+	if err != nil {
+		c.resetLocked()
+	}
+	return reqID, err
+}
+
+// Read reads a response from the current connection.
+func (c *ReconnectingConsole) Read() (string, int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return "", 0, errors.New("rcon connection is not established")
+	}
+	resp, reqID, err := c.conn.Read()
+	if err != nil {
+		c.resetLocked()
+	}
+	return resp, reqID, err
+}
+
+// Close closes the current connection, if any.
+func (c *ReconnectingConsole) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.resetLocked()
+	return nil
+}
+
 // edit from https://github.com/itzg/rcon-cli/blob/43ccb0311317dba9a99dd4836e4a274fbf993492/cli/entry.go#L98-L123
 
 func exec(remoteConsole Console, command ...string) (string, error) {
@@ -62,6 +153,18 @@ func WhitelistSwitch(remoteConsole Console, enabled bool) error {
 	return err
 }
 
+// Difficulty changes the difficulty of the server.
+func Difficulty(remoteConsole Console, difficulty string) error {
+	_, err := exec(remoteConsole, "difficulty", difficulty)
+	return err
+}
+
+// DefaultGameMode changes the game mode of new players.
+func DefaultGameMode(remoteConsole Console, gamemode string) error {
+	_, err := exec(remoteConsole, "defaultgamemode", gamemode)
+	return err
+}
+
 // Whitelist adds or removes users from the whitelist.
 func Whitelist(remoteConsole Console, action string, users []string) error {
 	if action != "add" && action != "remove" {
@@ -127,6 +230,41 @@ func Deop(remoteConsole Console, users []string) error {
 	return nil
 }
 
+// WhitelistReload reloads whitelist.json.
+func WhitelistReload(remoteConsole Console) error {
+	_, err := exec(remoteConsole, "whitelist", "reload")
+	return err
+}
+
+// Ban adds users to the ban list.
+func Ban(remoteConsole Console, users []string) error {
+	return execEach(remoteConsole, "ban", users)
+}
+
+// Pardon removes users from the ban list.
+func Pardon(remoteConsole Console, users []string) error {
+	return execEach(remoteConsole, "pardon", users)
+}
+
+// BanIP adds IP addresses to the ban list.
+func BanIP(remoteConsole Console, ips []string) error {
+	return execEach(remoteConsole, "ban-ip", ips)
+}
+
+// PardonIP removes IP addresses from the ban list.
+func PardonIP(remoteConsole Console, ips []string) error {
+	return execEach(remoteConsole, "pardon-ip", ips)
+}
+
+func execEach(remoteConsole Console, command string, targets []string) error {
+	for _, target := range targets {
+		if _, err := exec(remoteConsole, command, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // SaveOff disables the server auto-save.
 func SaveOff(remoteConsole Console) error {
 	_, err := exec(remoteConsole, "save-off")
@@ -144,3 +282,15 @@ func SaveOn(remoteConsole Console) error {
 	_, err := exec(remoteConsole, "save-on")
 	return err
 }
+
+// Say broadcasts a message to the players.
+func Say(remoteConsole Console, message string) error {
+	_, err := exec(remoteConsole, "say", message)
+	return err
+}
+
+// Stop stops the server.
+func Stop(remoteConsole Console) error {
+	_, err := exec(remoteConsole, "stop")
+	return err
+}
@@ -128,6 +128,70 @@ func TestWhitelistSwitch(t *testing.T) {
 	}
 }
 
+func TestPropertyCommands(t *testing.T) {
+	tests := []struct {
+		name    string
+		run     func(Console) error
+		wantCmd string
+	}{
+		{
+			name:    "difficulty",
+			run:     func(c Console) error { return Difficulty(c, "hard") },
+			wantCmd: "difficulty hard",
+		},
+		{
+			name:    "default game mode",
+			run:     func(c Console) error { return DefaultGameMode(c, "creative") },
+			wantCmd: "defaultgamemode creative",
+		},
+		{
+			name:    "whitelist reload",
+			run:     WhitelistReload,
+			wantCmd: "whitelist reload",
+		},
+		{
+			name:    "ban",
+			run:     func(c Console) error { return Ban(c, []string{"griefer"}) },
+			wantCmd: "ban griefer",
+		},
+		{
+			name:    "pardon",
+			run:     func(c Console) error { return Pardon(c, []string{"griefer"}) },
+			wantCmd: "pardon griefer",
+		},
+		{
+			name:    "ban ip",
+			run:     func(c Console) error { return BanIP(c, []string{"192.0.2.1"}) },
+			wantCmd: "ban-ip 192.0.2.1",
+		},
+		{
+			name:    "pardon ip",
+			run:     func(c Console) error { return PardonIP(c, []string{"192.0.2.1"}) },
+			wantCmd: "pardon-ip 192.0.2.1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			mock := &MockConsole{
+				WriteFunc: func(cmd string) (int, error) {
+					got = cmd
+					return 1, nil
+				},
+				ReadFunc: func() (string, int, error) {
+					return "", 1, nil
+				},
+			}
+			if err := tt.run(mock); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.wantCmd {
+				t.Errorf("command = %q, want %q", got, tt.wantCmd)
+			}
+		})
+	}
+}
+
 func TestListWhitelist(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -224,3 +288,87 @@ func TestOp(t *testing.T) {
 		})
 	}
 }
+
+func TestReconnectingConsole(t *testing.T) {
+	dials := 0
+	failWrite := true
+	c := &ReconnectingConsole{
+		hostPort: "127.0.0.1:25575",
+		password: "password",
+		dial: func(_, _ string) (Console, error) {
+			dials++
+			return &MockConsole{
+				WriteFunc: func(_ string) (int, error) {
+					if failWrite {
+						failWrite = false
+						return 0, errors.New("broken pipe")
+					}
+					return 1, nil
+				},
+				ReadFunc: func() (string, int, error) {
+					return "Saved the game", 1, nil
+				},
+			}, nil
+		},
+	}
+
+	if err := SaveAllFlush(c); err == nil {
+		t.Fatal("SaveAllFlush() should fail on the broken connection")
+	}
+	if err := SaveAllFlush(c); err != nil {
+		t.Fatalf("SaveAllFlush() error = %v", err)
+	}
+	if dials != 2 {
+		t.Errorf("dials = %d, want 2", dials)
+	}
+}
+
+func TestReconnectingConsoleDialError(t *testing.T) {
+	c := &ReconnectingConsole{
+		hostPort: "127.0.0.1:25575",
+		password: "password",
+		dial: func(_, _ string) (Console, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+	if err := c.Connect(); err == nil {
+		t.Error("Connect() should fail")
+	}
+	if _, _, err := c.Read(); err == nil {
+		t.Error("Read() without a connection should fail")
+	}
+}
+
+func TestStop(t *testing.T) {
+	mock := &MockConsole{
+		WriteFunc: func(cmd string) (int, error) {
+			if cmd != "stop" {
+				t.Errorf("unexpected command: %s", cmd)
+			}
+			return 1, nil
+		},
+		ReadFunc: func() (string, int, error) {
+			return "Stopping the server", 1, nil
+		},
+	}
+	if err := Stop(mock); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+}
+
+func TestSay(t *testing.T) {
+	mock := &MockConsole{
+		WriteFunc: func(cmd string) (int, error) {
+			if cmd != "say The server is shutting down" {
+				t.Errorf("unexpected command: %s", cmd)
+			}
+			return 1, nil
+		},
+		ReadFunc: func() (string, int, error) {
+			return "", 1, nil
+		},
+	}
+	if err := Say(mock, "The server is shutting down"); err != nil {
+		t.Errorf("Say() error = %v", err)
+	}
+}
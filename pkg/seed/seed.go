@@ -0,0 +1,187 @@
+// Package seed seeds the data volume of a new server with the data of another server.
+package seed
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kmdkuk/mcing/pkg/constants"
+)
+
+const markerFileName = "seed"
+
+// Source is how the data volume is seeded.
+type Source struct {
+	// ID identifies the server being seeded. The data volume is seeded only once for an ID,
+	// even if it was cloned from a volume that had been seeded for another ID.
+	ID string
+	// URL is a tar.gz archive of the data directory to extract. It is empty when the volume has been cloned.
+	URL string
+	// SHA256 is the expected hex-encoded SHA-256 digest of the archive.
+	SHA256 string
+	// StripOps empties ops.json.
+	StripOps bool
+	// StripWhitelist empties whitelist.json.
+	StripWhitelist bool
+}
+
+// Seeder seeds the data volume.
+type Seeder struct {
+	dataPath string
+	client   *http.Client
+}
+
+// NewSeeder returns a new Seeder for the data volume at dataPath.
+func NewSeeder(dataPath string, client *http.Client) *Seeder {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Seeder{
+		dataPath: dataPath,
+		client:   client,
+	}
+}
+
+func (s *Seeder) stateDir() string {
+	return filepath.Join(s.dataPath, constants.StateDirName)
+}
+
+func (s *Seeder) markerPath() string {
+	return filepath.Join(s.stateDir(), markerFileName)
+}
+
+// Seed extracts the archive into the data volume and strips the operators and the whitelist copied from the source.
+// It does nothing if the data volume has already been seeded for src.ID, so that the data written by the server
+// is kept on restarts.
+func (s *Seeder) Seed(ctx context.Context, src Source) error {
+	marker, err := os.ReadFile(s.markerPath())
+	if err == nil && string(marker) == src.ID {
+		return nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.MkdirAll(s.stateDir(), 0o750); err != nil {
+		return err
+	}
+
+	if src.URL != "" {
+		if err := s.extract(ctx, src.URL, src.SHA256); err != nil {
+			return fmt.Errorf("failed to seed from %s: %w", src.URL, err)
+		}
+	}
+	if src.StripOps {
+		if err := os.WriteFile(filepath.Join(s.dataPath, constants.OpsName), []byte("[]\n"), 0o600); err != nil {
+			return err
+		}
+	}
+	if src.StripWhitelist {
+		if err := os.WriteFile(filepath.Join(s.dataPath, constants.WhiteListName), []byte("[]\n"), 0o600); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(s.markerPath(), []byte(src.ID), 0o600)
+}
+
+// extract downloads the archive, verifies it and extracts it into the data volume.
+// The archive is verified before extracting so that a broken archive does not leave a partial world.
+func (s *Seeder) extract(ctx context.Context, url, expected string) error {
+	tmp, err := os.CreateTemp(s.stateDir(), ".seed-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, h), resp.Body); err != nil {
+		return err
+	}
+	if digest := hex.EncodeToString(h.Sum(nil)); expected != "" && expected != digest {
+		return fmt.Errorf("sha256 mismatch: expected %s, got %s", expected, digest)
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return s.untar(tmp)
+}
+
+// untar extracts the directories and the regular files of a tar.gz archive. The other entries are skipped.
+func (s *Seeder) untar(r io.Reader) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := strings.TrimPrefix(filepath.Clean(hdr.Name), "./")
+		if name == "." {
+			continue
+		}
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("invalid path in the archive: %s", hdr.Name)
+		}
+		dst := filepath.Join(s.dataPath, name)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(dst, 0o750); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(dst, tr, hdr.FileInfo().Mode().Perm()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeFile(dst string, r io.Reader, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	//nolint:gosec // the archive is verified by its digest.
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
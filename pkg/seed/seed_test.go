@@ -0,0 +1,178 @@
+package seed
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// archive returns a tar.gz archive of the files, like `tar czf - -C /data .` does.
+func archive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(&tar.Header{Name: "./", Typeflag: tar.TypeDir, Mode: 0o755}); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range files {
+		hdr := &tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(data))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func digestOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func serve(t *testing.T, data []byte) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL + "/data.tar.gz"
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestSeeder_Seed(t *testing.T) {
+	dataPath := t.TempDir()
+	data := archive(t, map[string]string{
+		"./world/level.dat": "level",
+		"./ops.json":        `[{"name":"admin"}]`,
+		"./whitelist.json":  `[{"name":"player"}]`,
+	})
+	src := Source{
+		ID:             "uid-1",
+		URL:            serve(t, data),
+		SHA256:         digestOf(data),
+		StripOps:       true,
+		StripWhitelist: true,
+	}
+
+	s := NewSeeder(dataPath, nil)
+	if err := s.Seed(context.Background(), src); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, filepath.Join(dataPath, "world", "level.dat")); got != "level" {
+		t.Errorf("level.dat = %q, want %q", got, "level")
+	}
+	if got := readFile(t, filepath.Join(dataPath, "ops.json")); got != "[]\n" {
+		t.Errorf("ops.json = %q, want it stripped", got)
+	}
+	if got := readFile(t, filepath.Join(dataPath, "whitelist.json")); got != "[]\n" {
+		t.Errorf("whitelist.json = %q, want it stripped", got)
+	}
+
+	// The world written by the server must be kept on restarts.
+	if err := os.WriteFile(filepath.Join(dataPath, "world", "level.dat"), []byte("played"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Seed(context.Background(), src); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, filepath.Join(dataPath, "world", "level.dat")); got != "played" {
+		t.Errorf("level.dat = %q, want %q after a restart", got, "played")
+	}
+}
+
+func TestSeeder_Seed_Cloned(t *testing.T) {
+	dataPath := t.TempDir()
+	// The volume was cloned from a server that had been seeded itself.
+	if err := os.MkdirAll(filepath.Join(dataPath, ".mcing"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dataPath, ".mcing", "seed"), []byte("uid-source"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dataPath, "ops.json"), []byte(`[{"name":"admin"}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	whitelist := []byte(`[{"name":"player"}]`)
+	if err := os.WriteFile(filepath.Join(dataPath, "whitelist.json"), whitelist, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := NewSeeder(dataPath, nil).Seed(context.Background(), Source{ID: "uid-clone", StripOps: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, filepath.Join(dataPath, "ops.json")); got != "[]\n" {
+		t.Errorf("ops.json = %q, want it stripped", got)
+	}
+	if got := readFile(t, filepath.Join(dataPath, "whitelist.json")); got != `[{"name":"player"}]` {
+		t.Errorf("whitelist.json = %q, want it kept", got)
+	}
+	if got := readFile(t, filepath.Join(dataPath, ".mcing", "seed")); got != "uid-clone" {
+		t.Errorf("marker = %q, want %q", got, "uid-clone")
+	}
+}
+
+func TestSeeder_Seed_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		sha256  func(data []byte) string
+		wantErr string
+	}{
+		{
+			name:    "digest mismatch",
+			files:   map[string]string{"./world/level.dat": "level"},
+			sha256:  func([]byte) string { return digestOf([]byte("other")) },
+			wantErr: "sha256 mismatch",
+		},
+		{
+			name:    "path traversal",
+			files:   map[string]string{"../escape": "evil"},
+			sha256:  digestOf,
+			wantErr: "invalid path",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataPath := t.TempDir()
+			data := archive(t, tt.files)
+			src := Source{ID: "uid-1", URL: serve(t, data), SHA256: tt.sha256(data)}
+			err := NewSeeder(dataPath, nil).Seed(context.Background(), src)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Seed() = %v, want %q", err, tt.wantErr)
+			}
+			if _, err := os.Stat(filepath.Join(dataPath, ".mcing", "seed")); !os.IsNotExist(err) {
+				t.Errorf("the volume must not be marked as seeded: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(dataPath, "world")); !os.IsNotExist(err) {
+				t.Errorf("a broken archive must not be extracted: %v", err)
+			}
+		})
+	}
+}
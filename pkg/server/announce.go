@@ -0,0 +1,15 @@
+package server
+
+import (
+	"context"
+
+	"github.com/kmdkuk/mcing/pkg/proto"
+	"github.com/kmdkuk/mcing/pkg/rcon"
+)
+
+func (s agentService) Announce(_ context.Context, req *proto.AnnounceRequest) (*proto.AnnounceResponse, error) {
+	if err := rcon.Say(s.conn, req.GetMessage()); err != nil {
+		return nil, err
+	}
+	return &proto.AnnounceResponse{}, nil
+}
@@ -0,0 +1,20 @@
+package server
+
+import (
+	"context"
+	"syscall"
+
+	"github.com/kmdkuk/mcing/pkg/proto"
+)
+
+func (s agentService) DiskUsage(_ context.Context, _ *proto.DiskUsageRequest) (*proto.DiskUsageResponse, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(s.dataPath, &st); err != nil {
+		return nil, err
+	}
+	bsize := uint64(st.Bsize) //nolint:gosec // the block size is positive
+	return &proto.DiskUsageResponse{
+		UsedBytes:  (st.Blocks - st.Bfree) * bsize,
+		TotalBytes: st.Blocks * bsize,
+	}, nil
+}
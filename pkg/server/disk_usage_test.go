@@ -0,0 +1,29 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kmdkuk/mcing/pkg/proto"
+)
+
+func TestDiskUsage(t *testing.T) {
+	s := &agentService{ //nolint:exhaustruct // test
+		logger:   zap.NewNop(),
+		dataPath: t.TempDir(),
+	}
+	got, err := s.DiskUsage(context.Background(), &proto.DiskUsageRequest{})
+	if err != nil {
+		t.Fatalf("DiskUsage() error = %v", err)
+	}
+	if got.GetTotalBytes() == 0 || got.GetUsedBytes() > got.GetTotalBytes() {
+		t.Errorf("DiskUsage() = %v, want used bytes within a non-empty file system", got)
+	}
+
+	s.dataPath = "/nonexistent"
+	if _, err := s.DiskUsage(context.Background(), &proto.DiskUsageRequest{}); err == nil {
+		t.Error("DiskUsage() should fail for a missing directory")
+	}
+}
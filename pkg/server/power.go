@@ -0,0 +1,37 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kmdkuk/mcing/pkg/proto"
+)
+
+// Wake starts the server process if it is sleeping and waits until it accepts connections.
+func (s agentService) Wake(ctx context.Context, _ *proto.WakeRequest) (*proto.WakeResponse, error) {
+	if s.autoPause == nil {
+		return &proto.WakeResponse{WasSleeping: false}, nil
+	}
+	wasSleeping, err := s.autoPause.Wake(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		return nil, err
+	}
+	return &proto.WakeResponse{WasSleeping: wasSleeping}, nil
+}
+
+// Sleep stops the server process until the next login.
+func (s agentService) Sleep(ctx context.Context, _ *proto.SleepRequest) (*proto.SleepResponse, error) {
+	if s.autoPause == nil {
+		return nil, status.Error(codes.FailedPrecondition, "auto pause is disabled; stopping the server would restart the pod")
+	}
+	wasSleeping, err := s.autoPause.Sleep(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.SleepResponse{WasSleeping: wasSleeping}, nil
+}
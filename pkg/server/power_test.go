@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kmdkuk/mcing/pkg/autopause"
+	"github.com/kmdkuk/mcing/pkg/proto"
+)
+
+type mockAutoPause struct {
+	wasSleeping bool
+	err         error
+}
+
+var _ autopause.Controller = &mockAutoPause{} //nolint:exhaustruct // interface check
+
+func (m *mockAutoPause) Wake(_ context.Context) (bool, error) {
+	return m.wasSleeping, m.err
+}
+
+func (m *mockAutoPause) Sleep(_ context.Context) (bool, error) {
+	return m.wasSleeping, m.err
+}
+
+func TestWake(t *testing.T) {
+	tests := []struct {
+		name            string
+		autoPause       autopause.Controller
+		wantWasSleeping bool
+		wantErr         bool
+	}{
+		{
+			name:            "auto pause disabled",
+			autoPause:       nil,
+			wantWasSleeping: false,
+		},
+		{
+			name:            "sleeping",
+			autoPause:       &mockAutoPause{wasSleeping: true},
+			wantWasSleeping: true,
+		},
+		{
+			name:      "error",
+			autoPause: &mockAutoPause{err: errors.New("failed to knock")},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &agentService{ //nolint:exhaustruct // test
+				logger:    zap.NewNop(),
+				autoPause: tt.autoPause,
+			}
+			got, err := s.Wake(context.Background(), &proto.WakeRequest{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Wake() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.GetWasSleeping() != tt.wantWasSleeping {
+				t.Errorf("Wake() WasSleeping = %v, want %v", got.GetWasSleeping(), tt.wantWasSleeping)
+			}
+		})
+	}
+}
+
+func TestSleep(t *testing.T) {
+	tests := []struct {
+		name            string
+		autoPause       autopause.Controller
+		wantWasSleeping bool
+		wantCode        codes.Code
+	}{
+		{
+			name:      "auto pause disabled",
+			autoPause: nil,
+			wantCode:  codes.FailedPrecondition,
+		},
+		{
+			name:            "already sleeping",
+			autoPause:       &mockAutoPause{wasSleeping: true},
+			wantWasSleeping: true,
+			wantCode:        codes.OK,
+		},
+		{
+			name:      "error",
+			autoPause: &mockAutoPause{err: errors.New("rcon error")},
+			wantCode:  codes.Unknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &agentService{ //nolint:exhaustruct // test
+				logger:    zap.NewNop(),
+				autoPause: tt.autoPause,
+			}
+			got, err := s.Sleep(context.Background(), &proto.SleepRequest{})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("Sleep() code = %v, want %v", code, tt.wantCode)
+			}
+			if err == nil && got.GetWasSleeping() != tt.wantWasSleeping {
+				t.Errorf("Sleep() WasSleeping = %v, want %v", got.GetWasSleeping(), tt.wantWasSleeping)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"context"
+	"path"
+
+	"github.com/kmdkuk/mcing/pkg/config"
+	"github.com/kmdkuk/mcing/pkg/constants"
+	"github.com/kmdkuk/mcing/pkg/proto"
+)
+
+// PendingRestart returns the properties changed since the server started that take effect after a restart.
+// The properties the server started with are recorded by mcing-init and the start command of the server,
+// so that they survive restarts of mcing-agent and follow the server restarted by auto-pause.
+func (s agentService) PendingRestart(
+	_ context.Context,
+	_ *proto.PendingRestartRequest,
+) (*proto.PendingRestartResponse, error) {
+	started, err := config.ParseServerPropsFromPath(path.Join(s.dataPath, constants.StateDirName, constants.StartPropsName))
+	if err != nil {
+		return nil, err
+	}
+	desired, err := config.ParseServerPropsFromPath(path.Join(s.configPath, constants.ServerPropsName))
+	if err != nil {
+		return nil, err
+	}
+	_, restartRequired := config.DiffServerProps(started, desired)
+	return &proto.PendingRestartResponse{Properties: restartRequired}, nil
+}
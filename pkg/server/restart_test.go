@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+
+	"github.com/kmdkuk/mcing/pkg/constants"
+	"github.com/kmdkuk/mcing/pkg/proto"
+)
+
+func TestPendingRestart(t *testing.T) {
+	const startProps = "difficulty=easy\nmax-players=20\nmotd=hello\n"
+	tests := []struct {
+		name       string
+		startProps string
+		config     string
+		want       []string
+		wantErr    bool
+	}{
+		{
+			name:       "unchanged",
+			startProps: startProps,
+			config:     "difficulty=easy\nmax-players=20\nmotd=hello\n",
+		},
+		{
+			name:       "hot-reloadable only",
+			startProps: startProps,
+			config:     "difficulty=hard\nmax-players=20\nmotd=hello\n",
+		},
+		{
+			name:       "restart required",
+			startProps: startProps,
+			config:     "difficulty=hard\nmax-players=10\nmotd=world\n",
+			want:       []string{"max-players", "motd"},
+		},
+		{
+			name:       "no config",
+			startProps: startProps,
+			wantErr:    true,
+		},
+		{
+			name:    "start not recorded",
+			config:  "difficulty=easy\nmax-players=20\nmotd=hello\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataPath := t.TempDir()
+			configPath := t.TempDir()
+			if tt.startProps != "" {
+				stateDir := filepath.Join(dataPath, constants.StateDirName)
+				if err := os.MkdirAll(stateDir, 0o750); err != nil {
+					t.Fatal(err)
+				}
+				err := os.WriteFile(filepath.Join(stateDir, constants.StartPropsName), []byte(tt.startProps), 0o600)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.config != "" {
+				err := os.WriteFile(filepath.Join(configPath, constants.ServerPropsName), []byte(tt.config), 0o600)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			s := &agentService{ //nolint:exhaustruct // test
+				logger:     zap.NewNop(),
+				dataPath:   dataPath,
+				configPath: configPath,
+			}
+			got, err := s.PendingRestart(context.Background(), &proto.PendingRestartRequest{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PendingRestart() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got.GetProperties()); diff != "" {
+				t.Errorf("PendingRestart() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -3,25 +3,41 @@ package server
 import (
 	"go.uber.org/zap"
 
+	"github.com/kmdkuk/mcing/pkg/autopause"
 	"github.com/kmdkuk/mcing/pkg/constants"
 	"github.com/kmdkuk/mcing/pkg/proto"
 	"github.com/kmdkuk/mcing/pkg/rcon"
+	"github.com/kmdkuk/mcing/pkg/watcher"
 )
 
 // NewAgentService creates a new AgentServer.
-// NewAgentService creates a new AgentServer.
-func NewAgentService(logger *zap.Logger, conn rcon.Console) proto.AgentServer {
+// autoPause is nil when auto-pause is disabled.
+// syncResults are the results of the config watcher.
+func NewAgentService(
+	logger *zap.Logger,
+	conn rcon.Console,
+	autoPause autopause.Controller,
+	syncResults *watcher.Results,
+) proto.AgentServer {
 	return agentService{ //nolint:exhaustruct // unimplemented embedded struct
-		logger:   logger.With(zap.String("service", "mcing-agent")),
-		conn:     conn,
-		dataPath: constants.DataPath,
+		logger:      logger.With(zap.String("service", "mcing-agent")),
+		conn:        conn,
+		dataPath:    constants.DataPath,
+		configPath:  constants.ConfigPath,
+		syncResults: syncResults,
+		autoPause:   autoPause,
 	}
 }
 
 type agentService struct {
 	proto.UnimplementedAgentServer
 
-	logger   *zap.Logger
-	conn     rcon.Console
-	dataPath string
+	logger     *zap.Logger
+	conn       rcon.Console
+	dataPath   string
+	configPath string
+
+	syncResults *watcher.Results
+
+	autoPause autopause.Controller
 }
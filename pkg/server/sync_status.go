@@ -0,0 +1,28 @@
+package server
+
+import (
+	"context"
+
+	"github.com/kmdkuk/mcing/pkg/proto"
+)
+
+// SyncStatus returns the results of the last sync of each config file.
+func (s agentService) SyncStatus(
+	_ context.Context,
+	_ *proto.SyncStatusRequest,
+) (*proto.SyncStatusResponse, error) {
+	results := s.syncResults.List()
+	files := make([]*proto.FileSyncResult, 0, len(results))
+	for _, r := range results {
+		var syncedAt int64
+		if !r.SyncedAt.IsZero() {
+			syncedAt = r.SyncedAt.Unix()
+		}
+		files = append(files, &proto.FileSyncResult{
+			Name:     r.Name,
+			SyncedAt: syncedAt,
+			Error:    r.Error,
+		})
+	}
+	return &proto.SyncStatusResponse{Files: files}, nil
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/kmdkuk/mcing/pkg/proto"
+	"github.com/kmdkuk/mcing/pkg/watcher"
+)
+
+func TestSyncStatus(t *testing.T) {
+	results := watcher.NewResults()
+	results.Set("whitelist.json", nil)
+	results.Set("ops.json", errors.New("rcon is not available"))
+
+	s := &agentService{ //nolint:exhaustruct // test
+		logger:      zap.NewNop(),
+		syncResults: results,
+	}
+	got, err := s.SyncStatus(context.Background(), &proto.SyncStatusRequest{})
+	if err != nil {
+		t.Fatalf("SyncStatus() error = %v", err)
+	}
+	files := got.GetFiles()
+	if len(files) != 2 {
+		t.Fatalf("SyncStatus() returned %d files, want 2", len(files))
+	}
+	if files[0].GetName() != "ops.json" || files[0].GetError() != "rcon is not available" || files[0].GetSyncedAt() != 0 {
+		t.Errorf("unexpected result for ops.json: %v", files[0])
+	}
+	if files[1].GetName() != "whitelist.json" || files[1].GetError() != "" || files[1].GetSyncedAt() == 0 {
+		t.Errorf("unexpected result for whitelist.json: %v", files[1])
+	}
+}
+
+func TestSyncStatus_NoWatcher(t *testing.T) {
+	s := &agentService{ //nolint:exhaustruct // test
+		logger: zap.NewNop(),
+	}
+	got, err := s.SyncStatus(context.Background(), &proto.SyncStatusRequest{})
+	if err != nil {
+		t.Fatalf("SyncStatus() error = %v", err)
+	}
+	if len(got.GetFiles()) != 0 {
+		t.Errorf("SyncStatus() = %v, want no files", got.GetFiles())
+	}
+}
@@ -0,0 +1,316 @@
+// Package slp implements the small part of the Minecraft protocol needed to
+// query a server via Server List Ping and to knock on a lazymc proxy.
+package slp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultProtocolVersion is used for the handshake when the caller does not know the server version.
+	// -1 asks the server to answer with whatever version it runs.
+	DefaultProtocolVersion = -1
+
+	// NextStateStatus is the next state of a handshake that queries the server status.
+	NextStateStatus = 1
+	// NextStateLogin is the next state of a handshake that starts a login.
+	NextStateLogin = 2
+
+	packetIDHandshake     = 0x00
+	packetIDStatusRequest = 0x00
+	packetIDPing          = 0x01
+	packetIDLoginStart    = 0x00
+
+	maxVarIntBytes  = 5
+	maxPacketLength = 2 * 1024 * 1024
+	defaultTimeout  = 5 * time.Second
+	uuidLength      = 16
+)
+
+// Status is the subset of the Server List Ping response used by MCing.
+type Status struct {
+	Version struct {
+		Name     string `json:"name"`
+		Protocol int32  `json:"protocol"`
+	} `json:"version"`
+	Players struct {
+		Max    int32 `json:"max"`
+		Online int32 `json:"online"`
+	} `json:"players"`
+	Description json.RawMessage `json:"description,omitempty"`
+}
+
+// Handshake is the first packet a client sends.
+type Handshake struct {
+	ProtocolVersion int32
+	Address         string
+	Port            uint16
+	NextState       int32
+}
+
+// ReadHandshake reads a handshake packet from r.
+// It also returns the raw packet so that the caller can replay it to another server.
+func ReadHandshake(r io.ByteReader) (*Handshake, []byte, error) {
+	rec := &recordingReader{r: r}
+	id, payload, err := readPacket(rec)
+	if err != nil {
+		return nil, nil, err
+	}
+	if id != packetIDHandshake {
+		return nil, nil, fmt.Errorf("unexpected packet id 0x%02x in handshake", id)
+	}
+	br := bytes.NewReader(payload)
+	hs := &Handshake{} //nolint:exhaustruct // filled below
+	if hs.ProtocolVersion, err = readVarInt(br); err != nil {
+		return nil, nil, err
+	}
+	if hs.Address, err = readString(br); err != nil {
+		return nil, nil, err
+	}
+	if err := binary.Read(br, binary.BigEndian, &hs.Port); err != nil {
+		return nil, nil, err
+	}
+	if hs.NextState, err = readVarInt(br); err != nil {
+		return nil, nil, err
+	}
+	return hs, rec.buf.Bytes(), nil
+}
+
+// ServeStatus answers the status request and the following ping of a client
+// that has sent a handshake with NextStateStatus.
+func ServeStatus(r io.ByteReader, w io.Writer, status *Status) error {
+	id, _, err := readPacket(r)
+	if err != nil {
+		return err
+	}
+	if id != packetIDStatusRequest {
+		return fmt.Errorf("unexpected packet id 0x%02x in status request", id)
+	}
+	raw, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	writeString(&buf, string(raw))
+	if err := writePacket(w, packetIDStatusRequest, buf.Bytes()); err != nil {
+		return err
+	}
+
+	// The client measures the latency with a ping. Some clients close the connection without it.
+	id, payload, err := readPacket(r)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if id != packetIDPing {
+		return fmt.Errorf("unexpected packet id 0x%02x in ping", id)
+	}
+	return writePacket(w, packetIDPing, payload)
+}
+
+// TextStatus returns a status that shows text in the server list.
+// It echoes the client version so that the client does not show the server as incompatible.
+func TextStatus(protocolVersion int32, text string) *Status {
+	st := &Status{} //nolint:exhaustruct // filled below
+	st.Version.Name = "mcing"
+	st.Version.Protocol = protocolVersion
+	st.Description, _ = json.Marshal(map[string]string{"text": text})
+	return st
+}
+
+// recordingReader keeps the bytes read through it.
+type recordingReader struct {
+	r   io.ByteReader
+	buf bytes.Buffer
+}
+
+func (rr *recordingReader) ReadByte() (byte, error) {
+	c, err := rr.r.ReadByte()
+	if err == nil {
+		rr.buf.WriteByte(c)
+	}
+	return c, err
+}
+
+// Ping performs a Server List Ping against addr and returns the decoded status.
+func Ping(ctx context.Context, addr string) (*Status, error) {
+	conn, err := dial(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := WriteHandshake(conn, addr, DefaultProtocolVersion, NextStateStatus); err != nil {
+		return nil, err
+	}
+	if err := writePacket(conn, packetIDStatusRequest, nil); err != nil {
+		return nil, err
+	}
+
+	r := bufio.NewReader(conn)
+	id, payload, err := readPacket(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read status response: %w", err)
+	}
+	if id != packetIDStatusRequest {
+		return nil, fmt.Errorf("unexpected packet id 0x%02x in status response", id)
+	}
+	raw, err := readString(bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	status := &Status{}
+	if err := json.Unmarshal([]byte(raw), status); err != nil {
+		return nil, fmt.Errorf("failed to decode status response: %w", err)
+	}
+	return status, nil
+}
+
+// Knock starts a login as the given player and disconnects right away.
+// lazymc starts the backend server as soon as a client tries to log in, so this is
+// enough to wake a sleeping server without holding a real client session.
+func Knock(ctx context.Context, addr string, protocolVersion int32, name string) error {
+	conn, err := dial(ctx, addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := WriteHandshake(conn, addr, protocolVersion, NextStateLogin); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	writeString(&buf, name)
+	// Newer clients append the player UUID. Older servers ignore the trailing bytes.
+	buf.Write(make([]byte, uuidLength))
+	return writePacket(conn, packetIDLoginStart, buf.Bytes())
+}
+
+func dial(ctx context.Context, addr string) (net.Conn, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultTimeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// WriteHandshake writes a handshake packet to addr, which is a host and a port, to w.
+func WriteHandshake(w io.Writer, addr string, protocolVersion int32, nextState int32) error {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	writeVarInt(&buf, protocolVersion)
+	writeString(&buf, host)
+	_ = binary.Write(&buf, binary.BigEndian, uint16(port))
+	writeVarInt(&buf, nextState)
+	return writePacket(w, packetIDHandshake, buf.Bytes())
+}
+
+func writePacket(w io.Writer, id int32, payload []byte) error {
+	var body bytes.Buffer
+	writeVarInt(&body, id)
+	body.Write(payload)
+
+	var packet bytes.Buffer
+	writeVarInt(&packet, int32(body.Len())) //nolint:gosec // packets we write are tiny
+	packet.Write(body.Bytes())
+	_, err := w.Write(packet.Bytes())
+	return err
+}
+
+func readPacket(r io.ByteReader) (int32, []byte, error) {
+	length, err := readVarInt(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if length <= 0 || length > maxPacketLength {
+		return 0, nil, fmt.Errorf("invalid packet length %d", length)
+	}
+	data := make([]byte, length)
+	for i := range data {
+		if data[i], err = r.ReadByte(); err != nil {
+			return 0, nil, err
+		}
+	}
+	br := bytes.NewReader(data)
+	id, err := readVarInt(br)
+	if err != nil {
+		return 0, nil, err
+	}
+	payload := data[len(data)-br.Len():]
+	return id, payload, nil
+}
+
+func writeVarInt(w *bytes.Buffer, v int32) {
+	u := uint32(v)
+	for {
+		if u&^0x7f == 0 {
+			w.WriteByte(byte(u))
+			return
+		}
+		w.WriteByte(byte(u&0x7f | 0x80))
+		u >>= 7
+	}
+}
+
+func readVarInt(r io.ByteReader) (int32, error) {
+	var result uint32
+	for i := range maxVarIntBytes {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		result |= uint32(b&0x7f) << (7 * i)
+		if b&0x80 == 0 {
+			return int32(result), nil //nolint:gosec // VarInt is a two's complement int32
+		}
+	}
+	return 0, errors.New("VarInt is too big")
+}
+
+func writeString(w *bytes.Buffer, s string) {
+	writeVarInt(w, int32(len(s))) //nolint:gosec // strings we write are tiny
+	w.WriteString(s)
+}
+
+func readString(r *bytes.Reader) (string, error) {
+	n, err := readVarInt(r)
+	if err != nil {
+		return "", err
+	}
+	if n < 0 || int(n) > r.Len() {
+		return "", fmt.Errorf("invalid string length %d", n)
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,236 @@
+package slp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+type fakeServer struct {
+	addr      string
+	listener  net.Listener
+	handshake chan handshake
+	loginName chan string
+}
+
+type handshake struct {
+	protocol  int32
+	nextState int32
+}
+
+// newFakeServer starts a TCP listener that speaks just enough of the protocol
+// to answer a status request with the given JSON and to record login attempts.
+func newFakeServer(t *testing.T, statusJSON string) *fakeServer {
+	t.Helper()
+	var lc net.ListenConfig
+	l, err := lc.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeServer{
+		addr:      l.Addr().String(),
+		listener:  l,
+		handshake: make(chan handshake, 1),
+		loginName: make(chan string, 1),
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			s.serve(conn, statusJSON)
+		}
+	}()
+	return s
+}
+
+func (s *fakeServer) serve(conn net.Conn, statusJSON string) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	_, payload, err := readPacket(r)
+	if err != nil {
+		return
+	}
+	br := bytes.NewReader(payload)
+	protocol, _ := readVarInt(br)
+	_, _ = readString(br)
+	_, _ = br.ReadByte()
+	_, _ = br.ReadByte()
+	next, _ := readVarInt(br)
+	s.handshake <- handshake{protocol: protocol, nextState: next}
+
+	if _, payload, err = readPacket(r); err != nil {
+		return
+	}
+	switch next {
+	case NextStateStatus:
+		var buf bytes.Buffer
+		writeString(&buf, statusJSON)
+		_ = writePacket(conn, packetIDStatusRequest, buf.Bytes())
+	case NextStateLogin:
+		name, _ := readString(bytes.NewReader(payload))
+		s.loginName <- name
+	}
+}
+
+func TestPing(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      string
+		wantOnline  int32
+		wantMax     int32
+		wantVersion string
+		wantErr     bool
+	}{
+		{
+			name:        "players online",
+			status:      `{"version":{"name":"1.20.4","protocol":765},"players":{"max":20,"online":3},"description":"hi"}`,
+			wantOnline:  3,
+			wantMax:     20,
+			wantVersion: "1.20.4",
+		},
+		{
+			name:        "nobody online",
+			status:      `{"version":{"name":"1.20.4","protocol":765},"players":{"max":20,"online":0}}`,
+			wantOnline:  0,
+			wantMax:     20,
+			wantVersion: "1.20.4",
+		},
+		{
+			name:    "broken json",
+			status:  `{"version":`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newFakeServer(t, tt.status)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			got, err := Ping(ctx, s.addr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Ping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			hs := <-s.handshake
+			if hs.nextState != NextStateStatus {
+				t.Errorf("handshake next state = %d, want %d", hs.nextState, NextStateStatus)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Players.Online != tt.wantOnline || got.Players.Max != tt.wantMax {
+				t.Errorf("players = %d/%d, want %d/%d", got.Players.Online, got.Players.Max, tt.wantOnline, tt.wantMax)
+			}
+			if got.Version.Name != tt.wantVersion {
+				t.Errorf("version = %q, want %q", got.Version.Name, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestPingConnectionRefused(t *testing.T) {
+	var lc net.ListenConfig
+	l, err := lc.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	_ = l.Close()
+
+	if _, err := Ping(context.Background(), addr); err == nil {
+		t.Error("Ping() to a closed port should fail")
+	}
+}
+
+func TestKnock(t *testing.T) {
+	s := newFakeServer(t, "")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := Knock(ctx, s.addr, 765, "mcing"); err != nil {
+		t.Fatalf("Knock() error = %v", err)
+	}
+	hs := <-s.handshake
+	if hs.nextState != NextStateLogin {
+		t.Errorf("handshake next state = %d, want %d", hs.nextState, NextStateLogin)
+	}
+	if hs.protocol != 765 {
+		t.Errorf("handshake protocol = %d, want 765", hs.protocol)
+	}
+	if name := <-s.loginName; name != "mcing" {
+		t.Errorf("login name = %q, want %q", name, "mcing")
+	}
+}
+
+func TestVarInt(t *testing.T) {
+	for _, v := range []int32{0, 1, 127, 128, 255, 25565, 2147483647, -1, -2147483648} {
+		var buf bytes.Buffer
+		writeVarInt(&buf, v)
+		got, err := readVarInt(&buf)
+		if err != nil {
+			t.Fatalf("readVarInt(%d) error = %v", v, err)
+		}
+		if got != v {
+			t.Errorf("round trip of %d = %d", v, got)
+		}
+	}
+}
+
+func TestServeStatus(t *testing.T) {
+	var lc net.ListenConfig
+	l, err := lc.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	want := &Status{} //nolint:exhaustruct // test
+	want.Version.Name = "sleeping"
+	want.Version.Protocol = 765
+	want.Players.Max = 20
+
+	served := make(chan error, 1)
+	handshakes := make(chan *Handshake, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			served <- err
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		hs, raw, err := ReadHandshake(r)
+		if err != nil {
+			served <- err
+			return
+		}
+		if len(raw) == 0 {
+			t.Error("ReadHandshake() returned an empty raw packet")
+		}
+		handshakes <- hs
+		served <- ServeStatus(r, conn, want)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	got, err := Ping(ctx, l.Addr().String())
+	if err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	if err := <-served; err != nil {
+		t.Fatalf("ServeStatus() error = %v", err)
+	}
+	hs := <-handshakes
+	if hs.NextState != NextStateStatus || hs.Address != "127.0.0.1" {
+		t.Errorf("handshake = %+v", hs)
+	}
+	if got.Version.Name != "sleeping" || got.Players.Max != 20 {
+		t.Errorf("status = %+v", got)
+	}
+}
@@ -0,0 +1,129 @@
+package watcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/kmdkuk/mcing/pkg/config"
+	"github.com/kmdkuk/mcing/pkg/constants"
+	"github.com/kmdkuk/mcing/pkg/log"
+	"github.com/kmdkuk/mcing/pkg/rcon"
+)
+
+// managedFile is a config file rendered by the controller.
+type managedFile struct {
+	name string
+	// required files always exist in the config.
+	required bool
+	// reload executes /reload after the file is applied.
+	reload bool
+	// apply applies the change of the file to the running server.
+	apply func(conn rcon.Console, previous, current []byte) error
+}
+
+// managedFiles are the config files that the watcher applies.
+// The server reads the lists only when it starts, so their changes are applied with commands.
+var managedFiles = []managedFile{
+	{name: constants.ServerPropsName, required: true, reload: true, apply: applyProps},
+	{name: constants.BanIPName, apply: applyList("ip", rcon.BanIP, rcon.PardonIP)},
+	{name: constants.BanPlayerName, apply: applyList("name", rcon.Ban, rcon.Pardon)},
+	{name: constants.OpsName, apply: applyList("name", rcon.Op, rcon.Deop)},
+	{name: constants.WhiteListName, apply: func(conn rcon.Console, _, _ []byte) error {
+		return rcon.WhitelistReload(conn)
+	}},
+}
+
+// applyProps applies the changed hot-reloadable properties to the running server.
+// The other changes take effect after the server restarts.
+func applyProps(conn rcon.Console, previous, current []byte) error {
+	prev, err := config.ParseServerProps(bytes.NewReader(previous))
+	if err != nil {
+		return fmt.Errorf("failed to parse the previous server.properties: %w", err)
+	}
+	cur, err := config.ParseServerProps(bytes.NewReader(current))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	reloadable, restartRequired := config.DiffServerProps(prev, cur)
+	for _, k := range reloadable {
+		v := cur[k]
+		if v == "" {
+			continue
+		}
+		switch k {
+		case constants.DifficultyProps:
+			err = rcon.Difficulty(conn, v)
+		case constants.GamemodeProps:
+			err = rcon.DefaultGameMode(conn, v)
+		case constants.WhitelistProps:
+			err = rcon.WhitelistSwitch(conn, v == "true")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to apply %s=%s: %w", k, v, err))
+		}
+	}
+	if len(restartRequired) != 0 {
+		log.Warnf("restart is required to apply %v", restartRequired)
+	}
+	return errors.Join(errs...)
+}
+
+// applyList returns a function that applies the entries added to or removed from a JSON list such as ops.json.
+// key is the field that identifies an entry.
+func applyList(key string, add, remove func(rcon.Console, []string) error) func(rcon.Console, []byte, []byte) error {
+	return func(conn rcon.Console, previous, current []byte) error {
+		prev, err := listEntries(previous, key)
+		if err != nil {
+			return fmt.Errorf("failed to parse the previous list: %w", err)
+		}
+		cur, err := listEntries(current, key)
+		if err != nil {
+			return err
+		}
+		if added := difference(cur, prev); len(added) != 0 {
+			if err := add(conn, added); err != nil {
+				return err
+			}
+		}
+		if removed := difference(prev, cur); len(removed) != 0 {
+			if err := remove(conn, removed); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// listEntries returns the values of key in a JSON list. An empty input is an empty list.
+func listEntries(data []byte, key string) ([]string, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	var entries []map[string]any
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	var ret []string
+	for _, e := range entries {
+		if v, ok := e[key].(string); ok && v != "" {
+			ret = append(ret, v)
+		}
+	}
+	return ret, nil
+}
+
+// difference returns the elements of a that are not in b.
+func difference(a, b []string) []string {
+	var ret []string
+	for _, v := range a {
+		if !slices.Contains(b, v) {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,60 @@
+package watcher
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// FileResult is the result of the last sync of a config file.
+type FileResult struct {
+	Name string
+	// SyncedAt is the time of the last successful sync.
+	SyncedAt time.Time
+	// Error is the error of the last sync. It is empty when the sync succeeded.
+	Error string
+}
+
+// Results holds the results of the syncs. It is safe for concurrent use.
+type Results struct {
+	mu    sync.Mutex
+	files map[string]FileResult
+}
+
+// NewResults returns an empty Results.
+func NewResults() *Results {
+	return &Results{files: map[string]FileResult{}}
+}
+
+// Set records the result of a sync of the file. A nil Results ignores it.
+func (r *Results) Set(name string, err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res := r.files[name]
+	res.Name = name
+	res.Error = ""
+	if err != nil {
+		res.Error = err.Error()
+	} else {
+		res.SyncedAt = time.Now()
+	}
+	r.files[name] = res
+}
+
+// List returns the results sorted by the file name.
+func (r *Results) List() []FileResult {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ret := make([]FileResult, 0, len(r.files))
+	for _, res := range r.files {
+		ret = append(ret, res)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
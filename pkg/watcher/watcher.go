@@ -1,83 +1,376 @@
 package watcher
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"time"
 
-	"github.com/google/go-cmp/cmp"
+	"github.com/fsnotify/fsnotify"
 
+	"github.com/kmdkuk/mcing/pkg/artifact"
+	"github.com/kmdkuk/mcing/pkg/config"
+	"github.com/kmdkuk/mcing/pkg/configfile"
 	"github.com/kmdkuk/mcing/pkg/constants"
 	"github.com/kmdkuk/mcing/pkg/log"
 	"github.com/kmdkuk/mcing/pkg/rcon"
 )
 
+const (
+	// eventDelay is the time to wait for more events before syncing.
+	// Kubernetes updates a ConfigMap volume with several file operations.
+	eventDelay = 100 * time.Millisecond
+
+	// minRetryDelay is the time to wait before a change that failed to be applied is tried again.
+	// The time doubles after every failure up to maxRetryDelay.
+	minRetryDelay = 10 * time.Second
+	maxRetryDelay = 5 * time.Minute
+)
+
 // Config represents the configuration for the watcher.
 type Config struct {
 	DataPath   string
 	ConfigPath string
+	// ConfigFilesPath is the directory of the config files projected from ConfigMaps and Secrets.
+	ConfigFilesPath string
+	// SecretsPath is the directory of the Secret keys referenced in the config files.
+	SecretsPath string
+	// Results receives the results of the syncs. It can be nil.
+	Results *Results
+	// Paused reports whether auto-pause has stopped the server process. It can be nil.
+	// While the server is paused, the changes are only written to the data volume,
+	// and the server reads them when it starts.
+	Paused func(ctx context.Context) bool
 }
 
 // NewDefaultConfig returns a new default configuration.
 func NewDefaultConfig() Config {
 	return Config{
-		DataPath:   constants.DataPath,
-		ConfigPath: constants.ConfigPath,
+		DataPath:        constants.DataPath,
+		ConfigPath:      constants.ConfigPath,
+		ConfigFilesPath: constants.ConfigFilesPath,
+		SecretsPath:     constants.SecretsPath,
+		Results:         NewResults(),
+		Paused:          nil,
 	}
 }
 
-// Watch watches the RCON server.
+// Watch watches the config files rendered by the controller and applies their changes to the running server.
+// Changed files are copied to the data volume and applied with the commands for each file.
+// A file also changes when a Secret key referenced in it changes.
+// The datapacks in the config are installed and reloaded, and the config files of spec.configFiles are placed.
 //
-//nolint:gocognit // complex logic
+// Changes are detected with inotify, and the config is also checked at every interval
+// in case an event is missed.
 func Watch(ctx context.Context, conn rcon.Console, interval time.Duration, cfg Config) error {
-	tick := time.NewTicker(interval)
-	defer tick.Stop()
-
-	preConfig := map[string][]byte{}
-
-	var err error
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer fw.Close()
+	// Watch the directory because Kubernetes replaces the files by swapping a symlink.
+	if err := fw.Add(cfg.ConfigPath); err != nil {
+		return err
+	}
+	// The directories exist only when a config file is read from a ConfigMap or a Secret,
+	// and when a Secret key is referenced.
+	for _, dir := range []string{cfg.ConfigFilesPath, cfg.SecretsPath} {
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+		if err := fw.Add(dir); err != nil {
+			return err
+		}
+	}
 
-	preConfig[constants.ServerPropsName], err = os.ReadFile(filepath.Join(cfg.ConfigPath, constants.ServerPropsName))
+	s, err := newSyncer(conn, cfg)
 	if err != nil {
 		return err
 	}
 
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	var delay <-chan time.Time
 	for {
 		select {
-		case <-tick.C:
 		case <-ctx.Done():
 			log.Debug("quit")
 			return nil
-		}
-
-		reload := false
-		for k, v := range preConfig {
-			path := filepath.Join(cfg.ConfigPath, k)
-			current, err := os.ReadFile(path)
-			if err != nil {
-				continue
+		case ev, ok := <-fw.Events:
+			if !ok {
+				return errors.New("the config watcher is closed")
 			}
-			if cmp.Equal(string(current), string(v)) {
-				continue
+			log.Debugf("config event: %s", ev)
+			delay = time.After(eventDelay)
+			continue
+		case err, ok := <-fw.Errors:
+			if !ok {
+				return errors.New("the config watcher is closed")
 			}
-			dataPath := filepath.Join(cfg.DataPath, k)
-			err = os.Remove(dataPath)
-			if err != nil && !os.IsNotExist(err) {
-				continue
-			}
-			err = os.WriteFile(dataPath, current, 0o600)
-			if err != nil {
-				continue
-			}
-			preConfig[k] = current
-			reload = true
+			log.Errorf("failed to watch the config: %v", err)
+			continue
+		case <-delay:
+			delay = nil
+		case <-tick.C:
 		}
+		s.sync(ctx)
+	}
+}
 
-		if reload {
-			if err := rcon.Reload(conn); err != nil {
-				return err
-			}
+// syncer applies the changes of the config to the data volume and the running server.
+type syncer struct {
+	conn      rcon.Console
+	cfg       Config
+	installer *artifact.Installer
+
+	// preConfig is the content of each config file that has been applied, with the Secret references resolved.
+	// A missing file is nil.
+	preConfig      map[string][]byte
+	preDatapacks   string
+	preConfigFiles string
+
+	// retries are the changes that failed to be applied, by the name of the file.
+	retries map[string]*retry
+	now     func() time.Time
+}
+
+// retry is a change that failed to be applied and is tried again after delay.
+type retry struct {
+	state string
+	delay time.Duration
+	next  time.Time
+}
+
+func newSyncer(conn rcon.Console, cfg Config) (*syncer, error) {
+	s := &syncer{
+		conn:      conn,
+		cfg:       cfg,
+		installer: artifact.NewInstaller(cfg.DataPath, nil),
+		preConfig: map[string][]byte{},
+		retries:   map[string]*retry{},
+		now:       time.Now,
+	}
+
+	// mcing-init has copied the config files and installed the datapacks when the pod started.
+	for _, f := range managedFiles {
+		current, err := os.ReadFile(filepath.Join(cfg.ConfigPath, f.name))
+		switch {
+		case err == nil:
+			s.cfg.Results.Set(f.name, nil)
+		case os.IsNotExist(err) && !f.required:
+		default:
+			return nil, err
+		}
+		if resolved, err := s.resolve(f.name, current); err == nil {
+			current = resolved
+		}
+		s.preConfig[f.name] = current
+	}
+	manifest, err := artifact.ReadManifest(filepath.Join(cfg.ConfigPath, constants.ArtifactsName))
+	if err != nil {
+		return nil, err
+	}
+	s.preDatapacks = datapackState(manifest)
+
+	files, err := configfile.ReadManifest(filepath.Join(cfg.ConfigPath, constants.ConfigFilesName))
+	if err != nil {
+		return nil, err
+	}
+	if s.preConfigFiles, err = s.configFilesState(files); err != nil {
+		return nil, err
+	}
+	if len(files.Files) != 0 {
+		s.cfg.Results.Set(constants.ConfigFilesName, nil)
+	}
+	return s, nil
+}
+
+// sync applies the changed files.
+// A change that failed to be applied is tried again after a delay, or at once when the file changes again.
+func (s *syncer) sync(ctx context.Context) {
+	paused := s.cfg.Paused != nil && s.cfg.Paused(ctx)
+	reload := false
+	for _, f := range managedFiles {
+		current, err := os.ReadFile(filepath.Join(s.cfg.ConfigPath, f.name))
+		if err != nil && !os.IsNotExist(err) {
+			log.Errorf("failed to read %s: %v", f.name, err)
+			s.cfg.Results.Set(f.name, err)
+			continue
+		}
+		if current == nil {
+			// The file is removed from the config. The server keeps its own copy.
+			s.preConfig[f.name] = nil
+			continue
+		}
+		// The file on the data volume is replaced only after the Secret references are resolved,
+		// so that the server keeps the current file if a referenced key is not available.
+		current, err = s.resolve(f.name, current)
+		if err != nil {
+			log.Errorf("failed to apply %s: %v", f.name, err)
+			s.cfg.Results.Set(f.name, err)
+			continue
+		}
+		previous := s.preConfig[f.name]
+		if bytes.Equal(current, previous) || s.backingOff(f.name, string(current)) {
+			continue
+		}
+
+		if err := s.apply(f, previous, current, paused); err != nil {
+			log.Errorf("failed to apply %s: %v", f.name, err)
+			s.cfg.Results.Set(f.name, err)
+			s.failed(f.name, string(current))
+			continue
 		}
+		log.Debugf("applied %s", f.name)
+		s.cfg.Results.Set(f.name, nil)
+		delete(s.retries, f.name)
+		s.preConfig[f.name] = current
+		reload = reload || f.reload
 	}
+
+	if s.syncDatapacks(ctx) {
+		reload = true
+	}
+	s.syncConfigFiles()
+
+	// A paused server loads the changes when it starts.
+	if reload && !paused {
+		if err := rcon.Reload(s.conn); err != nil {
+			// The changes are on the data volume, and the server loads them when it restarts.
+			log.Errorf("failed to reload: %v", err)
+		}
+	}
+}
+
+// syncDatapacks installs the datapacks when they change. It reports whether they are installed.
+func (s *syncer) syncDatapacks(ctx context.Context) bool {
+	manifest, err := artifact.ReadManifest(filepath.Join(s.cfg.ConfigPath, constants.ArtifactsName))
+	if err != nil {
+		return false
+	}
+	current := datapackState(manifest)
+	if current == s.preDatapacks || s.backingOff(constants.ArtifactsName, current) {
+		return false
+	}
+	_, err = s.installer.InstallDatapacks(ctx, manifest)
+	s.cfg.Results.Set(constants.ArtifactsName, err)
+	if err != nil {
+		log.Errorf("failed to install datapacks: %v", err)
+		s.failed(constants.ArtifactsName, current)
+		return false
+	}
+	delete(s.retries, constants.ArtifactsName)
+	s.preDatapacks = current
+	return true
+}
+
+// syncConfigFiles places the config files of spec.configFiles when they change.
+// Plugins and mods read them when they are loaded, so the changes take effect after a restart.
+func (s *syncer) syncConfigFiles() {
+	manifest, err := configfile.ReadManifest(filepath.Join(s.cfg.ConfigPath, constants.ConfigFilesName))
+	if err != nil {
+		log.Errorf("failed to read the config files: %v", err)
+		s.cfg.Results.Set(constants.ConfigFilesName, err)
+		return
+	}
+	current, err := s.configFilesState(manifest)
+	if err != nil {
+		log.Errorf("failed to read the config files: %v", err)
+		s.cfg.Results.Set(constants.ConfigFilesName, err)
+		return
+	}
+	if current == s.preConfigFiles || s.backingOff(constants.ConfigFilesName, current) {
+		return
+	}
+	err = configfile.Place(s.cfg.DataPath, s.cfg.SecretsPath, manifest)
+	s.cfg.Results.Set(constants.ConfigFilesName, err)
+	if err != nil {
+		log.Errorf("failed to place the config files: %v", err)
+		s.failed(constants.ConfigFilesName, current)
+		return
+	}
+	log.Debug("placed the config files")
+	delete(s.retries, constants.ConfigFilesName)
+	s.preConfigFiles = current
+}
+
+// backingOff reports whether the change of name to state has failed recently and is not tried yet.
+func (s *syncer) backingOff(name, state string) bool {
+	r, ok := s.retries[name]
+	return ok && r.state == state && s.now().Before(r.next)
+}
+
+// failed records that the change of name to state failed to be applied, and doubles the delay of the next try.
+func (s *syncer) failed(name, state string) {
+	r, ok := s.retries[name]
+	if !ok || r.state != state {
+		r = &retry{state: state, delay: minRetryDelay, next: time.Time{}}
+		s.retries[name] = r
+	} else {
+		r.delay = min(2*r.delay, maxRetryDelay)
+	}
+	r.next = s.now().Add(r.delay)
+}
+
+// resolve replaces the references to Secret keys in the content of a config file.
+func (s *syncer) resolve(name string, content []byte) ([]byte, error) {
+	if content == nil {
+		return nil, nil
+	}
+	return config.ResolveSecretRefs(content, s.cfg.SecretsPath, name)
+}
+
+// configFilesState returns the state of the config files of spec.configFiles.
+// The Secret references in the state are resolved, so that a change of a referenced key places the files again.
+// If a key is not available, the files are placed as far as possible and the error is reported by Place.
+func (s *syncer) configFilesState(m *configfile.Manifest) (string, error) {
+	state, err := configfile.State(m)
+	if err != nil {
+		return "", err
+	}
+	// The values are not escaped, because the state is only compared with the previous one.
+	if resolved, err := config.ResolveSecretRefs([]byte(state), s.cfg.SecretsPath, ""); err == nil {
+		return string(resolved), nil
+	}
+	return state, nil
+}
+
+// apply writes the resolved file to the data volume and applies the change to the running server.
+// A paused server reads the file when it starts, so RCON is not used.
+func (s *syncer) apply(f managedFile, previous, current []byte, paused bool) error {
+	if err := replaceFile(filepath.Join(s.cfg.DataPath, f.name), current); err != nil {
+		return err
+	}
+	if paused {
+		return nil
+	}
+	return f.apply(s.conn, previous, current)
+}
+
+// replaceFile atomically replaces the file at path with data.
+func replaceFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func datapackState(m *artifact.Manifest) string {
+	data, _ := json.Marshal(struct {
+		Datapacks []artifact.Artifact
+		LevelName string
+	}{m.Datapacks, m.LevelName})
+	return string(data)
 }
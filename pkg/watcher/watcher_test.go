@@ -1,8 +1,13 @@
 package watcher
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 	"testing"
 	"time"
@@ -99,3 +104,598 @@ func TestWatch(t *testing.T) {
 		t.Error("expected Reload to be called after config change")
 	}
 }
+
+func TestWatch_Datapacks(t *testing.T) {
+	tempDir := t.TempDir()
+	configDir := filepath.Join(tempDir, "config")
+	dataDir := filepath.Join(tempDir, "data")
+	for _, dir := range []string{configDir, dataDir} {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := os.WriteFile(filepath.Join(configDir, constants.ServerPropsName), []byte("test=1"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("datapack"))
+	}))
+	defer srv.Close()
+
+	var mu sync.Mutex
+	reloadCount := 0
+	mock := &MockConsole{
+		WriteFunc: func(cmd string) (int, error) {
+			if cmd == "reload" {
+				mu.Lock()
+				reloadCount++
+				mu.Unlock()
+			}
+			return 1, nil
+		},
+		ReadFunc: func() (string, int, error) {
+			return "Reloaded", 1, nil
+		},
+	}
+
+	go func() {
+		_ = Watch(t.Context(), mock, 100*time.Millisecond, Config{DataPath: dataDir, ConfigPath: configDir})
+	}()
+	time.Sleep(200 * time.Millisecond)
+
+	manifest := `{"datapacks":[{"name":"pack.zip","url":"` + srv.URL + `/pack.zip"}],"levelName":"survival"}`
+	err = os.WriteFile(filepath.Join(configDir, constants.ArtifactsName), []byte(manifest), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	data, err := os.ReadFile(filepath.Join(dataDir, "survival", "datapacks", "pack.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "datapack" {
+		t.Errorf("pack.zip = %q", data)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if reloadCount != 1 {
+		t.Errorf("reload was called %d times, want 1", reloadCount)
+	}
+}
+
+func TestWatch_HotReloadableProps(t *testing.T) {
+	tempDir := t.TempDir()
+	configDir := filepath.Join(tempDir, "config")
+	dataDir := filepath.Join(tempDir, "data")
+	for _, dir := range []string{configDir, dataDir} {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	serverProps := filepath.Join(configDir, constants.ServerPropsName)
+	err := os.WriteFile(serverProps, []byte("difficulty=easy\nmax-players=20\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var commands []string
+	mock := &MockConsole{
+		WriteFunc: func(cmd string) (int, error) {
+			mu.Lock()
+			commands = append(commands, cmd)
+			mu.Unlock()
+			return 1, nil
+		},
+		ReadFunc: func() (string, int, error) {
+			return "", 1, nil
+		},
+	}
+
+	go func() {
+		_ = Watch(t.Context(), mock, 100*time.Millisecond, Config{DataPath: dataDir, ConfigPath: configDir})
+	}()
+	time.Sleep(200 * time.Millisecond)
+
+	err = os.WriteFile(serverProps, []byte("difficulty=hard\nmax-players=10\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"difficulty hard", "reload"}
+	if !slices.Equal(commands, want) {
+		t.Errorf("commands = %v, want %v", commands, want)
+	}
+}
+
+func TestWatch_Lists(t *testing.T) {
+	tempDir := t.TempDir()
+	configDir := filepath.Join(tempDir, "config")
+	dataDir := filepath.Join(tempDir, "data")
+	for _, dir := range []string{configDir, dataDir} {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]string{
+		constants.ServerPropsName: "test=1",
+		constants.OpsName:         `[{"uuid":"1","name":"alice","level":4}]`,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(configDir, name), []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var mu sync.Mutex
+	var commands []string
+	mock := &MockConsole{
+		WriteFunc: func(cmd string) (int, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			commands = append(commands, cmd)
+			return 1, nil
+		},
+		ReadFunc: func() (string, int, error) {
+			return "", 1, nil
+		},
+	}
+
+	results := NewResults()
+	cfg := Config{DataPath: dataDir, ConfigPath: configDir, Results: results}
+	go func() {
+		_ = Watch(t.Context(), mock, time.Hour, cfg)
+	}()
+	time.Sleep(200 * time.Millisecond)
+
+	// The changes are detected by inotify without waiting for the interval.
+	files = map[string]string{
+		constants.OpsName:       `[{"uuid":"2","name":"bob","level":4}]`,
+		constants.BanPlayerName: `[{"uuid":"3","name":"griefer"}]`,
+		constants.BanIPName:     `[{"ip":"192.0.2.1"}]`,
+		constants.WhiteListName: `[{"uuid":"2","name":"bob"}]`,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(configDir, name), []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	for name, want := range files {
+		data, err := os.ReadFile(filepath.Join(dataDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != want {
+			t.Errorf("%s = %q, want %q", name, data, want)
+		}
+	}
+
+	mu.Lock()
+	got := slices.Clone(commands)
+	mu.Unlock()
+	slices.Sort(got)
+	want := []string{"ban griefer", "ban-ip 192.0.2.1", "deop alice", "op bob", "whitelist reload"}
+	if !slices.Equal(got, want) {
+		t.Errorf("commands = %v, want %v", got, want)
+	}
+
+	var synced []string
+	for _, res := range results.List() {
+		if res.Error != "" {
+			t.Errorf("%s: %s", res.Name, res.Error)
+		}
+		synced = append(synced, res.Name)
+	}
+	wantSynced := []string{
+		constants.BanIPName, constants.BanPlayerName, constants.OpsName,
+		constants.ServerPropsName, constants.WhiteListName,
+	}
+	if !slices.Equal(synced, wantSynced) {
+		t.Errorf("synced files = %v, want %v", synced, wantSynced)
+	}
+}
+
+func TestWatch_Errors(t *testing.T) {
+	tempDir := t.TempDir()
+	configDir := filepath.Join(tempDir, "config")
+	dataDir := filepath.Join(tempDir, "data")
+	for _, dir := range []string{configDir, dataDir} {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	serverProps := filepath.Join(configDir, constants.ServerPropsName)
+	if err := os.WriteFile(serverProps, []byte("test=1"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	mock := &MockConsole{
+		WriteFunc: func(_ string) (int, error) {
+			return 0, errors.New("connection refused")
+		},
+	}
+	results := NewResults()
+	done := make(chan error)
+	go func() {
+		done <- Watch(t.Context(), mock, 100*time.Millisecond, Config{
+			DataPath:   dataDir,
+			ConfigPath: configDir,
+			Results:    results,
+		})
+	}()
+	time.Sleep(200 * time.Millisecond)
+
+	// A failed reload does not stop the watcher.
+	if err := os.WriteFile(serverProps, []byte("test=2"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// An invalid list is reported and retried.
+	if err := os.WriteFile(filepath.Join(configDir, constants.OpsName), []byte("invalid"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	select {
+	case err := <-done:
+		t.Fatalf("Watch() returned: %v", err)
+	default:
+	}
+	var opsErr string
+	for _, res := range results.List() {
+		if res.Name == constants.OpsName {
+			opsErr = res.Error
+		}
+	}
+	if opsErr == "" {
+		t.Errorf("the error of %s is not reported", constants.OpsName)
+	}
+}
+
+func TestWatch_ConfigFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	configDir := filepath.Join(tempDir, "config")
+	configFilesDir := filepath.Join(tempDir, "config-files")
+	dataDir := filepath.Join(tempDir, "data")
+	for _, dir := range []string{configDir, configFilesDir, dataDir} {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(configDir, constants.ServerPropsName), []byte("test=1"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source := filepath.Join(configFilesDir, "bukkit.yml")
+	if err := os.WriteFile(source, []byte("settings:\n  allow-end: true\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	manifest := `{"files":[{"path":"bukkit.yml","source":"` + source + `","merge":true}]}`
+	if err := os.WriteFile(filepath.Join(configDir, constants.ConfigFilesName), []byte(manifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// The server has written the other settings.
+	target := filepath.Join(dataDir, "bukkit.yml")
+	if err := os.WriteFile(target, []byte("settings:\n  allow-end: true\n  shutdown-message: bye\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	results := NewResults()
+	go func() {
+		_ = Watch(t.Context(), &MockConsole{}, time.Hour, Config{
+			DataPath:        dataDir,
+			ConfigPath:      configDir,
+			ConfigFilesPath: configFilesDir,
+			Results:         results,
+		})
+	}()
+	time.Sleep(200 * time.Millisecond)
+
+	if err := os.WriteFile(source, []byte("settings:\n  allow-end: false\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	data, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "settings:\n  allow-end: false\n  shutdown-message: bye\n"
+	if string(data) != want {
+		t.Errorf("bukkit.yml = %q, want %q", data, want)
+	}
+	for _, res := range results.List() {
+		if res.Name == constants.ConfigFilesName && res.Error != "" {
+			t.Errorf("%s: %s", res.Name, res.Error)
+		}
+	}
+}
+
+func TestWatch_SecretRefs(t *testing.T) {
+	tempDir := t.TempDir()
+	configDir := filepath.Join(tempDir, "config")
+	secretsDir := filepath.Join(tempDir, "secrets")
+	dataDir := filepath.Join(tempDir, "data")
+	for _, dir := range []string{configDir, filepath.Join(secretsDir, "rcon"), dataDir} {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(secretsDir, "rcon", "password"), []byte("s3cr3t"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	serverProps := filepath.Join(configDir, constants.ServerPropsName)
+	if err := os.WriteFile(serverProps, []byte("motd=a\nrcon.password=${secret:rcon/password}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		_ = Watch(t.Context(), &MockConsole{}, time.Hour, Config{
+			DataPath:    dataDir,
+			ConfigPath:  configDir,
+			SecretsPath: secretsDir,
+		})
+	}()
+	time.Sleep(200 * time.Millisecond)
+
+	if err := os.WriteFile(serverProps, []byte("motd=b\nrcon.password=${secret:rcon/password}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	data, err := os.ReadFile(filepath.Join(dataDir, constants.ServerPropsName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "motd=b\nrcon.password=s3cr3t\n"; string(data) != want {
+		t.Errorf("server.properties = %q, want %q", data, want)
+	}
+}
+
+func TestWatch_SecretChanged(t *testing.T) {
+	tempDir := t.TempDir()
+	configDir := filepath.Join(tempDir, "config")
+	secretsDir := filepath.Join(tempDir, "secrets")
+	dataDir := filepath.Join(tempDir, "data")
+	for _, dir := range []string{configDir, filepath.Join(secretsDir, "rcon"), dataDir} {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	password := filepath.Join(secretsDir, "rcon", "password")
+	if err := os.WriteFile(password, []byte("s3cr3t"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	serverProps := filepath.Join(configDir, constants.ServerPropsName)
+	if err := os.WriteFile(serverProps, []byte("motd=a\nrcon.password=${secret:rcon/password}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// mcing-init has copied server.properties when the pod started.
+	current := filepath.Join(dataDir, constants.ServerPropsName)
+	if err := os.WriteFile(current, []byte("motd=a\nrcon.password=s3cr3t\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		_ = Watch(t.Context(), &MockConsole{}, 100*time.Millisecond, Config{
+			DataPath:    dataDir,
+			ConfigPath:  configDir,
+			SecretsPath: secretsDir,
+		})
+	}()
+	time.Sleep(200 * time.Millisecond)
+
+	// Only the value of the referenced Secret key changes.
+	if err := os.WriteFile(password, []byte("n3w"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	data, err := os.ReadFile(current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "motd=a\nrcon.password=n3w\n"; string(data) != want {
+		t.Errorf("server.properties = %q, want %q", data, want)
+	}
+}
+
+func TestWatch_SecretRefsMissing(t *testing.T) {
+	tempDir := t.TempDir()
+	configDir := filepath.Join(tempDir, "config")
+	secretsDir := filepath.Join(tempDir, "secrets")
+	dataDir := filepath.Join(tempDir, "data")
+	for _, dir := range []string{configDir, secretsDir, dataDir} {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	serverProps := filepath.Join(configDir, constants.ServerPropsName)
+	if err := os.WriteFile(serverProps, []byte("motd=a\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// mcing-init has copied server.properties when the pod started.
+	current := filepath.Join(dataDir, constants.ServerPropsName)
+	if err := os.WriteFile(current, []byte("motd=a\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	results := NewResults()
+	go func() {
+		_ = Watch(t.Context(), &MockConsole{}, time.Hour, Config{
+			DataPath:    dataDir,
+			ConfigPath:  configDir,
+			SecretsPath: secretsDir,
+			Results:     results,
+		})
+	}()
+	time.Sleep(200 * time.Millisecond)
+
+	// The referenced Secret key is not projected yet.
+	if err := os.WriteFile(serverProps, []byte("motd=b\nrcon.password=${secret:rcon/password}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	data, err := os.ReadFile(current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "motd=a\n"; string(data) != want {
+		t.Errorf("server.properties = %q, want %q", data, want)
+	}
+	var propsErr string
+	for _, res := range results.List() {
+		if res.Name == constants.ServerPropsName {
+			propsErr = res.Error
+		}
+	}
+	if propsErr == "" {
+		t.Errorf("the error of %s is not reported", constants.ServerPropsName)
+	}
+}
+
+func TestSync_Backoff(t *testing.T) {
+	tempDir := t.TempDir()
+	configDir := filepath.Join(tempDir, "config")
+	dataDir := filepath.Join(tempDir, "data")
+	for _, dir := range []string{configDir, dataDir} {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(configDir, constants.ServerPropsName), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ops := filepath.Join(configDir, constants.OpsName)
+	if err := os.WriteFile(ops, []byte("[]"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var commands []string
+	mock := &MockConsole{
+		WriteFunc: func(cmd string) (int, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			commands = append(commands, cmd)
+			return 0, errors.New("connection refused")
+		},
+	}
+	s, err := newSyncer(mock, Config{DataPath: dataDir, ConfigPath: configDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	tries := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		n := len(commands)
+		commands = nil
+		return n
+	}
+
+	if err := os.WriteFile(ops, []byte(`[{"name":"alice"}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s.sync(t.Context())
+	if n := tries(); n != 1 {
+		t.Fatalf("tries = %d, want 1", n)
+	}
+
+	// The failed change is not tried again until the delay passes.
+	s.sync(t.Context())
+	if n := tries(); n != 0 {
+		t.Errorf("tries before the delay = %d, want 0", n)
+	}
+	now = now.Add(minRetryDelay)
+	s.sync(t.Context())
+	if n := tries(); n != 1 {
+		t.Errorf("tries after the delay = %d, want 1", n)
+	}
+
+	// The delay doubles after every failure.
+	now = now.Add(minRetryDelay)
+	s.sync(t.Context())
+	if n := tries(); n != 0 {
+		t.Errorf("tries before the doubled delay = %d, want 0", n)
+	}
+	now = now.Add(minRetryDelay)
+	s.sync(t.Context())
+	if n := tries(); n != 1 {
+		t.Errorf("tries after the doubled delay = %d, want 1", n)
+	}
+
+	// A new change is tried at once.
+	if err := os.WriteFile(ops, []byte(`[{"name":"bob"}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s.sync(t.Context())
+	if n := tries(); n != 1 {
+		t.Errorf("tries after a new change = %d, want 1", n)
+	}
+}
+
+func TestSync_Paused(t *testing.T) {
+	tempDir := t.TempDir()
+	configDir := filepath.Join(tempDir, "config")
+	dataDir := filepath.Join(tempDir, "data")
+	for _, dir := range []string{configDir, dataDir} {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	serverProps := filepath.Join(configDir, constants.ServerPropsName)
+	if err := os.WriteFile(serverProps, []byte("difficulty=easy\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var commands []string
+	mock := &MockConsole{
+		WriteFunc: func(cmd string) (int, error) {
+			commands = append(commands, cmd)
+			return 0, nil
+		},
+	}
+	paused := true
+	s, err := newSyncer(mock, Config{
+		DataPath:   dataDir,
+		ConfigPath: configDir,
+		Paused:     func(context.Context) bool { return paused },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(serverProps, []byte("difficulty=hard\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s.sync(t.Context())
+	s.sync(t.Context())
+
+	// The paused server reads the file when it starts.
+	data, err := os.ReadFile(filepath.Join(dataDir, constants.ServerPropsName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "difficulty=hard\n"; string(data) != want {
+		t.Errorf("server.properties = %q, want %q", data, want)
+	}
+	if len(commands) != 0 {
+		t.Errorf("commands = %v, want none while the server is paused", commands)
+	}
+
+	// The applied file is not applied again when the server wakes up.
+	paused = false
+	s.sync(t.Context())
+	if len(commands) != 0 {
+		t.Errorf("commands = %v, want none after the server wakes up", commands)
+	}
+}